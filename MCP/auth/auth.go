@@ -0,0 +1,297 @@
+// Package auth authenticates clients of the MCP HTTP endpoint and maps them
+// to ClickSend credentials kept on the server.
+//
+// Clients present either a static bearer token or, in OAuth 2.1
+// resource-server mode, a JWT access token checked against a local JWKS
+// file. The authenticated principal selects the ClickSend credentials used
+// for its tool calls, so clients never hold the ClickSend API key.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPrincipal names the credentials used by principals without their
+// own entry.
+const DefaultPrincipal = "*"
+
+// File is the auth configuration file named by MCP_AUTH_FILE.
+type File struct {
+	// Tokens are the static bearer tokens accepted.
+	Tokens []Token `yaml:"tokens"`
+	// OAuth enables JWT access tokens when set.
+	OAuth *OAuth `yaml:"oauth"`
+	// Principals maps principal names, or DefaultPrincipal, to ClickSend
+	// credentials.
+	Principals map[string]Credentials `yaml:"principals"`
+}
+
+// Token is a static bearer token. Only one of Token and SHA256 is set;
+// SHA256 is the hex SHA-256 digest of the token and keeps it out of the file.
+type Token struct {
+	Principal string `yaml:"principal"`
+	Token     string `yaml:"token"`
+	SHA256    string `yaml:"sha256"`
+}
+
+// OAuth configures resource-server mode.
+type OAuth struct {
+	// Resource is the canonical URL of the MCP endpoint. Tokens must list it
+	// in their aud claim.
+	Resource string `yaml:"resource"`
+	// Issuer, when set, must match the iss claim and is advertised as the
+	// authorization server.
+	Issuer string `yaml:"issuer"`
+	// JWKSFile is a JSON Web Key Set holding the token signing keys.
+	JWKSFile string `yaml:"jwks_file"`
+	// Scopes must all be granted by the token's scope or scp claim.
+	Scopes []string `yaml:"scopes"`
+	// PrincipalClaim names the claim identifying the principal; it defaults
+	// to sub.
+	PrincipalClaim string `yaml:"principal_claim"`
+}
+
+// Credentials are the ClickSend credentials of a principal. BasicAuth is the
+// base64 "username:api_key" pair; Username and APIKey may be given instead.
+// Profile names a server-side credential profile to use instead, and
+// Profiles lists the other profiles the principal may select per tool call.
+type Credentials struct {
+	BaseURL   string   `yaml:"api_base_url"`
+	BasicAuth string   `yaml:"basic_auth"`
+	Username  string   `yaml:"username"`
+	APIKey    string   `yaml:"api_key"`
+	Profile   string   `yaml:"profile"`
+	Profiles  []string `yaml:"profiles"`
+}
+
+// Basic returns the base64 "username:api_key" pair.
+func (c Credentials) Basic() string {
+	if c.BasicAuth != "" {
+		return c.BasicAuth
+	}
+	if c.Username == "" && c.APIKey == "" {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.APIKey))
+}
+
+// Principal is an authenticated client.
+type Principal struct {
+	Name        string
+	Scopes      []string
+	Credentials Credentials
+}
+
+// Error is an authentication failure, reported with the OAuth error codes
+// of RFC 6750.
+type Error struct {
+	Status      int    // 401 or 403
+	Code        string // "invalid_token" or "insufficient_scope"; empty when no token was sent
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Description
+}
+
+// Authenticator checks the bearer token of each request.
+type Authenticator struct {
+	tokens     map[[sha256.Size]byte]string
+	oauth      *OAuth
+	keys       *keySet
+	principals map[string]Credentials
+}
+
+// FromEnv loads the file named by MCP_AUTH_FILE. It returns nil, nil when
+// the variable is unset and the MCP endpoint is open.
+func FromEnv() (*Authenticator, error) {
+	path := configfile.Getenv("MCP_AUTH_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path)
+}
+
+// Load reads an auth configuration file.
+func Load(path string) (*Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	a, err := New(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
+
+// New returns an authenticator for f.
+func New(f File) (*Authenticator, error) {
+	if len(f.Tokens) == 0 && f.OAuth == nil {
+		return nil, errors.New("no tokens or oauth section")
+	}
+	a := &Authenticator{tokens: map[[sha256.Size]byte]string{}, oauth: f.OAuth, principals: f.Principals}
+	for i, t := range f.Tokens {
+		if t.Principal == "" {
+			return nil, fmt.Errorf("token %d has no principal", i)
+		}
+		var digest [sha256.Size]byte
+		switch {
+		case t.Token != "" && t.SHA256 == "":
+			digest = sha256.Sum256([]byte(t.Token))
+		case t.SHA256 != "" && t.Token == "":
+			b, err := hex.DecodeString(t.SHA256)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("token %d: sha256 must be 64 hex digits", i)
+			}
+			copy(digest[:], b)
+		default:
+			return nil, fmt.Errorf("token %d must have exactly one of token and sha256", i)
+		}
+		a.tokens[digest] = t.Principal
+	}
+	if o := f.OAuth; o != nil {
+		if o.Resource == "" || o.JWKSFile == "" {
+			return nil, errors.New("oauth needs resource and jwks_file")
+		}
+		if u, err := url.Parse(o.Resource); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("oauth resource %q is not an absolute URL", o.Resource)
+		}
+		if o.PrincipalClaim == "" {
+			o.PrincipalClaim = "sub"
+		}
+		keys, err := loadKeySet(o.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = keys
+	}
+	return a, nil
+}
+
+// Authenticate returns the principal presenting the bearer token of r.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, &Error{Status: http.StatusUnauthorized, Description: "bearer token required"}
+	}
+
+	var p *Principal
+	if name, ok := a.tokens[sha256.Sum256([]byte(token))]; ok {
+		p = &Principal{Name: name}
+	} else if a.oauth != nil && strings.Count(token, ".") == 2 {
+		var err error
+		if p, err = a.verifyJWT(token); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, &Error{Status: http.StatusUnauthorized, Code: "invalid_token", Description: "unknown token"}
+	}
+
+	creds, ok := a.principals[p.Name]
+	if !ok {
+		creds, ok = a.principals[DefaultPrincipal]
+	}
+	if !ok {
+		return nil, &Error{Status: http.StatusForbidden, Description: fmt.Sprintf("no ClickSend credentials for principal %q", p.Name)}
+	}
+	p.Credentials = creds
+	return p, nil
+}
+
+type principalKey struct{}
+
+// FromContext returns the principal authenticated by Middleware, or nil.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// Middleware rejects requests without a valid token and adds the principal
+// to the context of the others.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r)
+		if err != nil {
+			var authErr *Error
+			if !errors.As(err, &authErr) {
+				authErr = &Error{Status: http.StatusUnauthorized, Code: "invalid_token", Description: err.Error()}
+			}
+			a.challenge(w, authErr)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	})
+}
+
+// challenge writes err with a WWW-Authenticate header pointing OAuth clients
+// at the protected resource metadata.
+func (a *Authenticator) challenge(w http.ResponseWriter, err *Error) {
+	params := []string{}
+	if a.oauth != nil {
+		params = append(params, fmt.Sprintf("resource_metadata=%q", a.metadataURL()))
+		if len(a.oauth.Scopes) > 0 {
+			params = append(params, fmt.Sprintf("scope=%q", strings.Join(a.oauth.Scopes, " ")))
+		}
+	}
+	if err.Code != "" {
+		params = append(params, fmt.Sprintf("error=%q", err.Code), fmt.Sprintf("error_description=%q", err.Description))
+	}
+	challenge := "Bearer"
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, err.Description, err.Status)
+}
+
+// MetadataPath is where the protected resource metadata of RFC 9728 is
+// served, or "" when OAuth is off.
+func (a *Authenticator) MetadataPath() string {
+	if a.oauth == nil {
+		return ""
+	}
+	u, _ := url.Parse(a.oauth.Resource)
+	return "/.well-known/oauth-protected-resource" + strings.TrimSuffix(u.Path, "/")
+}
+
+func (a *Authenticator) metadataURL() string {
+	u, _ := url.Parse(a.oauth.Resource)
+	return u.Scheme + "://" + u.Host + a.MetadataPath()
+}
+
+// MetadataHandler serves the protected resource metadata, which tells MCP
+// clients which authorization server issues tokens for this server.
+func (a *Authenticator) MetadataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		metadata := map[string]any{
+			"resource":                 a.oauth.Resource,
+			"bearer_methods_supported": []string{"header"},
+		}
+		if a.oauth.Issuer != "" {
+			metadata["authorization_servers"] = []string{a.oauth.Issuer}
+		}
+		if len(a.oauth.Scopes) > 0 {
+			metadata["scopes_supported"] = a.oauth.Scopes
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metadata)
+	})
+}
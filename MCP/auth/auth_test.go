@@ -0,0 +1,165 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const resource = "https://mcp.example.com/mcp"
+
+func newAuthenticator(t *testing.T) (*auth.Authenticator, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC", "kid": "k1", "crv": "P-256",
+		"x": enc.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y": enc.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}}})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("hashed-token"))
+	a, err := auth.New(auth.File{
+		Tokens: []auth.Token{
+			{Principal: "ci", Token: "plain-token"},
+			{Principal: "ops", SHA256: hex.EncodeToString(digest[:])},
+			{Principal: "nobody", Token: "orphan-token"},
+		},
+		OAuth: &auth.OAuth{Resource: resource, Issuer: "https://auth.example.com", JWKSFile: jwksFile, Scopes: []string{"clicksend"}},
+		Principals: map[string]auth.Credentials{
+			"ci":    {Username: "ci-user", APIKey: "ci-key"},
+			"ops":   {BasicAuth: "b3BzOmtleQ==", BaseURL: "https://staging.example.com/v3"},
+			"alice": {Username: "alice", APIKey: "alice-key"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = "k1"
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthenticate(t *testing.T) {
+	a, key := newAuthenticator(t)
+	valid := jwt.MapClaims{
+		"iss": "https://auth.example.com", "aud": resource, "sub": "alice",
+		"scope": "openid clicksend", "exp": time.Now().Add(time.Hour).Unix(),
+	}
+	with := func(changes jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		for k, v := range changes {
+			claims[k] = v
+		}
+		return claims
+	}
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name      string
+		header    string
+		principal string
+		basic     string
+		status    int
+		code      string
+	}{
+		{name: "static token", header: "Bearer plain-token", principal: "ci", basic: "Y2ktdXNlcjpjaS1rZXk="},
+		{name: "hashed token", header: "Bearer hashed-token", principal: "ops", basic: "b3BzOmtleQ=="},
+		{name: "jwt", header: "Bearer " + sign(t, key, valid), principal: "alice", basic: "YWxpY2U6YWxpY2Uta2V5"},
+		{name: "no header", status: 401},
+		{name: "basic scheme", header: "Basic Y2k6a2V5", status: 401},
+		{name: "unknown token", header: "Bearer nope", status: 401, code: "invalid_token"},
+		{name: "no credentials", header: "Bearer orphan-token", status: 403},
+		{name: "expired", header: "Bearer " + sign(t, key, with(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})), status: 401, code: "invalid_token"},
+		{name: "wrong audience", header: "Bearer " + sign(t, key, with(jwt.MapClaims{"aud": "https://other.example.com"})), status: 401, code: "invalid_token"},
+		{name: "wrong issuer", header: "Bearer " + sign(t, key, with(jwt.MapClaims{"iss": "https://evil.example.com"})), status: 401, code: "invalid_token"},
+		{name: "wrong key", header: "Bearer " + sign(t, other, valid), status: 401, code: "invalid_token"},
+		{name: "missing scope", header: "Bearer " + sign(t, key, with(jwt.MapClaims{"scope": "openid"})), status: 403, code: "insufficient_scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *auth.Principal
+			handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = auth.FromContext(r.Context())
+			}))
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.status == 0 {
+				if rec.Code != 200 || got == nil {
+					t.Fatalf("status %d: %s", rec.Code, rec.Body)
+				}
+				if got.Name != tt.principal || got.Credentials.Basic() != tt.basic {
+					t.Errorf("principal = %s with %s, want %s with %s", got.Name, got.Credentials.Basic(), tt.principal, tt.basic)
+				}
+				return
+			}
+			if rec.Code != tt.status || got != nil {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if !strings.HasPrefix(challenge, `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`) {
+				t.Errorf("WWW-Authenticate = %s", challenge)
+			}
+			if tt.code != "" && !strings.Contains(challenge, `error="`+tt.code+`"`) {
+				t.Errorf("WWW-Authenticate = %s, want error %s", challenge, tt.code)
+			}
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	a, _ := newAuthenticator(t)
+	if path := a.MetadataPath(); path != "/.well-known/oauth-protected-resource/mcp" {
+		t.Errorf("MetadataPath = %s", path)
+	}
+	rec := httptest.NewRecorder()
+	a.MetadataHandler().ServeHTTP(rec, httptest.NewRequest("GET", a.MetadataPath(), nil))
+	var metadata struct {
+		Resource             string   `json:"resource"`
+		AuthorizationServers []string `json:"authorization_servers"`
+		ScopesSupported      []string `json:"scopes_supported"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Resource != resource || len(metadata.AuthorizationServers) != 1 || metadata.ScopesSupported[0] != "clicksend" {
+		t.Errorf("metadata = %+v", metadata)
+	}
+}
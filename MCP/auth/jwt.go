@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway is the clock skew allowed when checking exp and nbf.
+const leeway = 30 * time.Second
+
+// keySet holds the verification keys of a JWKS file.
+type keySet struct {
+	byID map[string]any
+	all  []any
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadKeySet reads the RSA, EC and Ed25519 signing keys of a JWKS file.
+func loadKeySet(path string) (*keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	set := &keySet{byID: map[string]any{}}
+	for i, k := range jwks.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d: %w", path, i, err)
+		}
+		if k.Kid != "" {
+			set.byID[k.Kid] = key
+		}
+		set.all = append(set.all, key)
+	}
+	if len(set.all) == 0 {
+		return nil, fmt.Errorf("%s: no signing keys", path)
+	}
+	return set, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err1 := decodeInt(k.N)
+		e, err2 := decodeInt(k.E)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err1 := decodeInt(k.X)
+		y, err2 := decodeInt(k.Y)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// key picks the key for a token: the one named by its kid header, or the
+// only key in the set.
+func (s *keySet) key(t *jwt.Token) (any, error) {
+	if kid, ok := t.Header["kid"].(string); ok {
+		if key, ok := s.byID[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if len(s.all) == 1 {
+		return s.all[0], nil
+	}
+	return nil, errors.New("token has no kid")
+}
+
+// verifyJWT checks the signature, lifetime, issuer, audience and scopes of
+// an access token.
+func (a *Authenticator) verifyJWT(token string) (*Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithAudience(a.oauth.Resource),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if a.oauth.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.oauth.Issuer))
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, a.keys.key, opts...); err != nil {
+		return nil, &Error{Status: http.StatusUnauthorized, Code: "invalid_token", Description: err.Error()}
+	}
+
+	name, _ := claims[a.oauth.PrincipalClaim].(string)
+	if name == "" {
+		return nil, &Error{Status: http.StatusUnauthorized, Code: "invalid_token", Description: fmt.Sprintf("token has no %s claim", a.oauth.PrincipalClaim)}
+	}
+	scopes := tokenScopes(claims)
+	for _, want := range a.oauth.Scopes {
+		if !slices.Contains(scopes, want) {
+			return nil, &Error{Status: http.StatusForbidden, Code: "insufficient_scope", Description: fmt.Sprintf("token lacks scope %q", want)}
+		}
+	}
+	return &Principal{Name: name, Scopes: scopes}, nil
+}
+
+// tokenScopes reads the space-separated scope claim, or the scp array some
+// authorization servers issue instead.
+func tokenScopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	var scopes []string
+	switch scp := claims["scp"].(type) {
+	case string:
+		scopes = strings.Fields(scp)
+	case []any:
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	return scopes
+}
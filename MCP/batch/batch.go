@@ -0,0 +1,134 @@
+// Package batch runs many independent ClickSend calls at once, for tools
+// that change many contacts in one call.
+//
+// A Runner bounds how many calls of one batch run at a time and spaces the
+// calls of all its batches to a rate limit, so several bulk tool calls
+// running together still respect it.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// DefaultWorkers is used when BULK_CONCURRENCY is not set.
+const DefaultWorkers = 4
+
+// Runner runs batches of calls.
+type Runner struct {
+	workers int
+
+	mu       sync.Mutex
+	interval time.Duration // between call starts; 0 is unlimited
+	next     time.Time     // earliest start of the next call
+}
+
+// New returns a runner running workers calls of a batch at a time, and at
+// most rate calls a second across batches; 0 is unlimited.
+func New(workers int, rate float64) *Runner {
+	r := &Runner{workers: max(1, workers)}
+	r.SetRate(rate)
+	return r
+}
+
+// FromEnv returns the runner set by BULK_CONCURRENCY and BULK_RATE_LIMIT.
+func FromEnv() (*Runner, error) {
+	workers := DefaultWorkers
+	if v := configfile.Getenv("BULK_CONCURRENCY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BULK_CONCURRENCY must be a positive integer, got %q", v)
+		}
+		workers = n
+	}
+	var rate float64
+	if v := configfile.Getenv("BULK_RATE_LIMIT"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("BULK_RATE_LIMIT must be a non-negative number of calls per second, got %q", v)
+		}
+		rate = f
+	}
+	return New(workers, rate), nil
+}
+
+// SetRate changes the rate limit in calls a second; 0 is unlimited.
+func (r *Runner) SetRate(rate float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interval = 0
+	if rate > 0 {
+		r.interval = time.Duration(float64(time.Second) / rate)
+	}
+}
+
+// Workers returns the number of calls of a batch run at a time. A nil
+// Runner runs DefaultWorkers.
+func (r *Runner) Workers() int {
+	if r == nil {
+		return DefaultWorkers
+	}
+	return r.workers
+}
+
+// Run calls fn for each index in [0, n), with at most Workers calls running
+// at a time, and returns the error of each call by index. A failed call does
+// not stop the others. Once ctx is done, calls not yet started fail with
+// ctx.Err() instead of running. A nil Runner has no rate limit.
+func (r *Runner) Run(ctx context.Context, n int, fn func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n)
+	workers := max(1, min(r.Workers(), n))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := r.wait(ctx); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(ctx, i)
+			}
+		}()
+	}
+	for i := range n {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
+
+// wait blocks until the rate limit allows another call.
+func (r *Runner) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil || r == nil {
+		return err
+	}
+	r.mu.Lock()
+	now := time.Now()
+	start := now
+	if r.next.After(now) {
+		start = r.next
+	}
+	r.next = start.Add(r.interval)
+	r.mu.Unlock()
+
+	if !start.After(now) {
+		return nil
+	}
+	t := time.NewTimer(start.Sub(now))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,51 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var running, peak atomic.Int32
+	errs := New(3, 0).Run(context.Background(), 20, func(ctx context.Context, i int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(time.Millisecond)
+		if i%5 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if peak.Load() > 3 {
+		t.Errorf("%d calls ran at once, want at most 3", peak.Load())
+	}
+	for i, err := range errs {
+		if (err != nil) != (i%5 == 0) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, err := range (*Runner)(nil).Run(ctx, 3, func(ctx context.Context, i int) error { return nil }) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("errs[%d] = %v after cancel", i, err)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	r := New(4, 100)
+	start := time.Now()
+	r.Run(context.Background(), 6, func(ctx context.Context, i int) error { return nil })
+	r.Run(context.Background(), 5, func(ctx context.Context, i int) error { return nil })
+	// 11 calls 10ms apart, the first without waiting
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("11 calls at 100/s took %v, want at least 100ms", elapsed)
+	}
+}
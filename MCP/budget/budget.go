@@ -0,0 +1,131 @@
+// Package budget caps the ClickSend credit the server spends per day.
+//
+// Transport refuses sends once the day's spend reaches the limit and adds
+// the price ClickSend reports for each successful send. Days are UTC and the
+// spend is kept in memory, so it starts again at zero on restart.
+package budget
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// ErrExceeded is returned for sends refused because the daily limit is spent.
+var ErrExceeded = errors.New("daily send budget exceeded")
+
+// Budget tracks the credit spent today against a limit.
+type Budget struct {
+	mu    sync.Mutex
+	limit float64 // 0 is unlimited
+	day   string
+	spent float64
+	now   func() time.Time
+}
+
+// New returns a budget allowing limit credit per day; 0 is unlimited.
+func New(limit float64) *Budget {
+	return &Budget{limit: limit, now: time.Now}
+}
+
+// FromEnv returns the budget set by BUDGET_DAILY_LIMIT.
+func FromEnv() (*Budget, error) {
+	var limit float64
+	if v := configfile.Getenv("BUDGET_DAILY_LIMIT"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("BUDGET_DAILY_LIMIT must be a non-negative number, got %q", v)
+		}
+		limit = f
+	}
+	return New(limit), nil
+}
+
+// SetLimit changes the limit, keeping what has been spent today.
+func (b *Budget) SetLimit(limit float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limit = limit
+}
+
+// Limit returns the daily limit; 0 is unlimited.
+func (b *Budget) Limit() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.limit
+}
+
+// Spent returns the credit spent today.
+func (b *Budget) Spent() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+	return b.spent
+}
+
+// rollover starts a new day's spend. b.mu must be held.
+func (b *Budget) rollover() {
+	if day := b.now().UTC().Format(time.DateOnly); day != b.day {
+		b.day, b.spent = day, 0
+	}
+}
+
+func (b *Budget) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+	if b.limit > 0 && b.spent >= b.limit {
+		return fmt.Errorf("%w: spent %g of %g today (UTC)", ErrExceeded, b.spent, b.limit)
+	}
+	return nil
+}
+
+func (b *Budget) add(cost float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+	b.spent += cost
+}
+
+// Transport checks sends made through next (http.DefaultTransport when nil)
+// against the budget. The check happens before each send, so sends already
+// in flight when the limit is reached still complete.
+func (b *Budget) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{b: b, next: next}
+}
+
+type transport struct {
+	b    *Budget
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !clicksend.IsSend(req) {
+		return t.next.RoundTrip(req)
+	}
+	if err := t.b.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode >= 400 {
+		return resp, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr == nil {
+		t.b.add(clicksend.SendCost(body))
+	}
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+)
+
+func TestBudget(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	b := New(0.01)
+	b.now = func() time.Time { return now }
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: b.Transport(nil)}))
+	sms := &clicksend.SmsMessageCollection{Messages: []clicksend.SmsMessage{{To: "+61411111111", Body: "Hello"}}}
+	ctx := context.Background()
+
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	if b.Spent() <= 0 {
+		t.Fatalf("Spent() = %v after a send, want > 0", b.Spent())
+	}
+	if _, err := client.SmsPrice(ctx, sms); err != nil {
+		t.Errorf("price check was refused: %v", err)
+	}
+	if _, err := client.SendSms(ctx, sms); !errors.Is(err, ErrExceeded) {
+		t.Errorf("second send error = %v, want ErrExceeded", err)
+	}
+
+	b.SetLimit(0)
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Errorf("send with no limit: %v", err)
+	}
+
+	b.SetLimit(0.01)
+	now = now.Add(2 * time.Hour)
+	if b.Spent() != 0 {
+		t.Errorf("Spent() = %v on a new day, want 0", b.Spent())
+	}
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Errorf("send on a new day: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+// Package cassette records ClickSend HTTP traffic to a file and replays it.
+//
+// A Recorder is an http.RoundTripper that forwards requests and appends each
+// request and response, with credentials and phone numbers redacted, to a
+// cassette file as one JSON line. A Replayer serves the responses in a
+// cassette back without calling the API, so an agent session can be
+// reproduced offline:
+//
+//	rec, err := cassette.NewRecorder("session.jsonl", nil)
+//	...
+//	defer rec.Close()
+//	client := clicksend.NewClient(baseURL, clicksend.WithHTTPClient(&http.Client{Transport: rec}))
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Modes accepted by Transport.
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a redacted outbound request. URL holds the path and query
+// only, so a cassette can be replayed against any base URL host.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a redacted API response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Load reads the interactions of a cassette file, one JSON object a line.
+func Load(path string) ([]Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var interactions []Interaction
+	dec := json.NewDecoder(f)
+	for {
+		var in Interaction
+		err := dec.Decode(&in)
+		if errors.Is(err, io.EOF) {
+			return interactions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: interaction %d: %w", path, len(interactions)+1, err)
+		}
+		interactions = append(interactions, in)
+	}
+}
+
+// Transport returns the round tripper for mode: a Recorder writing to path
+// for "record", a Replayer reading path for "replay" and nil, meaning the
+// default transport, for an empty mode.
+func Transport(mode, path string) (http.RoundTripper, error) {
+	switch mode {
+	case "":
+		return nil, nil
+	case ModeRecord:
+		return NewRecorder(path, nil)
+	case ModeReplay:
+		return NewReplayer(path)
+	}
+	return nil, fmt.Errorf("unknown cassette mode %q, want %q or %q", mode, ModeRecord, ModeReplay)
+}
+
+// Recorder forwards requests to an underlying transport and records them.
+type Recorder struct {
+	next http.RoundTripper
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder returns a Recorder that writes to path, replacing any existing
+// cassette, and sends requests with next (http.DefaultTransport when nil).
+// Close closes the file.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette: %w", err)
+	}
+	return &Recorder{next: next, file: f}, nil
+}
+
+// RoundTrip sends a copy of req and records it with the response. Each
+// interaction is appended to the cassette as it completes, so the file
+// survives the process being killed. The caller receives the unredacted
+// response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	if reqBody != nil {
+		out.Body = io.NopCloser(bytes.NewReader(reqBody))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(reqBody)), nil
+		}
+	}
+	resp, err := r.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := json.Marshal(Interaction{
+		Request: recordRequest(req, reqBody),
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redactBody(respBody),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to save cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Close closes the cassette file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Replayer serves recorded responses instead of calling the API.
+//
+// A request is matched to the first unused interaction with the same method,
+// path and query after redaction, preferring one whose body is also the
+// same. Repeated identical requests therefore get the recorded responses in
+// their original order. A request with no unused match fails.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette at path.
+func NewReplayer(path string) (*Replayer, error) {
+	interactions, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{interactions: interactions, used: make([]bool, len(interactions))}, nil
+}
+
+// RoundTrip returns the recorded response matching req.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	want := recordRequest(req, body)
+
+	r.mu.Lock()
+	match := -1
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != want.Method || in.Request.URL != want.URL {
+			continue
+		}
+		if in.Request.Body == want.Body {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match >= 0 {
+		r.used[match] = true
+	}
+	r.mu.Unlock()
+
+	if match < 0 {
+		return nil, fmt.Errorf("cassette has no recorded response for %s %s", want.Method, want.URL)
+	}
+	rec := r.interactions[match].Response
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Remaining returns the number of recorded interactions not replayed yet.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+func recordRequest(req *http.Request, body []byte) Request {
+	u := req.URL.EscapedPath()
+	if req.URL.RawQuery != "" {
+		// Re-encode so the query is in a canonical order.
+		q, err := url.ParseQuery(req.URL.RawQuery)
+		if err == nil {
+			u += "?" + q.Encode()
+		} else {
+			u += "?" + req.URL.RawQuery
+		}
+	}
+	return Request{
+		Method: req.Method,
+		URL:    redactText(u),
+		Header: redactHeader(req.Header),
+		Body:   redactBody(body),
+	}
+}
+
+// requestBody reads and closes the body of req, leaving req itself alone.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// readBody reads *body and replaces it with a reader over the same bytes.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,182 @@
+package cassette_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/cassette"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+)
+
+// recorder returns a Recorder writing to path, closed when t ends.
+func recorder(t *testing.T, path string) *cassette.Recorder {
+	t.Helper()
+	rec, err := cassette.NewRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rec.Close() })
+	return rec
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	sms := &clicksend.SmsMessageCollection{Messages: []clicksend.SmsMessage{{To: "+61411111111", Body: "Hello"}}}
+
+	api := clicksendtest.NewServer()
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: recorder(t, path)}))
+	sent, err := client.SendSms(ctx, sms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Data.Messages[0].To != "+61411111111" {
+		t.Errorf("recorder changed the live response: to = %s", sent.Data.Messages[0].To)
+	}
+	first, err := client.SmsHistory(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.SmsHistory(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{clicksendtest.APIKey, "61411111111"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+	if !strings.Contains(string(data), cassette.Redacted) {
+		t.Error("cassette does not contain a redacted Authorization header")
+	}
+
+	replayer, err := cassette.NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = clicksend.NewClient("https://replay.invalid",
+		clicksend.WithCredentials("someone", "else"),
+		clicksend.WithHTTPClient(&http.Client{Transport: replayer}))
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.SmsHistory(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Data.Total != first.Data.Total {
+		t.Errorf("first replayed history total = %d, want %d", got.Data.Total, first.Data.Total)
+	}
+	if _, err := client.SendSms(ctx, sms); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = client.SmsHistory(ctx, nil); got.Data.Total != second.Data.Total {
+		t.Errorf("second replayed history total = %d, want %d", got.Data.Total, second.Data.Total)
+	}
+	if n := replayer.Remaining(); n != 0 {
+		t.Errorf("%d interactions not replayed", n)
+	}
+	if _, err := client.SmsHistory(ctx, nil); err == nil {
+		t.Error("replaying past the end of the cassette succeeded")
+	}
+}
+
+func TestRecorderAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte("an old cassette\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	rec := recorder(t, path)
+
+	body := []byte(`{"list_name":"Mine"}`)
+	for i := 1; i <= 3; i++ {
+		req, err := http.NewRequest("POST", api.URL+"/lists", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetBasicAuth(clicksendtest.Username, clicksendtest.APIKey)
+		reqBody, header := req.Body, req.Header.Clone()
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if req.Body != reqBody || !reflect.DeepEqual(req.Header, header) {
+			t.Error("RoundTrip changed the caller's request")
+		}
+
+		// Each interaction is on disk as soon as it completes.
+		interactions, err := cassette.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(interactions) != i || interactions[i-1].Request.Body != string(body) {
+			t.Fatalf("after %d calls the cassette holds %+v", i, interactions)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if n := bytes.Count(data, []byte("\n")); n != 3 {
+		t.Errorf("cassette has %d lines, want one per interaction", n)
+	}
+	if reqs := api.Requests(); len(reqs) != 3 || string(reqs[2].Body) != string(body) {
+		t.Errorf("API got %+v, want the full body each time", reqs)
+	}
+}
+
+func TestReplayAPIError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: recorder(t, path)}))
+	_, recorded := client.GetContactList(context.Background(), "999")
+
+	replayer, err := cassette.NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = clicksend.NewClient(api.URL, clicksend.WithHTTPClient(&http.Client{Transport: replayer}))
+	_, replayed := client.GetContactList(context.Background(), "999")
+	var want, got *clicksend.APIError
+	if !errors.As(recorded, &want) || !errors.As(replayed, &got) {
+		t.Fatalf("errors = %v, %v; want API errors", recorded, replayed)
+	}
+	if got.StatusCode != want.StatusCode || got.ResponseCode != want.ResponseCode {
+		t.Errorf("replayed error = %d %s, want %d %s", got.StatusCode, got.ResponseCode, want.StatusCode, want.ResponseCode)
+	}
+}
+
+func TestRedactPhone(t *testing.T) {
+	a, b := cassette.RedactPhone("+61411111111"), cassette.RedactPhone("+61411111111")
+	if a != b {
+		t.Errorf("redaction is not deterministic: %s != %s", a, b)
+	}
+	if len(a) != len("+61411111111") || !strings.HasPrefix(a, "+61") || a == "+61411111111" {
+		t.Errorf("RedactPhone = %s", a)
+	}
+	if got := cassette.RedactPhone("%2B61411111111"); !strings.HasPrefix(got, "%2B61") || len(got) != 14 {
+		t.Errorf("RedactPhone(encoded) = %s", got)
+	}
+}
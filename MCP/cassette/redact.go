@@ -0,0 +1,133 @@
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces credentials in recorded requests and responses.
+const Redacted = "[REDACTED]"
+
+// sensitiveHeaders are replaced by Redacted.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveKeys are JSON fields holding credentials or card details.
+var sensitiveKeys = map[string]bool{
+	"password":         true,
+	"api_key":          true,
+	"activation_token": true,
+	"number":           true,
+	"cvc":              true,
+	"token":            true,
+}
+
+// phoneKeys are JSON fields holding phone numbers, which ClickSend sometimes
+// returns unquoted.
+var phoneKeys = map[string]bool{
+	"to":               true,
+	"from":             true,
+	"phone_number":     true,
+	"user_phone":       true,
+	"dedicated_number": true,
+	"fax_number":       true,
+	"mobile":           true,
+}
+
+// phonePattern matches international phone numbers in text, URL paths and
+// query strings, with the plus sign literal or percent-encoded.
+var phonePattern = regexp.MustCompile(`(\+|%2[bB])\d{8,15}\b`)
+
+// RedactPhone replaces the digits of a phone number after the first two with
+// digits derived from a hash of the number. The result keeps the length and
+// format, and the same number always redacts to the same value, so redacted
+// requests can still be matched during replay.
+func RedactPhone(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	var b strings.Builder
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' || (i > 0 && s[i-1] == '%') {
+			b.WriteByte(c)
+			continue
+		}
+		if digits < 2 {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('0' + sum[digits%len(sum)]%10)
+		}
+		digits++
+	}
+	return b.String()
+}
+
+// redactText replaces phone numbers in s.
+func redactText(s string) string {
+	return phonePattern.ReplaceAllStringFunc(s, RedactPhone)
+}
+
+// redactHeader returns a copy of h with credentials replaced.
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if out.Get(k) != "" {
+			out.Set(k, Redacted)
+		}
+	}
+	return out
+}
+
+// redactBody redacts a request or response body. JSON bodies are rewritten
+// with credentials and phone numbers replaced; other bodies only have phone
+// numbers replaced.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return redactText(string(body))
+	}
+	out, err := json.Marshal(redactValue("", v))
+	if err != nil {
+		return redactText(string(body))
+	}
+	return string(out)
+}
+
+func redactValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = redactValue(k, e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = redactValue(key, e)
+		}
+		return v
+	case string:
+		switch {
+		case sensitiveKeys[key] && v != "":
+			return Redacted
+		case phoneKeys[key]:
+			return RedactPhone(v)
+		}
+		return redactText(v)
+	case json.Number:
+		switch {
+		case sensitiveKeys[key]:
+			return Redacted
+		case phoneKeys[key]:
+			return json.Number(RedactPhone(v.String()))
+		}
+	}
+	return v
+}
@@ -0,0 +1,182 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Account is a ClickSend user account. Password is only sent in requests;
+// balances and settings are only set in responses.
+type Account struct {
+	Username                   string `json:"username,omitempty"`
+	Password                   string `json:"password,omitempty"`
+	UserEmail                  string `json:"user_email,omitempty"`
+	UserPhone                  string `json:"user_phone,omitempty"`
+	UserFirstName              string `json:"user_first_name,omitempty"`
+	UserLastName               string `json:"user_last_name,omitempty"`
+	AccountName                string `json:"account_name,omitempty"`
+	Country                    string `json:"country,omitempty"`
+	Timezone                   string `json:"timezone,omitempty"`
+	PrivateUploads             *Bool  `json:"private_uploads,omitempty"`
+	SettingSmsHideBusinessName *Bool  `json:"setting_sms_hide_business_name,omitempty"`
+	SettingSmsHideYourNumber   *Bool  `json:"setting_sms_hide_your_number,omitempty"`
+
+	UserID               Int         `json:"user_id,omitempty"`
+	Active               Bool        `json:"active,omitempty"`
+	Banned               Bool        `json:"banned,omitempty"`
+	Balance              Float       `json:"balance,omitempty"`
+	BalanceCommission    Float       `json:"balance_commission,omitempty"`
+	AutoRecharge         Bool        `json:"auto_recharge,omitempty"`
+	AutoRechargeAmount   Float       `json:"auto_recharge_amount,omitempty"`
+	LowCreditAmount      Float       `json:"low_credit_amount,omitempty"`
+	DefaultCountrySms    string      `json:"default_country_sms,omitempty"`
+	AccountBillingEmail  string      `json:"account_billing_email,omitempty"`
+	AccountBillingMobile string      `json:"account_billing_mobile,omitempty"`
+	Currency             *Currency   `json:"_currency,omitempty"`
+	Subaccount           *Subaccount `json:"_subaccount,omitempty"`
+}
+
+// AccountVerification requests an account activation token by SMS or email.
+type AccountVerification struct {
+	Country         string `json:"country,omitempty"`
+	UserPhone       string `json:"user_phone,omitempty"`
+	Type            string `json:"type,omitempty"`
+	ActivationToken string `json:"activation_token,omitempty"`
+}
+
+// ForgotUsernameRequest identifies the account whose username is emailed.
+type ForgotUsernameRequest struct {
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+// ForgotPasswordRequest starts a password reset.
+type ForgotPasswordRequest struct {
+	Username string `json:"username"`
+}
+
+// VerifyForgotPasswordRequest completes a password reset.
+type VerifyForgotPasswordRequest struct {
+	SubaccountID    Int    `json:"subaccount_id"`
+	ActivationToken string `json:"activation_token"`
+	Password        string `json:"password"`
+}
+
+// UsageEntry is the usage of one subaccount in an account usage report.
+type UsageEntry struct {
+	SubaccountID Int    `json:"subaccount_id"`
+	Username     string `json:"username"`
+	TotalCount   Float  `json:"total_count"`
+	TotalPrice   Float  `json:"total_price"`
+}
+
+// CreditCard is the card used to recharge the account. Number and CVC are
+// only sent in requests.
+type CreditCard struct {
+	Number        string `json:"number,omitempty"`
+	ExpiryMonth   Int    `json:"expiry_month,omitempty"`
+	ExpiryYear    Int    `json:"expiry_year,omitempty"`
+	Cvc           string `json:"cvc,omitempty"`
+	Name          string `json:"name,omitempty"`
+	BankName      string `json:"bank_name,omitempty"`
+	DisplayNumber string `json:"display_number,omitempty"`
+	Token         string `json:"token,omitempty"`
+}
+
+// RechargePackages lists the credit packages available in a country.
+type RechargePackages struct {
+	Currency Currency `json:"currency"`
+	Packages []Object `json:"packages"`
+}
+
+// Transaction is a credit purchase.
+type Transaction struct {
+	InvoiceNumber string `json:"invoice_number,omitempty"`
+	Amount        Float  `json:"amount"`
+	AmountAud     Float  `json:"amount_aud,omitempty"`
+	Currency      string `json:"currency"`
+	Date          Int    `json:"date"`
+	UserID        Int    `json:"user_id,omitempty"`
+}
+
+// GetAccount returns the authenticated account.
+func (c *Client) GetAccount(ctx context.Context) (*Response[Account], error) {
+	return Do[Account](ctx, c, http.MethodGet, "/account", nil, nil)
+}
+
+// CreateAccount creates a new account.
+func (c *Client) CreateAccount(ctx context.Context, req *Account) (*Response[Account], error) {
+	return Do[Account](ctx, c, http.MethodPost, "/account", nil, req)
+}
+
+// UpdateAccount updates the authenticated account.
+func (c *Client) UpdateAccount(ctx context.Context, req *Account) (*Response[Account], error) {
+	return Do[Account](ctx, c, http.MethodPut, "/account", nil, req)
+}
+
+// SendAccountVerification sends an account activation token.
+func (c *Client) SendAccountVerification(ctx context.Context, req *AccountVerification) (*Response[AccountVerification], error) {
+	return Do[AccountVerification](ctx, c, http.MethodPut, "/account-verify/send", nil, req)
+}
+
+// VerifyAccount activates a new account with the token sent to it.
+func (c *Client) VerifyAccount(ctx context.Context, activationToken string) (*Response[Account], error) {
+	return Do[Account](ctx, c, http.MethodPut, pathf("/account-verify/verify/%s", activationToken), nil, nil)
+}
+
+// AccountUsage reports usage per subaccount for a month. usageType is
+// "subaccount" or "user".
+func (c *Client) AccountUsage(ctx context.Context, year, month, usageType string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodGet, pathf("/account/usage/%s/%s/%s", year, month, usageType), nil, nil)
+}
+
+// ForgotUsername emails the username of an account.
+func (c *Client) ForgotUsername(ctx context.Context, req *ForgotUsernameRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/forgot-username", nil, req)
+}
+
+// ForgotPassword sends a password reset token.
+func (c *Client) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/forgot-password", nil, req)
+}
+
+// VerifyForgotPassword sets a new password with a reset token.
+func (c *Client) VerifyForgotPassword(ctx context.Context, req *VerifyForgotPasswordRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/forgot-password/verify", nil, req)
+}
+
+// GetCreditCard returns the card used for recharges.
+func (c *Client) GetCreditCard(ctx context.Context) (*Response[CreditCard], error) {
+	return Do[CreditCard](ctx, c, http.MethodGet, "/recharge/credit-card", nil, nil)
+}
+
+// UpdateCreditCard replaces the card used for recharges.
+func (c *Client) UpdateCreditCard(ctx context.Context, req *CreditCard) (*Response[CreditCard], error) {
+	return Do[CreditCard](ctx, c, http.MethodPut, "/recharge/credit-card", nil, req)
+}
+
+// RechargePackages lists the credit packages available in a country.
+func (c *Client) RechargePackages(ctx context.Context, country string) (*Response[RechargePackages], error) {
+	q := url.Values{}
+	if country != "" {
+		q.Set("country", country)
+	}
+	return Do[RechargePackages](ctx, c, http.MethodGet, "/recharge/packages", q, nil)
+}
+
+// PurchasePackage buys a credit package with the saved card.
+func (c *Client) PurchasePackage(ctx context.Context, packageID string) (*Response[Transaction], error) {
+	return Do[Transaction](ctx, c, http.MethodPut, pathf("/recharge/purchase/%s", packageID), nil, nil)
+}
+
+// ListTransactions lists credit purchases.
+func (c *Client) ListTransactions(ctx context.Context, opts *ListOptions) (*Response[Page[Transaction]], error) {
+	return Do[Page[Transaction]](ctx, c, http.MethodGet, "/recharge/transactions", opts.apply(nil), nil)
+}
+
+// GetTransaction returns a credit purchase.
+func (c *Client) GetTransaction(ctx context.Context, transactionID string) (*Response[Transaction], error) {
+	return Do[Transaction](ctx, c, http.MethodGet, pathf("/recharge/transactions/%s", transactionID), nil, nil)
+}
@@ -0,0 +1,182 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// ReceiptRule forwards delivery receipts to an action. MatchType selects which
+// receipts match: 0 all, 1 only failed, 2 only successful.
+type ReceiptRule struct {
+	ReceiptRuleID Int    `json:"receipt_rule_id,omitempty"`
+	RuleName      string `json:"rule_name,omitempty"`
+	MatchType     *Int   `json:"match_type,omitempty"`
+	Action        string `json:"action,omitempty"`
+	ActionAddress string `json:"action_address,omitempty"`
+	Enabled       *Bool  `json:"enabled,omitempty"`
+}
+
+// InboundRule handles inbound SMS or faxes on a dedicated number. The message
+// search fields only apply to SMS; MessageSearchType is 0 any message, 1
+// starts with, 2 contains or 3 does not contain.
+type InboundRule struct {
+	InboundRuleID     Int    `json:"inbound_rule_id,omitempty"`
+	RuleName          string `json:"rule_name,omitempty"`
+	DedicatedNumber   string `json:"dedicated_number,omitempty"`
+	MessageSearchType *Int   `json:"message_search_type,omitempty"`
+	MessageSearchTerm string `json:"message_search_term,omitempty"`
+	Action            string `json:"action,omitempty"`
+	ActionAddress     string `json:"action_address,omitempty"`
+	Body              string `json:"body,omitempty"`
+	Enabled           *Bool  `json:"enabled,omitempty"`
+}
+
+// ListEmailReceiptRules lists email delivery receipt rules.
+func (c *Client) ListEmailReceiptRules(ctx context.Context, opts *ListOptions) (*Response[Page[ReceiptRule]], error) {
+	return Do[Page[ReceiptRule]](ctx, c, http.MethodGet, "/automations/email/receipt", opts.apply(nil), nil)
+}
+
+// GetEmailReceiptRule returns an email delivery receipt rule.
+func (c *Client) GetEmailReceiptRule(ctx context.Context, ruleID string) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodGet, pathf("/automations/email/receipt/%s", ruleID), nil, nil)
+}
+
+// CreateEmailReceiptRule creates an email delivery receipt rule.
+func (c *Client) CreateEmailReceiptRule(ctx context.Context, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPost, "/automations/email/receipt", nil, req)
+}
+
+// UpdateEmailReceiptRule updates an email delivery receipt rule.
+func (c *Client) UpdateEmailReceiptRule(ctx context.Context, ruleID string, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPut, pathf("/automations/email/receipt/%s", ruleID), nil, req)
+}
+
+// DeleteEmailReceiptRule deletes an email delivery receipt rule.
+func (c *Client) DeleteEmailReceiptRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/email/receipt/%s", ruleID), nil, nil)
+}
+
+// ListSmsReceiptRules lists SMS delivery receipt rules.
+func (c *Client) ListSmsReceiptRules(ctx context.Context, opts *ListOptions) (*Response[Page[ReceiptRule]], error) {
+	return Do[Page[ReceiptRule]](ctx, c, http.MethodGet, "/automations/sms/receipts", opts.apply(nil), nil)
+}
+
+// GetSmsReceiptRule returns an SMS delivery receipt rule.
+func (c *Client) GetSmsReceiptRule(ctx context.Context, ruleID string) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodGet, pathf("/automations/sms/receipts/%s", ruleID), nil, nil)
+}
+
+// CreateSmsReceiptRule creates an SMS delivery receipt rule.
+func (c *Client) CreateSmsReceiptRule(ctx context.Context, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPost, "/automations/sms/receipts", nil, req)
+}
+
+// UpdateSmsReceiptRule updates an SMS delivery receipt rule.
+func (c *Client) UpdateSmsReceiptRule(ctx context.Context, ruleID string, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPut, pathf("/automations/sms/receipts/%s", ruleID), nil, req)
+}
+
+// DeleteSmsReceiptRule deletes an SMS delivery receipt rule.
+func (c *Client) DeleteSmsReceiptRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/sms/receipts/%s", ruleID), nil, nil)
+}
+
+// ListVoiceReceiptRules lists voice delivery receipt rules.
+func (c *Client) ListVoiceReceiptRules(ctx context.Context, opts *ListOptions) (*Response[Page[ReceiptRule]], error) {
+	return Do[Page[ReceiptRule]](ctx, c, http.MethodGet, "/automations/voice/receipts", opts.apply(nil), nil)
+}
+
+// GetVoiceReceiptRule returns a voice delivery receipt rule.
+func (c *Client) GetVoiceReceiptRule(ctx context.Context, ruleID string) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodGet, pathf("/automations/voice/receipts/%s", ruleID), nil, nil)
+}
+
+// CreateVoiceReceiptRule creates a voice delivery receipt rule.
+func (c *Client) CreateVoiceReceiptRule(ctx context.Context, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPost, "/automations/voice/receipts", nil, req)
+}
+
+// UpdateVoiceReceiptRule updates a voice delivery receipt rule.
+func (c *Client) UpdateVoiceReceiptRule(ctx context.Context, ruleID string, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPut, pathf("/automations/voice/receipts/%s", ruleID), nil, req)
+}
+
+// DeleteVoiceReceiptRule deletes a voice delivery receipt rule.
+func (c *Client) DeleteVoiceReceiptRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/voice/receipts/%s", ruleID), nil, nil)
+}
+
+// ListFaxReceiptRules lists fax delivery receipt rules.
+func (c *Client) ListFaxReceiptRules(ctx context.Context, opts *ListOptions) (*Response[Page[ReceiptRule]], error) {
+	return Do[Page[ReceiptRule]](ctx, c, http.MethodGet, "/automations/fax/receipts", opts.apply(nil), nil)
+}
+
+// GetFaxReceiptRule returns a fax delivery receipt rule.
+func (c *Client) GetFaxReceiptRule(ctx context.Context, ruleID string) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodGet, pathf("/automations/fax/receipts/%s", ruleID), nil, nil)
+}
+
+// CreateFaxReceiptRule creates a fax delivery receipt rule.
+func (c *Client) CreateFaxReceiptRule(ctx context.Context, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPost, "/automations/fax/receipts", nil, req)
+}
+
+// UpdateFaxReceiptRule updates a fax delivery receipt rule.
+func (c *Client) UpdateFaxReceiptRule(ctx context.Context, ruleID string, req *ReceiptRule) (*Response[ReceiptRule], error) {
+	return Do[ReceiptRule](ctx, c, http.MethodPut, pathf("/automations/fax/receipts/%s", ruleID), nil, req)
+}
+
+// DeleteFaxReceiptRule deletes a fax delivery receipt rule.
+func (c *Client) DeleteFaxReceiptRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/fax/receipts/%s", ruleID), nil, nil)
+}
+
+// ListSmsInboundRules lists inbound SMS rules.
+func (c *Client) ListSmsInboundRules(ctx context.Context, opts *ListOptions) (*Response[Page[InboundRule]], error) {
+	return Do[Page[InboundRule]](ctx, c, http.MethodGet, "/automations/sms/inbound", opts.apply(nil), nil)
+}
+
+// GetSmsInboundRule returns an inbound SMS rule.
+func (c *Client) GetSmsInboundRule(ctx context.Context, ruleID string) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodGet, pathf("/automations/sms/inbound/%s", ruleID), nil, nil)
+}
+
+// CreateSmsInboundRule creates an inbound SMS rule.
+func (c *Client) CreateSmsInboundRule(ctx context.Context, req *InboundRule) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodPost, "/automations/sms/inbound", nil, req)
+}
+
+// UpdateSmsInboundRule updates an inbound SMS rule.
+func (c *Client) UpdateSmsInboundRule(ctx context.Context, ruleID string, req *InboundRule) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodPut, pathf("/automations/sms/inbound/%s", ruleID), nil, req)
+}
+
+// DeleteSmsInboundRule deletes an inbound SMS rule.
+func (c *Client) DeleteSmsInboundRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/sms/inbound/%s", ruleID), nil, nil)
+}
+
+// ListFaxInboundRules lists inbound fax rules.
+func (c *Client) ListFaxInboundRules(ctx context.Context, opts *ListOptions) (*Response[Page[InboundRule]], error) {
+	return Do[Page[InboundRule]](ctx, c, http.MethodGet, "/automations/fax/inbound", opts.apply(nil), nil)
+}
+
+// GetFaxInboundRule returns an inbound fax rule.
+func (c *Client) GetFaxInboundRule(ctx context.Context, ruleID string) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodGet, pathf("/automations/fax/inbound/%s", ruleID), nil, nil)
+}
+
+// CreateFaxInboundRule creates an inbound fax rule.
+func (c *Client) CreateFaxInboundRule(ctx context.Context, req *InboundRule) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodPost, "/automations/fax/inbound", nil, req)
+}
+
+// UpdateFaxInboundRule updates an inbound fax rule.
+func (c *Client) UpdateFaxInboundRule(ctx context.Context, ruleID string, req *InboundRule) (*Response[InboundRule], error) {
+	return Do[InboundRule](ctx, c, http.MethodPut, pathf("/automations/fax/inbound/%s", ruleID), nil, req)
+}
+
+// DeleteFaxInboundRule deletes an inbound fax rule.
+func (c *Client) DeleteFaxInboundRule(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/automations/fax/inbound/%s", ruleID), nil, nil)
+}
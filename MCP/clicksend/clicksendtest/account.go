@@ -0,0 +1,328 @@
+package clicksendtest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// accountFields are the account settings a client may update.
+var accountFields = []string{
+	"username", "user_email", "user_phone", "user_first_name", "user_last_name",
+	"account_name", "country", "timezone", "default_country_sms", "private_uploads",
+	"setting_sms_hide_business_name", "setting_sms_hide_your_number",
+	"setting_email_sms_subject", "setting_fix_sender_id", "setting_beta",
+	"setting_unicode_sms", "reply_to", "delivery_to", "low_credit_amount",
+	"auto_recharge", "auto_recharge_amount",
+}
+
+func defaultAccount() record {
+	return record{
+		"user_id":                        1,
+		"username":                       Username,
+		"user_email":                     "mock@example.com",
+		"user_phone":                     "+61411111111",
+		"user_first_name":                "Mock",
+		"user_last_name":                 "User",
+		"account_name":                   "Mock Account",
+		"active":                         1,
+		"banned":                         0,
+		"country":                        "AU",
+		"default_country_sms":            "AU",
+		"timezone":                       "Australia/Melbourne",
+		"private_uploads":                0,
+		"setting_sms_hide_business_name": 0,
+		"setting_sms_hide_your_number":   0,
+		"setting_email_sms_subject":      0,
+		"setting_fix_sender_id":          0,
+		"setting_unicode_sms":            0,
+		"reply_to":                       "originalemail",
+		"delivery_to":                    nil,
+		"low_credit_amount":              "0.00",
+		"auto_recharge":                  0,
+		"auto_recharge_amount":           "0.00",
+		"balance_commission":             "0.000000",
+		"account_billing_email":          "mock@example.com",
+		"_subaccount":                    nil,
+	}
+}
+
+func formatBalance(balance float64) string {
+	return strconv.FormatFloat(math.Round(balance*1e6)/1e6, 'f', 6, 64)
+}
+
+func (a *API) accountView() record {
+	v := a.state.account.clone()
+	v["balance"] = formatBalance(a.state.balance)
+	v["_currency"] = currency()
+	return v
+}
+
+func (a *API) accountRoutes() {
+	a.mux.HandleFunc("GET /account", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here's your account", a.accountView())
+	})
+	a.mux.HandleFunc("POST /account", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range []string{"username", "password", "user_email", "user_phone", "country"} {
+			if body.str(f) == "" {
+				badRequest(w, f+" is required.")
+				return
+			}
+		}
+		acct := defaultAccount()
+		acct.merge(body, "user_id")
+		delete(acct, "password")
+		acct["user_id"] = 2
+		acct["active"] = 0
+		acct["balance"] = formatBalance(0)
+		acct["_currency"] = currency()
+		writeData(w, "New account has been created.", acct)
+	})
+	a.mux.HandleFunc("PUT /account", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range accountFields {
+			if v, ok := body[f]; ok {
+				a.state.account[f] = v
+			}
+		}
+		writeData(w, "Account has been updated.", a.accountView())
+	})
+	a.mux.HandleFunc("PUT /account-verify/send", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		writeData(w, "Account activation token has been sent.", body)
+	})
+	a.mux.HandleFunc("PUT /account-verify/verify/{activation_token}", func(w http.ResponseWriter, r *http.Request) {
+		a.state.account["active"] = 1
+		writeData(w, "Account has been verified.", a.accountView())
+	})
+	a.mux.HandleFunc("GET /account/usage/{year}/{month}/{type}", a.accountUsage)
+
+	a.mux.HandleFunc("PUT /forgot-username", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("email") == "" && body.str("phone_number") == "" {
+			badRequest(w, "email or phone_number is required.")
+			return
+		}
+		writeData(w, "Your username has been sent.", []any{})
+	})
+	a.mux.HandleFunc("PUT /forgot-password", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("username") == "" {
+			badRequest(w, "username is required.")
+			return
+		}
+		writeData(w, "Password reset instructions have been sent.", []any{})
+	})
+	a.mux.HandleFunc("PUT /forgot-password/verify", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("activation_token") == "" || body.str("password") == "" {
+			badRequest(w, "activation_token and password are required.")
+			return
+		}
+		writeData(w, "Your password has been changed.", []any{})
+	})
+
+	a.rechargeRoutes()
+	a.subaccountRoutes()
+	a.resellerRoutes()
+}
+
+// accountUsage reports sends per channel for the requested month. Every
+// message sent by the fake belongs to subaccount 1.
+func (a *API) accountUsage(w http.ResponseWriter, r *http.Request) {
+	year, errYear := strconv.Atoi(r.PathValue("year"))
+	month, errMonth := strconv.Atoi(r.PathValue("month"))
+	if errYear != nil || errMonth != nil || month < 1 || month > 12 {
+		badRequest(w, "Invalid year or month.")
+		return
+	}
+	usageType := r.PathValue("type")
+	data := record{}
+	for name, ch := range a.state.channels {
+		var count int
+		var price float64
+		for _, m := range ch.history.items {
+			if m["status"] == "Cancelled" {
+				continue
+			}
+			t := timeOf(m.int("date"))
+			if t.Year() != year || int(t.Month()) != month {
+				continue
+			}
+			count++
+			var p float64
+			fmt.Sscan(m.str("message_price"), &p)
+			price += p
+		}
+		total := record{"count": count, "price": fmt.Sprintf("%.4f", price)}
+		data[name+"_total"] = total
+		if usageType == "subaccount" {
+			data[name] = []record{{"subaccount_id": 1, "username": Username, "total_count": count, "total_price": fmt.Sprintf("%.4f", price)}}
+		}
+	}
+	writeData(w, "Here is your usage.", data)
+}
+
+func (a *API) rechargeRoutes() {
+	a.mux.HandleFunc("GET /recharge/credit-card", func(w http.ResponseWriter, r *http.Request) {
+		card := a.state.resource("credit-card")
+		if len(card.items) == 0 {
+			notFound(w, "Credit card")
+			return
+		}
+		writeData(w, "Here's your credit card.", card.items[0])
+	})
+	a.mux.HandleFunc("PUT /recharge/credit-card", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		number := strings.ReplaceAll(body.str("number"), " ", "")
+		if len(number) < 4 {
+			badRequest(w, "number is required.")
+			return
+		}
+		stored := record{
+			"number":       strings.Repeat("X", len(number)-4) + number[len(number)-4:],
+			"expiry_month": body["expiry_month"],
+			"expiry_year":  body["expiry_year"],
+			"name":         body["name"],
+			"bank_name":    body["bank_name"],
+		}
+		a.state.resource("credit-card").items = []record{stored}
+		writeData(w, "Credit card has been updated.", stored)
+	})
+	a.mux.HandleFunc("GET /recharge/packages", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are the packages.", record{"currency": currency(), "packages": rechargePackages()})
+	})
+	a.mux.HandleFunc("PUT /recharge/purchase/{package_id}", func(w http.ResponseWriter, r *http.Request) {
+		var pkg record
+		for _, p := range rechargePackages() {
+			if p.str("package_id") == r.PathValue("package_id") {
+				pkg = p
+			}
+		}
+		if pkg == nil {
+			notFound(w, "Package")
+			return
+		}
+		if len(a.state.resource("credit-card").items) == 0 {
+			badRequest(w, "Add a credit card before purchasing a package.")
+			return
+		}
+		amount, _ := strconv.ParseFloat(pkg.str("package_price"), 64)
+		a.state.balance += amount
+		tx := a.state.resource("transactions")
+		t := tx.add(record{
+			"invoice_number": fmt.Sprintf("MOCK-INV-%06d", len(tx.items)+1),
+			"date":           a.now().Unix(),
+			"amount":         pkg["package_price"],
+			"currency":       "AUD",
+		})
+		writeData(w, "Package has been purchased.", t)
+	})
+	a.mux.HandleFunc("GET /recharge/transactions", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, "Here are your transactions.", a.state.resource("transactions").filter(nil))
+	})
+	a.mux.HandleFunc("GET /recharge/transactions/{transaction_id}", func(w http.ResponseWriter, r *http.Request) {
+		t := a.state.resource("transactions").get(r.PathValue("transaction_id"))
+		if t == nil {
+			notFound(w, "Transaction")
+			return
+		}
+		writeData(w, "Here is your transaction.", t)
+	})
+}
+
+func rechargePackages() []record {
+	return []record{
+		{"package_id": 1, "package_price": "20.00", "sms_price": "0.0770", "sms_quantity": 259},
+		{"package_id": 2, "package_price": "100.00", "sms_price": "0.0700", "sms_quantity": 1428},
+		{"package_id": 3, "package_price": "500.00", "sms_price": "0.0600", "sms_quantity": 8333},
+	}
+}
+
+func (a *API) subaccountRoutes() {
+	a.crud("/subaccounts", "subaccounts", "Subaccount", "LCRUD", "api_username", "password", "email", "phone_number", "first_name", "last_name")
+	a.mux.HandleFunc("PUT /subaccounts/{id}/regen-api-key", func(w http.ResponseWriter, r *http.Request) {
+		s := a.state.resource("subaccounts").get(r.PathValue("id"))
+		if s == nil {
+			notFound(w, "Subaccount")
+			return
+		}
+		s["api_key"] = a.state.messageID()
+		writeData(w, "API key has been regenerated.", s)
+	})
+}
+
+func (a *API) resellerRoutes() {
+	a.mux.HandleFunc("GET /reseller", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are your reseller settings.", []record{a.state.reseller})
+	})
+	a.mux.HandleFunc("PUT /reseller", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		a.state.reseller.merge(body, "")
+		writeData(w, "Reseller settings have been updated.", a.state.reseller)
+	})
+	a.mux.HandleFunc("GET /reseller/{subdomain}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("subdomain") != a.state.reseller.str("subdomain") {
+			notFound(w, "Reseller")
+			return
+		}
+		writeData(w, "Here is the reseller.", a.state.reseller)
+	})
+
+	a.crud("/reseller/accounts", "reseller-accounts", "Reseller account", "LCRU", "username", "password", "user_email", "user_phone", "country")
+	a.mux.HandleFunc("POST /reseller/accounts-public", a.create("reseller-accounts", "New account has been created.", "username", "password", "user_email", "user_phone", "country"))
+	a.mux.HandleFunc("PUT /reseller/transfer-credit", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		client := a.state.resource("reseller-accounts").get(body.str("client_user_id"))
+		if client == nil {
+			notFound(w, "Reseller account")
+			return
+		}
+		amount, err := strconv.ParseFloat(body.str("balance"), 64)
+		if err != nil || amount <= 0 {
+			badRequest(w, "balance must be a positive number.")
+			return
+		}
+		if amount > a.state.balance {
+			writeError(w, http.StatusPaymentRequired, "INSUFFICIENT_CREDIT", "You don't have enough credit to transfer.")
+			return
+		}
+		a.state.balance -= amount
+		current, _ := strconv.ParseFloat(client.str("balance"), 64)
+		client["balance"] = formatBalance(current + amount)
+		writeData(w, "Credit has been transferred.", client)
+	})
+	a.mux.HandleFunc("GET /referral/accounts", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, "Here are your referral accounts.", a.state.resource("referral-accounts").filter(nil))
+	})
+}
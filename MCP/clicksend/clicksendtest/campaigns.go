@@ -0,0 +1,334 @@
+package clicksendtest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// emailPrice is charged per transactional or campaign email recipient.
+const emailPrice = 0.0100
+
+func roundPrice(p float64) float64 {
+	return math.Round(p*10000) / 10000
+}
+
+// charge deducts price from the balance, writing an INSUFFICIENT_CREDIT error
+// when the balance is too low.
+func (a *API) charge(w http.ResponseWriter, price float64) bool {
+	if a.state.balance < price {
+		writeError(w, http.StatusPaymentRequired, "INSUFFICIENT_CREDIT", "You don't have enough credit.")
+		return false
+	}
+	a.state.balance -= price
+	return true
+}
+
+func (a *API) campaignRoutes() {
+	a.mux.HandleFunc("POST /email/send", a.sendEmail(true))
+	a.mux.HandleFunc("POST /email/price", a.sendEmail(false))
+	a.mux.HandleFunc("GET /email/history", func(w http.ResponseWriter, r *http.Request) {
+		from, to := int64(queryInt(r, "date_from", 0)), int64(queryInt(r, "date_to", 0))
+		items := a.state.resource("email-history").filter(func(m record) bool {
+			date := m.int("date_added")
+			return (from == 0 || date >= from) && (to == 0 || date <= to)
+		})
+		writePage(w, r, "Here is your history.", items)
+	})
+	a.mux.HandleFunc("GET /email/history/export", exportHandler("email-history"))
+	a.mux.HandleFunc("POST /email/receipts", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("url") == "" {
+			badRequest(w, "url is required.")
+			return
+		}
+		writeData(w, "Receipt has been added", record{"url": body["url"], "message_id": a.state.messageID()})
+	})
+
+	a.mux.HandleFunc("POST /email-campaigns/send", a.sendEmailCampaign(true))
+	a.mux.HandleFunc("POST /email-campaigns/price", a.sendEmailCampaign(false))
+	a.crud("/email-campaigns", "email-campaigns", "Email campaign", "LRU")
+	a.mux.HandleFunc("PUT /email-campaigns/{id}/cancel", a.withResource("email-campaigns", "Email campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		if !a.cancelCampaign(w, c) {
+			return
+		}
+		writeData(w, "Email campaign has been cancelled.", []any{})
+	}))
+	a.mux.HandleFunc("GET /email-campaigns/{id}/history", a.withResource("email-campaigns", "Email campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		id := c.str("email_campaign_id")
+		items := a.state.resource("email-campaign-messages").filter(func(m record) bool { return m.str("email_campaign_id") == id })
+		writePage(w, r, "Here is your campaign history.", items)
+	}))
+
+	a.mux.HandleFunc("POST /sms-campaigns/send", a.sendSmsCampaign(true))
+	a.mux.HandleFunc("POST /sms-campaigns/price", a.sendSmsCampaign(false))
+	a.crud("/sms-campaigns", "sms-campaigns", "SMS campaign", "LRU")
+	a.mux.HandleFunc("PUT /sms-campaigns/{id}/cancel", a.withResource("sms-campaigns", "SMS campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		if !a.cancelCampaign(w, c) {
+			return
+		}
+		id := c.str("sms_campaign_id")
+		for _, m := range a.state.channels["sms"].history.items {
+			if m.str("_sms_campaign_id") == id && m["status"] == "Scheduled" {
+				m["status"] = "Cancelled"
+			}
+		}
+		writeData(w, "SMS campaign has been cancelled.", c)
+	}))
+	a.mux.HandleFunc("GET /sms-campaigns/{id}/link-statistics", a.withResource("sms-campaigns", "SMS campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		writeData(w, "Here are your link statistics.", record{"total_clicks": 0, "unique_clicks": 0, "total_sent": c["_total_count"]})
+	}))
+	a.mux.HandleFunc("GET /sms-campaigns/{id}/link-tracking", a.withResource("sms-campaigns", "SMS campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		writePage(w, r, "Here is your link tracking.", []record{})
+	}))
+	a.mux.HandleFunc("GET /sms-campaigns/{id}/link-export", a.withResource("sms-campaigns", "SMS campaign", func(w http.ResponseWriter, r *http.Request, c record) {
+		exportHandler("link-tracking-"+c.str("sms_campaign_id"))(w, r)
+	}))
+}
+
+// recipients decodes a list of {email, name} objects. Bare strings are
+// accepted as email addresses.
+func recipients(v any) []record {
+	items, _ := v.([]any)
+	out := []record{}
+	for _, item := range items {
+		switch it := item.(type) {
+		case string:
+			out = append(out, record{"email": it, "name": nil})
+		case map[string]any:
+			if record(it).str("email") != "" {
+				out = append(out, record(it))
+			}
+		}
+	}
+	return out
+}
+
+func (a *API) sendEmail(send bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		to, cc, bcc := recipients(body["to"]), recipients(body["cc"]), recipients(body["bcc"])
+		if len(to) == 0 {
+			badRequest(w, "to is required.")
+			return
+		}
+		from, _ := body["from"].(map[string]any)
+		fromID := record(from).str("email_address_id")
+		if fromID == "" || a.state.resource("email-addresses").get(fromID) == nil {
+			badRequest(w, "from.email_address_id must be one of your allowed email addresses.")
+			return
+		}
+		if body.str("body") == "" {
+			badRequest(w, "body is required.")
+			return
+		}
+		price := roundPrice(emailPrice * float64(len(to)+len(cc)+len(bcc)))
+		if !send {
+			writeData(w, "Here's the email price.", record{
+				"price":     price,
+				"subject":   body["subject"],
+				"body":      body["body"],
+				"to_array":  to,
+				"cc_array":  cc,
+				"bcc_array": bcc,
+				"_currency": currency(),
+			})
+			return
+		}
+		if !a.charge(w, price) {
+			return
+		}
+		status := "Sent"
+		if body.int("schedule") > a.now().Unix() {
+			status = "Scheduled"
+		}
+		sent := a.state.resource("email-history").add(record{
+			"message_id":            a.state.messageID(),
+			"to":                    to,
+			"cc":                    cc,
+			"bcc":                   bcc,
+			"subject":               body["subject"],
+			"body":                  body["body"],
+			"body_plain_text":       stripTags(body.str("body")),
+			"from_email_address_id": record(from).int("email_address_id"),
+			"from_name":             from["name"],
+			"schedule":              body.int("schedule"),
+			"date_added":            a.now().Unix(),
+			"price":                 fmt.Sprintf("%.4f", price),
+			"status":                status,
+			"custom_string":         body["custom_string"],
+			"soft_bounce_count":     0,
+			"hard_bounce_count":     0,
+			"_currency":             currency(),
+		})
+		writeData(w, "Here is your result.", sent)
+	}
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+func stripTags(s string) string {
+	return strings.TrimSpace(tagPattern.ReplaceAllString(s, ""))
+}
+
+// listContacts returns the contacts of list_id that have field set.
+func (a *API) listContacts(w http.ResponseWriter, listID, field string) ([]record, bool) {
+	if a.state.lists.get(listID) == nil {
+		notFound(w, "List")
+		return nil, false
+	}
+	return a.state.contacts.filter(func(c record) bool {
+		return c.str("list_id") == listID && c.str(field) != ""
+	}), true
+}
+
+func (a *API) campaignStatus(schedule int64) string {
+	if schedule > a.now().Unix() {
+		return "Scheduled"
+	}
+	return "Queued"
+}
+
+// cancelCampaign cancels a scheduled campaign and refunds its price.
+func (a *API) cancelCampaign(w http.ResponseWriter, c record) bool {
+	if c["status"] != "Scheduled" {
+		badRequest(w, "Only scheduled campaigns can be cancelled.")
+		return false
+	}
+	c["status"] = "Cancelled"
+	var price float64
+	fmt.Sscan(c.str("_total_price"), &price)
+	a.state.balance += price
+	return true
+}
+
+func (a *API) sendEmailCampaign(send bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range []string{"list_id", "subject", "name", "from_email_address_id"} {
+			if body.str(f) == "" {
+				badRequest(w, f+" is required.")
+				return
+			}
+		}
+		if body.str("body") == "" {
+			tmpl := a.state.resource("email-templates").get(body.str("template_id"))
+			if tmpl == nil {
+				badRequest(w, "body or a valid template_id is required.")
+				return
+			}
+			body["body"] = tmpl["body"]
+		}
+		from := a.state.resource("email-addresses").get(body.str("from_email_address_id"))
+		if from == nil {
+			badRequest(w, "from_email_address_id must be one of your allowed email addresses.")
+			return
+		}
+		contacts, ok := a.listContacts(w, body.str("list_id"), "email")
+		if !ok {
+			return
+		}
+		price := roundPrice(emailPrice * float64(len(contacts)))
+		campaign := body.clone()
+		campaign["status"] = a.campaignStatus(body.int("schedule"))
+		campaign["date_added"] = fmt.Sprint(a.now().Unix())
+		campaign["user_id"] = 1
+		campaign["subaccount_id"] = 1
+		campaign["send_count"] = 0
+		campaign["_total_price"] = fmt.Sprintf("%.4f", price)
+		result := record{"total_price": price, "total_count": len(contacts), "queued_count": 0, "currency": currency()}
+		if send {
+			if !a.charge(w, price) {
+				return
+			}
+			campaign = a.state.resource("email-campaigns").add(campaign)
+			if campaign["status"] == "Queued" {
+				campaign["status"] = "Sent"
+				campaign["send_count"] = len(contacts)
+			}
+			messages := a.state.resource("email-campaign-messages")
+			for _, c := range contacts {
+				messages.add(record{
+					"message_id":        a.state.messageID(),
+					"email_campaign_id": campaign["email_campaign_id"],
+					"contact_id":        c["contact_id"],
+					"to_address":        c["email"],
+					"to_name":           strings.TrimSpace(c.str("first_name") + " " + c.str("last_name")),
+					"from_address":      from["email_address"],
+					"from_name":         body["from_name"],
+					"subject":           body["subject"],
+					"status":            campaign["status"],
+					"open_count":        0,
+					"click_count":       0,
+					"soft_bounce_count": 0,
+					"hard_bounce_count": 0,
+				})
+			}
+			result["queued_count"] = len(contacts)
+		}
+		result["data"] = campaign
+		msg := "Here is your price."
+		if send {
+			msg = "Your new campaign has been added."
+		}
+		writeData(w, msg, result)
+	}
+}
+
+func (a *API) sendSmsCampaign(send bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range []string{"list_id", "name", "body"} {
+			if body.str(f) == "" {
+				badRequest(w, f+" is required.")
+				return
+			}
+		}
+		contacts, ok := a.listContacts(w, body.str("list_id"), "phone_number")
+		if !ok {
+			return
+		}
+		sms := a.state.channels["sms"]
+		text := body.str("body")
+		if u := body.str("url_to_shorten"); u != "" {
+			text = strings.ReplaceAll(text, "smsg.us/xxxxx", "smsg.us/mock1")
+		}
+		price := roundPrice(sms.unitPrice * float64(smsParts(text)*len(contacts)))
+		campaign := body.clone()
+		campaign["status"] = a.campaignStatus(body.int("schedule"))
+		campaign["date_added"] = fmt.Sprint(a.now().Unix())
+		campaign["user_id"] = 1
+		campaign["subaccount_id"] = 1
+		campaign["_total_count"] = len(contacts)
+		campaign["_total_price"] = fmt.Sprintf("%.4f", price)
+		if !send {
+			writeData(w, "Here is your price.", record{"total_price": price, "total_count": len(contacts), "data": campaign, "currency": currency()})
+			return
+		}
+		if !a.charge(w, price) {
+			return
+		}
+		campaign = a.state.resource("sms-campaigns").add(campaign)
+		for _, c := range contacts {
+			msg := record{"to": c["phone_number"], "body": text, "from": body["from"], "schedule": body["schedule"], "list_id": body["list_id"]}
+			a.prepareMessage(sms, msg)
+			msg["_sms_campaign_id"] = campaign["sms_campaign_id"]
+			a.queueMessage(sms, msg)
+		}
+		writeData(w, "Your new campaign has been added.", campaign)
+	}
+}
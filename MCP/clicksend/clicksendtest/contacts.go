@@ -0,0 +1,338 @@
+package clicksendtest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// contactFields are the fields ClickSend stores for a contact.
+var contactFields = []string{
+	"phone_number", "email", "fax_number", "first_name", "last_name",
+	"organization_name", "custom_1", "custom_2", "custom_3", "custom_4",
+	"address_line_1", "address_line_2", "address_city", "address_state",
+	"address_postal_code", "address_country",
+}
+
+func (a *API) contactRoutes() {
+	a.mux.HandleFunc("GET /lists", func(w http.ResponseWriter, r *http.Request) {
+		items := []record{}
+		for _, l := range a.state.lists.items {
+			items = append(items, a.listView(l))
+		}
+		writePage(w, r, "Here are your contact lists.", items)
+	})
+	a.mux.HandleFunc("POST /lists", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("list_name") == "" {
+			badRequest(w, "list_name is required.")
+			return
+		}
+		l := a.state.lists.add(record{"list_name": body.str("list_name")})
+		l["list_email_id"] = fmt.Sprintf("MOCKLIST%08d", l.int("list_id"))
+		writeData(w, "New list has been created.", a.listView(l))
+	})
+	a.mux.HandleFunc("GET /lists/{list_id}", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		writeData(w, "Here is your contact list.", a.listView(l))
+	}))
+	a.mux.HandleFunc("PUT /lists/{list_id}", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if name := body.str("list_name"); name != "" {
+			l["list_name"] = name
+		}
+		writeData(w, "Your list has been updated.", a.listView(l))
+	}))
+	a.mux.HandleFunc("DELETE /lists/{list_id}", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		id := l.str("list_id")
+		a.state.lists.remove(id)
+		a.state.contacts.items = a.state.contacts.filter(func(c record) bool { return c.str("list_id") != id })
+		writeData(w, fmt.Sprintf("List #%s has been deleted.", id), []any{})
+	}))
+	a.mux.HandleFunc("GET /lists/{list_id}/export", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		exportHandler("list-"+l.str("list_id"))(w, r)
+	}))
+	a.mux.HandleFunc("GET /lists/{list_id}/import-fields", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		fields := []record{}
+		for _, f := range contactFields {
+			fields = append(fields, record{"field": f, "label": strings.ReplaceAll(f, "_", " ")})
+		}
+		writeData(w, "Here are the import fields.", fields)
+	}))
+	a.mux.HandleFunc("POST /lists/{list_id}/import", a.withList(a.importContacts))
+	a.mux.HandleFunc("POST /lists/{list_id}/import-csv-preview", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		rows, ok := a.csvUpload(w, body.str("file_url"))
+		if !ok {
+			return
+		}
+		var first []string
+		if len(rows) > 0 {
+			first = rows[0]
+		}
+		writeData(w, "Here is your csv preview.", record{"row": first})
+	}))
+	a.mux.HandleFunc("PUT /lists/{list_id}/remove-duplicates", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		fields := []string{"phone_number"}
+		if raw, ok := body["fields"].([]any); ok && len(raw) > 0 {
+			fields = fields[:0]
+			for _, f := range raw {
+				fields = append(fields, fmt.Sprint(f))
+			}
+		}
+		listID := l.str("list_id")
+		seen := map[string]bool{}
+		deleted := 0
+		a.state.contacts.items = a.state.contacts.filter(func(c record) bool {
+			if c.str("list_id") != listID {
+				return true
+			}
+			var key []string
+			for _, f := range fields {
+				key = append(key, c.str(f))
+			}
+			k := strings.Join(key, "\x00")
+			if seen[k] {
+				deleted++
+				return false
+			}
+			seen[k] = true
+			return true
+		})
+		writeData(w, "Duplicate contacts have been removed.", record{"deleted": deleted})
+	}))
+	a.mux.HandleFunc("PUT /lists/{list_id}/remove-opted-out-contacts/{opt_out_list_id}", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		optOutID := r.PathValue("opt_out_list_id")
+		if a.state.lists.get(optOutID) == nil {
+			notFound(w, "Opt out list")
+			return
+		}
+		optedOut := map[string]bool{}
+		for _, c := range a.state.contacts.filter(func(c record) bool { return c.str("list_id") == optOutID }) {
+			optedOut[c.str("phone_number")] = true
+		}
+		listID := l.str("list_id")
+		deleted := 0
+		a.state.contacts.items = a.state.contacts.filter(func(c record) bool {
+			if c.str("list_id") == listID && optedOut[c.str("phone_number")] {
+				deleted++
+				return false
+			}
+			return true
+		})
+		writeData(w, "Opted out contacts have been removed.", record{"deleted": deleted})
+	}))
+
+	a.mux.HandleFunc("GET /lists/{list_id}/contacts", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		listID := l.str("list_id")
+		items := a.state.contacts.filter(func(c record) bool { return c.str("list_id") == listID })
+		for i, c := range items {
+			items[i] = a.contactView(c)
+		}
+		writePage(w, r, "Here are your contacts.", items)
+	}))
+	a.mux.HandleFunc("POST /lists/{list_id}/contacts", a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		c, msg := a.addContact(l, body)
+		if c == nil {
+			badRequest(w, msg)
+			return
+		}
+		writeData(w, "New contact has been created.", a.contactView(c))
+	}))
+	a.mux.HandleFunc("GET /lists/{list_id}/contacts/{contact_id}", a.withContact(func(w http.ResponseWriter, r *http.Request, c record) {
+		writeData(w, "Here is your contact.", a.contactView(c))
+	}))
+	a.mux.HandleFunc("PUT /lists/{list_id}/contacts/{contact_id}", a.withContact(func(w http.ResponseWriter, r *http.Request, c record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range contactFields {
+			if v, ok := body[f]; ok {
+				c[f] = v
+			}
+		}
+		writeData(w, "Contact has been updated.", a.contactView(c))
+	}))
+	a.mux.HandleFunc("DELETE /lists/{list_id}/contacts/{contact_id}", a.withContact(func(w http.ResponseWriter, r *http.Request, c record) {
+		a.state.contacts.remove(c.str("contact_id"))
+		writeData(w, fmt.Sprintf("Contact #%s has been deleted.", c.str("contact_id")), []any{})
+	}))
+	a.mux.HandleFunc("PUT /lists/{list_id}/contacts/{contact_id}/{to_list_id}", a.withContact(func(w http.ResponseWriter, r *http.Request, c record) {
+		to := a.state.lists.get(r.PathValue("to_list_id"))
+		if to == nil {
+			notFound(w, "List")
+			return
+		}
+		c["list_id"] = to["list_id"]
+		writeData(w, "Contact has been transferred.", a.contactView(c))
+	}))
+
+	a.mux.HandleFunc("GET /contact-suggestions", func(w http.ResponseWriter, r *http.Request) {
+		items := []record{}
+		for i := len(a.state.contacts.items) - 1; i >= 0 && len(items) < 10; i-- {
+			items = append(items, a.contactView(a.state.contacts.items[i]))
+		}
+		writeData(w, "Here are your contact suggestions.", items)
+	})
+	a.mux.HandleFunc("GET /search/contacts-lists", func(w http.ResponseWriter, r *http.Request) {
+		q := strings.ToLower(r.URL.Query().Get("q"))
+		items := []record{}
+		for _, l := range a.state.lists.items {
+			if strings.Contains(strings.ToLower(l.str("list_name")), q) {
+				v := a.listView(l)
+				items = append(items, record{"id": l["list_id"], "type": "list", "name": l["list_name"], "contacts_count": v["_contacts_count"]})
+			}
+		}
+		for _, c := range a.state.contacts.items {
+			name := strings.TrimSpace(c.str("first_name") + " " + c.str("last_name"))
+			if strings.Contains(strings.ToLower(name), q) || strings.Contains(c.str("phone_number"), q) || strings.Contains(strings.ToLower(c.str("email")), q) {
+				items = append(items, record{"id": c["contact_id"], "type": "contact", "name": name, "contact": a.contactView(c)})
+			}
+		}
+		writePage(w, r, "Here are your search results.", items)
+	})
+}
+
+// withList resolves the list_id path value before calling h.
+func (a *API) withList(h func(http.ResponseWriter, *http.Request, record)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := a.state.lists.get(r.PathValue("list_id"))
+		if l == nil {
+			notFound(w, "List")
+			return
+		}
+		h(w, r, l)
+	}
+}
+
+// withContact resolves the list_id and contact_id path values before calling h.
+func (a *API) withContact(h func(http.ResponseWriter, *http.Request, record)) http.HandlerFunc {
+	return a.withList(func(w http.ResponseWriter, r *http.Request, l record) {
+		c := a.state.contacts.get(r.PathValue("contact_id"))
+		if c == nil || c.str("list_id") != l.str("list_id") {
+			notFound(w, "Contact")
+			return
+		}
+		h(w, r, c)
+	})
+}
+
+func (a *API) listView(l record) record {
+	v := l.clone()
+	id := l.str("list_id")
+	v["_contacts_count"] = len(a.state.contacts.filter(func(c record) bool { return c.str("list_id") == id }))
+	return v
+}
+
+func (a *API) contactView(c record) record {
+	v := c.clone()
+	if l := a.state.lists.get(c.str("list_id")); l != nil {
+		v["_list_name"] = l["list_name"]
+	}
+	return v
+}
+
+// addContact validates and stores a contact in list l. It returns nil and a
+// reason when the contact has no phone number, email or fax number.
+func (a *API) addContact(l record, fields record) (record, string) {
+	c := record{}
+	for _, f := range contactFields {
+		if v, ok := fields[f]; ok && v != nil {
+			c[f] = v
+		} else {
+			c[f] = nil
+		}
+	}
+	if c.str("phone_number") == "" && c.str("email") == "" && c.str("fax_number") == "" {
+		return nil, "phone_number, email or fax_number is required."
+	}
+	c["list_id"] = l["list_id"]
+	c["date_added"] = fmt.Sprint(a.now().Unix())
+	return a.state.contacts.add(c), ""
+}
+
+func (a *API) importContacts(w http.ResponseWriter, r *http.Request, l record) {
+	body, ok := decodeBody(w, r)
+	if !ok {
+		return
+	}
+	rows, ok := a.csvUpload(w, body.str("file_url"))
+	if !ok {
+		return
+	}
+	var order []string
+	if raw, ok := body["field_order"].([]any); ok {
+		for _, f := range raw {
+			order = append(order, fmt.Sprint(f))
+		}
+	}
+	if len(order) == 0 {
+		badRequest(w, "field_order is required.")
+		return
+	}
+	ids := []any{}
+	for _, row := range rows {
+		fields := record{}
+		for i, f := range order {
+			if i < len(row) && slices.Contains(contactFields, f) {
+				fields[f] = row[i]
+			}
+		}
+		if c, _ := a.addContact(l, fields); c != nil {
+			ids = append(ids, c["contact_id"])
+		}
+	}
+	writeData(w, fmt.Sprintf("%d contacts have been imported.", len(ids)), record{"ids": ids, "msg": "Your file is now queued."})
+}
+
+// csvUpload parses a CSV file previously stored with POST /uploads.
+func (a *API) csvUpload(w http.ResponseWriter, fileURL string) ([][]string, bool) {
+	if fileURL == "" {
+		badRequest(w, "file_url is required.")
+		return nil, false
+	}
+	content, ok := a.uploadContent(fileURL)
+	if !ok {
+		badRequest(w, "Unable to download file. Upload it with POST /uploads first.")
+		return nil, false
+	}
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		badRequest(w, "File is not a valid CSV file.")
+		return nil, false
+	}
+	return rows, true
+}
+
+// Contacts returns a copy of the contacts stored in a list.
+func (a *API) Contacts(listID string) []map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := []map[string]any{}
+	for _, c := range a.state.contacts.items {
+		if c.str("list_id") == listID {
+			out = append(out, c.clone())
+		}
+	}
+	return out
+}
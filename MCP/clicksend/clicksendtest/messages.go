@@ -0,0 +1,506 @@
+package clicksendtest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// channel holds the history and delivery receipts of one message type.
+type channel struct {
+	name string
+	// unitPrice is charged per message part.
+	unitPrice float64
+	// recipientField is the contact field used when sending to a list.
+	recipientField string
+	// currencyKey is the key the send and price responses use for currency.
+	currencyKey string
+	// shared lists top-level request fields copied onto every message.
+	shared   []string
+	history  *collection
+	receipts *collection
+	read     map[string]bool
+}
+
+func newChannels() map[string]*channel {
+	newChannel := func(name string, price float64, field, currencyKey string, shared ...string) *channel {
+		return &channel{
+			name:           name,
+			unitPrice:      price,
+			recipientField: field,
+			currencyKey:    currencyKey,
+			shared:         shared,
+			history:        newCollection("message_id", 0),
+			receipts:       newCollection("message_id", 0),
+			read:           map[string]bool{},
+		}
+	}
+	return map[string]*channel{
+		"sms":   newChannel("sms", 0.0770, "phone_number", "_currency"),
+		"mms":   newChannel("mms", 0.3500, "phone_number", "_currency", "media_file"),
+		"voice": newChannel("voice", 0.0900, "phone_number", "currency"),
+		"fax":   newChannel("fax", 0.1800, "fax_number", "_currency", "file_url"),
+	}
+}
+
+// smsParts returns how many message parts body is billed as.
+func smsParts(body string) int {
+	single, multi := 160, 153
+	for _, r := range body {
+		if r > 127 {
+			single, multi = 70, 67
+			break
+		}
+	}
+	n := utf8.RuneCountInString(body)
+	if n <= single {
+		return 1
+	}
+	return (n + multi - 1) / multi
+}
+
+func currency() map[string]any {
+	return map[string]any{
+		"currency_name_short": "AUD",
+		"currency_prefix_d":   "$",
+		"currency_prefix_c":   "c",
+		"currency_name_long":  "Australian Dollars",
+	}
+}
+
+// channelRoutes registers the message endpoints of one channel. Handlers look
+// the channel up per request so Reset takes effect.
+func (a *API) channelRoutes(name string) {
+	prefix := "/" + name
+	a.mux.HandleFunc("POST "+prefix+"/send", a.sendMessages(name, true))
+	a.mux.HandleFunc("POST "+prefix+"/price", a.sendMessages(name, false))
+	a.mux.HandleFunc("GET "+prefix+"/history", a.messageHistory(name))
+	a.mux.HandleFunc("GET "+prefix+"/history/export", exportHandler(name+"-history"))
+	a.mux.HandleFunc("PUT "+prefix+"/cancel-all", a.cancelAll(name))
+	a.mux.HandleFunc("PUT "+prefix+"/{message_id}/{action}", a.cancelMessage(name))
+	a.mux.HandleFunc("GET "+prefix+"/receipts", a.listReceipts(name))
+	a.mux.HandleFunc("POST "+prefix+"/receipts", a.addTestReceipt(name))
+	a.mux.HandleFunc("GET "+prefix+"/receipts/{message_id}", a.getReceipt(name))
+	a.mux.HandleFunc("PUT "+prefix+"/receipts-read", a.markReceiptsRead(name))
+}
+
+func (a *API) sendMessages(name string, charge bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		items, _ := body["messages"].([]any)
+		if len(items) == 0 {
+			badRequest(w, "messages is required.")
+			return
+		}
+
+		var (
+			out        = []record{}
+			totalPrice float64
+			queued     int
+		)
+		for _, item := range items {
+			in, _ := item.(map[string]any)
+			for _, msg := range a.expandRecipients(ch, record(in).clone()) {
+				for _, field := range ch.shared {
+					if v, ok := body[field]; ok {
+						msg[field] = v
+					}
+				}
+				price := a.prepareMessage(ch, msg)
+				if msg["status"] == "SUCCESS" {
+					if charge && a.state.balance < price {
+						msg["status"] = "INSUFFICIENT_CREDIT"
+					} else {
+						totalPrice += price
+						queued++
+						if charge {
+							a.state.balance -= price
+							a.queueMessage(ch, msg.clone())
+						}
+					}
+				}
+				out = append(out, msg)
+			}
+		}
+
+		respMsg := "Messages queued for delivery."
+		if !charge {
+			respMsg = "Here are some results."
+		}
+		writeData(w, respMsg, map[string]any{
+			"total_price":  math.Round(totalPrice*10000) / 10000,
+			"total_count":  len(out),
+			"queued_count": queued,
+			"messages":     out,
+			ch.currencyKey: currency(),
+		})
+	}
+}
+
+// expandRecipients turns a message addressed to list_id into one message per
+// contact in the list.
+func (a *API) expandRecipients(ch *channel, msg record) []record {
+	if msg.str("to") != "" || msg.str("list_id") == "" {
+		return []record{msg}
+	}
+	listID := msg.str("list_id")
+	var out []record
+	for _, c := range a.state.contacts.filter(func(c record) bool { return c.str("list_id") == listID }) {
+		to := c.str(ch.recipientField)
+		if to == "" {
+			continue
+		}
+		m := msg.clone()
+		m["to"] = to
+		out = append(out, m)
+	}
+	if len(out) == 0 {
+		return []record{msg}
+	}
+	return out
+}
+
+// prepareMessage fills in the fields ClickSend adds to a sent message and
+// returns its price. Messages that cannot be sent get an error status.
+func (a *API) prepareMessage(ch *channel, msg record) float64 {
+	parts := 1
+	if ch.name == "sms" {
+		parts = smsParts(msg.str("body"))
+	}
+	price := ch.unitPrice * float64(parts)
+
+	msg["message_id"] = a.state.messageID()
+	msg["direction"] = "out"
+	msg["date"] = a.now().Unix()
+	msg["user_id"] = 1
+	msg["subaccount_id"] = 1
+	msg["message_parts"] = parts
+	msg["message_price"] = fmt.Sprintf("%.4f", price)
+	if msg.str("country") == "" {
+		msg["country"] = "AU"
+	}
+	if _, ok := msg["schedule"]; !ok {
+		msg["schedule"] = ""
+	}
+	msg["carrier"] = "Telstra"
+
+	switch {
+	case msg.str("to") == "":
+		msg["status"] = "INVALID_RECIPIENT"
+		return 0
+	case ch.name != "fax" && ch.name != "mms" && strings.TrimSpace(msg.str("body")) == "":
+		msg["status"] = "EMPTY_MESSAGE"
+		return 0
+	}
+	msg["status"] = "SUCCESS"
+	return price
+}
+
+// queueMessage stores a sent message in the history, delivering it now or
+// holding it until its schedule time.
+func (a *API) queueMessage(ch *channel, msg record) {
+	ch.history.add(msg)
+	if msg.int("schedule") > a.now().Unix() {
+		msg["status"] = "Scheduled"
+		msg["status_code"] = nil
+		msg["status_text"] = nil
+		return
+	}
+	a.deliver(ch, msg)
+}
+
+func (a *API) deliver(ch *channel, msg record) {
+	now := a.now().Unix()
+	msg["status"] = "Delivered"
+	msg["status_code"] = "201"
+	msg["status_text"] = "Success: Message received on handset."
+	msg["error_code"] = nil
+	msg["error_text"] = nil
+	ch.receipts.add(record{
+		"message_id":     msg["message_id"],
+		"message_type":   ch.name,
+		"status_code":    "201",
+		"status_text":    "Success: Message received on handset.",
+		"error_code":     nil,
+		"error_text":     nil,
+		"custom_string":  msg["custom_string"],
+		"timestamp":      fmt.Sprint(now),
+		"timestamp_send": fmt.Sprint(now),
+		"subaccount_id":  1,
+	})
+}
+
+// deliverDue delivers scheduled messages whose send time has passed.
+func (a *API) deliverDue() {
+	now := a.now().Unix()
+	for _, ch := range a.state.channels {
+		for _, msg := range ch.history.items {
+			if msg["status"] == "Scheduled" && msg.int("schedule") <= now {
+				a.deliver(ch, msg)
+			}
+		}
+	}
+}
+
+func (a *API) messageHistory(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		q := r.URL.Query()
+		from, to := queryInt(r, "date_from", 0), queryInt(r, "date_to", 0)
+		search := strings.ToLower(q.Get("q"))
+		items := ch.history.filter(func(m record) bool {
+			date := m.int("date")
+			if from > 0 && date < int64(from) || to > 0 && date > int64(to) {
+				return false
+			}
+			if search == "" {
+				return true
+			}
+			for _, field := range []string{"to", "from", "body", "custom_string", "message_id"} {
+				if strings.Contains(strings.ToLower(m.str(field)), search) {
+					return true
+				}
+			}
+			return false
+		})
+		if strings.HasSuffix(strings.ToLower(q.Get("order_by")), ":desc") {
+			slices.Reverse(items)
+		}
+		writePage(w, r, "Here is your history.", items)
+	}
+}
+
+func (a *API) cancelMessage(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		if r.PathValue("action") != "cancel" {
+			notFound(w, "Resource")
+			return
+		}
+		id := r.PathValue("message_id")
+		msg := ch.history.get(id)
+		if msg == nil {
+			notFound(w, "Message")
+			return
+		}
+		if msg["status"] != "Scheduled" {
+			badRequest(w, fmt.Sprintf("Message %s can not be cancelled.", id))
+			return
+		}
+		a.cancel(msg)
+		writeData(w, fmt.Sprintf("Message %s has been cancelled.", id), []any{})
+	}
+}
+
+func (a *API) cancelAll(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		count := 0
+		for _, msg := range ch.history.items {
+			if msg["status"] == "Scheduled" {
+				a.cancel(msg)
+				count++
+			}
+		}
+		writeData(w, fmt.Sprintf("%d messages has been cancelled.", count), map[string]any{"count": count})
+	}
+}
+
+// cancel cancels a scheduled message and refunds its price.
+func (a *API) cancel(msg record) {
+	msg["status"] = "Cancelled"
+	var price float64
+	fmt.Sscan(msg.str("message_price"), &price)
+	a.state.balance += price
+}
+
+func (a *API) listReceipts(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		items := ch.receipts.filter(func(rc record) bool { return !ch.read[rc.str("message_id")] })
+		writePage(w, r, "Here are your delivery receipts.", items)
+	}
+}
+
+func (a *API) getReceipt(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		rc := ch.receipts.get(r.PathValue("message_id"))
+		if rc == nil {
+			notFound(w, "Delivery receipt")
+			return
+		}
+		writeData(w, "Here is your receipt.", rc)
+	}
+}
+
+func (a *API) addTestReceipt(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("url") == "" {
+			badRequest(w, "url is required.")
+			return
+		}
+		now := fmt.Sprint(a.now().Unix())
+		rc := ch.receipts.add(record{
+			"message_id":     a.state.messageID(),
+			"message_type":   ch.name,
+			"status_code":    "201",
+			"status_text":    "Success: Message received on handset.",
+			"error_code":     nil,
+			"error_text":     nil,
+			"custom_string":  nil,
+			"timestamp":      now,
+			"timestamp_send": now,
+		})
+		writeData(w, "Receipt has been added", rc)
+	}
+}
+
+func (a *API) markReceiptsRead(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch := a.state.channels[name]
+		before, ok := dateBefore(w, r)
+		if !ok {
+			return
+		}
+		for _, rc := range ch.receipts.items {
+			if before == 0 || rc.int("timestamp") <= before {
+				ch.read[rc.str("message_id")] = true
+			}
+		}
+		writeData(w, "Receipts have been marked as read.", []any{})
+	}
+}
+
+// dateBefore reads date_before from the JSON body or the query string.
+func dateBefore(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	body, ok := decodeBody(w, r)
+	if !ok {
+		return 0, false
+	}
+	if v := r.URL.Query().Get("date_before"); v != "" {
+		body["date_before"] = v
+	}
+	return body.int("date_before"), true
+}
+
+func (a *API) inboundRoutes() {
+	a.mux.HandleFunc("GET /sms/inbound", func(w http.ResponseWriter, r *http.Request) {
+		items := a.state.inbound.filter(func(m record) bool { return !a.state.inboundRead[m.str("message_id")] })
+		writePage(w, r, "Here are your inbound messages.", items)
+	})
+	a.mux.HandleFunc("POST /sms/inbound", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("url") == "" {
+			badRequest(w, "url is required.")
+			return
+		}
+		msg := a.receiveSms("+61411111111", "+61411111112", "This is a test incoming SMS.")
+		writeData(w, "Here are your incoming messages.", msg)
+	})
+	a.mux.HandleFunc("GET /sms/inbound/{outbound_message_id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("outbound_message_id")
+		items := a.state.inbound.filter(func(m record) bool { return m.str("original_message_id") == id })
+		if len(items) == 0 {
+			notFound(w, "Inbound message")
+			return
+		}
+		writeData(w, "Here is your inbound message.", items[len(items)-1])
+	})
+	a.mux.HandleFunc("PUT /sms/inbound-read", func(w http.ResponseWriter, r *http.Request) {
+		before, ok := dateBefore(w, r)
+		if !ok {
+			return
+		}
+		for _, m := range a.state.inbound.items {
+			if before == 0 || m.int("timestamp") <= before {
+				a.state.inboundRead[m.str("message_id")] = true
+			}
+		}
+		writeData(w, "Inbound messages have been marked as read.", []any{})
+	})
+	a.mux.HandleFunc("PUT /sms/inbound-read/{message_id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("message_id")
+		if a.state.inbound.get(id) == nil {
+			notFound(w, "Inbound message")
+			return
+		}
+		a.state.inboundRead[id] = true
+		writeData(w, "Inbound message has been marked as read.", []any{})
+	})
+}
+
+// ReceiveSms simulates an inbound SMS from a recipient. When an earlier
+// outbound SMS was sent to from, the reply is linked to it.
+func (a *API) ReceiveSms(from, to, body string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.receiveSms(from, to, body)
+}
+
+func (a *API) receiveSms(from, to, body string) record {
+	var original record
+	for _, m := range a.state.channels["sms"].history.items {
+		if m.str("to") == from {
+			original = m
+		}
+	}
+	msg := record{
+		"message_id":          a.state.messageID(),
+		"timestamp":           fmt.Sprint(a.now().Unix()),
+		"from":                from,
+		"to":                  to,
+		"body":                body,
+		"original_body":       nil,
+		"original_message_id": nil,
+		"custom_string":       nil,
+		"_keyword":            strings.ToLower(strings.SplitN(body+" ", " ", 2)[0]),
+	}
+	if original != nil {
+		msg["original_body"] = original["body"]
+		msg["original_message_id"] = original["message_id"]
+		msg["custom_string"] = original["custom_string"]
+	}
+	return a.state.inbound.add(msg)
+}
+
+// Messages returns a copy of the stored history for a message type ("sms",
+// "mms", "voice" or "fax").
+func (a *API) Messages(channel string) []map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ch, ok := a.state.channels[channel]
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]any, 0, len(ch.history.items))
+	for _, m := range ch.history.items {
+		out = append(out, m.clone())
+	}
+	return out
+}
+
+func voiceLanguages() []record {
+	return []record{
+		{"code": "en-au", "country": "Australia", "gender": []string{"female", "male"}},
+		{"code": "en-us", "country": "United States", "gender": []string{"female", "male"}},
+		{"code": "en-gb", "country": "United Kingdom", "gender": []string{"female", "male"}},
+		{"code": "de-de", "country": "Germany", "gender": []string{"female", "male"}},
+		{"code": "fr-fr", "country": "France", "gender": []string{"female", "male"}},
+	}
+}
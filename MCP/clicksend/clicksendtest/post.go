@@ -0,0 +1,275 @@
+package clicksendtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Post prices per recipient.
+const (
+	letterPrice     = 1.2000
+	colourSurcharge = 0.3000
+	postcardPrice   = 0.9500
+	directMailPrice = 0.3000
+)
+
+var addressFields = []string{"address_name", "address_line_1", "address_city", "address_postal_code", "address_country"}
+
+func (a *API) postRoutes() {
+	a.mux.HandleFunc("POST /post/letters/send", a.sendPost("post-letters", true))
+	a.mux.HandleFunc("POST /post/letters/price", a.sendPost("post-letters", false))
+	a.mux.HandleFunc("GET /post/letters/history", a.postHistory("post-letters"))
+	a.mux.HandleFunc("GET /post/letters/history/export", exportHandler("letters-history"))
+	a.mux.HandleFunc("POST /post/letters/detect-address", a.detectAddress)
+	a.mux.HandleFunc("POST /post/postcards/send", a.sendPost("postcards", true))
+	a.mux.HandleFunc("POST /post/postcards/price", a.sendPost("postcards", false))
+	a.mux.HandleFunc("GET /post/postcards/history", a.postHistory("postcards"))
+	a.mux.HandleFunc("GET /post/postcards/export", exportHandler("postcards-history"))
+	a.crud("/post/return-addresses", "return-addresses", "Return address", "LCRUD", addressFields...)
+
+	a.mux.HandleFunc("GET /post/direct-mail/campaigns", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, "Here are your campaigns.", a.state.resource("direct-mail-campaigns").filter(nil))
+	})
+	a.mux.HandleFunc("POST /post/direct-mail/campaigns/send", a.sendDirectMail(true))
+	a.mux.HandleFunc("POST /post/direct-mail/campaigns/price", a.sendDirectMail(false))
+	a.mux.HandleFunc("GET /post/direct-mail/locations/search/{country}/", func(w http.ResponseWriter, r *http.Request) {
+		country := strings.ToUpper(r.PathValue("country"))
+		q := strings.ToLower(r.URL.Query().Get("q"))
+		items := []record{}
+		for _, l := range directMailLocations() {
+			if l.str("country_code") != country {
+				continue
+			}
+			if q == "" || strings.Contains(strings.ToLower(l.str("place_name")), q) || strings.HasPrefix(l.str("postal_code"), q) {
+				items = append(items, l)
+			}
+		}
+		writePage(w, r, "Here are some results.", items)
+	})
+}
+
+func (a *API) sendPost(resource string, send bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		var fileURL any
+		if resource == "postcards" {
+			urls, _ := body["file_urls"].([]any)
+			if len(urls) == 0 {
+				badRequest(w, "file_urls is required.")
+				return
+			}
+			fileURL = urls[0]
+		} else {
+			if body.str("file_url") == "" {
+				badRequest(w, "file_url is required.")
+				return
+			}
+			fileURL = body["file_url"]
+		}
+		items, _ := body["recipients"].([]any)
+		if len(items) == 0 {
+			badRequest(w, "recipients is required.")
+			return
+		}
+
+		unit := letterPrice
+		if resource == "postcards" {
+			unit = postcardPrice
+		} else if body.int("colour") == 1 || body["colour"] == true {
+			unit += colourSurcharge
+		}
+		out := []record{}
+		var total float64
+		var queued int
+		for _, item := range items {
+			in, _ := item.(map[string]any)
+			rec := record(in).clone()
+			rec["message_id"] = a.state.messageID()
+			rec["date_added"] = a.now().Unix()
+			rec["post_pages"] = 1
+			rec["post_price"] = fmt.Sprintf("%.4f", unit)
+			rec["_file_url"] = fileURL
+			for _, f := range []string{"colour", "duplex", "priority_post"} {
+				if v, ok := body[f]; ok {
+					rec[f] = v
+				}
+			}
+			if ra := a.state.resource("return-addresses").get(rec.str("return_address_id")); ra != nil {
+				rec["_return_address"] = ra
+			}
+			rec["status"] = "SUCCESS"
+			for _, f := range addressFields {
+				if rec.str(f) == "" {
+					rec["status"] = "MISSING_" + strings.ToUpper(strings.TrimPrefix(f, "address_"))
+					break
+				}
+			}
+			if rec["status"] == "SUCCESS" {
+				total += unit
+				queued++
+			}
+			out = append(out, rec)
+		}
+		total = roundPrice(total)
+		if send {
+			if !a.charge(w, total) {
+				return
+			}
+			history := a.state.resource(resource)
+			for _, rec := range out {
+				if rec["status"] == "SUCCESS" {
+					stored := rec.clone()
+					stored["status"] = a.campaignStatus(rec.int("schedule"))
+					history.add(stored)
+				}
+			}
+		}
+		writeData(w, "Here are your results.", record{
+			"total_price":  total,
+			"total_count":  len(out),
+			"queued_count": queued,
+			"recipients":   out,
+			"_currency":    currency(),
+		})
+	}
+}
+
+func (a *API) postHistory(resource string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to := int64(queryInt(r, "date_from", 0)), int64(queryInt(r, "date_to", 0))
+		items := a.state.resource(resource).filter(func(m record) bool {
+			date := m.int("date_added")
+			return (from == 0 || date >= from) && (to == 0 || date <= to)
+		})
+		writePage(w, r, "Here is your history.", items)
+	}
+}
+
+var postcodeLine = regexp.MustCompile(`^(.*?)[\s,]+([A-Z]{2,3})[\s,]+(\d{4,5})$`)
+
+// detectAddress parses a plain text address: the first line is the name, the
+// last line holds the city, state and postal code and the lines in between
+// are the street address. Base64 content is decoded and parsed the same way
+// when it is plain text.
+func (a *API) detectAddress(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r)
+	if !ok {
+		return
+	}
+	text := body.str("address")
+	if text == "" && body.str("content") != "" {
+		decoded, err := base64.StdEncoding.DecodeString(body.str("content"))
+		if err != nil || !utf8.Valid(decoded) {
+			writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "Unable to detect an address in the file.")
+			return
+		}
+		text = string(decoded)
+	}
+	var lines []string
+	for _, l := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) < 3 {
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "Unable to detect an address.")
+		return
+	}
+	m := postcodeLine.FindStringSubmatch(lines[len(lines)-1])
+	if m == nil {
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "Unable to detect a city, state and postal code.")
+		return
+	}
+	addr := record{
+		"address_name":        lines[0],
+		"address_line_1":      lines[1],
+		"address_line_2":      "",
+		"address_city":        m[1],
+		"address_state":       m[2],
+		"address_postal_code": m[3],
+		"address_country":     "AU",
+	}
+	if len(lines) > 3 {
+		addr["address_line_2"] = strings.Join(lines[2:len(lines)-1], ", ")
+	}
+	if len(m[3]) == 5 {
+		addr["address_country"] = "US"
+	}
+	writeData(w, "Address detected.", addr)
+}
+
+func directMailLocations() []record {
+	return []record{
+		{"location_id": 1, "place_name": "Melbourne", "postal_code": "3000", "country_code": "AU", "latitude": -37.814, "longitude": 144.963, "accuracy": 4, "_households": 12000},
+		{"location_id": 2, "place_name": "Sydney", "postal_code": "2000", "country_code": "AU", "latitude": -33.868, "longitude": 151.209, "accuracy": 4, "_households": 15000},
+		{"location_id": 3, "place_name": "Perth", "postal_code": "6000", "country_code": "AU", "latitude": -31.952, "longitude": 115.861, "accuracy": 4, "_households": 8000},
+		{"location_id": 4, "place_name": "Austin", "postal_code": "78701", "country_code": "US", "latitude": 30.271, "longitude": -97.742, "accuracy": 4, "_households": 9000},
+		{"location_id": 5, "place_name": "New York", "postal_code": "10001", "country_code": "US", "latitude": 40.750, "longitude": -73.997, "accuracy": 4, "_households": 20000},
+	}
+}
+
+func (a *API) sendDirectMail(send bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("name") == "" {
+			badRequest(w, "name is required.")
+			return
+		}
+		areas, _ := body["areas"].([]any)
+		if len(areas) == 0 {
+			badRequest(w, "areas is required.")
+			return
+		}
+		locations := map[string]record{}
+		for _, l := range directMailLocations() {
+			locations[l.str("location_id")] = l
+		}
+		details := []record{}
+		var quantity int64
+		for _, item := range areas {
+			in, _ := item.(map[string]any)
+			area := record(in).clone()
+			loc, ok := locations[area.str("location_id")]
+			if !ok {
+				badRequest(w, fmt.Sprintf("Unknown location_id %s.", area.str("location_id")))
+				return
+			}
+			if area.int("quantity") <= 0 {
+				area["quantity"] = loc["_households"]
+			}
+			area["price"] = roundPrice(directMailPrice * float64(area.int("quantity")))
+			area["status"] = a.campaignStatus(body.int("schedule"))
+			quantity += area.int("quantity")
+			details = append(details, area)
+		}
+		price := roundPrice(directMailPrice * float64(quantity))
+		campaign := body.clone()
+		campaign["status"] = a.campaignStatus(body.int("schedule"))
+		campaign["date_added"] = a.now().Unix()
+		campaign["_total_quantity"] = quantity
+		campaign["_areas"] = details
+		if send {
+			if !a.charge(w, price) {
+				return
+			}
+			campaign = a.state.resource("direct-mail-campaigns").add(campaign)
+			campaign["campaign_id"] = campaign["direct_mail_campaign_id"]
+		}
+		writeData(w, "Here are your results.", record{
+			"total_price":    price,
+			"total_quantity": quantity,
+			"data":           campaign,
+			"currency":       currency(),
+		})
+	}
+}
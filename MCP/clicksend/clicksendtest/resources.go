@@ -0,0 +1,473 @@
+package clicksendtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (a *API) routes() {
+	for _, name := range []string{"sms", "mms", "voice", "fax"} {
+		a.channelRoutes(name)
+	}
+	a.inboundRoutes()
+	a.contactRoutes()
+	a.accountRoutes()
+	a.campaignRoutes()
+	a.postRoutes()
+
+	a.crud("/automations/email/receipt", "email-receipt-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/automations/sms/receipts", "sms-receipt-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/automations/voice/receipts", "voice-receipt-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/automations/fax/receipts", "fax-receipt-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/automations/sms/inbound", "sms-inbound-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/automations/fax/inbound", "fax-inbound-rules", "Rule", "LCRUD", "rule_name", "action")
+	a.crud("/delivery-issues", "delivery-issues", "Delivery issue", "LC", "message_id", "type", "description")
+	a.crud("/email/addresses", "email-addresses", "Email address", "LCRD", "email_address")
+	a.crud("/email/templates", "email-templates", "Template", "LCRUD", "template_name")
+	a.crud("/sms/templates", "sms-templates", "Template", "LCUD", "template_name", "body")
+	a.crud("/sms/email-sms", "email-sms-addresses", "Allowed email address", "LCRUD", "email_address")
+	a.crud("/sms/email-sms-stripped-strings", "email-sms-strings", "Stripped string", "LCRUD", "strip_string")
+	a.emailAddressRoutes()
+	a.masterTemplateRoutes()
+	a.numberRoutes()
+	a.referenceRoutes()
+	a.uploadRoutes()
+
+	a.mux.HandleFunc("GET /voice/lang", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are the possible languages.", voiceLanguages())
+	})
+	a.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		notFound(w, "Resource")
+	})
+}
+
+// crud registers the handlers for a generic collection. ops selects the
+// operations: L list, C create, R read, U update and D delete. required lists
+// the fields a create request must include.
+func (a *API) crud(prefix, resource, name, ops string, required ...string) {
+	item := prefix + "/{id}"
+	if strings.Contains(ops, "L") {
+		a.mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+			writePage(w, r, fmt.Sprintf("Here are your %ss.", strings.ToLower(name)), a.state.resource(resource).filter(nil))
+		})
+	}
+	if strings.Contains(ops, "C") {
+		a.mux.HandleFunc("POST "+prefix, a.create(resource, fmt.Sprintf("New %s has been created.", strings.ToLower(name)), required...))
+	}
+	if strings.Contains(ops, "R") {
+		a.mux.HandleFunc("GET "+item, a.withResource(resource, name, func(w http.ResponseWriter, r *http.Request, rec record) {
+			writeData(w, fmt.Sprintf("Here is your %s.", strings.ToLower(name)), rec)
+		}))
+	}
+	if strings.Contains(ops, "U") {
+		a.mux.HandleFunc("PUT "+item, a.withResource(resource, name, func(w http.ResponseWriter, r *http.Request, rec record) {
+			body, ok := decodeBody(w, r)
+			if !ok {
+				return
+			}
+			delete(body, "password")
+			rec.merge(body, a.state.resource(resource).idField)
+			writeData(w, fmt.Sprintf("%s has been updated.", name), rec)
+		}))
+	}
+	if strings.Contains(ops, "D") {
+		a.mux.HandleFunc("DELETE "+item, a.withResource(resource, name, func(w http.ResponseWriter, r *http.Request, rec record) {
+			c := a.state.resource(resource)
+			c.remove(rec.str(c.idField))
+			writeData(w, fmt.Sprintf("%s #%s has been deleted.", name, rec.str(c.idField)), []any{})
+		}))
+	}
+}
+
+// create returns a handler that stores the request body in a generic
+// collection. Passwords are never stored.
+func (a *API) create(resource, msg string, required ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, f := range required {
+			if body.str(f) == "" {
+				badRequest(w, f+" is required.")
+				return
+			}
+		}
+		c := a.state.resource(resource)
+		delete(body, c.idField)
+		delete(body, "password")
+		body["date_added"] = fmt.Sprint(a.now().Unix())
+		if resource == "subaccounts" || resource == "reseller-accounts" {
+			body["api_key"] = a.state.messageID()
+			body["balance"] = formatBalance(0)
+		}
+		writeData(w, msg, c.add(body))
+	}
+}
+
+// withResource resolves the id path value in a generic collection before
+// calling h.
+func (a *API) withResource(resource, name string, h func(http.ResponseWriter, *http.Request, record)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := a.state.resource(resource).get(r.PathValue("id"))
+		if rec == nil {
+			notFound(w, name)
+			return
+		}
+		h(w, r, rec)
+	}
+}
+
+// exportHandler answers history export requests with a download URL.
+func exportHandler(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filename := r.URL.Query().Get("filename")
+		if filename == "" {
+			filename = name + ".csv"
+		}
+		writeData(w, "Download your file here.", record{"url": fmt.Sprintf("%s/exports/%s", origin(r), path.Base(filename))})
+	}
+}
+
+// origin returns the scheme and host the request was sent to.
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func timeOf(unix int64) time.Time {
+	return time.Unix(unix, 0).UTC()
+}
+
+func (a *API) emailAddressRoutes() {
+	a.mux.HandleFunc("PUT /email/address-verify/{id}/send", a.withResource("email-addresses", "Email address", func(w http.ResponseWriter, r *http.Request, rec record) {
+		rec["activation_token"] = fmt.Sprintf("MOCKTOKEN%d", rec.int("email_address_id"))
+		writeData(w, "Verification email has been sent.", []any{})
+	}))
+	a.mux.HandleFunc("PUT /email/address-verify/{id}/verify/{activation_token}", a.withResource("email-addresses", "Email address", func(w http.ResponseWriter, r *http.Request, rec record) {
+		if token := rec.str("activation_token"); token != "" && token != r.PathValue("activation_token") {
+			badRequest(w, "Invalid activation token.")
+			return
+		}
+		rec["verified"] = 1
+		writeData(w, "Email address has been verified.", rec)
+	}))
+}
+
+func masterTemplates() []record {
+	return []record{
+		{"template_id_master": 1, "template_name": "Basic", "category_id": 1, "thumbnail": map[string]string{"small": "basic-small.png"}, "body": "<p>{{content}}</p>"},
+		{"template_id_master": 2, "template_name": "Newsletter", "category_id": 2, "thumbnail": map[string]string{"small": "newsletter-small.png"}, "body": "<h1>{{title}}</h1><p>{{content}}</p>"},
+	}
+}
+
+func (a *API) masterTemplateRoutes() {
+	categories := []record{{"category_id": 1, "name": "Basic"}, {"category_id": 2, "name": "Newsletters"}}
+	find := func(items []record, field, id string) record {
+		for _, it := range items {
+			if it.str(field) == id {
+				return it
+			}
+		}
+		return nil
+	}
+	a.mux.HandleFunc("GET /email/master-templates", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are the master templates.", masterTemplates())
+	})
+	a.mux.HandleFunc("GET /email/master-templates/{id}", func(w http.ResponseWriter, r *http.Request) {
+		t := find(masterTemplates(), "template_id_master", r.PathValue("id"))
+		if t == nil {
+			notFound(w, "Master template")
+			return
+		}
+		writeData(w, "Here is the master template.", t)
+	})
+	a.mux.HandleFunc("GET /email/master-templates-categories", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are the categories.", categories)
+	})
+	a.mux.HandleFunc("GET /email/master-templates-categories/{id}", func(w http.ResponseWriter, r *http.Request) {
+		c := find(categories, "category_id", r.PathValue("id"))
+		if c == nil {
+			notFound(w, "Category")
+			return
+		}
+		writeData(w, "Here is the category.", c)
+	})
+	a.mux.HandleFunc("GET /email/master-templates-categories/{id}/master-templates", func(w http.ResponseWriter, r *http.Request) {
+		if find(categories, "category_id", r.PathValue("id")) == nil {
+			notFound(w, "Category")
+			return
+		}
+		items := []record{}
+		for _, t := range masterTemplates() {
+			if t.str("category_id") == r.PathValue("id") {
+				items = append(items, t)
+			}
+		}
+		writeData(w, "Here are the master templates.", items)
+	})
+	a.mux.HandleFunc("POST /email/templates-images/{id}", a.withResource("email-templates", "Template", func(w http.ResponseWriter, r *http.Request, rec record) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body.str("image") == "" {
+			badRequest(w, "image is required.")
+			return
+		}
+		writeData(w, "Image has been uploaded.", fmt.Sprintf("%s/uploads/files/template-%s.png", origin(r), rec.str("template_id")))
+	}))
+}
+
+// numberPrefixes are the dialling prefixes used to generate searchable
+// dedicated numbers per country.
+var numberPrefixes = map[string]string{"AU": "+614", "US": "+1512", "GB": "+447", "NZ": "+6421", "CA": "+1416"}
+
+func availableNumbers(country string) []record {
+	prefix, ok := numberPrefixes[strings.ToUpper(country)]
+	if !ok {
+		return nil
+	}
+	width := 12 - len(prefix)
+	mod := 1
+	for range width {
+		mod *= 10
+	}
+	out := []record{}
+	for i := 0; i < 40; i++ {
+		number := fmt.Sprintf("%s%0*d", prefix, width, (1234567+i*7919)%mod)
+		out = append(out, record{"dedicated_number": number, "country": strings.ToUpper(country), "price": "18.59"})
+	}
+	return out
+}
+
+func (a *API) numberRoutes() {
+	a.mux.HandleFunc("GET /numbers", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, "Here are your dedicated numbers.", a.state.resource("dedicated-numbers").filter(nil))
+	})
+	a.mux.HandleFunc("GET /numbers/search/{country}", func(w http.ResponseWriter, r *http.Request) {
+		search := r.URL.Query().Get("search")
+		searchType := r.URL.Query().Get("search_type")
+		owned := a.state.resource("dedicated-numbers")
+		items := []record{}
+		for _, n := range availableNumbers(r.PathValue("country")) {
+			number := n.str("dedicated_number")
+			if owned.get(number) != nil {
+				continue
+			}
+			digits := strings.TrimPrefix(number, "+")
+			match := true
+			switch {
+			case search == "":
+			case searchType == "0":
+				match = strings.HasPrefix(digits, search)
+			case searchType == "2":
+				match = strings.HasSuffix(digits, search)
+			default:
+				match = strings.Contains(digits, search)
+			}
+			if match {
+				items = append(items, n)
+			}
+		}
+		writePage(w, r, "Here are some results.", items)
+	})
+	a.mux.HandleFunc("POST /numbers/buy/{dedicated_number}", func(w http.ResponseWriter, r *http.Request) {
+		number := r.PathValue("dedicated_number")
+		var found record
+		for country := range numberPrefixes {
+			for _, n := range availableNumbers(country) {
+				if n.str("dedicated_number") == number {
+					found = n
+				}
+			}
+		}
+		owned := a.state.resource("dedicated-numbers")
+		switch {
+		case found == nil:
+			notFound(w, "Dedicated number")
+			return
+		case owned.get(number) != nil:
+			badRequest(w, "This number has already been purchased.")
+			return
+		case a.state.balance < 18.59:
+			writeError(w, http.StatusPaymentRequired, "INSUFFICIENT_CREDIT", "You don't have enough credit to purchase this number.")
+			return
+		}
+		a.state.balance -= 18.59
+		owned.add(found.clone())
+		writeData(w, "Here is your new number.", record{
+			"dedicated_number": number,
+			"country":          found["country"],
+			"price_total":      "18.59",
+			"_price_monthly":   "18.59",
+			"_price_setup":     "0.00",
+			"_currency":        currency(),
+		})
+	})
+}
+
+var countries = []record{
+	{"code": "AU", "value": "Australia"},
+	{"code": "CA", "value": "Canada"},
+	{"code": "DE", "value": "Germany"},
+	{"code": "FR", "value": "France"},
+	{"code": "GB", "value": "United Kingdom"},
+	{"code": "NZ", "value": "New Zealand"},
+	{"code": "US", "value": "United States of America"},
+}
+
+func (a *API) referenceRoutes() {
+	a.mux.HandleFunc("GET /countries", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are some results.", countries)
+	})
+	a.mux.HandleFunc("GET /timezones", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Here are some results.", []string{
+			"Australia/Melbourne", "Australia/Perth", "Australia/Sydney", "America/Chicago",
+			"America/Los_Angeles", "America/New_York", "Europe/Berlin", "Europe/London",
+			"Pacific/Auckland", "UTC",
+		})
+	})
+	a.mux.HandleFunc("GET /pricing/{country}", func(w http.ResponseWriter, r *http.Request) {
+		country := strings.ToUpper(r.PathValue("country"))
+		if !slices.ContainsFunc(countries, func(c record) bool { return c.str("code") == country }) {
+			notFound(w, "Country")
+			return
+		}
+		ch := a.state.channels
+		writeData(w, "Here are some results.", record{
+			"country":  country,
+			"currency": currency(),
+			"sms":      record{"price_rate": fmt.Sprintf("%.4f", ch["sms"].unitPrice)},
+			"mms":      record{"price_rate": fmt.Sprintf("%.4f", ch["mms"].unitPrice)},
+			"voice":    record{"price_rate": fmt.Sprintf("%.4f", ch["voice"].unitPrice)},
+			"fax":      record{"price_rate": fmt.Sprintf("%.4f", ch["fax"].unitPrice)},
+		})
+	})
+	a.mux.HandleFunc("GET /statistics/sms", a.statistics("sms"))
+	a.mux.HandleFunc("GET /statistics/voice", a.statistics("voice"))
+	a.mux.HandleFunc("GET /sdk-download/{type}", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, "Download your SDK here.", fmt.Sprintf("%s/sdk/clicksend-%s.zip", origin(r), path.Base(r.PathValue("type"))))
+	})
+}
+
+// statistics reports daily outbound, inbound and bounced counts for a channel.
+func (a *API) statistics(channel string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		type day struct {
+			outbound, inbound, bounced int
+			price                      float64
+		}
+		days := map[int64]*day{}
+		bucket := func(unix int64) *day {
+			t := timeOf(unix)
+			key := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix()
+			if days[key] == nil {
+				days[key] = &day{}
+			}
+			return days[key]
+		}
+		for _, m := range a.state.channels[channel].history.items {
+			if m["status"] == "Cancelled" {
+				continue
+			}
+			d := bucket(m.int("date"))
+			d.outbound++
+			var p float64
+			fmt.Sscan(m.str("message_price"), &p)
+			d.price += p
+		}
+		if channel == "sms" {
+			for _, m := range a.state.inbound.items {
+				bucket(m.int("timestamp")).inbound++
+			}
+		}
+		keys := make([]int64, 0, len(days))
+		for k := range days {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		stats := []record{}
+		for _, k := range keys {
+			d := days[k]
+			stats = append(stats, record{
+				"date":     k,
+				"outbound": record{"count": d.outbound, "price": d.price},
+				"inbound":  record{"count": d.inbound},
+				"bounced":  record{"count": d.bounced},
+			})
+		}
+		writeData(w, "Here are some statistics.", record{"stat": stats, "currency": currency()})
+	}
+}
+
+// uploadExtensions maps the convert parameter to the stored file extension.
+var uploadExtensions = map[string]string{"fax": "pdf", "mms": "jpg", "post": "pdf", "csv": "csv"}
+
+func (a *API) uploadRoutes() {
+	a.mux.HandleFunc("POST /uploads", func(w http.ResponseWriter, r *http.Request) {
+		convert := r.URL.Query().Get("convert")
+		ext, ok := uploadExtensions[convert]
+		if !ok {
+			badRequest(w, "convert must be one of fax, mms, post or csv.")
+			return
+		}
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		content, err := base64.StdEncoding.DecodeString(body.str("content"))
+		if err != nil || len(content) == 0 {
+			badRequest(w, "content must be base64 encoded file contents.")
+			return
+		}
+		uploads := a.state.resource("uploads")
+		now := a.now()
+		rec := uploads.add(record{})
+		name := fmt.Sprintf("%s.%s", a.state.messageID(), ext)
+		a.state.uploads[name] = content
+		rec["file_name"] = name
+		rec["_url"] = fmt.Sprintf("%s/uploads/files/%s", origin(r), name)
+		rec["date_added"] = now.Unix()
+		rec["date_delete"] = now.Add(7 * 24 * time.Hour).Unix()
+		rec["user_id"] = 1
+		writeData(w, "Your file has been uploaded.", rec)
+	})
+}
+
+// uploadContent returns the content of a file stored with POST /uploads,
+// looked up by its URL.
+func (a *API) uploadContent(fileURL string) ([]byte, bool) {
+	_, name, ok := strings.Cut(fileURL, "/uploads/files/")
+	if !ok {
+		return nil, false
+	}
+	content, ok := a.state.uploads[name]
+	return content, ok
+}
+
+// serveUpload serves files stored with POST /uploads. Like ClickSend upload
+// URLs they do not require authentication.
+func (a *API) serveUpload(w http.ResponseWriter, r *http.Request) bool {
+	name, ok := strings.CutPrefix(r.URL.Path, "/uploads/files/")
+	if !ok || r.Method != http.MethodGet {
+		return false
+	}
+	a.mu.Lock()
+	content, found := a.state.uploads[name]
+	a.mu.Unlock()
+	if !found {
+		notFound(w, "File")
+		return true
+	}
+	w.Write(content)
+	return true
+}
@@ -0,0 +1,463 @@
+// Package clicksendtest is an in-memory fake of the ClickSend REST API v3 for
+// tests and local development.
+//
+// The fake keeps contact lists, contacts, message history, delivery receipts,
+// inbound messages and the account balance in memory, charges sends against
+// the balance and holds scheduled messages until their send time. Every
+// request is recorded for inspection with Requests. Faults add latency or fail
+// matching requests:
+//
+//	srv := clicksendtest.NewServer()
+//	defer srv.Close()
+//	srv.AddFault(clicksendtest.Fault{Method: "POST", Path: "/sms/send", Status: 500, Times: 1})
+//	client := clicksend.NewClient(srv.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+package clicksendtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default credentials accepted by the fake.
+const (
+	Username = "mock-user"
+	APIKey   = "mock-api-key"
+)
+
+// DefaultBalance is the account balance a new fake starts with.
+const DefaultBalance = 100.0
+
+// API is the fake ClickSend API. It implements http.Handler.
+type API struct {
+	mu        sync.Mutex
+	mux       *http.ServeMux
+	username  string
+	apiKey    string
+	noAuth    bool
+	balance   float64
+	clock     func() time.Time
+	offset    time.Duration
+	latency   time.Duration
+	errorRate float64
+	rand      *rand.Rand
+	faults    []*Fault
+	requests  []Request
+	state     *state
+}
+
+// Option configures an API.
+type Option func(*API)
+
+// WithCredentials sets the API username and key the fake accepts. The API
+// username and key of each subaccount are accepted too.
+func WithCredentials(username, apiKey string) Option {
+	return func(a *API) {
+		a.username = username
+		a.apiKey = apiKey
+	}
+}
+
+// WithoutAuth accepts requests without checking the Authorization header.
+func WithoutAuth() Option {
+	return func(a *API) {
+		a.noAuth = true
+	}
+}
+
+// WithBalance sets the starting account balance.
+func WithBalance(balance float64) Option {
+	return func(a *API) {
+		a.balance = balance
+	}
+}
+
+// WithClock sets the time source used for timestamps and scheduling.
+func WithClock(now func() time.Time) Option {
+	return func(a *API) {
+		a.clock = now
+	}
+}
+
+// WithLatency delays every response by d.
+func WithLatency(d time.Duration) Option {
+	return func(a *API) {
+		a.latency = d
+	}
+}
+
+// WithErrorRate fails the given fraction (0-1) of requests with a 500 error.
+func WithErrorRate(rate float64, seed int64) Option {
+	return func(a *API) {
+		a.errorRate = rate
+		a.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// New returns a fake API with empty state.
+func New(opts ...Option) *API {
+	a := &API{
+		username: Username,
+		apiKey:   APIKey,
+		balance:  DefaultBalance,
+		clock:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.state = newState(a.balance)
+	a.mux = http.NewServeMux()
+	a.routes()
+	return a
+}
+
+// Server is an API listening on a local httptest server.
+type Server struct {
+	*httptest.Server
+	*API
+}
+
+// NewServer starts a fake API on a local httptest server. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	api := New(opts...)
+	return &Server{Server: httptest.NewServer(api), API: api}
+}
+
+// Fault adds latency to, or fails, requests matching Method and Path.
+type Fault struct {
+	// Method matches the request method; empty matches any method.
+	Method string `json:"method,omitempty"`
+	// Path matches the request path; a trailing "*" matches any suffix and an
+	// empty path matches every request.
+	Path string `json:"path,omitempty"`
+	// Delay is added before the request is handled.
+	Delay time.Duration `json:"delay,omitempty"`
+	// Status, when set, is returned instead of handling the request.
+	Status int `json:"status,omitempty"`
+	// ResponseCode and ResponseMsg fill the error envelope. They default to a
+	// code derived from Status.
+	ResponseCode string `json:"response_code,omitempty"`
+	ResponseMsg  string `json:"response_msg,omitempty"`
+	// Times limits how many requests the fault applies to; zero means until
+	// ClearFaults is called.
+	Times int `json:"times,omitempty"`
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+	return f.Path == "" || strings.TrimSuffix(f.Path, "/") == strings.TrimSuffix(r.URL.Path, "/")
+}
+
+// Request is a request received by the fake.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Requests returns every request received since the fake was created or last
+// reset, oldest first.
+func (a *API) Requests() []Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Request(nil), a.requests...)
+}
+
+// ClearRequests forgets the recorded requests.
+func (a *API) ClearRequests() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = nil
+}
+
+func (a *API) record(r *http.Request) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.EscapedPath(),
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+}
+
+// AddFault registers a fault. Faults are checked in the order they were added
+// and only the first match applies.
+func (a *API) AddFault(f Fault) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = append(a.faults, &f)
+}
+
+// ClearFaults removes every registered fault.
+func (a *API) ClearFaults() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = nil
+}
+
+// Reset clears all state, faults and recorded requests and restores the
+// starting balance.
+func (a *API) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = nil
+	a.requests = nil
+	a.offset = 0
+	a.state = newState(a.balance)
+}
+
+// Advance moves the fake's clock forward, delivering scheduled messages whose
+// send time has passed.
+func (a *API) Advance(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.offset += d
+	a.deliverDue()
+}
+
+// Balance returns the current account balance.
+func (a *API) Balance() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.state.balance
+}
+
+// SetBalance sets the current account balance.
+func (a *API) SetBalance(balance float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.balance = balance
+}
+
+func (a *API) now() time.Time {
+	return a.clock().Add(a.offset)
+}
+
+// ServeHTTP implements http.Handler.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.record(r)
+	fault := a.takeFault(r)
+	delay := a.latency
+	if fault != nil {
+		delay += fault.Delay
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if fault != nil && fault.Status != 0 {
+		code, msg := fault.ResponseCode, fault.ResponseMsg
+		if code == "" {
+			code = strings.ToUpper(strings.ReplaceAll(http.StatusText(fault.Status), " ", "_"))
+		}
+		if msg == "" {
+			msg = "Injected failure."
+		}
+		writeError(w, fault.Status, code, msg)
+		return
+	}
+	if a.serveUpload(w, r) {
+		return
+	}
+	if a.randomFailure() {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Injected failure.")
+		return
+	}
+	if !a.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API credentials.")
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.deliverDue()
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *API) takeFault(r *http.Request) *Fault {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, f := range a.faults {
+		if !f.matches(r) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				a.faults = append(a.faults[:i], a.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+func (a *API) randomFailure() bool {
+	if a.errorRate <= 0 {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rand.Float64() < a.errorRate
+}
+
+func (a *API) authorized(r *http.Request) bool {
+	if a.noAuth {
+		return true
+	}
+	encoded, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Basic ")
+	if !ok {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	if string(decoded) == a.username+":"+a.apiKey {
+		return true
+	}
+	// Subaccounts authenticate with their own API username and key.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.state.resource("subaccounts").items {
+		if s.str("api_key") != "" && string(decoded) == s.str("api_username")+":"+s.str("api_key") {
+			return true
+		}
+	}
+	return false
+}
+
+// writeData writes a successful ClickSend response envelope.
+func writeData(w http.ResponseWriter, msg string, data any) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"http_code":     http.StatusOK,
+		"response_code": "SUCCESS",
+		"response_msg":  msg,
+		"data":          data,
+	})
+}
+
+// writeError writes a ClickSend error envelope.
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, map[string]any{
+		"http_code":     status,
+		"response_code": code,
+		"response_msg":  msg,
+		"data":          nil,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func notFound(w http.ResponseWriter, what string) {
+	writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("%s not found.", what))
+}
+
+func badRequest(w http.ResponseWriter, msg string) {
+	writeError(w, http.StatusBadRequest, "BAD_REQUEST", msg)
+}
+
+// decodeBody reads a JSON object request body. An empty body decodes to an
+// empty record.
+func decodeBody(w http.ResponseWriter, r *http.Request) (record, bool) {
+	body := record{}
+	if r.Body == nil || r.ContentLength == 0 {
+		return body, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		badRequest(w, "Request body is not valid JSON.")
+		return nil, false
+	}
+	return body, true
+}
+
+// writePage writes items as a ClickSend paginated collection, honouring the
+// page and limit query parameters.
+func writePage(w http.ResponseWriter, r *http.Request, msg string, items []record) {
+	pageNum := queryInt(r, "page", 1)
+	limit := queryInt(r, "limit", 15)
+	if limit > 100 {
+		limit = 100
+	}
+	total := len(items)
+	lastPage := (total + limit - 1) / limit
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	start := (pageNum - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	pageURL := func(n int) any {
+		if n < 1 || n > lastPage {
+			return nil
+		}
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(n))
+		return path.Clean(r.URL.Path) + "?" + q.Encode()
+	}
+	data := make([]record, 0, end-start)
+	data = append(data, items[start:end]...)
+	page := map[string]any{
+		"total":         total,
+		"per_page":      limit,
+		"current_page":  pageNum,
+		"last_page":     lastPage,
+		"next_page_url": pageURL(pageNum + 1),
+		"prev_page_url": pageURL(pageNum - 1),
+		"from":          nil,
+		"to":            nil,
+		"data":          data,
+	}
+	if len(data) > 0 {
+		page["from"] = start + 1
+		page["to"] = end
+	}
+	writeData(w, msg, page)
+}
+
+func queryInt(r *http.Request, name string, def int) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
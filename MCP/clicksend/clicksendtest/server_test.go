@@ -0,0 +1,142 @@
+package clicksendtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// call sends a request to srv with the default credentials, or with creds
+// when set, and returns the status and decoded envelope.
+func call(t *testing.T, srv *Server, method, path, body, creds string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds == "" {
+		creds = Username + ":" + APIKey
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(creds)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var env map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, env
+}
+
+func TestPaging(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := range 40 {
+		if status, env := call(t, srv, "POST", "/lists", fmt.Sprintf(`{"list_name":"L%d"}`, i), ""); status != http.StatusOK {
+			t.Fatalf("create list: %d %v", status, env)
+		}
+	}
+
+	tests := []struct {
+		query            string
+		page, limit      float64
+		count            int
+		last             float64
+		from, to         any
+		hasNext, hasPrev bool
+	}{
+		{"", 1, 15, 15, 3, 1.0, 15.0, true, false},
+		{"?page=2", 2, 15, 15, 3, 16.0, 30.0, true, true},
+		{"?page=3", 3, 15, 10, 3, 31.0, 40.0, false, true},
+		{"?page=4", 4, 15, 0, 3, nil, nil, false, true},
+		{"?limit=100", 1, 100, 40, 1, 1.0, 40.0, false, false},
+		{"?limit=500", 1, 100, 40, 1, 1.0, 40.0, false, false},
+		{"?page=0&limit=x", 1, 15, 15, 3, 1.0, 15.0, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			status, env := call(t, srv, "GET", "/lists"+tt.query, "", "")
+			if status != http.StatusOK || env["response_code"] != "SUCCESS" {
+				t.Fatalf("status %d, %v", status, env)
+			}
+			page := env["data"].(map[string]any)
+			data := page["data"].([]any)
+			if page["total"] != 40.0 || page["current_page"] != tt.page || page["per_page"] != tt.limit || page["last_page"] != tt.last || len(data) != tt.count {
+				t.Errorf("page = total %v, current %v, per_page %v, last %v with %d items", page["total"], page["current_page"], page["per_page"], page["last_page"], len(data))
+			}
+			if page["from"] != tt.from || page["to"] != tt.to {
+				t.Errorf("from, to = %v, %v; want %v, %v", page["from"], page["to"], tt.from, tt.to)
+			}
+			if (page["next_page_url"] != nil) != tt.hasNext || (page["prev_page_url"] != nil) != tt.hasPrev {
+				t.Errorf("next_page_url %v, prev_page_url %v", page["next_page_url"], page["prev_page_url"])
+			}
+		})
+	}
+
+	_, env := call(t, srv, "GET", "/lists?page=2&limit=20", "", "")
+	if next := env["data"].(map[string]any)["next_page_url"]; next != nil {
+		t.Errorf("next_page_url of the last page = %v", next)
+	}
+	_, env = call(t, srv, "GET", "/lists?page=1&limit=20", "", "")
+	if next, _ := env["data"].(map[string]any)["next_page_url"].(string); !strings.Contains(next, "page=2") || !strings.Contains(next, "limit=20") {
+		t.Errorf("next_page_url = %q, want page 2 with the same limit", next)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddFault(Fault{Method: "GET", Path: "/account", Status: http.StatusServiceUnavailable, Times: 1})
+	srv.AddFault(Fault{Path: "/sms/*", Status: http.StatusTooManyRequests, ResponseCode: "TOO_MANY_REQUESTS", ResponseMsg: "Slow down."})
+
+	tests := []struct {
+		name         string
+		method, path string
+		body, creds  string
+		status       int
+		code         string
+	}{
+		{"fault", "GET", "/account", "", "", http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE"},
+		{"fault used up", "GET", "/account", "", "", http.StatusOK, "SUCCESS"},
+		{"wildcard fault", "GET", "/sms/history", "", "", http.StatusTooManyRequests, "TOO_MANY_REQUESTS"},
+		{"faults before auth", "GET", "/sms/inbound", "", "someone:wrong", http.StatusTooManyRequests, "TOO_MANY_REQUESTS"},
+		{"wrong credentials", "GET", "/account", "", "someone:wrong", http.StatusUnauthorized, "UNAUTHORIZED"},
+		{"missing list", "GET", "/lists/999", "", "", http.StatusNotFound, "NOT_FOUND"},
+		{"invalid JSON", "POST", "/lists", "{", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"missing field", "POST", "/lists", "{}", "", http.StatusBadRequest, "BAD_REQUEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, env := call(t, srv, tt.method, tt.path, tt.body, tt.creds)
+			if status != tt.status || env["response_code"] != tt.code || env["http_code"] != float64(tt.status) {
+				t.Errorf("got %d %v, want %d %s", status, env, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK && env["data"] != nil {
+				t.Errorf("error data = %v, want null", env["data"])
+			}
+		})
+	}
+	if _, env := call(t, srv, "GET", "/sms/history", "", ""); env["response_msg"] != "Slow down." {
+		t.Errorf("response_msg = %v, want the fault's message", env["response_msg"])
+	}
+
+	srv.ClearFaults()
+	if status, _ := call(t, srv, "GET", "/sms/history", "", ""); status != http.StatusOK {
+		t.Errorf("status %d after ClearFaults", status)
+	}
+	if n := len(srv.Requests()); n != 10 {
+		t.Errorf("recorded %d requests, want 10", n)
+	}
+
+	failing := NewServer(WithErrorRate(1, 1))
+	defer failing.Close()
+	if status, env := call(t, failing, "GET", "/account", "", ""); status != http.StatusInternalServerError || env["response_code"] != "INTERNAL_SERVER_ERROR" {
+		t.Errorf("with an error rate of 1: %d %v", status, env)
+	}
+}
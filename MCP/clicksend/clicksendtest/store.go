@@ -0,0 +1,197 @@
+package clicksendtest
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// record is a stored API object. Records keep every field a client sends so
+// responses echo requests the way ClickSend does.
+type record map[string]any
+
+func (r record) str(key string) string {
+	switch v := r[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (r record) int(key string) int64 {
+	switch v := r[key].(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+func (r record) clone() record {
+	c := make(record, len(r))
+	for k, v := range r {
+		c[k] = v
+	}
+	return c
+}
+
+// merge copies the fields of src into r, leaving idField untouched.
+func (r record) merge(src record, idField string) {
+	for k, v := range src {
+		if k != idField {
+			r[k] = v
+		}
+	}
+}
+
+// collection is an ordered set of records keyed by idField. Records without
+// an id are assigned the next integer id.
+type collection struct {
+	idField string
+	nextID  int64
+	items   []record
+}
+
+func newCollection(idField string, firstID int64) *collection {
+	return &collection{idField: idField, nextID: firstID}
+}
+
+func (c *collection) add(r record) record {
+	if _, ok := r[c.idField]; !ok {
+		r[c.idField] = c.nextID
+		c.nextID++
+	}
+	c.items = append(c.items, r)
+	return r
+}
+
+func (c *collection) get(id string) record {
+	for _, r := range c.items {
+		if r.str(c.idField) == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (c *collection) remove(id string) bool {
+	for i, r := range c.items {
+		if r.str(c.idField) == id {
+			c.items = append(c.items[:i], c.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *collection) filter(keep func(record) bool) []record {
+	out := []record{}
+	for _, r := range c.items {
+		if keep == nil || keep(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// resourceIDs lists the generic collections and the id field and first id
+// of each.
+var resourceIDs = map[string]struct {
+	idField string
+	firstID int64
+}{
+	"credit-card":             {"number", 1},
+	"transactions":            {"invoice_number", 1},
+	"subaccounts":             {"subaccount_id", 2},
+	"reseller-accounts":       {"client_user_id", 100},
+	"referral-accounts":       {"user_id", 1},
+	"email-receipt-rules":     {"receipt_rule_id", 1},
+	"sms-receipt-rules":       {"receipt_rule_id", 1},
+	"voice-receipt-rules":     {"receipt_rule_id", 1},
+	"fax-receipt-rules":       {"receipt_rule_id", 1},
+	"sms-inbound-rules":       {"inbound_rule_id", 1},
+	"fax-inbound-rules":       {"inbound_rule_id", 1},
+	"delivery-issues":         {"issue_id", 1},
+	"email-addresses":         {"email_address_id", 1},
+	"email-templates":         {"template_id", 1},
+	"sms-templates":           {"template_id", 1},
+	"email-sms-addresses":     {"email_address_id", 1},
+	"email-sms-strings":       {"rule_id", 1},
+	"return-addresses":        {"return_address_id", 1},
+	"dedicated-numbers":       {"dedicated_number", 1},
+	"sms-campaigns":           {"sms_campaign_id", 1},
+	"email-campaigns":         {"email_campaign_id", 1},
+	"email-campaign-messages": {"message_id", 1},
+	"email-history":           {"message_id", 1},
+	"post-letters":            {"message_id", 1},
+	"postcards":               {"message_id", 1},
+	"direct-mail-campaigns":   {"direct_mail_campaign_id", 1},
+	"uploads":                 {"upload_id", 1},
+}
+
+// state is everything the fake stores.
+type state struct {
+	balance  float64
+	seq      int64
+	account  record
+	reseller record
+	lists    *collection
+	contacts *collection
+	channels map[string]*channel
+	inbound  *collection
+	// inboundRead holds the ids of inbound messages marked as read.
+	inboundRead map[string]bool
+	resources   map[string]*collection
+	uploads     map[string][]byte
+}
+
+func newState(balance float64) *state {
+	s := &state{
+		balance: balance,
+		account: defaultAccount(),
+		reseller: record{
+			"subdomain":            "mock",
+			"company_name":         "Mock Reseller",
+			"colour_navigation":    "#3F51B5",
+			"allow_public_signups": 1,
+			"currency":             "AUD",
+		},
+		lists:       newCollection("list_id", 1000),
+		contacts:    newCollection("contact_id", 50000),
+		channels:    newChannels(),
+		inbound:     newCollection("message_id", 0),
+		inboundRead: map[string]bool{},
+		resources:   map[string]*collection{},
+		uploads:     map[string][]byte{},
+	}
+	for name, id := range resourceIDs {
+		s.resources[name] = newCollection(id.idField, id.firstID)
+	}
+	return s
+}
+
+// messageID returns a new ClickSend style message id.
+func (s *state) messageID() string {
+	s.seq++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012X", s.seq)
+}
+
+// resource returns the generic collection stored under name. name must be a
+// key of resourceIDs.
+func (s *state) resource(name string) *collection {
+	c, ok := s.resources[name]
+	if !ok {
+		panic("clicksendtest: unknown resource " + name)
+	}
+	return c
+}
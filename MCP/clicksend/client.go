@@ -0,0 +1,329 @@
+// Package clicksend is a typed client for the ClickSend REST API v3.
+//
+// Every endpoint of the API is exposed as a method on Client. Responses are
+// decoded into Response values holding ClickSend's standard envelope, the
+// typed data and the raw response body:
+//
+//	client := clicksend.NewClient(clicksend.DefaultBaseURL, clicksend.WithCredentials("user", "api-key"))
+//	resp, err := client.SendSms(ctx, &clicksend.SmsMessageCollection{
+//		Messages: []clicksend.SmsMessage{{To: "+61411111111", Body: "Hello"}},
+//	})
+package clicksend
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the production ClickSend v3 API.
+const DefaultBaseURL = "https://rest.clicksend.com/v3"
+
+// Client calls the ClickSend REST API v3.
+type Client struct {
+	baseURL    string
+	authHeader string
+	httpClient *http.Client
+	maxRetries int
+	retryBase  time.Duration
+	endpoint   func(context.Context) (*Endpoint, error)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. A nil client keeps
+// http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithCredentials authenticates with an API username and API key.
+func WithCredentials(username, apiKey string) Option {
+	return func(c *Client) {
+		token := base64.StdEncoding.EncodeToString([]byte(username + ":" + apiKey))
+		c.authHeader = "Basic " + token
+	}
+}
+
+// WithBasicAuth authenticates with an already base64-encoded "username:apikey"
+// pair. An empty value sends no Authorization header.
+func WithBasicAuth(encoded string) Option {
+	return func(c *Client) {
+		if encoded != "" {
+			c.authHeader = "Basic " + encoded
+		}
+	}
+}
+
+// Endpoint is the base URL and credentials a request is sent with.
+type Endpoint struct {
+	// BaseURL replaces the client's base URL when set.
+	BaseURL string
+	// BasicAuth is the base64-encoded "username:apikey" pair.
+	BasicAuth string
+}
+
+// WithEndpointFunc resolves the base URL and credentials of each request
+// from its context, so one client can act for several accounts. When fn
+// returns nil the client's own base URL and credentials are used; when it
+// fails the request is not sent.
+func WithEndpointFunc(fn func(ctx context.Context) (*Endpoint, error)) Option {
+	return func(c *Client) {
+		c.endpoint = fn
+	}
+}
+
+// NewClient returns a client for the API at baseURL.
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the API base URL the client sends requests to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Response is ClickSend's standard response envelope.
+type Response[T any] struct {
+	HTTPCode     int    `json:"http_code"`
+	ResponseCode string `json:"response_code"`
+	ResponseMsg  string `json:"response_msg"`
+	Data         T      `json:"data"`
+	// Raw is the undecoded response body, including fields the typed Data
+	// does not model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Page is the data wrapper ClickSend uses for paginated collections.
+type Page[T any] struct {
+	Total       Int     `json:"total"`
+	PerPage     Int     `json:"per_page"`
+	CurrentPage Int     `json:"current_page"`
+	LastPage    Int     `json:"last_page"`
+	NextPageURL *string `json:"next_page_url"`
+	PrevPageURL *string `json:"prev_page_url"`
+	From        Int     `json:"from"`
+	To          Int     `json:"to"`
+	Data        []T     `json:"data"`
+}
+
+// ListOptions selects a page of a paginated collection. Zero values use the
+// API defaults (page 1, 15 items).
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+func (o *ListOptions) apply(q url.Values) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if o == nil {
+		return q
+	}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return q
+}
+
+const (
+	// MaxPageLimit is the largest page ClickSend returns.
+	MaxPageLimit = 100
+	// MaxAllPages is the most pages All fetches, like the max_pages cap of
+	// the list tools.
+	MaxAllPages = 50
+)
+
+// All fetches every page of a paginated collection with list, in pages of
+// MaxPageLimit items. It fails rather than fetch more than MaxAllPages pages.
+func All[T any](ctx context.Context, list func(ctx context.Context, opts *ListOptions) (*Response[Page[T]], error)) ([]T, error) {
+	var items []T
+	for page := 1; ; page++ {
+		if page > MaxAllPages {
+			return nil, fmt.Errorf("collection has more than %d pages of %d items", MaxAllPages, MaxPageLimit)
+		}
+		resp, err := list(ctx, &ListOptions{Page: page, Limit: MaxPageLimit})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Data.Data...)
+		if int(resp.Data.LastPage) <= page || len(resp.Data.Data) == 0 {
+			return items, nil
+		}
+	}
+}
+
+// APIError is returned when ClickSend answers with an HTTP error status.
+type APIError struct {
+	StatusCode   int
+	ResponseCode string
+	ResponseMsg  string
+	Body         []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Body)
+}
+
+// DecodeError is returned when a successful response does not match the typed
+// model. The Response is still returned with Raw populated.
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Do sends a request and decodes ClickSend's response envelope into a
+// Response. query may be nil; body is JSON-encoded when not nil.
+func Do[T any](ctx context.Context, c *Client, method, path string, query url.Values, body any) (*Response[T], error) {
+	raw, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response[T]{Raw: raw}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return resp, &DecodeError{Err: err}
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body any) (json.RawMessage, error) {
+	baseURL, authHeader := c.baseURL, c.authHeader
+	if c.endpoint != nil {
+		ep, err := c.endpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ep != nil {
+			if ep.BaseURL != "" {
+				baseURL = strings.TrimRight(ep.BaseURL, "/")
+			}
+			authHeader = ""
+			if ep.BasicAuth != "" {
+				authHeader = "Basic " + ep.BasicAuth
+			}
+		}
+	}
+	u := baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		payload = b
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(context.WithValue(ctx, attemptKey{}, attempt), method, u, authHeader, payload, body != nil)
+		if attempt >= c.maxRetries || !retryable(method, statusOf(resp), err) || ctx.Err() != nil {
+			return finish(resp, err)
+		}
+		delay := c.retryDelay(attempt+1, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, u, authHeader string, payload []byte, hasBody bool) (*http.Response, error) {
+	var reader io.Reader
+	if hasBody {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	req.Header.Set("Accept", "application/json")
+	return c.httpClient.Do(req)
+}
+
+func statusOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// finish reads the response of the last attempt.
+func finish(resp *http.Response, err error) (json.RawMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: raw}
+		var envelope struct {
+			ResponseCode string `json:"response_code"`
+			ResponseMsg  string `json:"response_msg"`
+		}
+		if json.Unmarshal(raw, &envelope) == nil {
+			apiErr.ResponseCode = envelope.ResponseCode
+			apiErr.ResponseMsg = envelope.ResponseMsg
+		}
+		return nil, apiErr
+	}
+	return raw, nil
+}
+
+// pathf builds a request path, escaping each argument as a path segment.
+func pathf(format string, args ...string) string {
+	escaped := make([]any, len(args))
+	for i, a := range args {
+		escaped[i] = url.PathEscape(a)
+	}
+	return fmt.Sprintf(format, escaped...)
+}
@@ -0,0 +1,44 @@
+package clicksend_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+)
+
+func TestAll(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	client := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	ctx := context.Background()
+	for i := range 3 {
+		if _, err := client.CreateContactList(ctx, &clicksend.ContactList{ListName: fmt.Sprintf("L%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lists, err := clicksend.All(ctx, client.ListContactLists)
+	if err != nil || len(lists) != 3 {
+		t.Fatalf("All = %d lists, %v; want 3", len(lists), err)
+	}
+
+	// An API that always reports another page must not be paged forever.
+	pages := 0
+	_, err = clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.ContactList]], error) {
+		pages++
+		resp, err := client.ListContactLists(ctx, &clicksend.ListOptions{Page: 1, Limit: opts.Limit})
+		if err == nil {
+			resp.Data.LastPage = clicksend.Int(opts.Page + 1)
+		}
+		return resp, err
+	})
+	if err == nil {
+		t.Error("All of an endless collection succeeded")
+	}
+	if pages != clicksend.MaxAllPages {
+		t.Errorf("fetched %d pages, want %d", pages, clicksend.MaxAllPages)
+	}
+}
@@ -0,0 +1,185 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ContactList is a list of contacts.
+type ContactList struct {
+	ListID        Int    `json:"list_id,omitempty"`
+	ListName      string `json:"list_name,omitempty"`
+	ListEmailID   string `json:"list_email_id,omitempty"`
+	ContactsCount Int    `json:"_contacts_count,omitempty"`
+}
+
+// Contact is a contact in a list.
+type Contact struct {
+	ContactID         Int    `json:"contact_id,omitempty"`
+	ListID            Int    `json:"list_id,omitempty"`
+	PhoneNumber       string `json:"phone_number,omitempty"`
+	FirstName         string `json:"first_name,omitempty"`
+	LastName          string `json:"last_name,omitempty"`
+	Email             string `json:"email,omitempty"`
+	FaxNumber         string `json:"fax_number,omitempty"`
+	OrganizationName  string `json:"organization_name,omitempty"`
+	AddressLine1      string `json:"address_line_1,omitempty"`
+	AddressLine2      string `json:"address_line_2,omitempty"`
+	AddressCity       string `json:"address_city,omitempty"`
+	AddressState      string `json:"address_state,omitempty"`
+	AddressPostalCode String `json:"address_postal_code,omitempty"`
+	AddressCountry    string `json:"address_country,omitempty"`
+	Custom1           string `json:"custom_1,omitempty"`
+	Custom2           string `json:"custom_2,omitempty"`
+	Custom3           string `json:"custom_3,omitempty"`
+	Custom4           string `json:"custom_4,omitempty"`
+	DateAdded         Int    `json:"date_added,omitempty"`
+	ListName          string `json:"_list_name,omitempty"`
+}
+
+// ImportField is a contact field a CSV column can be mapped to.
+type ImportField struct {
+	Field string `json:"field"`
+	Label string `json:"label"`
+}
+
+// ContactImport imports contacts from a CSV or Excel file. FieldOrder maps
+// each column of the file to an ImportField.
+type ContactImport struct {
+	FileURL    string   `json:"file_url"`
+	FieldOrder []string `json:"field_order"`
+}
+
+// ContactImportResult identifies a queued contact import.
+type ContactImportResult struct {
+	ID  string   `json:"id"`
+	IDs []String `json:"ids"`
+	Msg string   `json:"msg"`
+}
+
+// CsvPreviewRequest asks for a preview of the first row of a file.
+type CsvPreviewRequest struct {
+	FileURL string `json:"file_url"`
+}
+
+// CsvPreview is the first row of a file to import.
+type CsvPreview struct {
+	Row []string `json:"row"`
+}
+
+// RemoveDuplicatesRequest lists the fields used to detect duplicate contacts.
+type RemoveDuplicatesRequest struct {
+	Fields []string `json:"fields"`
+}
+
+// DeletedCount reports how many items an operation removed.
+type DeletedCount struct {
+	Deleted Int `json:"deleted"`
+}
+
+// SearchResult is a contact or list matching a search.
+type SearchResult struct {
+	ID            Int    `json:"id"`
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	Contact       string `json:"contact"`
+	ContactsCount Int    `json:"contacts_count"`
+}
+
+// ListContactLists lists contact lists.
+func (c *Client) ListContactLists(ctx context.Context, opts *ListOptions) (*Response[Page[ContactList]], error) {
+	return Do[Page[ContactList]](ctx, c, http.MethodGet, "/lists", opts.apply(nil), nil)
+}
+
+// GetContactList returns a contact list.
+func (c *Client) GetContactList(ctx context.Context, listID string) (*Response[ContactList], error) {
+	return Do[ContactList](ctx, c, http.MethodGet, pathf("/lists/%s", listID), nil, nil)
+}
+
+// CreateContactList creates a contact list.
+func (c *Client) CreateContactList(ctx context.Context, req *ContactList) (*Response[ContactList], error) {
+	return Do[ContactList](ctx, c, http.MethodPost, "/lists", nil, req)
+}
+
+// UpdateContactList renames a contact list.
+func (c *Client) UpdateContactList(ctx context.Context, listID string, req *ContactList) (*Response[ContactList], error) {
+	return Do[ContactList](ctx, c, http.MethodPut, pathf("/lists/%s", listID), nil, req)
+}
+
+// DeleteContactList deletes a contact list and its contacts.
+func (c *Client) DeleteContactList(ctx context.Context, listID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/lists/%s", listID), nil, nil)
+}
+
+// ExportContactList exports a contact list to a CSV file and returns its URL.
+func (c *Client) ExportContactList(ctx context.Context, listID, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, pathf("/lists/%s/export", listID), filenameQuery(filename), nil)
+}
+
+// ImportFields lists the fields contacts can be imported into.
+func (c *Client) ImportFields(ctx context.Context, listID string) (*Response[[]ImportField], error) {
+	return Do[[]ImportField](ctx, c, http.MethodGet, pathf("/lists/%s/import-fields", listID), nil, nil)
+}
+
+// ImportContacts queues an import of contacts from a file into a list.
+func (c *Client) ImportContacts(ctx context.Context, listID string, req *ContactImport) (*Response[ContactImportResult], error) {
+	return Do[ContactImportResult](ctx, c, http.MethodPost, pathf("/lists/%s/import", listID), nil, req)
+}
+
+// PreviewCsvImport returns the first row of a file to import into a list.
+func (c *Client) PreviewCsvImport(ctx context.Context, listID string, req *CsvPreviewRequest) (*Response[CsvPreview], error) {
+	return Do[CsvPreview](ctx, c, http.MethodPost, pathf("/lists/%s/import-csv-preview", listID), nil, req)
+}
+
+// RemoveDuplicateContacts deletes contacts in a list that share the given
+// fields.
+func (c *Client) RemoveDuplicateContacts(ctx context.Context, listID string, req *RemoveDuplicatesRequest) (*Response[DeletedCount], error) {
+	return Do[DeletedCount](ctx, c, http.MethodPut, pathf("/lists/%s/remove-duplicates", listID), nil, req)
+}
+
+// RemoveOptedOutContacts deletes contacts in a list that appear in an opt-out
+// list.
+func (c *Client) RemoveOptedOutContacts(ctx context.Context, listID, optOutListID string) (*Response[DeletedCount], error) {
+	return Do[DeletedCount](ctx, c, http.MethodPut, pathf("/lists/%s/remove-opted-out-contacts/%s", listID, optOutListID), nil, nil)
+}
+
+// ListContacts lists the contacts in a list.
+func (c *Client) ListContacts(ctx context.Context, listID string, opts *ListOptions) (*Response[Page[Contact]], error) {
+	return Do[Page[Contact]](ctx, c, http.MethodGet, pathf("/lists/%s/contacts", listID), opts.apply(nil), nil)
+}
+
+// GetContact returns a contact.
+func (c *Client) GetContact(ctx context.Context, listID, contactID string) (*Response[Contact], error) {
+	return Do[Contact](ctx, c, http.MethodGet, pathf("/lists/%s/contacts/%s", listID, contactID), nil, nil)
+}
+
+// CreateContact adds a contact to a list.
+func (c *Client) CreateContact(ctx context.Context, listID string, req *Contact) (*Response[Contact], error) {
+	return Do[Contact](ctx, c, http.MethodPost, pathf("/lists/%s/contacts", listID), nil, req)
+}
+
+// UpdateContact updates a contact.
+func (c *Client) UpdateContact(ctx context.Context, listID, contactID string, req *Contact) (*Response[Contact], error) {
+	return Do[Contact](ctx, c, http.MethodPut, pathf("/lists/%s/contacts/%s", listID, contactID), nil, req)
+}
+
+// DeleteContact deletes a contact.
+func (c *Client) DeleteContact(ctx context.Context, listID, contactID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/lists/%s/contacts/%s", listID, contactID), nil, nil)
+}
+
+// TransferContact moves a contact to another list.
+func (c *Client) TransferContact(ctx context.Context, fromListID, contactID, toListID string) (*Response[Contact], error) {
+	return Do[Contact](ctx, c, http.MethodPut, pathf("/lists/%s/contacts/%s/%s", fromListID, contactID, toListID), nil, nil)
+}
+
+// ContactSuggestions lists contacts suggested as recipients.
+func (c *Client) ContactSuggestions(ctx context.Context) (*Response[[]Contact], error) {
+	return Do[[]Contact](ctx, c, http.MethodGet, "/contact-suggestions", nil, nil)
+}
+
+// SearchContactsLists searches contacts and lists by name or number.
+func (c *Client) SearchContactsLists(ctx context.Context, q string, opts *ListOptions) (*Response[Page[SearchResult]], error) {
+	return Do[Page[SearchResult]](ctx, c, http.MethodGet, "/search/contacts-lists", opts.apply(url.Values{"q": {q}}), nil)
+}
@@ -0,0 +1,31 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// DeliveryIssue reports a message that was not delivered as expected.
+type DeliveryIssue struct {
+	IssueID         Int    `json:"issue_id,omitempty"`
+	MessageID       string `json:"message_id,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Description     string `json:"description,omitempty"`
+	ClientComments  string `json:"client_comments,omitempty"`
+	EmailAddress    string `json:"email_address,omitempty"`
+	Status          string `json:"status,omitempty"`
+	Resolved        Bool   `json:"resolved,omitempty"`
+	SupportComments string `json:"support_comments,omitempty"`
+	DateAdded       Int    `json:"date_added,omitempty"`
+	UserID          Int    `json:"user_id,omitempty"`
+}
+
+// ListDeliveryIssues lists reported delivery issues.
+func (c *Client) ListDeliveryIssues(ctx context.Context, opts *ListOptions) (*Response[Page[DeliveryIssue]], error) {
+	return Do[Page[DeliveryIssue]](ctx, c, http.MethodGet, "/delivery-issues", opts.apply(nil), nil)
+}
+
+// CreateDeliveryIssue reports a delivery issue.
+func (c *Client) CreateDeliveryIssue(ctx context.Context, req *DeliveryIssue) (*Response[DeliveryIssue], error) {
+	return Do[DeliveryIssue](ctx, c, http.MethodPost, "/delivery-issues", nil, req)
+}
@@ -0,0 +1,105 @@
+package clicksend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// EmailRecipient is an address in the to, cc or bcc list of an email.
+type EmailRecipient struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// UnmarshalJSON also accepts a bare email address string.
+func (r *EmailRecipient) UnmarshalJSON(b []byte) error {
+	var email string
+	if json.Unmarshal(b, &email) == nil {
+		*r = EmailRecipient{Email: email}
+		return nil
+	}
+	type plain EmailRecipient
+	return json.Unmarshal(b, (*plain)(r))
+}
+
+// EmailFrom identifies the sender of an email by allowed address ID.
+type EmailFrom struct {
+	EmailAddressID Int    `json:"email_address_id"`
+	Name           string `json:"name,omitempty"`
+}
+
+// EmailAttachment is a file attached to an email.
+type EmailAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+// TransactionalEmail is a single email sent through the transactional email
+// API.
+type TransactionalEmail struct {
+	To          []EmailRecipient  `json:"to"`
+	Cc          []EmailRecipient  `json:"cc,omitempty"`
+	Bcc         []EmailRecipient  `json:"bcc,omitempty"`
+	From        EmailFrom         `json:"from"`
+	Subject     string            `json:"subject,omitempty"`
+	Body        string            `json:"body"`
+	Attachments []EmailAttachment `json:"attachments,omitempty"`
+	Schedule    Int               `json:"schedule,omitempty"`
+}
+
+// SentEmail is a transactional email as reported by send and history
+// endpoints.
+type SentEmail struct {
+	MessageID          string   `json:"message_id"`
+	Subject            string   `json:"subject"`
+	Body               string   `json:"body"`
+	BodyPlainText      string   `json:"body_plain_text"`
+	FromEmailAddressID Int      `json:"from_email_address_id"`
+	FromName           string   `json:"from_name"`
+	Schedule           Int      `json:"schedule"`
+	DateAdded          Int      `json:"date_added"`
+	Price              Float    `json:"price"`
+	Status             string   `json:"status"`
+	CustomString       string   `json:"custom_string"`
+	SoftBounceCount    Int      `json:"soft_bounce_count"`
+	HardBounceCount    Int      `json:"hard_bounce_count"`
+	Currency           Currency `json:"_currency"`
+}
+
+// EmailPrice is the price of a transactional email.
+type EmailPrice struct {
+	Price    Float    `json:"price"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body"`
+	Currency Currency `json:"_currency"`
+}
+
+// SendEmail sends a transactional email.
+func (c *Client) SendEmail(ctx context.Context, req *TransactionalEmail) (*Response[SentEmail], error) {
+	return Do[SentEmail](ctx, c, http.MethodPost, "/email/send", nil, req)
+}
+
+// EmailPrice calculates the price of a transactional email without sending it.
+func (c *Client) EmailPrice(ctx context.Context, req *TransactionalEmail) (*Response[EmailPrice], error) {
+	return Do[EmailPrice](ctx, c, http.MethodPost, "/email/price", nil, req)
+}
+
+// EmailHistory lists sent transactional emails.
+func (c *Client) EmailHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[SentEmail]], error) {
+	return Do[Page[SentEmail]](ctx, c, http.MethodGet, "/email/history", opts.values(), nil)
+}
+
+// ExportEmailHistory exports transactional email history to a CSV file and
+// returns its URL.
+func (c *Client) ExportEmailHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/email/history/export", filenameQuery(filename), nil)
+}
+
+// AddTestEmailReceipt posts a test delivery receipt to url.
+func (c *Client) AddTestEmailReceipt(ctx context.Context, req *URLRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPost, "/email/receipts", nil, req)
+}
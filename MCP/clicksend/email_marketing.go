@@ -0,0 +1,219 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// EmailAddress is an allowed sender address for email campaigns and
+// transactional email.
+type EmailAddress struct {
+	EmailAddressID Int    `json:"email_address_id,omitempty"`
+	EmailAddress   string `json:"email_address"`
+	Verified       Bool   `json:"verified,omitempty"`
+	DateAdded      Int    `json:"date_added,omitempty"`
+}
+
+// EmailCampaign is an email marketing campaign sent to a contact list.
+type EmailCampaign struct {
+	EmailCampaignID    Int    `json:"email_campaign_id,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Subject            string `json:"subject,omitempty"`
+	Body               string `json:"body,omitempty"`
+	ListID             Int    `json:"list_id,omitempty"`
+	TemplateID         Int    `json:"template_id,omitempty"`
+	FromEmailAddressID Int    `json:"from_email_address_id,omitempty"`
+	FromName           string `json:"from_name,omitempty"`
+	Schedule           Int    `json:"schedule,omitempty"`
+
+	Status           string `json:"status,omitempty"`
+	SendCount        Int    `json:"send_count,omitempty"`
+	OpenCount        Int    `json:"open_count,omitempty"`
+	ClickCount       Int    `json:"click_count,omitempty"`
+	SoftBounceCount  Int    `json:"soft_bounce_count,omitempty"`
+	HardBounceCount  Int    `json:"hard_bounce_count,omitempty"`
+	UnsubscribeCount Int    `json:"unsubscribe_count,omitempty"`
+	AbuseCount       Int    `json:"abuse_count,omitempty"`
+	DateAdded        Int    `json:"date_added,omitempty"`
+	UserID           Int    `json:"user_id,omitempty"`
+	SubaccountID     Int    `json:"subaccount_id,omitempty"`
+}
+
+// EmailCampaignResult is returned when sending or pricing an email campaign.
+type EmailCampaignResult struct {
+	TotalPrice  Float         `json:"total_price"`
+	TotalCount  Int           `json:"total_count"`
+	QueuedCount Int           `json:"queued_count"`
+	Campaign    EmailCampaign `json:"data"`
+	Currency    Currency      `json:"currency"`
+}
+
+// EmailCampaignMessage is the delivery record of a campaign email to one
+// contact.
+type EmailCampaignMessage struct {
+	MessageID       string `json:"message_id"`
+	EmailCampaignID Int    `json:"email_campaign_id"`
+	ContactID       Int    `json:"contact_id"`
+	ToAddress       string `json:"to_address"`
+	ToName          string `json:"to_name"`
+	FromAddress     string `json:"from_address"`
+	FromName        string `json:"from_name"`
+	Subject         string `json:"subject"`
+	Status          string `json:"status"`
+	OpenCount       Int    `json:"open_count"`
+	ClickCount      Int    `json:"click_count"`
+	SoftBounceCount Int    `json:"soft_bounce_count"`
+	HardBounceCount Int    `json:"hard_bounce_count"`
+}
+
+// EmailTemplate is a user email template.
+type EmailTemplate struct {
+	TemplateID       Int    `json:"template_id,omitempty"`
+	TemplateName     string `json:"template_name,omitempty"`
+	TemplateIDMaster Int    `json:"template_id_master,omitempty"`
+	Body             string `json:"body,omitempty"`
+}
+
+// MasterTemplate is a predefined email template user templates are created
+// from.
+type MasterTemplate struct {
+	TemplateIDMaster Int               `json:"template_id_master"`
+	TemplateName     string            `json:"template_name"`
+	DateAdded        Int               `json:"date_added"`
+	Thumbnail        map[string]string `json:"thumbnail"`
+}
+
+// TemplateCategory groups master templates.
+type TemplateCategory struct {
+	CategoryID Int    `json:"category_id"`
+	Name       string `json:"name"`
+}
+
+// TemplateImage uploads an image to a template, either from a URL or as an
+// image file.
+type TemplateImage struct {
+	URL   string `json:"url,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+// ListEmailAddresses lists allowed sender email addresses.
+func (c *Client) ListEmailAddresses(ctx context.Context, opts *ListOptions) (*Response[Page[EmailAddress]], error) {
+	return Do[Page[EmailAddress]](ctx, c, http.MethodGet, "/email/addresses", opts.apply(nil), nil)
+}
+
+// GetEmailAddress returns an allowed sender email address.
+func (c *Client) GetEmailAddress(ctx context.Context, emailAddressID string) (*Response[EmailAddress], error) {
+	return Do[EmailAddress](ctx, c, http.MethodGet, pathf("/email/addresses/%s", emailAddressID), nil, nil)
+}
+
+// CreateEmailAddress adds an allowed sender email address.
+func (c *Client) CreateEmailAddress(ctx context.Context, req *EmailAddress) (*Response[EmailAddress], error) {
+	return Do[EmailAddress](ctx, c, http.MethodPost, "/email/addresses", nil, req)
+}
+
+// DeleteEmailAddress removes an allowed sender email address.
+func (c *Client) DeleteEmailAddress(ctx context.Context, emailAddressID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/email/addresses/%s", emailAddressID), nil, nil)
+}
+
+// SendEmailVerification sends a verification email to an allowed address.
+func (c *Client) SendEmailVerification(ctx context.Context, emailAddressID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/email/address-verify/%s/send", emailAddressID), nil, nil)
+}
+
+// VerifyEmailAddress verifies an allowed address with the emailed token.
+func (c *Client) VerifyEmailAddress(ctx context.Context, emailAddressID, activationToken string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/email/address-verify/%s/verify/%s", emailAddressID, activationToken), nil, nil)
+}
+
+// ListEmailCampaigns lists email campaigns.
+func (c *Client) ListEmailCampaigns(ctx context.Context, opts *ListOptions) (*Response[Page[EmailCampaign]], error) {
+	return Do[Page[EmailCampaign]](ctx, c, http.MethodGet, "/email-campaigns", opts.apply(nil), nil)
+}
+
+// GetEmailCampaign returns an email campaign.
+func (c *Client) GetEmailCampaign(ctx context.Context, emailCampaignID string) (*Response[EmailCampaign], error) {
+	return Do[EmailCampaign](ctx, c, http.MethodGet, pathf("/email-campaigns/%s", emailCampaignID), nil, nil)
+}
+
+// SendEmailCampaign sends or schedules an email campaign.
+func (c *Client) SendEmailCampaign(ctx context.Context, req *EmailCampaign) (*Response[EmailCampaignResult], error) {
+	return Do[EmailCampaignResult](ctx, c, http.MethodPost, "/email-campaigns/send", nil, req)
+}
+
+// EmailCampaignPrice calculates the price of an email campaign without
+// sending it.
+func (c *Client) EmailCampaignPrice(ctx context.Context, req *EmailCampaign) (*Response[EmailCampaignResult], error) {
+	return Do[EmailCampaignResult](ctx, c, http.MethodPost, "/email-campaigns/price", nil, req)
+}
+
+// UpdateEmailCampaign updates a scheduled email campaign.
+func (c *Client) UpdateEmailCampaign(ctx context.Context, emailCampaignID string, req *EmailCampaign) (*Response[EmailCampaign], error) {
+	return Do[EmailCampaign](ctx, c, http.MethodPut, pathf("/email-campaigns/%s", emailCampaignID), nil, req)
+}
+
+// CancelEmailCampaign cancels a scheduled email campaign.
+func (c *Client) CancelEmailCampaign(ctx context.Context, emailCampaignID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/email-campaigns/%s/cancel", emailCampaignID), nil, nil)
+}
+
+// EmailCampaignHistory lists the emails sent by a campaign.
+func (c *Client) EmailCampaignHistory(ctx context.Context, campaignID string, opts *HistoryOptions) (*Response[Page[EmailCampaignMessage]], error) {
+	return Do[Page[EmailCampaignMessage]](ctx, c, http.MethodGet, pathf("/email-campaigns/%s/history", campaignID), opts.values(), nil)
+}
+
+// ListEmailTemplates lists user email templates.
+func (c *Client) ListEmailTemplates(ctx context.Context, opts *ListOptions) (*Response[Page[EmailTemplate]], error) {
+	return Do[Page[EmailTemplate]](ctx, c, http.MethodGet, "/email/templates", opts.apply(nil), nil)
+}
+
+// GetEmailTemplate returns a user email template.
+func (c *Client) GetEmailTemplate(ctx context.Context, templateID string) (*Response[EmailTemplate], error) {
+	return Do[EmailTemplate](ctx, c, http.MethodGet, pathf("/email/templates/%s", templateID), nil, nil)
+}
+
+// CreateEmailTemplate creates a user email template from a master template.
+func (c *Client) CreateEmailTemplate(ctx context.Context, req *EmailTemplate) (*Response[EmailTemplate], error) {
+	return Do[EmailTemplate](ctx, c, http.MethodPost, "/email/templates", nil, req)
+}
+
+// UpdateEmailTemplate updates a user email template.
+func (c *Client) UpdateEmailTemplate(ctx context.Context, templateID string, req *EmailTemplate) (*Response[EmailTemplate], error) {
+	return Do[EmailTemplate](ctx, c, http.MethodPut, pathf("/email/templates/%s", templateID), nil, req)
+}
+
+// DeleteEmailTemplate deletes a user email template.
+func (c *Client) DeleteEmailTemplate(ctx context.Context, templateID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/email/templates/%s", templateID), nil, nil)
+}
+
+// UploadTemplateImage uploads an image for use in a user email template and
+// returns its URL.
+func (c *Client) UploadTemplateImage(ctx context.Context, templateID string, req *TemplateImage) (*Response[string], error) {
+	return Do[string](ctx, c, http.MethodPost, pathf("/email/templates-images/%s", templateID), nil, req)
+}
+
+// ListMasterTemplates lists master email templates.
+func (c *Client) ListMasterTemplates(ctx context.Context) (*Response[[]MasterTemplate], error) {
+	return Do[[]MasterTemplate](ctx, c, http.MethodGet, "/email/master-templates", nil, nil)
+}
+
+// GetMasterTemplate returns a master email template.
+func (c *Client) GetMasterTemplate(ctx context.Context, templateID string) (*Response[MasterTemplate], error) {
+	return Do[MasterTemplate](ctx, c, http.MethodGet, pathf("/email/master-templates/%s", templateID), nil, nil)
+}
+
+// ListTemplateCategories lists master template categories.
+func (c *Client) ListTemplateCategories(ctx context.Context) (*Response[[]TemplateCategory], error) {
+	return Do[[]TemplateCategory](ctx, c, http.MethodGet, "/email/master-templates-categories", nil, nil)
+}
+
+// GetTemplateCategory returns a master template category.
+func (c *Client) GetTemplateCategory(ctx context.Context, categoryID string) (*Response[TemplateCategory], error) {
+	return Do[TemplateCategory](ctx, c, http.MethodGet, pathf("/email/master-templates-categories/%s", categoryID), nil, nil)
+}
+
+// ListCategoryTemplates lists the master templates in a category.
+func (c *Client) ListCategoryTemplates(ctx context.Context, categoryID string) (*Response[[]MasterTemplate], error) {
+	return Do[[]MasterTemplate](ctx, c, http.MethodGet, pathf("/email/master-templates-categories/%s/master-templates", categoryID), nil, nil)
+}
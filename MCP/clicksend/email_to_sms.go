@@ -0,0 +1,69 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// EmailToSmsAddress is an email address allowed to send SMS by email.
+type EmailToSmsAddress struct {
+	EmailAddressID Int    `json:"email_address_id,omitempty"`
+	EmailAddress   string `json:"email_address,omitempty"`
+	From           string `json:"from,omitempty"`
+}
+
+// StrippedString is text removed from email-to-SMS messages.
+type StrippedString struct {
+	RuleID      Int    `json:"rule_id,omitempty"`
+	StripString string `json:"strip_string,omitempty"`
+}
+
+// ListEmailToSmsAddresses lists email-to-SMS allowed addresses.
+func (c *Client) ListEmailToSmsAddresses(ctx context.Context, opts *ListOptions) (*Response[Page[EmailToSmsAddress]], error) {
+	return Do[Page[EmailToSmsAddress]](ctx, c, http.MethodGet, "/sms/email-sms", opts.apply(nil), nil)
+}
+
+// GetEmailToSmsAddress returns an email-to-SMS allowed address.
+func (c *Client) GetEmailToSmsAddress(ctx context.Context, emailAddressID string) (*Response[EmailToSmsAddress], error) {
+	return Do[EmailToSmsAddress](ctx, c, http.MethodGet, pathf("/sms/email-sms/%s", emailAddressID), nil, nil)
+}
+
+// CreateEmailToSmsAddress adds an email-to-SMS allowed address.
+func (c *Client) CreateEmailToSmsAddress(ctx context.Context, req *EmailToSmsAddress) (*Response[EmailToSmsAddress], error) {
+	return Do[EmailToSmsAddress](ctx, c, http.MethodPost, "/sms/email-sms", nil, req)
+}
+
+// UpdateEmailToSmsAddress updates an email-to-SMS allowed address.
+func (c *Client) UpdateEmailToSmsAddress(ctx context.Context, emailAddressID string, req *EmailToSmsAddress) (*Response[EmailToSmsAddress], error) {
+	return Do[EmailToSmsAddress](ctx, c, http.MethodPut, pathf("/sms/email-sms/%s", emailAddressID), nil, req)
+}
+
+// DeleteEmailToSmsAddress removes an email-to-SMS allowed address.
+func (c *Client) DeleteEmailToSmsAddress(ctx context.Context, emailAddressID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/sms/email-sms/%s", emailAddressID), nil, nil)
+}
+
+// ListStrippedStrings lists email-to-SMS stripped strings.
+func (c *Client) ListStrippedStrings(ctx context.Context, opts *ListOptions) (*Response[Page[StrippedString]], error) {
+	return Do[Page[StrippedString]](ctx, c, http.MethodGet, "/sms/email-sms-stripped-strings", opts.apply(nil), nil)
+}
+
+// GetStrippedString returns an email-to-SMS stripped string.
+func (c *Client) GetStrippedString(ctx context.Context, ruleID string) (*Response[StrippedString], error) {
+	return Do[StrippedString](ctx, c, http.MethodGet, pathf("/sms/email-sms-stripped-strings/%s", ruleID), nil, nil)
+}
+
+// CreateStrippedString adds an email-to-SMS stripped string.
+func (c *Client) CreateStrippedString(ctx context.Context, req *StrippedString) (*Response[StrippedString], error) {
+	return Do[StrippedString](ctx, c, http.MethodPost, "/sms/email-sms-stripped-strings", nil, req)
+}
+
+// UpdateStrippedString updates an email-to-SMS stripped string.
+func (c *Client) UpdateStrippedString(ctx context.Context, ruleID string, req *StrippedString) (*Response[StrippedString], error) {
+	return Do[StrippedString](ctx, c, http.MethodPut, pathf("/sms/email-sms-stripped-strings/%s", ruleID), nil, req)
+}
+
+// DeleteStrippedString deletes an email-to-SMS stripped string.
+func (c *Client) DeleteStrippedString(ctx context.Context, ruleID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/sms/email-sms-stripped-strings/%s", ruleID), nil, nil)
+}
@@ -0,0 +1,73 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// FaxMessage is an outbound fax. Fields such as MessageID and Status are only
+// set in responses.
+type FaxMessage struct {
+	To           string `json:"to,omitempty"`
+	ListID       Int    `json:"list_id,omitempty"`
+	From         string `json:"from,omitempty"`
+	FromEmail    string `json:"from_email,omitempty"`
+	Schedule     Int    `json:"schedule,omitempty"`
+	Country      string `json:"country,omitempty"`
+	CustomString string `json:"custom_string,omitempty"`
+	Source       string `json:"source,omitempty"`
+
+	MessageID    string `json:"message_id,omitempty"`
+	DateAdded    Int    `json:"date_added,omitempty"`
+	Carrier      string `json:"carrier,omitempty"`
+	MessagePages Int    `json:"message_pages,omitempty"`
+	MessagePrice Float  `json:"message_price,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// FaxMessageCollection is the request body for sending or pricing faxes. The
+// PDF at FileURL is sent to every recipient.
+type FaxMessageCollection struct {
+	FileURL  string       `json:"file_url"`
+	Messages []FaxMessage `json:"messages"`
+}
+
+// SendFax sends a fax to one or more recipients.
+func (c *Client) SendFax(ctx context.Context, req *FaxMessageCollection) (*Response[SendResult[FaxMessage]], error) {
+	return Do[SendResult[FaxMessage]](ctx, c, http.MethodPost, "/fax/send", nil, req)
+}
+
+// FaxPrice calculates the price of faxes without sending them.
+func (c *Client) FaxPrice(ctx context.Context, req *FaxMessageCollection) (*Response[SendResult[FaxMessage]], error) {
+	return Do[SendResult[FaxMessage]](ctx, c, http.MethodPost, "/fax/price", nil, req)
+}
+
+// FaxHistory lists sent faxes.
+func (c *Client) FaxHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[FaxMessage]], error) {
+	return Do[Page[FaxMessage]](ctx, c, http.MethodGet, "/fax/history", opts.values(), nil)
+}
+
+// ExportFaxHistory exports fax history to a CSV file and returns its URL.
+func (c *Client) ExportFaxHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/fax/history/export", filenameQuery(filename), nil)
+}
+
+// ListFaxReceipts lists fax delivery receipts.
+func (c *Client) ListFaxReceipts(ctx context.Context, opts *ListOptions) (*Response[Page[DeliveryReceipt]], error) {
+	return Do[Page[DeliveryReceipt]](ctx, c, http.MethodGet, "/fax/receipts", opts.apply(nil), nil)
+}
+
+// GetFaxReceipt returns the delivery receipt for a fax.
+func (c *Client) GetFaxReceipt(ctx context.Context, messageID string) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodGet, pathf("/fax/receipts/%s", messageID), nil, nil)
+}
+
+// AddTestFaxReceipt posts a test delivery receipt to url.
+func (c *Client) AddTestFaxReceipt(ctx context.Context, req *URLRequest) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodPost, "/fax/receipts", nil, req)
+}
+
+// MarkFaxReceiptsRead marks fax delivery receipts as read.
+func (c *Client) MarkFaxReceiptsRead(ctx context.Context, req *DateBeforeRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/fax/receipts-read", nil, req)
+}
@@ -0,0 +1,80 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// MmsMessage is an outbound MMS. Fields such as MessageID and Status are only
+// set in responses.
+type MmsMessage struct {
+	To           string `json:"to,omitempty"`
+	ListID       Int    `json:"list_id,omitempty"`
+	Subject      string `json:"subject"`
+	Body         string `json:"body"`
+	From         string `json:"from,omitempty"`
+	FromEmail    string `json:"from_email,omitempty"`
+	Schedule     Int    `json:"schedule,omitempty"`
+	Country      string `json:"country,omitempty"`
+	CustomString string `json:"custom_string,omitempty"`
+	Source       string `json:"source,omitempty"`
+
+	MessageID    string `json:"message_id,omitempty"`
+	ContactID    Int    `json:"contact_id,omitempty"`
+	MediaFileURL string `json:"_media_file_url,omitempty"`
+	MessageParts Float  `json:"message_parts,omitempty"`
+	MessagePrice Float  `json:"message_price,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// MmsMessageCollection is the request body for sending or pricing MMS. The
+// media file is shared by all messages.
+type MmsMessageCollection struct {
+	MediaFile string       `json:"media_file"`
+	Messages  []MmsMessage `json:"messages"`
+}
+
+// SendMms sends one or more MMS messages.
+func (c *Client) SendMms(ctx context.Context, req *MmsMessageCollection) (*Response[SendResult[MmsMessage]], error) {
+	return Do[SendResult[MmsMessage]](ctx, c, http.MethodPost, "/mms/send", nil, req)
+}
+
+// MmsPrice calculates the price of MMS messages without sending them.
+func (c *Client) MmsPrice(ctx context.Context, req *MmsMessageCollection) (*Response[SendResult[MmsMessage]], error) {
+	return Do[SendResult[MmsMessage]](ctx, c, http.MethodPost, "/mms/price", nil, req)
+}
+
+// MmsHistory lists sent MMS messages.
+func (c *Client) MmsHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[MmsMessage]], error) {
+	return Do[Page[MmsMessage]](ctx, c, http.MethodGet, "/mms/history", opts.values(), nil)
+}
+
+// ExportMmsHistory exports MMS history to a CSV file and returns its URL.
+func (c *Client) ExportMmsHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/mms/history/export", filenameQuery(filename), nil)
+}
+
+// CancelMms cancels a scheduled MMS.
+func (c *Client) CancelMms(ctx context.Context, messageID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/mms/%s/cancel", messageID), nil, nil)
+}
+
+// CancelAllMms cancels all scheduled MMS.
+func (c *Client) CancelAllMms(ctx context.Context) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/mms/cancel-all", nil, nil)
+}
+
+// ListMmsReceipts lists MMS delivery receipts.
+func (c *Client) ListMmsReceipts(ctx context.Context, opts *ListOptions) (*Response[Page[DeliveryReceipt]], error) {
+	return Do[Page[DeliveryReceipt]](ctx, c, http.MethodGet, "/mms/receipts", opts.apply(nil), nil)
+}
+
+// GetMmsReceipt returns the delivery receipt for an MMS.
+func (c *Client) GetMmsReceipt(ctx context.Context, messageID string) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodGet, pathf("/mms/receipts/%s", messageID), nil, nil)
+}
+
+// MarkMmsReceiptsRead marks MMS delivery receipts as read.
+func (c *Client) MarkMmsReceiptsRead(ctx context.Context, req *DateBeforeRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/mms/receipts-read", nil, req)
+}
@@ -0,0 +1,62 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// DedicatedNumber is a dedicated number owned by the account or available to
+// buy.
+type DedicatedNumber struct {
+	DedicatedNumber string `json:"dedicated_number"`
+	Country         string `json:"country"`
+	CountryName     string `json:"country_name,omitempty"`
+	Currency        string `json:"currency,omitempty"`
+	Price           Float  `json:"price,omitempty"`
+}
+
+// NumberPurchase is returned after buying a dedicated number.
+type NumberPurchase struct {
+	DedicatedNumber string   `json:"dedicated_number"`
+	Country         string   `json:"country"`
+	PriceTotal      Float    `json:"price_total"`
+	PriceMonthly    Float    `json:"_price_monthly"`
+	PriceSetup      Float    `json:"_price_setup"`
+	Currency        Currency `json:"_currency"`
+}
+
+// NumberSearchOptions filters dedicated numbers available to buy. SearchType
+// is 0 (starts with), 1 (anywhere) or 2 (ends with).
+type NumberSearchOptions struct {
+	Search     string
+	SearchType string
+	ListOptions
+}
+
+// ListDedicatedNumbers lists the account's dedicated numbers.
+func (c *Client) ListDedicatedNumbers(ctx context.Context, opts *ListOptions) (*Response[Page[DedicatedNumber]], error) {
+	return Do[Page[DedicatedNumber]](ctx, c, http.MethodGet, "/numbers", opts.apply(nil), nil)
+}
+
+// SearchDedicatedNumbers searches the dedicated numbers available to buy in a
+// country.
+func (c *Client) SearchDedicatedNumbers(ctx context.Context, country string, opts *NumberSearchOptions) (*Response[Page[DedicatedNumber]], error) {
+	q := url.Values{}
+	var list *ListOptions
+	if opts != nil {
+		if opts.Search != "" {
+			q.Set("search", opts.Search)
+		}
+		if opts.SearchType != "" {
+			q.Set("search_type", opts.SearchType)
+		}
+		list = &opts.ListOptions
+	}
+	return Do[Page[DedicatedNumber]](ctx, c, http.MethodGet, pathf("/numbers/search/%s", country), list.apply(q), nil)
+}
+
+// BuyDedicatedNumber buys a dedicated number.
+func (c *Client) BuyDedicatedNumber(ctx context.Context, dedicatedNumber string) (*Response[NumberPurchase], error) {
+	return Do[NumberPurchase](ctx, c, http.MethodPost, pathf("/numbers/buy/%s", dedicatedNumber), nil, nil)
+}
@@ -0,0 +1,221 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Address is a postal address.
+type Address struct {
+	AddressName       string `json:"address_name,omitempty"`
+	AddressLine1      string `json:"address_line_1,omitempty"`
+	AddressLine2      string `json:"address_line_2,omitempty"`
+	AddressCity       string `json:"address_city,omitempty"`
+	AddressState      string `json:"address_state,omitempty"`
+	AddressPostalCode String `json:"address_postal_code,omitempty"`
+	AddressCountry    string `json:"address_country,omitempty"`
+}
+
+// ReturnAddress is a saved return address for letters and postcards.
+type ReturnAddress struct {
+	ReturnAddressID Int `json:"return_address_id,omitempty"`
+	UserID          Int `json:"user_id,omitempty"`
+	Address
+}
+
+// PostRecipient is the recipient of a letter or postcard. Fields such as
+// MessageID and Status are only set in responses.
+type PostRecipient struct {
+	Address
+	ReturnAddressID Int    `json:"return_address_id,omitempty"`
+	Schedule        Int    `json:"schedule,omitempty"`
+	CustomString    string `json:"custom_string,omitempty"`
+
+	MessageID     string         `json:"message_id,omitempty"`
+	Status        string         `json:"status,omitempty"`
+	StatusCode    string         `json:"status_code,omitempty"`
+	StatusText    string         `json:"status_text,omitempty"`
+	PostPages     Int            `json:"post_pages,omitempty"`
+	PostPrice     Float          `json:"post_price,omitempty"`
+	PriorityPost  Bool           `json:"priority_post,omitempty"`
+	Colour        Bool           `json:"colour,omitempty"`
+	Duplex        Bool           `json:"duplex,omitempty"`
+	DateAdded     Int            `json:"date_added,omitempty"`
+	FileURL       string         `json:"_file_url,omitempty"`
+	ReturnAddress *ReturnAddress `json:"_return_address,omitempty"`
+}
+
+// PostLetter is a letter sent by post to one or more recipients.
+type PostLetter struct {
+	FileURL      string          `json:"file_url"`
+	TemplateUsed *Bool           `json:"template_used,omitempty"`
+	Colour       *Bool           `json:"colour,omitempty"`
+	Duplex       *Bool           `json:"duplex,omitempty"`
+	PriorityPost *Bool           `json:"priority_post,omitempty"`
+	Recipients   []PostRecipient `json:"recipients"`
+}
+
+// Postcard is a postcard sent to one or more recipients. FileURLs holds the
+// front and back of the card.
+type Postcard struct {
+	FileURLs   []string        `json:"file_urls"`
+	Recipients []PostRecipient `json:"recipients"`
+}
+
+// PostResult is returned when sending or pricing letters and postcards.
+type PostResult struct {
+	TotalPrice  Float           `json:"total_price"`
+	TotalCount  Int             `json:"total_count"`
+	QueuedCount Int             `json:"queued_count"`
+	Recipients  []PostRecipient `json:"recipients"`
+	Currency    Currency        `json:"_currency"`
+}
+
+// DetectAddressRequest extracts an address either from a base64-encoded PDF
+// or from free text.
+type DetectAddressRequest struct {
+	Content string `json:"content,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// DirectMailArea is a location and quantity targeted by a direct mail
+// campaign.
+type DirectMailArea struct {
+	LocationID Int    `json:"location_id"`
+	Quantity   Int    `json:"quantity,omitempty"`
+	CampaignID Int    `json:"campaign_id,omitempty"`
+	Price      Float  `json:"price,omitempty"`
+	Status     string `json:"status,omitempty"`
+}
+
+// DirectMailCampaign is an unaddressed mail campaign delivered to areas.
+type DirectMailCampaign struct {
+	Name     string           `json:"name,omitempty"`
+	Size     string           `json:"size,omitempty"`
+	FileURLs []string         `json:"file_urls,omitempty"`
+	Areas    []DirectMailArea `json:"areas,omitempty"`
+	Schedule Int              `json:"schedule,omitempty"`
+	Source   string           `json:"source,omitempty"`
+
+	CampaignID    Int              `json:"campaign_id,omitempty"`
+	Status        string           `json:"status,omitempty"`
+	CustomString  string           `json:"custom_string,omitempty"`
+	DateAdded     Int              `json:"date_added,omitempty"`
+	TotalQuantity Int              `json:"_total_quantity,omitempty"`
+	AreaDetails   []DirectMailArea `json:"_areas,omitempty"`
+}
+
+// DirectMailResult is returned when sending or pricing a direct mail
+// campaign.
+type DirectMailResult struct {
+	TotalPrice    Float              `json:"total_price"`
+	TotalQuantity Int                `json:"total_quantity"`
+	Campaign      DirectMailCampaign `json:"data"`
+	Currency      Currency           `json:"currency"`
+}
+
+// DirectMailLocation is a postal area direct mail can be delivered to.
+type DirectMailLocation struct {
+	LocationID  Int    `json:"location_id"`
+	PlaceName   string `json:"place_name"`
+	PostalCode  String `json:"postal_code"`
+	CountryCode string `json:"country_code"`
+	Latitude    Float  `json:"latitude"`
+	Longitude   Float  `json:"longitude"`
+	Accuracy    Int    `json:"accuracy"`
+}
+
+// SendPostLetter sends a letter.
+func (c *Client) SendPostLetter(ctx context.Context, req *PostLetter) (*Response[PostResult], error) {
+	return Do[PostResult](ctx, c, http.MethodPost, "/post/letters/send", nil, req)
+}
+
+// PostLetterPrice calculates the price of a letter without sending it.
+func (c *Client) PostLetterPrice(ctx context.Context, req *PostLetter) (*Response[PostResult], error) {
+	return Do[PostResult](ctx, c, http.MethodPost, "/post/letters/price", nil, req)
+}
+
+// PostLetterHistory lists sent letters.
+func (c *Client) PostLetterHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[PostRecipient]], error) {
+	return Do[Page[PostRecipient]](ctx, c, http.MethodGet, "/post/letters/history", opts.values(), nil)
+}
+
+// ExportPostLetterHistory exports letter history to a CSV file and returns
+// its URL.
+func (c *Client) ExportPostLetterHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/post/letters/history/export", filenameQuery(filename), nil)
+}
+
+// DetectAddress extracts the recipient address from a letter.
+func (c *Client) DetectAddress(ctx context.Context, req *DetectAddressRequest) (*Response[Address], error) {
+	return Do[Address](ctx, c, http.MethodPost, "/post/letters/detect-address", nil, req)
+}
+
+// SendPostcard sends a postcard.
+func (c *Client) SendPostcard(ctx context.Context, req *Postcard) (*Response[PostResult], error) {
+	return Do[PostResult](ctx, c, http.MethodPost, "/post/postcards/send", nil, req)
+}
+
+// PostcardPrice calculates the price of a postcard without sending it.
+func (c *Client) PostcardPrice(ctx context.Context, req *Postcard) (*Response[PostResult], error) {
+	return Do[PostResult](ctx, c, http.MethodPost, "/post/postcards/price", nil, req)
+}
+
+// PostcardHistory lists sent postcards.
+func (c *Client) PostcardHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[PostRecipient]], error) {
+	return Do[Page[PostRecipient]](ctx, c, http.MethodGet, "/post/postcards/history", opts.values(), nil)
+}
+
+// ExportPostcardHistory exports postcard history to a CSV file and returns
+// its URL.
+func (c *Client) ExportPostcardHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/post/postcards/export", filenameQuery(filename), nil)
+}
+
+// ListReturnAddresses lists saved return addresses.
+func (c *Client) ListReturnAddresses(ctx context.Context, opts *ListOptions) (*Response[Page[ReturnAddress]], error) {
+	return Do[Page[ReturnAddress]](ctx, c, http.MethodGet, "/post/return-addresses", opts.apply(nil), nil)
+}
+
+// GetReturnAddress returns a saved return address.
+func (c *Client) GetReturnAddress(ctx context.Context, returnAddressID string) (*Response[ReturnAddress], error) {
+	return Do[ReturnAddress](ctx, c, http.MethodGet, pathf("/post/return-addresses/%s", returnAddressID), nil, nil)
+}
+
+// CreateReturnAddress saves a return address.
+func (c *Client) CreateReturnAddress(ctx context.Context, req *Address) (*Response[ReturnAddress], error) {
+	return Do[ReturnAddress](ctx, c, http.MethodPost, "/post/return-addresses", nil, req)
+}
+
+// UpdateReturnAddress updates a saved return address.
+func (c *Client) UpdateReturnAddress(ctx context.Context, returnAddressID string, req *Address) (*Response[ReturnAddress], error) {
+	return Do[ReturnAddress](ctx, c, http.MethodPut, pathf("/post/return-addresses/%s", returnAddressID), nil, req)
+}
+
+// DeleteReturnAddress deletes a saved return address.
+func (c *Client) DeleteReturnAddress(ctx context.Context, returnAddressID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/post/return-addresses/%s", returnAddressID), nil, nil)
+}
+
+// ListDirectMailCampaigns lists direct mail campaigns.
+func (c *Client) ListDirectMailCampaigns(ctx context.Context, opts *ListOptions) (*Response[Page[DirectMailCampaign]], error) {
+	return Do[Page[DirectMailCampaign]](ctx, c, http.MethodGet, "/post/direct-mail/campaigns", opts.apply(nil), nil)
+}
+
+// SendDirectMailCampaign creates a direct mail campaign.
+func (c *Client) SendDirectMailCampaign(ctx context.Context, req *DirectMailCampaign) (*Response[DirectMailResult], error) {
+	return Do[DirectMailResult](ctx, c, http.MethodPost, "/post/direct-mail/campaigns/send", nil, req)
+}
+
+// DirectMailCampaignPrice calculates the price of a direct mail campaign
+// without creating it.
+func (c *Client) DirectMailCampaignPrice(ctx context.Context, req *DirectMailCampaign) (*Response[DirectMailResult], error) {
+	return Do[DirectMailResult](ctx, c, http.MethodPost, "/post/direct-mail/campaigns/price", nil, req)
+}
+
+// SearchDirectMailLocations searches the areas of a country direct mail can
+// be delivered to.
+func (c *Client) SearchDirectMailLocations(ctx context.Context, country, query string, opts *ListOptions) (*Response[Page[DirectMailLocation]], error) {
+	return Do[Page[DirectMailLocation]](ctx, c, http.MethodGet, pathf("/post/direct-mail/locations/search/%s/", country), opts.apply(url.Values{"q": {query}}), nil)
+}
@@ -0,0 +1,48 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Country is a country supported by ClickSend.
+type Country struct {
+	Code  string `json:"code"`
+	Value string `json:"value"`
+}
+
+// ListCountries lists supported countries.
+func (c *Client) ListCountries(ctx context.Context) (*Response[[]Country], error) {
+	return Do[[]Country](ctx, c, http.MethodGet, "/countries", nil, nil)
+}
+
+// ListTimezones lists supported timezone names.
+func (c *Client) ListTimezones(ctx context.Context) (*Response[[]string], error) {
+	return Do[[]string](ctx, c, http.MethodGet, "/timezones", nil, nil)
+}
+
+// CountryPricing returns the prices of every channel in a country, in
+// currency when given.
+func (c *Client) CountryPricing(ctx context.Context, country, currency string) (*Response[Object], error) {
+	q := url.Values{}
+	if currency != "" {
+		q.Set("currency", currency)
+	}
+	return Do[Object](ctx, c, http.MethodGet, pathf("/pricing/%s", country), q, nil)
+}
+
+// SmsStatistics returns daily SMS counts and costs.
+func (c *Client) SmsStatistics(ctx context.Context) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodGet, "/statistics/sms", nil, nil)
+}
+
+// VoiceStatistics returns daily call counts and costs.
+func (c *Client) VoiceStatistics(ctx context.Context) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodGet, "/statistics/voice", nil, nil)
+}
+
+// SdkDownload returns a download link for an SDK in the given language.
+func (c *Client) SdkDownload(ctx context.Context, sdkType string) (*Response[any], error) {
+	return Do[any](ctx, c, http.MethodGet, pathf("/sdk-download/%s", sdkType), nil, nil)
+}
@@ -0,0 +1,96 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResellerSettings configures a reseller's white-label portal.
+type ResellerSettings struct {
+	Subdomain            string `json:"subdomain,omitempty"`
+	CompanyName          string `json:"company_name,omitempty"`
+	ColourNavigation     string `json:"colour_navigation,omitempty"`
+	LogoURLLight         string `json:"logo_url_light,omitempty"`
+	LogoURLDark          string `json:"logo_url_dark,omitempty"`
+	DefaultMargin        Float  `json:"default_margin,omitempty"`
+	DefaultMarginNumbers Float  `json:"default_margin_numbers,omitempty"`
+	AllowPublicSignups   *Bool  `json:"allow_public_signups,omitempty"`
+	TrialBalance         Float  `json:"trial_balance,omitempty"`
+	ResellerUserID       Int    `json:"reseller_user_id,omitempty"`
+}
+
+// ResellerAccount is a client account of a reseller. ResellerUserID is only
+// used when signing up publicly.
+type ResellerAccount struct {
+	Account
+	ClientUserID   Int `json:"client_user_id,omitempty"`
+	ResellerUserID Int `json:"reseller_user_id,omitempty"`
+}
+
+// CreditTransfer moves credit from a reseller to a client account.
+type CreditTransfer struct {
+	ClientUserID Int    `json:"client_user_id"`
+	Balance      Float  `json:"balance"`
+	Currency     string `json:"currency"`
+}
+
+// ReferralAccount is an account referred by the authenticated account.
+type ReferralAccount struct {
+	ReferralRuleID     Int   `json:"referral_rule_id"`
+	ReferedUserID      Int   `json:"refered_user_id"`
+	PercentageReferral Float `json:"percentage_referral"`
+	DateReferred       Int   `json:"date_referred"`
+}
+
+// GetResellerSettings returns the reseller settings of the authenticated
+// account.
+func (c *Client) GetResellerSettings(ctx context.Context) (*Response[[]ResellerSettings], error) {
+	return Do[[]ResellerSettings](ctx, c, http.MethodGet, "/reseller", nil, nil)
+}
+
+// UpdateResellerSettings updates the reseller settings of the authenticated
+// account.
+func (c *Client) UpdateResellerSettings(ctx context.Context, req *ResellerSettings) (*Response[ResellerSettings], error) {
+	return Do[ResellerSettings](ctx, c, http.MethodPut, "/reseller", nil, req)
+}
+
+// GetResellerBySubdomain returns the public settings of a reseller portal.
+func (c *Client) GetResellerBySubdomain(ctx context.Context, subdomain string) (*Response[ResellerSettings], error) {
+	return Do[ResellerSettings](ctx, c, http.MethodGet, pathf("/reseller/%s", subdomain), nil, nil)
+}
+
+// ListResellerAccounts lists the client accounts of a reseller.
+func (c *Client) ListResellerAccounts(ctx context.Context, opts *ListOptions) (*Response[Page[ResellerAccount]], error) {
+	return Do[Page[ResellerAccount]](ctx, c, http.MethodGet, "/reseller/accounts", opts.apply(nil), nil)
+}
+
+// GetResellerAccount returns a client account.
+func (c *Client) GetResellerAccount(ctx context.Context, clientUserID string) (*Response[ResellerAccount], error) {
+	return Do[ResellerAccount](ctx, c, http.MethodGet, pathf("/reseller/accounts/%s", clientUserID), nil, nil)
+}
+
+// CreateResellerAccount creates a client account.
+func (c *Client) CreateResellerAccount(ctx context.Context, req *ResellerAccount) (*Response[ResellerAccount], error) {
+	return Do[ResellerAccount](ctx, c, http.MethodPost, "/reseller/accounts", nil, req)
+}
+
+// CreatePublicResellerAccount signs up a client account through a
+// reseller's public portal.
+func (c *Client) CreatePublicResellerAccount(ctx context.Context, req *ResellerAccount) (*Response[ResellerAccount], error) {
+	return Do[ResellerAccount](ctx, c, http.MethodPost, "/reseller/accounts-public", nil, req)
+}
+
+// UpdateResellerAccount updates a client account.
+func (c *Client) UpdateResellerAccount(ctx context.Context, clientUserID string, req *ResellerAccount) (*Response[ResellerAccount], error) {
+	return Do[ResellerAccount](ctx, c, http.MethodPut, pathf("/reseller/accounts/%s", clientUserID), nil, req)
+}
+
+// TransferCredit moves credit to a client account.
+func (c *Client) TransferCredit(ctx context.Context, req *CreditTransfer) (*Response[ResellerAccount], error) {
+	return Do[ResellerAccount](ctx, c, http.MethodPut, "/reseller/transfer-credit", nil, req)
+}
+
+// ListReferralAccounts lists referred accounts.
+func (c *Client) ListReferralAccounts(ctx context.Context, opts *ListOptions) (*Response[Page[ReferralAccount]], error) {
+	return Do[Page[ReferralAccount]](ctx, c, http.MethodGet, "/referral/accounts", opts.apply(nil), nil)
+}
@@ -0,0 +1,69 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryDelay caps the wait between attempts, including waits requested by
+// Retry-After.
+const maxRetryDelay = 10 * time.Second
+
+// WithRetries retries GET, PUT and DELETE requests up to n times when the
+// request fails to reach ClickSend or ClickSend answers 429, 502, 503 or 504.
+// Attempts back off exponentially from base, or wait as long as a
+// Retry-After header asks. POST requests are never retried, since ClickSend
+// may have accepted a send whose response was lost.
+func WithRetries(n int, base time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+		c.retryBase = base
+	}
+}
+
+type attemptKey struct{}
+
+// Attempt returns the retry attempt of a request context: 0 for the first
+// attempt and 1 or more for retries. Transports wrapped around the client's
+// HTTP client use it to tell retries apart.
+func Attempt(ctx context.Context) int {
+	n, _ := ctx.Value(attemptKey{}).(int)
+	return n
+}
+
+func retryable(method string, status int, err error) bool {
+	if method == http.MethodPost {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the wait before retry attempt (1-based).
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, maxRetryDelay)
+		}
+	}
+	return min(c.retryBase<<(attempt-1), maxRetryDelay)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package clicksend_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+)
+
+type attemptRecorder struct {
+	attempts []int
+}
+
+func (r *attemptRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.attempts = append(r.attempts, clicksend.Attempt(req.Context()))
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRetries(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	rec := &attemptRecorder{}
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: rec}),
+		clicksend.WithRetries(2, time.Millisecond))
+
+	api.AddFault(clicksendtest.Fault{Method: "GET", Path: "/account", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.GetAccount(context.Background()); err != nil {
+		t.Fatalf("GET was not retried: %v", err)
+	}
+	if want := []int{0, 1, 2}; !slices.Equal(rec.attempts, want) {
+		t.Errorf("attempts = %v, want %v", rec.attempts, want)
+	}
+
+	rec.attempts = nil
+	api.AddFault(clicksendtest.Fault{Method: "POST", Path: "/sms/send", Status: http.StatusServiceUnavailable, Times: 1})
+	_, err := client.SendSms(context.Background(), &clicksend.SmsMessageCollection{
+		Messages: []clicksend.SmsMessage{{To: "+61411111111", Body: "Hello"}},
+	})
+	var apiErr *clicksend.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("POST error = %v, want the 503", err)
+	}
+	if len(rec.attempts) != 1 {
+		t.Errorf("POST was sent %d times, want 1", len(rec.attempts))
+	}
+}
@@ -0,0 +1,129 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// SmsMessage is an outbound SMS. Fields such as MessageID and Status are only
+// set in responses.
+type SmsMessage struct {
+	To           string `json:"to,omitempty"`
+	ListID       Int    `json:"list_id,omitempty"`
+	Body         string `json:"body"`
+	From         string `json:"from,omitempty"`
+	FromEmail    string `json:"from_email,omitempty"`
+	Schedule     Int    `json:"schedule,omitempty"`
+	Country      string `json:"country,omitempty"`
+	CustomString string `json:"custom_string,omitempty"`
+	Source       string `json:"source,omitempty"`
+
+	MessageID    string `json:"message_id,omitempty"`
+	Direction    string `json:"direction,omitempty"`
+	Date         Int    `json:"date,omitempty"`
+	MessageParts Float  `json:"message_parts,omitempty"`
+	MessagePrice Float  `json:"message_price,omitempty"`
+	UserID       Int    `json:"user_id,omitempty"`
+	SubaccountID Int    `json:"subaccount_id,omitempty"`
+	Carrier      string `json:"carrier,omitempty"`
+	Status       string `json:"status,omitempty"`
+	StatusCode   string `json:"status_code,omitempty"`
+	StatusText   string `json:"status_text,omitempty"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorText    string `json:"error_text,omitempty"`
+}
+
+// SmsMessageCollection is the request body for sending or pricing SMS.
+type SmsMessageCollection struct {
+	Messages []SmsMessage `json:"messages"`
+}
+
+// InboundSms is a reply or inbound message received on a dedicated number.
+type InboundSms struct {
+	MessageID         string `json:"message_id"`
+	Timestamp         Int    `json:"timestamp"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Body              string `json:"body"`
+	OriginalBody      string `json:"original_body"`
+	OriginalMessageID string `json:"original_message_id"`
+	CustomString      string `json:"custom_string"`
+	UserID            Int    `json:"user_id"`
+	SubaccountID      Int    `json:"subaccount_id"`
+	Keyword           string `json:"_keyword"`
+}
+
+// SendSms sends one or more SMS messages.
+func (c *Client) SendSms(ctx context.Context, req *SmsMessageCollection) (*Response[SendResult[SmsMessage]], error) {
+	return Do[SendResult[SmsMessage]](ctx, c, http.MethodPost, "/sms/send", nil, req)
+}
+
+// SmsPrice calculates the price of SMS messages without sending them.
+func (c *Client) SmsPrice(ctx context.Context, req *SmsMessageCollection) (*Response[SendResult[SmsMessage]], error) {
+	return Do[SendResult[SmsMessage]](ctx, c, http.MethodPost, "/sms/price", nil, req)
+}
+
+// SmsHistory lists sent SMS messages.
+func (c *Client) SmsHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[SmsMessage]], error) {
+	return Do[Page[SmsMessage]](ctx, c, http.MethodGet, "/sms/history", opts.values(), nil)
+}
+
+// ExportSmsHistory exports SMS history to a CSV file and returns its URL.
+func (c *Client) ExportSmsHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/sms/history/export", filenameQuery(filename), nil)
+}
+
+// CancelSms cancels a scheduled SMS.
+func (c *Client) CancelSms(ctx context.Context, messageID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/sms/%s/cancel", messageID), nil, nil)
+}
+
+// CancelAllSms cancels all scheduled SMS.
+func (c *Client) CancelAllSms(ctx context.Context) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/sms/cancel-all", nil, nil)
+}
+
+// ListSmsReceipts lists SMS delivery receipts.
+func (c *Client) ListSmsReceipts(ctx context.Context, opts *ListOptions) (*Response[Page[DeliveryReceipt]], error) {
+	return Do[Page[DeliveryReceipt]](ctx, c, http.MethodGet, "/sms/receipts", opts.apply(nil), nil)
+}
+
+// GetSmsReceipt returns the delivery receipt for an SMS.
+func (c *Client) GetSmsReceipt(ctx context.Context, messageID string) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodGet, pathf("/sms/receipts/%s", messageID), nil, nil)
+}
+
+// AddTestSmsReceipt posts a test delivery receipt to url.
+func (c *Client) AddTestSmsReceipt(ctx context.Context, req *URLRequest) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodPost, "/sms/receipts", nil, req)
+}
+
+// MarkSmsReceiptsRead marks SMS delivery receipts as read.
+func (c *Client) MarkSmsReceiptsRead(ctx context.Context, req *DateBeforeRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/sms/receipts-read", nil, req)
+}
+
+// ListInboundSms lists unread inbound SMS.
+func (c *Client) ListInboundSms(ctx context.Context, opts *ListOptions) (*Response[Page[InboundSms]], error) {
+	return Do[Page[InboundSms]](ctx, c, http.MethodGet, "/sms/inbound", opts.apply(nil), nil)
+}
+
+// GetInboundSms returns the inbound replies to an outbound message.
+func (c *Client) GetInboundSms(ctx context.Context, outboundMessageID string) (*Response[InboundSms], error) {
+	return Do[InboundSms](ctx, c, http.MethodGet, pathf("/sms/inbound/%s", outboundMessageID), nil, nil)
+}
+
+// AddTestInboundSms posts a test inbound SMS to url.
+func (c *Client) AddTestInboundSms(ctx context.Context, req *URLRequest) (*Response[InboundSms], error) {
+	return Do[InboundSms](ctx, c, http.MethodPost, "/sms/inbound", nil, req)
+}
+
+// MarkInboundSmsRead marks inbound SMS as read.
+func (c *Client) MarkInboundSmsRead(ctx context.Context, req *DateBeforeRequest) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/sms/inbound-read", nil, req)
+}
+
+// MarkInboundSmsMessageRead marks a single inbound SMS as read.
+func (c *Client) MarkInboundSmsMessageRead(ctx context.Context, messageID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/sms/inbound-read/%s", messageID), nil, nil)
+}
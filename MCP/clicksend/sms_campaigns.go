@@ -0,0 +1,120 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// SmsCampaign is an SMS sent to every contact in a list. When URLToShorten is
+// set, the "smsg.us/xxxxx" placeholder in Body becomes a tracked short link.
+type SmsCampaign struct {
+	SmsCampaignID Int    `json:"sms_campaign_id,omitempty"`
+	ListID        Int    `json:"list_id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Body          string `json:"body,omitempty"`
+	From          string `json:"from,omitempty"`
+	Schedule      Int    `json:"schedule,omitempty"`
+	URLToShorten  string `json:"url_to_shorten,omitempty"`
+
+	Status       string `json:"status,omitempty"`
+	CustomString string `json:"custom_string,omitempty"`
+	DateAdded    Int    `json:"date_added,omitempty"`
+	TotalCount   Int    `json:"_total_count,omitempty"`
+	UserID       Int    `json:"user_id,omitempty"`
+	SubaccountID Int    `json:"subaccount_id,omitempty"`
+}
+
+// SmsCampaignPrice is the price of an SMS campaign.
+type SmsCampaignPrice struct {
+	TotalPrice Float       `json:"total_price"`
+	TotalCount Int         `json:"total_count"`
+	Campaign   SmsCampaign `json:"data"`
+	Currency   Currency    `json:"currency"`
+}
+
+// LinkTracking is the short link activity of one campaign recipient.
+type LinkTracking struct {
+	Contact        Contact `json:"contact"`
+	OpenCount      Int     `json:"open_count"`
+	DateOpened     Int     `json:"date_opened"`
+	UserBrowser    string  `json:"user_browser"`
+	UserDevice     string  `json:"user_device"`
+	UserOS         string  `json:"user_os"`
+	UserGeoCountry string  `json:"user_geo_country"`
+	UserGeoRegion  string  `json:"user_geo_region"`
+}
+
+// SmsTemplate is a saved SMS body.
+type SmsTemplate struct {
+	TemplateID   Int    `json:"template_id,omitempty"`
+	TemplateName string `json:"template_name,omitempty"`
+	Body         string `json:"body,omitempty"`
+}
+
+// ListSmsCampaigns lists SMS campaigns.
+func (c *Client) ListSmsCampaigns(ctx context.Context, opts *ListOptions) (*Response[Page[SmsCampaign]], error) {
+	return Do[Page[SmsCampaign]](ctx, c, http.MethodGet, "/sms-campaigns", opts.apply(nil), nil)
+}
+
+// GetSmsCampaign returns an SMS campaign.
+func (c *Client) GetSmsCampaign(ctx context.Context, smsCampaignID string) (*Response[SmsCampaign], error) {
+	return Do[SmsCampaign](ctx, c, http.MethodGet, pathf("/sms-campaigns/%s", smsCampaignID), nil, nil)
+}
+
+// SendSmsCampaign sends or schedules an SMS campaign.
+func (c *Client) SendSmsCampaign(ctx context.Context, req *SmsCampaign) (*Response[SmsCampaign], error) {
+	return Do[SmsCampaign](ctx, c, http.MethodPost, "/sms-campaigns/send", nil, req)
+}
+
+// SmsCampaignPrice calculates the price of an SMS campaign without sending
+// it.
+func (c *Client) SmsCampaignPrice(ctx context.Context, req *SmsCampaign) (*Response[SmsCampaignPrice], error) {
+	return Do[SmsCampaignPrice](ctx, c, http.MethodPost, "/sms-campaigns/price", nil, req)
+}
+
+// UpdateSmsCampaign updates a scheduled SMS campaign.
+func (c *Client) UpdateSmsCampaign(ctx context.Context, smsCampaignID string, req *SmsCampaign) (*Response[SmsCampaign], error) {
+	return Do[SmsCampaign](ctx, c, http.MethodPut, pathf("/sms-campaigns/%s", smsCampaignID), nil, req)
+}
+
+// CancelSmsCampaign cancels a scheduled SMS campaign.
+func (c *Client) CancelSmsCampaign(ctx context.Context, smsCampaignID string) (*Response[SmsCampaign], error) {
+	return Do[SmsCampaign](ctx, c, http.MethodPut, pathf("/sms-campaigns/%s/cancel", smsCampaignID), nil, nil)
+}
+
+// SmsCampaignLinkStatistics summarizes short link opens by browser, country,
+// device and OS.
+func (c *Client) SmsCampaignLinkStatistics(ctx context.Context, campaignID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodGet, pathf("/sms-campaigns/%s/link-statistics", campaignID), nil, nil)
+}
+
+// SmsCampaignLinkTracking lists short link activity per recipient.
+func (c *Client) SmsCampaignLinkTracking(ctx context.Context, campaignID string, opts *ListOptions) (*Response[Page[LinkTracking]], error) {
+	return Do[Page[LinkTracking]](ctx, c, http.MethodGet, pathf("/sms-campaigns/%s/link-tracking", campaignID), opts.apply(nil), nil)
+}
+
+// ExportSmsCampaignLinkTracking exports short link activity to a CSV file
+// and returns its URL.
+func (c *Client) ExportSmsCampaignLinkTracking(ctx context.Context, campaignID, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, pathf("/sms-campaigns/%s/link-export", campaignID), filenameQuery(filename), nil)
+}
+
+// ListSmsTemplates lists SMS templates.
+func (c *Client) ListSmsTemplates(ctx context.Context, opts *ListOptions) (*Response[Page[SmsTemplate]], error) {
+	return Do[Page[SmsTemplate]](ctx, c, http.MethodGet, "/sms/templates", opts.apply(nil), nil)
+}
+
+// CreateSmsTemplate creates an SMS template.
+func (c *Client) CreateSmsTemplate(ctx context.Context, req *SmsTemplate) (*Response[SmsTemplate], error) {
+	return Do[SmsTemplate](ctx, c, http.MethodPost, "/sms/templates", nil, req)
+}
+
+// UpdateSmsTemplate updates an SMS template.
+func (c *Client) UpdateSmsTemplate(ctx context.Context, templateID string, req *SmsTemplate) (*Response[SmsTemplate], error) {
+	return Do[SmsTemplate](ctx, c, http.MethodPut, pathf("/sms/templates/%s", templateID), nil, req)
+}
+
+// DeleteSmsTemplate deletes an SMS template.
+func (c *Client) DeleteSmsTemplate(ctx context.Context, templateID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/sms/templates/%s", templateID), nil, nil)
+}
@@ -0,0 +1,57 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+)
+
+// Subaccount is a user of an account with its own API credentials. Access
+// flags are pointers so requests can clear them; Password is only sent in
+// requests.
+type Subaccount struct {
+	SubaccountID    Int    `json:"subaccount_id,omitempty"`
+	APIUsername     string `json:"api_username,omitempty"`
+	APIKey          string `json:"api_key,omitempty"`
+	Password        string `json:"password,omitempty"`
+	Email           string `json:"email,omitempty"`
+	PhoneNumber     string `json:"phone_number,omitempty"`
+	FirstName       string `json:"first_name,omitempty"`
+	LastName        string `json:"last_name,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+	AccessUsers     *Bool  `json:"access_users,omitempty"`
+	AccessBilling   *Bool  `json:"access_billing,omitempty"`
+	AccessReporting *Bool  `json:"access_reporting,omitempty"`
+	AccessContacts  *Bool  `json:"access_contacts,omitempty"`
+	AccessSettings  *Bool  `json:"access_settings,omitempty"`
+	ShareCampaigns  *Bool  `json:"share_campaigns,omitempty"`
+}
+
+// ListSubaccounts lists subaccounts.
+func (c *Client) ListSubaccounts(ctx context.Context, opts *ListOptions) (*Response[Page[Subaccount]], error) {
+	return Do[Page[Subaccount]](ctx, c, http.MethodGet, "/subaccounts", opts.apply(nil), nil)
+}
+
+// GetSubaccount returns a subaccount.
+func (c *Client) GetSubaccount(ctx context.Context, subaccountID string) (*Response[Subaccount], error) {
+	return Do[Subaccount](ctx, c, http.MethodGet, pathf("/subaccounts/%s", subaccountID), nil, nil)
+}
+
+// CreateSubaccount creates a subaccount.
+func (c *Client) CreateSubaccount(ctx context.Context, req *Subaccount) (*Response[Subaccount], error) {
+	return Do[Subaccount](ctx, c, http.MethodPost, "/subaccounts", nil, req)
+}
+
+// UpdateSubaccount updates a subaccount.
+func (c *Client) UpdateSubaccount(ctx context.Context, subaccountID string, req *Subaccount) (*Response[Subaccount], error) {
+	return Do[Subaccount](ctx, c, http.MethodPut, pathf("/subaccounts/%s", subaccountID), nil, req)
+}
+
+// DeleteSubaccount deletes a subaccount.
+func (c *Client) DeleteSubaccount(ctx context.Context, subaccountID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodDelete, pathf("/subaccounts/%s", subaccountID), nil, nil)
+}
+
+// RegenerateSubaccountAPIKey issues a new API key for a subaccount.
+func (c *Client) RegenerateSubaccountAPIKey(ctx context.Context, subaccountID string) (*Response[Subaccount], error) {
+	return Do[Subaccount](ctx, c, http.MethodPut, pathf("/subaccounts/%s/regen-api-key", subaccountID), nil, nil)
+}
@@ -0,0 +1,273 @@
+package clicksend
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Object is an untyped JSON object, used for responses the API does not
+// document in enough detail to model.
+type Object = map[string]any
+
+// Int is an integer that also decodes from JSON strings and floats, since
+// ClickSend returns many numeric fields quoted ("15") in some endpoints and
+// unquoted in others. Empty strings and null decode to zero.
+type Int int64
+
+func (i *Int) UnmarshalJSON(b []byte) error {
+	s, ok := unquote(b)
+	if !ok {
+		return fmt.Errorf("clicksend: cannot decode %s into Int", b)
+	}
+	if s == "" {
+		*i = 0
+		return nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*i = Int(n)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("clicksend: cannot decode %s into Int", b)
+	}
+	*i = Int(f)
+	return nil
+}
+
+// Float is a number that also decodes from JSON strings.
+type Float float64
+
+func (f *Float) UnmarshalJSON(b []byte) error {
+	s, ok := unquote(b)
+	if !ok {
+		return fmt.Errorf("clicksend: cannot decode %s into Float", b)
+	}
+	if s == "" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("clicksend: cannot decode %s into Float", b)
+	}
+	*f = Float(v)
+	return nil
+}
+
+// Bool is a flag that decodes from true/false, 0/1 and their quoted forms.
+// It encodes as 0 or 1, which every ClickSend endpoint accepts.
+type Bool bool
+
+func (v Bool) MarshalJSON() ([]byte, error) {
+	if v {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+func (v *Bool) UnmarshalJSON(b []byte) error {
+	s, ok := unquote(b)
+	if !ok {
+		return fmt.Errorf("clicksend: cannot decode %s into Bool", b)
+	}
+	switch s {
+	case "", "0", "false", "FALSE", "False":
+		*v = false
+	case "1", "true", "TRUE", "True":
+		*v = true
+	default:
+		return fmt.Errorf("clicksend: cannot decode %s into Bool", b)
+	}
+	return nil
+}
+
+// unquote returns the scalar text of a JSON number, string, boolean or null.
+func unquote(b []byte) (string, bool) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		return "", true
+	}
+	if b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return "", false
+		}
+		return s, true
+	}
+	if b[0] == '{' || b[0] == '[' {
+		return "", false
+	}
+	return string(b), true
+}
+
+// UnmarshalJSON decodes the envelope and then Data. ClickSend sends empty
+// arrays, empty strings or false where an object is expected when there is
+// nothing to return; those leave Data at its zero value.
+func (r *Response[T]) UnmarshalJSON(b []byte) error {
+	var envelope struct {
+		HTTPCode     Int             `json:"http_code"`
+		ResponseCode string          `json:"response_code"`
+		ResponseMsg  string          `json:"response_msg"`
+		Data         json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+	r.HTTPCode = int(envelope.HTTPCode)
+	r.ResponseCode = envelope.ResponseCode
+	r.ResponseMsg = envelope.ResponseMsg
+	if len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, &r.Data); err != nil {
+		switch string(bytes.TrimSpace(envelope.Data)) {
+		case "[]", "{}", "null", `""`, "false", "true":
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// String is text that also decodes from JSON numbers, for fields such as
+// postal codes that ClickSend returns either quoted or unquoted.
+type String string
+
+func (s *String) UnmarshalJSON(b []byte) error {
+	v, ok := unquote(b)
+	if !ok {
+		return fmt.Errorf("clicksend: cannot decode %s into String", b)
+	}
+	*s = String(v)
+	return nil
+}
+
+// Ptr returns a pointer to v, for optional request fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Currency describes the currency prices are reported in.
+type Currency struct {
+	CurrencyNameShort string `json:"currency_name_short"`
+	CurrencyPrefixD   string `json:"currency_prefix_d"`
+	CurrencyPrefixC   string `json:"currency_prefix_c"`
+	CurrencyNameLong  string `json:"currency_name_long"`
+}
+
+// SendResult is returned by send and price endpoints.
+type SendResult[T any] struct {
+	TotalPrice  Float    `json:"total_price"`
+	TotalCount  Int      `json:"total_count"`
+	QueuedCount Int      `json:"queued_count"`
+	Messages    []T      `json:"messages"`
+	Currency    Currency `json:"_currency"`
+}
+
+// UnmarshalJSON also accepts "currency", which voice endpoints use instead of
+// "_currency".
+func (r *SendResult[T]) UnmarshalJSON(b []byte) error {
+	type plain SendResult[T]
+	var v struct {
+		plain
+		AltCurrency *Currency `json:"currency"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = SendResult[T](v.plain)
+	if v.AltCurrency != nil && r.Currency == (Currency{}) {
+		r.Currency = *v.AltCurrency
+	}
+	return nil
+}
+
+// Export is returned by history export endpoints.
+type Export struct {
+	URL string `json:"url"`
+}
+
+// URLRequest registers a URL, e.g. for test delivery receipts.
+type URLRequest struct {
+	URL string `json:"url"`
+}
+
+// DateBeforeRequest marks items before a unix timestamp.
+type DateBeforeRequest struct {
+	DateBefore Int `json:"date_before,omitempty"`
+}
+
+// DeliveryReceipt is a delivery report for an outbound message.
+type DeliveryReceipt struct {
+	MessageID     string `json:"message_id"`
+	MessageType   string `json:"message_type,omitempty"`
+	StatusCode    string `json:"status_code"`
+	StatusText    string `json:"status_text"`
+	ErrorCode     string `json:"error_code"`
+	ErrorText     string `json:"error_text"`
+	CustomString  string `json:"custom_string"`
+	Timestamp     Int    `json:"timestamp"`
+	TimestampSend Int    `json:"timestamp_send"`
+	SubaccountID  Int    `json:"subaccount_id,omitempty"`
+	Digits        string `json:"digits,omitempty"`
+}
+
+// HistoryOptions filters message history endpoints.
+type HistoryOptions struct {
+	DateFrom string
+	DateTo   string
+	Q        string
+	OrderBy  string
+	ListOptions
+}
+
+func (o *HistoryOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.DateFrom != "" {
+		q.Set("date_from", o.DateFrom)
+	}
+	if o.DateTo != "" {
+		q.Set("date_to", o.DateTo)
+	}
+	if o.Q != "" {
+		q.Set("q", o.Q)
+	}
+	if o.OrderBy != "" {
+		q.Set("order_by", o.OrderBy)
+	}
+	return o.ListOptions.apply(q)
+}
+
+func filenameQuery(filename string) url.Values {
+	return url.Values{"filename": {filename}}
+}
+
+// SendCost returns the credit charged by a send, read from the total_price,
+// _total_price or price field of its response body. It returns 0 when the
+// body reports no price.
+func SendCost(body []byte) float64 {
+	var envelope struct {
+		Data struct {
+			TotalPrice    Float `json:"total_price"`
+			InternalTotal Float `json:"_total_price"`
+			Price         Float `json:"price"`
+		} `json:"data"`
+	}
+	json.Unmarshal(body, &envelope)
+	return float64(cmp.Or(envelope.Data.TotalPrice, envelope.Data.InternalTotal, envelope.Data.Price))
+}
+
+// IsSend reports whether a request sends messages and is charged for.
+func IsSend(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/send")
+}
@@ -0,0 +1,36 @@
+package clicksend
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Upload conversion types.
+const (
+	ConvertFax  = "fax"
+	ConvertMms  = "mms"
+	ConvertPost = "post"
+	ConvertCsv  = "csv"
+)
+
+// UploadRequest uploads a base64-encoded file.
+type UploadRequest struct {
+	Content string `json:"content"`
+}
+
+// Upload is a file stored by ClickSend, converted for use by a channel.
+type Upload struct {
+	UploadID   Int    `json:"upload_id"`
+	URL        string `json:"_url"`
+	FileName   string `json:"file_name"`
+	DateAdded  Int    `json:"date_added"`
+	DateDelete Int    `json:"date_delete"`
+	UserID     Int    `json:"user_id,omitempty"`
+}
+
+// UploadFile uploads a file and converts it for convert (one of the Convert
+// constants). The returned URL can be passed to send endpoints.
+func (c *Client) UploadFile(ctx context.Context, convert string, req *UploadRequest) (*Response[Upload], error) {
+	return Do[Upload](ctx, c, http.MethodPost, "/uploads", url.Values{"convert": {convert}}, req)
+}
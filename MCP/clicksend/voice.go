@@ -0,0 +1,128 @@
+package clicksend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// VoiceMessage is an outbound text-to-speech call. Fields such as MessageID
+// and Status are only set in responses.
+type VoiceMessage struct {
+	To               string `json:"to,omitempty"`
+	ListID           Int    `json:"list_id,omitempty"`
+	Body             string `json:"body"`
+	Voice            string `json:"voice"`
+	Lang             string `json:"lang,omitempty"`
+	Schedule         Int    `json:"schedule,omitempty"`
+	Country          string `json:"country,omitempty"`
+	CustomString     string `json:"custom_string,omitempty"`
+	Source           string `json:"source,omitempty"`
+	RequireInput     *Bool  `json:"require_input,omitempty"`
+	MachineDetection *Bool  `json:"machine_detection,omitempty"`
+
+	MessageID    string `json:"message_id,omitempty"`
+	From         string `json:"from,omitempty"`
+	Date         Int    `json:"date,omitempty"`
+	Carrier      string `json:"carrier,omitempty"`
+	MessageParts Float  `json:"message_parts,omitempty"`
+	MessagePrice Float  `json:"message_price,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// VoiceMessageCollection is the request body for sending or pricing calls.
+type VoiceMessageCollection struct {
+	Messages []VoiceMessage `json:"messages"`
+}
+
+// VoiceLanguage is a language available for text-to-speech.
+type VoiceLanguage struct {
+	Code    string `json:"code"`
+	Country string `json:"country"`
+	// Gender lists the available voices. The API returns a single string
+	// when only one is available.
+	Gender []string `json:"gender"`
+}
+
+// UnmarshalJSON accepts gender as a string or an array of strings.
+func (l *VoiceLanguage) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Code    string          `json:"code"`
+		Country string          `json:"country"`
+		Gender  json.RawMessage `json:"gender"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	l.Code, l.Country, l.Gender = v.Code, v.Country, nil
+	if len(v.Gender) == 0 || string(v.Gender) == "null" {
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(v.Gender, &one); err == nil {
+		l.Gender = []string{one}
+		return nil
+	}
+	return json.Unmarshal(v.Gender, &l.Gender)
+}
+
+// SendVoice sends one or more text-to-speech calls.
+func (c *Client) SendVoice(ctx context.Context, req *VoiceMessageCollection) (*Response[SendResult[VoiceMessage]], error) {
+	return Do[SendResult[VoiceMessage]](ctx, c, http.MethodPost, "/voice/send", nil, req)
+}
+
+// VoicePrice calculates the price of calls without sending them.
+func (c *Client) VoicePrice(ctx context.Context, req *VoiceMessageCollection) (*Response[SendResult[VoiceMessage]], error) {
+	return Do[SendResult[VoiceMessage]](ctx, c, http.MethodPost, "/voice/price", nil, req)
+}
+
+// VoiceLanguages lists the languages available for text-to-speech.
+func (c *Client) VoiceLanguages(ctx context.Context) (*Response[[]VoiceLanguage], error) {
+	return Do[[]VoiceLanguage](ctx, c, http.MethodGet, "/voice/lang", nil, nil)
+}
+
+// VoiceHistory lists sent calls.
+func (c *Client) VoiceHistory(ctx context.Context, opts *HistoryOptions) (*Response[Page[VoiceMessage]], error) {
+	return Do[Page[VoiceMessage]](ctx, c, http.MethodGet, "/voice/history", opts.values(), nil)
+}
+
+// ExportVoiceHistory exports call history to a CSV file and returns its URL.
+func (c *Client) ExportVoiceHistory(ctx context.Context, filename string) (*Response[Export], error) {
+	return Do[Export](ctx, c, http.MethodGet, "/voice/history/export", filenameQuery(filename), nil)
+}
+
+// CancelVoice cancels a scheduled call.
+func (c *Client) CancelVoice(ctx context.Context, messageID string) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, pathf("/voice/%s/cancel", messageID), nil, nil)
+}
+
+// CancelAllVoice cancels all scheduled calls.
+func (c *Client) CancelAllVoice(ctx context.Context) (*Response[Object], error) {
+	return Do[Object](ctx, c, http.MethodPut, "/voice/cancel-all", nil, nil)
+}
+
+// ListVoiceReceipts lists call delivery receipts.
+func (c *Client) ListVoiceReceipts(ctx context.Context, opts *ListOptions) (*Response[Page[DeliveryReceipt]], error) {
+	return Do[Page[DeliveryReceipt]](ctx, c, http.MethodGet, "/voice/receipts", opts.apply(nil), nil)
+}
+
+// GetVoiceReceipt returns the delivery receipt for a call.
+func (c *Client) GetVoiceReceipt(ctx context.Context, messageID string) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodGet, pathf("/voice/receipts/%s", messageID), nil, nil)
+}
+
+// AddTestVoiceReceipt posts a test delivery receipt to url.
+func (c *Client) AddTestVoiceReceipt(ctx context.Context, req *URLRequest) (*Response[DeliveryReceipt], error) {
+	return Do[DeliveryReceipt](ctx, c, http.MethodPost, "/voice/receipts", nil, req)
+}
+
+// MarkVoiceReceiptsRead marks call delivery receipts before dateBefore (a
+// unix timestamp) as read.
+func (c *Client) MarkVoiceReceiptsRead(ctx context.Context, dateBefore string) (*Response[Object], error) {
+	q := url.Values{}
+	if dateBefore != "" {
+		q.Set("date_before", dateBefore)
+	}
+	return Do[Object](ctx, c, http.MethodPut, "/voice/receipts-read", q, nil)
+}
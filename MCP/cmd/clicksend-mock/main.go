@@ -0,0 +1,109 @@
+// Command clicksend-mock serves the in-memory ClickSend API fake from package
+// clicksendtest so the MCP server can run without network access or a real
+// account.
+//
+// Usage:
+//
+//	go run ./cmd/clicksend-mock -addr :4010 -latency 50ms -error-rate 0.05
+//
+// Point the server at it with API_BASE_URL=http://localhost:4010 and the
+// credentials mock-user / mock-api-key (or those set with -username and
+// -api-key). Unauthenticated control endpoints under /_mock/ manage the fake
+// while it runs:
+//
+//	POST   /_mock/faults   add a fault (JSON clicksendtest.Fault; delay as a duration string)
+//	DELETE /_mock/faults   remove all faults
+//	POST   /_mock/reset    clear all state and faults
+//	POST   /_mock/advance  move the clock forward, e.g. ?by=1h
+//	POST   /_mock/inbound  receive an SMS, e.g. {"from":"+61411111111","to":"+61400000000","body":"STOP"}
+//	GET    /_mock/balance  report the account balance
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+)
+
+func main() {
+	addr := flag.String("addr", ":4010", "listen address")
+	username := flag.String("username", clicksendtest.Username, "API username the mock accepts")
+	apiKey := flag.String("api-key", clicksendtest.APIKey, "API key the mock accepts")
+	noAuth := flag.Bool("no-auth", false, "accept requests without credentials")
+	balance := flag.Float64("balance", clicksendtest.DefaultBalance, "starting account balance")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	errorRate := flag.Float64("error-rate", 0, "fraction of requests (0-1) that fail with a 500 error")
+	seed := flag.Int64("seed", 1, "random seed for -error-rate")
+	flag.Parse()
+
+	opts := []clicksendtest.Option{
+		clicksendtest.WithCredentials(*username, *apiKey),
+		clicksendtest.WithBalance(*balance),
+		clicksendtest.WithLatency(*latency),
+		clicksendtest.WithErrorRate(*errorRate, *seed),
+	}
+	if *noAuth {
+		opts = append(opts, clicksendtest.WithoutAuth())
+	}
+	api := clicksendtest.New(opts...)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /_mock/faults", func(w http.ResponseWriter, r *http.Request) {
+		var f struct {
+			clicksendtest.Fault
+			Delay string `json:"delay"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if f.Delay != "" {
+			d, err := time.ParseDuration(f.Delay)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.Fault.Delay = d
+		}
+		api.AddFault(f.Fault)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /_mock/faults", func(w http.ResponseWriter, r *http.Request) {
+		api.ClearFaults()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /_mock/reset", func(w http.ResponseWriter, r *http.Request) {
+		api.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /_mock/advance", func(w http.ResponseWriter, r *http.Request) {
+		d, err := time.ParseDuration(r.URL.Query().Get("by"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		api.Advance(d)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /_mock/inbound", func(w http.ResponseWriter, r *http.Request) {
+		var msg struct{ From, To, Body string }
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		api.ReceiveSms(msg.From, msg.To, msg.Body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /_mock/balance", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]float64{"balance": api.Balance()})
+	})
+	mux.Handle("/", api)
+
+	log.Printf("Mock ClickSend API listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
@@ -0,0 +1,73 @@
+// Command clicksend-profiles creates keys for, and encrypts and decrypts,
+// the credential profiles file read by the MCP server.
+//
+// Usage:
+//
+//	go run ./cmd/clicksend-profiles keygen > profiles.key
+//	go run ./cmd/clicksend-profiles encrypt -key-file profiles.key < profiles.yaml > profiles.enc
+//	go run ./cmd/clicksend-profiles decrypt -key-file profiles.key < profiles.enc
+//
+// The key may also be passed in CLICKSEND_PROFILES_KEY. Serve the result
+// with CLICKSEND_PROFILES_FILE=profiles.enc and the same key.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal("usage: clicksend-profiles keygen | encrypt | decrypt [-key-file path]")
+	}
+	cmd := os.Args[1]
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	keyFile := flags.String("key-file", "", "file holding the base64 key (default $CLICKSEND_PROFILES_KEY)")
+	flags.Parse(os.Args[2:])
+
+	if cmd == "keygen" {
+		key, err := vault.NewKey()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	encoded := os.Getenv("CLICKSEND_PROFILES_KEY")
+	if *keyFile != "" {
+		b, err := os.ReadFile(*keyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		encoded = string(b)
+	}
+	key, err := vault.ParseKey(encoded)
+	if err != nil {
+		log.Fatal(err)
+	}
+	in, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out []byte
+	switch cmd {
+	case "encrypt":
+		out, err = vault.Encrypt(in, key)
+	case "decrypt":
+		out, err = vault.Decrypt(in, key)
+	default:
+		log.Fatalf("unknown command %q", cmd)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(out)
+}
@@ -0,0 +1,200 @@
+// Command gencountries writes the country table of package phone from the
+// countries get_countries returns and the numbering plans of plans.json.
+//
+// The countries are read from the get_countries example of the ClickSend
+// OpenAPI document and, with -countries, from a saved get_countries result,
+// which lists every country the account can reach. Each keeps the code and
+// name get_countries gives it. A listed country without a plan fails the
+// run, so the table cannot fall behind get_countries silently. Plans of
+// countries missing from the examples are kept under their own names.
+//
+// Usage:
+//
+//	go run ./cmd/gencountries -spec ../opeanapi.yaml -plans phone/plans.json -out phone/countries.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type country struct {
+	Code  string `json:"code" yaml:"code"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type plan struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	CallingCode string `json:"calling_code"`
+	Trunk       string `json:"trunk"`
+	Rules       []struct {
+		Prefixes []string `json:"prefixes"`
+		Lengths  []int    `json:"lengths"`
+		Type     string   `json:"type"`
+	} `json:"rules"`
+}
+
+// types are the Go constants of the rule types of plans.json.
+var types = map[string]string{
+	"mobile":    "TypeMobile",
+	"landline":  "TypeLandline",
+	"toll_free": "TypeTollFree",
+	"unknown":   "TypeUnknown",
+}
+
+func main() {
+	specPath := flag.String("spec", "../opeanapi.yaml", "path to the ClickSend OpenAPI document")
+	countriesPath := flag.String("countries", "", "path of a saved get_countries result to add to the spec's example")
+	plansPath := flag.String("plans", "phone/plans.json", "path of the numbering plans")
+	outPath := flag.String("out", "phone/countries.go", "path of the generated Go file")
+	flag.Parse()
+
+	listed, err := specCountries(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read the get_countries example: %v", err)
+	}
+	if *countriesPath != "" {
+		saved, err := savedCountries(*countriesPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *countriesPath, err)
+		}
+		listed = append(listed, saved...)
+	}
+	raw, err := os.ReadFile(*plansPath)
+	if err != nil {
+		log.Fatalf("Failed to read plans: %v", err)
+	}
+	var plans []plan
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		log.Fatalf("Failed to parse plans: %v", err)
+	}
+
+	names := map[string]string{}
+	for _, c := range listed {
+		names[c.Code] = c.Value
+	}
+	var missing []string
+	for code := range names {
+		if !slices.ContainsFunc(plans, func(p plan) bool { return p.Code == code }) {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		log.Fatalf("get_countries lists countries without a numbering plan in %s: %s", *plansPath, strings.Join(missing, ", "))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gencountries from get_countries and plans.json. DO NOT EDIT.\n\n")
+	b.WriteString("package phone\n\n")
+	b.WriteString("// countries are listed in the order numbers of a shared calling code are\n// attributed to them.\n")
+	b.WriteString("var countries = []*Country{\n")
+	for _, p := range plans {
+		name := p.Name
+		if listedName, ok := names[p.Code]; ok {
+			name = listedName
+		}
+		if p.Code == "" || name == "" || p.CallingCode == "" {
+			log.Fatalf("Plan %+v needs a code, a name and a calling code", p)
+		}
+		fmt.Fprintf(&b, "\t{Code: %q, Name: %q, CallingCode: %q, Trunk: %q, rules: []rule{\n", p.Code, name, p.CallingCode, p.Trunk)
+		for _, r := range p.Rules {
+			typ, ok := types[r.Type]
+			if !ok || len(r.Prefixes) == 0 || len(r.Lengths) == 0 {
+				log.Fatalf("Plan %s has an invalid rule %+v", p.Code, r)
+			}
+			fmt.Fprintf(&b, "\t\t{%s, %s, %s},\n", goStrings(r.Prefixes), goInts(r.Lengths), typ)
+		}
+		b.WriteString("\t}},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("Failed to format the table: %v", err)
+	}
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		log.Fatalf("Failed to write the table: %v", err)
+	}
+	log.Printf("Wrote %d countries to %s", len(plans), *outPath)
+}
+
+// specCountries returns the countries of the get_countries example of the
+// OpenAPI document.
+func specCountries(path string) ([]country, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Paths map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	node, ok := doc.Paths["/countries"]
+	if !ok {
+		return nil, fmt.Errorf("%s has no /countries path", path)
+	}
+	var ops map[string]struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Examples map[string]struct {
+					Value struct {
+						Data []country `yaml:"data"`
+					} `yaml:"value"`
+				} `yaml:"examples"`
+			} `yaml:"content"`
+		} `yaml:"responses"`
+	}
+	if err := node.Decode(&ops); err != nil {
+		return nil, err
+	}
+	for _, example := range ops["get"].Responses["200"].Content["application/json"].Examples {
+		if len(example.Value.Data) > 0 {
+			return example.Value.Data, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no GET /countries example", path)
+}
+
+// savedCountries returns the countries of a saved get_countries result.
+func savedCountries(path string) ([]country, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []country `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func goStrings(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+func goInts(ns []int) string {
+	parts := make([]string, len(ns))
+	for i, n := range ns {
+		parts[i] = fmt.Sprint(n)
+	}
+	return "[]int{" + strings.Join(parts, ", ") + "}"
+}
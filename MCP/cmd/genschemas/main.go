@@ -0,0 +1,159 @@
+// Command genschemas derives MCP output schemas for every tool from the
+// response examples in the ClickSend OpenAPI document.
+//
+// Usage:
+//
+//	go run ./cmd/genschemas -spec ../opeanapi.yaml -out schemas/output_schemas.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type spec struct {
+	Paths map[string]map[string]operation `yaml:"paths"`
+}
+
+type operation struct {
+	Responses map[string]response `yaml:"responses"`
+}
+
+type response struct {
+	Content map[string]mediaType `yaml:"content"`
+}
+
+type mediaType struct {
+	Example  interface{}            `yaml:"example"`
+	Examples map[string]exampleItem `yaml:"examples"`
+}
+
+type exampleItem struct {
+	Value interface{} `yaml:"value"`
+}
+
+var toolNameReplacer = strings.NewReplacer("/", "_", "{", "", "}", "")
+
+func main() {
+	specPath := flag.String("spec", "../opeanapi.yaml", "path to the ClickSend OpenAPI document")
+	outPath := flag.String("out", "schemas/output_schemas.json", "path of the generated schema file")
+	flag.Parse()
+
+	raw, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	var doc spec
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		log.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	schemas := map[string]interface{}{}
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			example := successExample(op)
+			if _, ok := example.(map[string]interface{}); !ok {
+				continue
+			}
+			name := method + "_" + toolNameReplacer.Replace(strings.TrimPrefix(path, "/"))
+			schemas[name] = infer(example)
+		}
+	}
+
+	out, err := json.MarshalIndent(schemas, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode schemas: %v", err)
+	}
+	if err := os.WriteFile(*outPath, append(out, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write schemas: %v", err)
+	}
+	log.Printf("Wrote %d output schemas to %s", len(schemas), *outPath)
+}
+
+// successExample returns the JSON example of the first 2xx response.
+func successExample(op operation) interface{} {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		media, ok := op.Responses[code].Content["application/json"]
+		if !ok {
+			continue
+		}
+		if media.Example != nil {
+			return media.Example
+		}
+		names := make([]string, 0, len(media.Examples))
+		for name := range media.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if v := media.Examples[name].Value; v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// infer builds a permissive JSON schema describing an example value. ClickSend
+// is loose with scalar types (ids and counts arrive as numbers or strings), so
+// scalars accept null and numbers also accept strings; no field is required.
+func infer(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		props := map[string]interface{}{}
+		for k, child := range val {
+			props[k] = infer(child)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if items := mergeItems(val); items != nil {
+			schema["items"] = items
+		}
+		return schema
+	case string:
+		return map[string]interface{}{"type": []string{"string", "null"}}
+	case bool:
+		return map[string]interface{}{"type": []string{"boolean", "number", "null"}}
+	case int, int64, float64:
+		return map[string]interface{}{"type": []string{"number", "string", "null"}}
+	}
+	return map[string]interface{}{}
+}
+
+// mergeItems combines the schemas of array elements. Object elements are merged
+// into one schema holding the union of their properties.
+func mergeItems(items []interface{}) map[string]interface{} {
+	var merged map[string]interface{}
+	for _, item := range items {
+		schema := infer(item)
+		if merged == nil {
+			merged = schema
+			continue
+		}
+		mp, ok1 := merged["properties"].(map[string]interface{})
+		sp, ok2 := schema["properties"].(map[string]interface{})
+		if !ok1 || !ok2 {
+			continue
+		}
+		for k, s := range sp {
+			if _, exists := mp[k]; !exists {
+				mp[k] = s
+			}
+		}
+	}
+	return merged
+}
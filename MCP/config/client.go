@@ -0,0 +1,126 @@
+package config
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/impersonate"
+	"github.com/clicksend-rest-api-v3/mcp-server/logging"
+	"github.com/clicksend-rest-api-v3/mcp-server/metrics"
+	"github.com/clicksend-rest-api-v3/mcp-server/tracing"
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
+)
+
+// retryBase is the first backoff between retries.
+const retryBase = 500 * time.Millisecond
+
+// Client returns a ClickSend client for the configured base URL and
+// credentials, using HTTPClient when it is set. A request whose tool call
+// or session selects a credential profile uses the profile instead, and one
+// that selects a subaccount uses the subaccount's credentials. Requests are
+// logged with the default slog logger, measured by the default metrics and
+// traced with the global tracer provider. Sends are refused once Budget is
+// spent.
+func (c *APIConfig) Client() *clicksend.Client {
+	return c.newClient(c.BaseURL, c.BasicAuth, clicksend.WithEndpointFunc(c.endpoint))
+}
+
+func (c *APIConfig) newClient(baseURL, basicAuth string, opts ...clicksend.Option) *clicksend.Client {
+	var hc http.Client
+	if c.HTTPClient != nil {
+		hc = *c.HTTPClient
+	}
+	hc.Transport = tracing.Transport(hc.Transport)
+	if c.Budget != nil {
+		hc.Transport = c.Budget.Transport(hc.Transport)
+	}
+	hc.Transport = logging.Transport(slog.Default(), metrics.Default.Transport(hc.Transport))
+	if c.RequestTimeout > 0 {
+		hc.Timeout = c.RequestTimeout
+	}
+	return clicksend.NewClient(baseURL, append([]clicksend.Option{
+		clicksend.WithBasicAuth(basicAuth),
+		clicksend.WithHTTPClient(&hc),
+		clicksend.WithRetries(c.MaxRetries, retryBase),
+	}, opts...)...)
+}
+
+// Account returns the ClickSend username a request acts as: that of the
+// selected profile or subaccount, else of BasicAuth. State the server keeps
+// for an account, such as SMS template history, is keyed by it.
+func (c *APIConfig) Account(ctx context.Context) (string, error) {
+	basic := c.BasicAuth
+	ep, err := c.endpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+	if ep != nil {
+		basic = ep.BasicAuth
+	}
+	raw, err := base64.StdEncoding.DecodeString(basic)
+	user, _, _ := strings.Cut(string(raw), ":")
+	if err != nil || user == "" {
+		return "", errors.New("no ClickSend username in the credentials")
+	}
+	return user, nil
+}
+
+// endpoint returns the credentials selected for a request. The tool call's
+// profile argument, else Profile, picks a credential profile; the tool
+// call's as_subaccount argument, else Subaccount, then acts as a subaccount
+// of that account. It returns nil when neither is selected.
+func (c *APIConfig) endpoint(ctx context.Context) (*clicksend.Endpoint, error) {
+	ep, err := c.profileEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := cmp.Or(impersonate.SubaccountFromContext(ctx), c.Subaccount)
+	if id == "" {
+		return ep, nil
+	}
+	if c.Subaccounts == nil {
+		return nil, fmt.Errorf("subaccount %s requested but SUBACCOUNT_IMPERSONATION is off", id)
+	}
+	parent := &clicksend.Endpoint{BaseURL: c.BaseURL, BasicAuth: c.BasicAuth}
+	if ep != nil {
+		parent = ep
+	}
+	basic, err := c.Subaccounts.Credentials(ctx, c.newClient(cmp.Or(parent.BaseURL, c.BaseURL), parent.BasicAuth), parent.BasicAuth, id)
+	if err != nil {
+		return nil, err
+	}
+	return &clicksend.Endpoint{BaseURL: parent.BaseURL, BasicAuth: basic}, nil
+}
+
+// profileEndpoint returns the credentials of the selected profile, or nil.
+// A profile naming no base URL uses ProfileBaseURL, so that stored
+// credentials never go to a base URL a request header chose.
+func (c *APIConfig) profileEndpoint(ctx context.Context) (*clicksend.Endpoint, error) {
+	name := cmp.Or(vault.ProfileFromContext(ctx), c.Profile)
+	if name == "" {
+		return nil, nil
+	}
+	if c.Profiles == nil {
+		return nil, fmt.Errorf("profile %q requested but no credential profiles are configured", name)
+	}
+	if c.AllowedProfiles != nil && len(c.AllowedProfiles) == 0 {
+		return nil, fmt.Errorf("profile %q requested by an unauthenticated client; profiles need MCP_AUTH_FILE", name)
+	}
+	if c.AllowedProfiles != nil && !slices.Contains(c.AllowedProfiles, name) {
+		return nil, fmt.Errorf("profile %q is not allowed", name)
+	}
+	p, err := c.Profiles.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &clicksend.Endpoint{BaseURL: cmp.Or(p.BaseURL, c.ProfileBaseURL, c.BaseURL), BasicAuth: p.Basic()}, nil
+}
@@ -2,47 +2,329 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/batch"
+	"github.com/clicksend-rest-api-v3/mcp-server/budget"
+	"github.com/clicksend-rest-api-v3/mcp-server/cassette"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+	"github.com/clicksend-rest-api-v3/mcp-server/impersonate"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/sendtime"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
 )
 
 type APIConfig struct {
-	BaseURL     string
-	BearerToken string // For OAuth2/Bearer authentication
-	APIKey      string // For API key authentication
-	BasicAuth   string // For basic authentication
-	Port        string // For server port configuration
+	BaseURL        string
+	BearerToken    string       // For OAuth2/Bearer authentication
+	APIKey         string       // For API key authentication
+	BasicAuth      string       // For basic authentication
+	Port           string       // For server port configuration
+	MaxOutputBytes int          // Tool output size above which results are truncated (0 disables)
+	HTTPClient     *http.Client // For recording or replaying ClickSend traffic (nil uses the default client)
+	MaxRetries     int          // Retries of failed GET, PUT and DELETE requests to ClickSend (0, the default, for none)
+
+	ReadinessProbe    bool          // Whether /readyz calls get_account with the configured credentials
+	ReadinessProbeTTL time.Duration // How long a readiness probe result is reused
+
+	Profiles        *vault.Vault // Named credential profiles (nil when none are configured)
+	ProfileBaseURL  string       // Base URL of profiles naming none, when BaseURL came from a request (empty uses BaseURL)
+	Profile         string       // Profile used when a tool call names none
+	AllowedProfiles []string     // Profiles that may be selected (nil allows all, empty none)
+
+	Subaccounts *impersonate.Resolver // Subaccount credential lookup (nil when impersonation is off)
+	Subaccount  string                // Subaccount ID to act as when a tool call names none
+
+	ListenAddr     string         // Address the HTTP/HTTPS server listens on
+	RequestTimeout time.Duration  // Timeout of each ClickSend request (0 disables)
+	ToolTimeout    time.Duration  // Deadline of each tool call (0 disables)
+	ToolsInclude   []string       // Globs of tool names to serve (empty serves all)
+	ToolsExclude   []string       // Globs of tool names never served
+	Budget         *budget.Budget // Daily credit limit on sends (nil for none)
+
+	Uploads      *upload.Uploader // Checks and uploads local files for sends
+	Bulk         *batch.Runner    // Worker pool and rate limit of bulk contact tools
+	PhoneCountry string           // ISO code of the country of local phone numbers that name none
+
+	Suppression *suppression.Registries // Recipients each account's send tools leave out (nil when suppression is off)
+	SendTime    *sendtime.Policy        // Quiet hours and timezone of send schedules
+	Templates   *smstemplate.Library    // Folder, history and lint limit of SMS templates
 }
 
+// DefaultMaxOutputBytes is used when MAX_OUTPUT_BYTES is not set.
+const DefaultMaxOutputBytes = 40000
+
+// DefaultMaxRetries is used when MAX_RETRIES is not set.
+const DefaultMaxRetries = 0
+
+// DefaultReadinessProbeTTL is used when READINESS_PROBE_TTL is not set.
+const DefaultReadinessProbeTTL = 30 * time.Second
+
 func LoadAPIConfig() (*APIConfig, error) {
 	// Check port environment variable (both uppercase and lowercase)
-	port := os.Getenv("PORT")
+	port := configfile.Getenv("PORT")
 	if port == "" {
-		port = os.Getenv("port")
+		port = configfile.Getenv("port")
+	}
+	listenAddr := configfile.Getenv("LISTEN_ADDR")
+	if listenAddr == "" && port != "" {
+		listenAddr = net.JoinHostPort("0.0.0.0", port)
 	}
-	
-	baseURL := os.Getenv("API_BASE_URL")
-	
+
+	baseURL := configfile.Getenv("API_BASE_URL")
+
 	// Check transport environment variable (both uppercase and lowercase)
-	transport := os.Getenv("TRANSPORT")
+	transport := configfile.Getenv("TRANSPORT")
 	if transport == "" {
-		transport = os.Getenv("transport")
+		transport = configfile.Getenv("transport")
+	}
+
+	// Replayed sessions never reach the API, so the base URL only needs a default
+	cassetteMode := configfile.Getenv("CASSETTE_MODE")
+	if cassetteMode == cassette.ModeReplay && baseURL == "" {
+		baseURL = clicksend.DefaultBaseURL
+	}
+
+	// Profiles carry their own credentials, so the base URL only needs a default
+	profiles, err := vault.FromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("credential profiles: %w", err)
+	}
+	profile := configfile.Getenv("CLICKSEND_PROFILE")
+	if profiles != nil && baseURL == "" {
+		baseURL = clicksend.DefaultBaseURL
 	}
-	
+	if profile != "" {
+		if profiles == nil {
+			return nil, fmt.Errorf("CLICKSEND_PROFILE is set but CLICKSEND_PROFILES_FILE and CLICKSEND_PROFILES_DIR are not")
+		}
+		if _, err := profiles.Get(profile); err != nil {
+			return nil, fmt.Errorf("CLICKSEND_PROFILE: %w", err)
+		}
+	}
+
+	subaccounts, err := impersonate.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	subaccount := configfile.Getenv("CLICKSEND_SUBACCOUNT")
+	if subaccount != "" && subaccounts == nil {
+		return nil, fmt.Errorf("CLICKSEND_SUBACCOUNT is set but SUBACCOUNT_IMPERSONATION is off")
+	}
+
 	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL is required from environment
 	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && baseURL == "" {
 		return nil, fmt.Errorf("API_BASE_URL environment variable not set")
 	}
-	
+
 	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
+	maxOutputBytes := DefaultMaxOutputBytes
+	if v := configfile.Getenv("MAX_OUTPUT_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("MAX_OUTPUT_BYTES must be a non-negative integer, got %q", v)
+		}
+		maxOutputBytes = n
+	}
+
+	maxRetries := DefaultMaxRetries
+	if v := configfile.Getenv("MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("MAX_RETRIES must be a non-negative integer, got %q", v)
+		}
+		maxRetries = n
+	}
+
+	var readinessProbe bool
+	if v := configfile.Getenv("READINESS_PROBE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("READINESS_PROBE must be true or false, got %q", v)
+		}
+		readinessProbe = b
+	}
+
+	readinessProbeTTL := DefaultReadinessProbeTTL
+	if v := configfile.Getenv("READINESS_PROBE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("READINESS_PROBE_TTL must be a non-negative duration such as 30s, got %q", v)
+		}
+		readinessProbeTTL = d
+	}
+
+	requestTimeout, err := durationEnv("REQUEST_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+	toolTimeout, err := durationEnv("TOOL_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+
+	toolsInclude, toolsExclude := splitList(configfile.Getenv("TOOLS_INCLUDE")), splitList(configfile.Getenv("TOOLS_EXCLUDE"))
+	for _, pattern := range append(slices.Clone(toolsInclude), toolsExclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("TOOLS_INCLUDE and TOOLS_EXCLUDE must be comma-separated globs, got %q", pattern)
+		}
+	}
+
+	spend, err := budget.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := upload.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	bulk, err := batch.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	phoneCountry := configfile.Getenv("PHONE_DEFAULT_COUNTRY")
+	if phoneCountry != "" {
+		c := phone.Lookup(phoneCountry)
+		if c == nil {
+			return nil, fmt.Errorf("PHONE_DEFAULT_COUNTRY must be one of %s, got %q", strings.Join(phone.Codes(), ", "), phoneCountry)
+		}
+		phoneCountry = c.Code
+	}
+
+	suppressed, err := suppression.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	sendTime, err := sendtime.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	templates, err := smstemplate.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var httpClient *http.Client
+	if cassetteMode != "" {
+		cassetteFile := configfile.Getenv("CASSETTE_FILE")
+		if cassetteFile == "" {
+			return nil, fmt.Errorf("CASSETTE_FILE environment variable is required when CASSETTE_MODE is set")
+		}
+		rt, err := cassette.Transport(cassetteMode, cassetteFile)
+		if err != nil {
+			return nil, fmt.Errorf("CASSETTE_MODE: %w", err)
+		}
+		httpClient = &http.Client{Transport: rt}
+	}
+
 	return &APIConfig{
-		BaseURL:     baseURL,
-		BearerToken: os.Getenv("BEARER_TOKEN"),
-		APIKey:      os.Getenv("API_KEY"),
-		BasicAuth:   os.Getenv("BASIC_AUTH"),
-		Port:        port,
+		BaseURL:        baseURL,
+		BearerToken:    configfile.Getenv("BEARER_TOKEN"),
+		APIKey:         configfile.Getenv("API_KEY"),
+		BasicAuth:      configfile.Getenv("BASIC_AUTH"),
+		Port:           port,
+		MaxOutputBytes: maxOutputBytes,
+		HTTPClient:     httpClient,
+		MaxRetries:     maxRetries,
+
+		ReadinessProbe:    readinessProbe,
+		ReadinessProbeTTL: readinessProbeTTL,
+
+		Profiles: profiles,
+		Profile:  profile,
+
+		Subaccounts: subaccounts,
+		Subaccount:  subaccount,
+
+		ListenAddr:     listenAddr,
+		RequestTimeout: requestTimeout,
+		ToolTimeout:    toolTimeout,
+		ToolsInclude:   toolsInclude,
+		ToolsExclude:   toolsExclude,
+		Budget:         spend,
+
+		Uploads:      uploads,
+		Bulk:         bulk,
+		PhoneCountry: phoneCountry,
+
+		Suppression: suppressed,
+		SendTime:    sendTime,
+		Templates:   templates,
 	}, nil
 }
 
+// Reload loads the configuration again, as after the configuration file
+// changed. The recording or replaying client of old is kept, and so is the
+// day's spend of its budget, which takes the new limit, the recipients
+// its suppression registry knows, and the template history when its file
+// is unchanged.
+func Reload(old *APIConfig) (*APIConfig, error) {
+	cfg, err := LoadAPIConfig()
+	if err != nil {
+		return nil, err
+	}
+	if old.HTTPClient != nil {
+		cfg.HTTPClient = old.HTTPClient
+	}
+	if old.Budget != nil {
+		old.Budget.SetLimit(cfg.Budget.Limit())
+		cfg.Budget = old.Budget
+	}
+	if old.Suppression != nil && cfg.Suppression != nil {
+		old.Suppression.Reconfigure(cfg.Suppression)
+		cfg.Suppression = old.Suppression
+	}
+	if old.Templates != nil && old.Templates.History.File() == cfg.Templates.History.File() {
+		cfg.Templates.History = old.Templates.History
+	}
+	return cfg, nil
+}
+
+// ToolEnabled reports whether a tool is served: its name matches a glob of
+// ToolsInclude, or ToolsInclude is empty, and no glob of ToolsExclude.
+func (c *APIConfig) ToolEnabled(name string) bool {
+	match := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(p string) bool {
+			ok, _ := path.Match(p, name)
+			return ok
+		})
+	}
+	return (len(c.ToolsInclude) == 0 || match(c.ToolsInclude)) && !match(c.ToolsExclude)
+}
 
+func durationEnv(name string) (time.Duration, error) {
+	v := configfile.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration such as 30s, got %q", name, v)
+	}
+	return d, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
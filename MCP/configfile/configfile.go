@@ -0,0 +1,443 @@
+// Package configfile loads the server configuration file.
+//
+// The file is YAML, or TOML when its name ends in .toml. Every setting in it
+// stands in for one of the environment variables the server already reads,
+// and a variable that is set in the environment overrides the file.
+// Packages read settings with Getenv instead of os.Getenv so both sources
+// are seen. Use swaps the active file, which is how SIGHUP reloads it.
+package configfile
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/clicksend-rest-api-v3/mcp-server/internal/textmatch"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a loaded and validated configuration file.
+type File struct {
+	// Path is the file the settings were read from.
+	Path string
+	// env maps environment variable names to the values the file sets.
+	env map[string]string
+}
+
+var active atomic.Pointer[File]
+
+// Use makes f the file Getenv falls back to. A nil f removes it.
+func Use(f *File) {
+	active.Store(f)
+}
+
+// Active returns the file passed to Use, or nil.
+func Active() *File {
+	return active.Load()
+}
+
+// LookupEnv returns the environment variable name, or the value the active
+// file sets for it when the environment does not.
+func LookupEnv(name string) (string, bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+	if f := active.Load(); f != nil {
+		v, ok := f.env[name]
+		return v, ok
+	}
+	return "", false
+}
+
+// Getenv is like LookupEnv but returns "" for unset variables.
+func Getenv(name string) string {
+	v, _ := LookupEnv(name)
+	return v
+}
+
+// Load reads and validates a configuration file. Every problem found is
+// reported, each with its key and, for YAML, its line.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]value
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		values, err = parseTOML(data)
+	} else {
+		values, err = parseYAML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	f := &File{Path: path, env: map[string]string{}}
+	var errs []error
+	report := func(v value, key, format string, args ...any) {
+		where := path
+		if v.line > 0 {
+			where += ":" + strconv.Itoa(v.line)
+		}
+		errs = append(errs, fmt.Errorf("%s: %s: %s", where, key, fmt.Sprintf(format, args...)))
+	}
+	for key, v := range values {
+		s, ok := fieldsByKey[key]
+		if !ok {
+			msg := "unknown setting"
+			if near := closest(key); near != "" {
+				msg += fmt.Sprintf(", did you mean %s?", near)
+			}
+			report(v, key, "%s", msg)
+			continue
+		}
+		canonical, err := s.kind.check(v.raw)
+		if err != nil {
+			report(v, key, "%v", err)
+			continue
+		}
+		if s.env != "" {
+			f.env[s.env] = canonical
+		}
+	}
+	if len(errs) == 0 {
+		for _, err := range crossCheck(values) {
+			report(values[err.key], err.key, "%s", err.msg)
+		}
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		return nil, errors.Join(errs...)
+	}
+
+	// username and api_key are a friendlier spelling of basic_auth.
+	if u, ok := values["clicksend.username"]; ok {
+		key := fmt.Sprint(values["clicksend.api_key"].raw)
+		f.env["BASIC_AUTH"] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(u.raw) + ":" + key))
+	}
+	return f, nil
+}
+
+type crossError struct {
+	key, msg string
+}
+
+// crossCheck reports settings that are valid alone but not together. A
+// setting counts as present when the file or the environment sets it.
+func crossCheck(values map[string]value) []crossError {
+	has := func(key string) bool {
+		_, ok := values[key]
+		return ok
+	}
+	get := func(key string) string {
+		if v := os.Getenv(fieldsByKey[key].env); v != "" {
+			return strings.ToLower(v)
+		}
+		if v, ok := values[key]; ok {
+			return strings.ToLower(fmt.Sprint(v.raw))
+		}
+		return ""
+	}
+	var errs []crossError
+	if has("transport") && get("transport") == "https" {
+		for _, key := range []string{"tls.cert_file", "tls.key_file"} {
+			if get(key) == "" {
+				errs = append(errs, crossError{"transport", "https needs " + key})
+			}
+		}
+	}
+	if has("clicksend.username") != has("clicksend.api_key") {
+		errs = append(errs, crossError{"clicksend.username", "username and api_key must be set together"})
+	}
+	if has("clicksend.username") && has("clicksend.basic_auth") {
+		errs = append(errs, crossError{"clicksend.basic_auth", "set either basic_auth or username and api_key, not both"})
+	}
+	if has("profiles.default") && get("profiles.file") == "" && get("profiles.dir") == "" {
+		errs = append(errs, crossError{"profiles.default", "needs profiles.file or profiles.dir"})
+	}
+	if has("profiles.file") && get("profiles.key_file") == "" && os.Getenv("CLICKSEND_PROFILES_KEY") == "" {
+		errs = append(errs, crossError{"profiles.file", "needs profiles.key_file or CLICKSEND_PROFILES_KEY"})
+	}
+	if has("subaccounts.default") && (get("subaccounts.impersonation") == "" || get("subaccounts.impersonation") == "off") {
+		errs = append(errs, crossError{"subaccounts.default", "needs subaccounts.impersonation on or regenerate"})
+	}
+	if has("cassette.mode") && get("cassette.file") == "" {
+		errs = append(errs, crossError{"cassette.mode", "needs cassette.file"})
+	}
+	return errs
+}
+
+// ListenerChanges returns the settings that differ between two files but
+// only take effect on restart.
+func ListenerChanges(old, new *File) []string {
+	var changed []string
+	for _, s := range fields {
+		if s.restart && s.env != "" && old.env[s.env] != new.env[s.env] {
+			changed = append(changed, s.key)
+		}
+	}
+	return changed
+}
+
+// value is a setting read from the file.
+type value struct {
+	raw  any // string, bool, int64, float64 or []string
+	line int // 0 when unknown
+}
+
+func parseYAML(data []byte) (map[string]value, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	values := map[string]value{}
+	if len(doc.Content) == 0 {
+		return values, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("the file must be a mapping of settings")
+	}
+	var walk func(prefix string, n *yaml.Node) error
+	walk = func(prefix string, n *yaml.Node) error {
+		switch n.Kind {
+		case yaml.MappingNode:
+			if prefix != "" && !sections[prefix] {
+				values[prefix] = value{raw: map[string]any{}, line: n.Line}
+				return nil
+			}
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i].Value
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				if err := walk(key, n.Content[i+1]); err != nil {
+					return err
+				}
+			}
+		case yaml.SequenceNode:
+			list := []string{}
+			for _, item := range n.Content {
+				if item.Kind != yaml.ScalarNode {
+					return fmt.Errorf("line %d: %s: list items must be strings", item.Line, prefix)
+				}
+				list = append(list, item.Value)
+			}
+			values[prefix] = value{raw: list, line: n.Line}
+		case yaml.ScalarNode:
+			if n.Tag == "!!null" {
+				return nil
+			}
+			values[prefix] = value{raw: n.Value, line: n.Line}
+		case yaml.AliasNode:
+			return walk(prefix, n.Alias)
+		}
+		return nil
+	}
+	return values, walk("", doc.Content[0])
+}
+
+func parseTOML(data []byte) (map[string]value, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	values := map[string]value{}
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			switch v := v.(type) {
+			case map[string]any:
+				if sections[key] {
+					walk(key, v)
+				} else {
+					values[key] = value{raw: v}
+				}
+			case []any:
+				list := make([]string, len(v))
+				for i, item := range v {
+					list[i] = fmt.Sprint(item)
+				}
+				values[key] = value{raw: list}
+			default:
+				values[key] = value{raw: v}
+			}
+		}
+	}
+	walk("", doc)
+	return values, nil
+}
+
+// closest suggests the known key nearest to an unknown one.
+func closest(key string) string {
+	best, bestDist := "", 3
+	for _, s := range fields {
+		if d := textmatch.Distance(key, s.key); d < bestDist {
+			best, bestDist = s.key, d
+		}
+	}
+	return best
+}
+
+// kind validates a setting and returns the value of its environment
+// variable.
+type kind interface {
+	check(raw any) (string, error)
+}
+
+type stringKind struct{}
+
+func (stringKind) check(raw any) (string, error) {
+	s, ok := raw.(string)
+	if !ok || s == "" {
+		return "", fmt.Errorf("must be a non-empty string, got %s", show(raw))
+	}
+	return s, nil
+}
+
+type enumKind []string
+
+func (e enumKind) check(raw any) (string, error) {
+	s, ok := raw.(string)
+	if ok && slices.Contains(e, strings.ToLower(s)) {
+		return strings.ToLower(s), nil
+	}
+	return "", fmt.Errorf("must be one of %s, got %s", strings.Join(e, ", "), show(raw))
+}
+
+type urlKind struct{}
+
+func (urlKind) check(raw any) (string, error) {
+	s, _ := raw.(string)
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("must be an http or https URL, got %s", show(raw))
+	}
+	return s, nil
+}
+
+type addrKind struct{}
+
+func (addrKind) check(raw any) (string, error) {
+	s, _ := raw.(string)
+	_, port, err := net.SplitHostPort(s)
+	if n, perr := strconv.Atoi(port); err != nil || perr != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("must be host:port or :port, got %s", show(raw))
+	}
+	return s, nil
+}
+
+type durationKind struct{}
+
+func (durationKind) check(raw any) (string, error) {
+	s, _ := raw.(string)
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return "", fmt.Errorf("must be a non-negative duration such as 30s or 5m, got %s", show(raw))
+	}
+	return s, nil
+}
+
+type countKind struct{}
+
+func (countKind) check(raw any) (string, error) {
+	var n int64
+	var err error
+	switch v := raw.(type) {
+	case int64:
+		n = v
+	case string:
+		n, err = strconv.ParseInt(v, 10, 64)
+	default:
+		err = errors.New("not an integer")
+	}
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("must be a non-negative integer, got %s", show(raw))
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+type amountKind struct{}
+
+func (amountKind) check(raw any) (string, error) {
+	var f float64
+	var err error
+	switch v := raw.(type) {
+	case float64:
+		f = v
+	case int64:
+		f = float64(v)
+	case string:
+		f, err = strconv.ParseFloat(v, 64)
+	default:
+		err = errors.New("not a number")
+	}
+	if err != nil || f < 0 {
+		return "", fmt.Errorf("must be a non-negative number, got %s", show(raw))
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
+
+type boolKind struct{}
+
+func (boolKind) check(raw any) (string, error) {
+	switch v := raw.(type) {
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return strconv.FormatBool(b), nil
+		}
+	}
+	return "", fmt.Errorf("must be true or false, got %s", show(raw))
+}
+
+// listKind is a list of strings, or one comma-separated string. Items must
+// be among allowed when it is set.
+type listKind struct {
+	allowed []string
+}
+
+func (l listKind) check(raw any) (string, error) {
+	var items []string
+	switch v := raw.(type) {
+	case []string:
+		items = v
+	case string:
+		items = strings.Split(v, ",")
+	default:
+		return "", fmt.Errorf("must be a list of strings, got %s", show(raw))
+	}
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+		if l.allowed != nil && !slices.Contains(l.allowed, strings.ToLower(items[i])) {
+			return "", fmt.Errorf("item %d must be one of %s, got %q", i+1, strings.Join(l.allowed, ", "), items[i])
+		}
+	}
+	return strings.Join(items, ","), nil
+}
+
+func show(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strconv.Quote(v)
+	case map[string]any:
+		return "a section"
+	case []string:
+		return "a list"
+	}
+	return fmt.Sprint(raw)
+}
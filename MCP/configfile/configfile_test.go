@@ -0,0 +1,161 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	yamlFile := `
+transport: http
+listen: ":8080"
+clicksend:
+  base_url: https://rest.clicksend.com/v3
+  username: alice
+  api_key: secret
+  max_retries: 3
+tools:
+  exclude: [delete_*, "post_reseller_*"]
+  timeout: 45s
+budget:
+  daily_limit: 12.5
+logging:
+  level: DEBUG
+  redact: credentials, phones
+readiness:
+  probe: true
+`
+	tomlFile := `
+transport = "http"
+listen = ":8080"
+
+[clicksend]
+base_url = "https://rest.clicksend.com/v3"
+username = "alice"
+api_key = "secret"
+max_retries = 3
+
+[tools]
+exclude = ["delete_*", "post_reseller_*"]
+timeout = "45s"
+
+[budget]
+daily_limit = 12.5
+
+[logging]
+level = "debug"
+redact = ["credentials", "phones"]
+
+[readiness]
+probe = true
+`
+	want := map[string]string{
+		"TRANSPORT":          "http",
+		"LISTEN_ADDR":        ":8080",
+		"API_BASE_URL":       "https://rest.clicksend.com/v3",
+		"BASIC_AUTH":         "YWxpY2U6c2VjcmV0",
+		"MAX_RETRIES":        "3",
+		"TOOLS_EXCLUDE":      "delete_*,post_reseller_*",
+		"TOOL_TIMEOUT":       "45s",
+		"BUDGET_DAILY_LIMIT": "12.5",
+		"LOG_LEVEL":          "debug",
+		"LOG_REDACT":         "credentials,phones",
+		"READINESS_PROBE":    "true",
+	}
+	for name, content := range map[string]string{"config.yaml": yamlFile, "config.toml": tomlFile} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Load(write(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range want {
+				if f.env[k] != v {
+					t.Errorf("%s = %q, want %q", k, f.env[k], v)
+				}
+			}
+			if len(f.env) != len(want) {
+				t.Errorf("file sets %d variables, want %d: %v", len(f.env), len(want), f.env)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := write(t, "config.yaml", `
+transport: grpc
+clicksend:
+  max_retries: -1
+  timeout: soon
+  base_url: rest.clicksend.com
+tools:
+  exclud: [delete_*]
+logging:
+  redact: [credentials, secrets]
+`)
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Load succeeded, want errors")
+	}
+	for _, want := range []string{
+		path + ":2: transport: must be one of stdio, http, https, got \"grpc\"",
+		path + ":4: clicksend.max_retries: must be a non-negative integer, got \"-1\"",
+		path + ":5: clicksend.timeout: must be a non-negative duration such as 30s or 5m, got \"soon\"",
+		path + ":6: clicksend.base_url: must be an http or https URL, got \"rest.clicksend.com\"",
+		path + ":8: tools.exclud: unknown setting, did you mean tools.exclude?",
+		path + ":10: logging.redact: item 2 must be one of all, none, credentials, phones, emails, bodies, got \"secrets\"",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("errors do not include %q:\n%v", want, err)
+		}
+	}
+
+	_, err = Load(write(t, "config.yaml", "transport: https\ntls:\n  cert_file: cert.pem\n"))
+	if err == nil || !strings.Contains(err.Error(), ":1: transport: https needs tls.key_file") {
+		t.Errorf("err = %v, want a missing tls.key_file error", err)
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	f, err := Load(write(t, "config.yaml", "logging:\n  level: warn\n  format: text\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Use(f)
+	defer Use(nil)
+	t.Setenv("LOG_LEVEL", "error")
+
+	if got := Getenv("LOG_LEVEL"); got != "error" {
+		t.Errorf("Getenv(LOG_LEVEL) = %q, want the environment's error", got)
+	}
+	if got := Getenv("LOG_FORMAT"); got != "text" {
+		t.Errorf("Getenv(LOG_FORMAT) = %q, want the file's text", got)
+	}
+	if _, ok := LookupEnv("LOG_REDACT"); ok {
+		t.Error("LookupEnv(LOG_REDACT) found a value set nowhere")
+	}
+}
+
+func TestListenerChanges(t *testing.T) {
+	old, err := Load(write(t, "old.yaml", "listen: \":8080\"\nlogging:\n  level: info\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := Load(write(t, "new.yaml", "listen: \":9090\"\nlogging:\n  level: debug\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ListenerChanges(old, new); len(got) != 1 || got[0] != "listen" {
+		t.Errorf("ListenerChanges = %v, want [listen]", got)
+	}
+}
@@ -0,0 +1,91 @@
+package configfile
+
+// field is a setting the file may contain.
+type field struct {
+	key  string // dotted path in the file
+	env  string // environment variable it sets; "" when derived from others
+	kind kind
+	// restart marks settings read once at startup, such as the listener.
+	// Reloading a file that changes them logs a warning instead.
+	restart bool
+}
+
+// fields is the schema of the configuration file.
+var fields = []field{
+	{key: "transport", env: "TRANSPORT", kind: enumKind{"stdio", "http", "https"}, restart: true},
+	{key: "listen", env: "LISTEN_ADDR", kind: addrKind{}, restart: true},
+	{key: "tls.cert_file", env: "CERT_FILE", kind: stringKind{}, restart: true},
+	{key: "tls.key_file", env: "KEY_FILE", kind: stringKind{}, restart: true},
+	{key: "auth_file", env: "MCP_AUTH_FILE", kind: stringKind{}},
+
+	{key: "clicksend.base_url", env: "API_BASE_URL", kind: urlKind{}},
+	{key: "clicksend.basic_auth", env: "BASIC_AUTH", kind: stringKind{}},
+	{key: "clicksend.username", kind: stringKind{}},
+	{key: "clicksend.api_key", kind: stringKind{}},
+	{key: "clicksend.timeout", env: "REQUEST_TIMEOUT", kind: durationKind{}},
+	{key: "clicksend.max_retries", env: "MAX_RETRIES", kind: countKind{}},
+	{key: "clicksend.max_output_bytes", env: "MAX_OUTPUT_BYTES", kind: countKind{}},
+
+	{key: "profiles.file", env: "CLICKSEND_PROFILES_FILE", kind: stringKind{}},
+	{key: "profiles.key_file", env: "CLICKSEND_PROFILES_KEY_FILE", kind: stringKind{}},
+	{key: "profiles.dir", env: "CLICKSEND_PROFILES_DIR", kind: stringKind{}},
+	{key: "profiles.default", env: "CLICKSEND_PROFILE", kind: stringKind{}},
+
+	{key: "subaccounts.impersonation", env: "SUBACCOUNT_IMPERSONATION", kind: enumKind{"off", "on", "regenerate"}},
+	{key: "subaccounts.credentials_ttl", env: "SUBACCOUNT_CREDENTIALS_TTL", kind: durationKind{}},
+	{key: "subaccounts.default", env: "CLICKSEND_SUBACCOUNT", kind: stringKind{}},
+
+	{key: "tools.include", env: "TOOLS_INCLUDE", kind: listKind{}},
+	{key: "tools.exclude", env: "TOOLS_EXCLUDE", kind: listKind{}},
+	{key: "tools.timeout", env: "TOOL_TIMEOUT", kind: durationKind{}},
+
+	{key: "uploads.dirs", env: "UPLOAD_DIRS", kind: listKind{}},
+	{key: "uploads.max_bytes", env: "UPLOAD_MAX_BYTES", kind: countKind{}},
+
+	{key: "bulk.concurrency", env: "BULK_CONCURRENCY", kind: countKind{}},
+	{key: "bulk.rate_limit", env: "BULK_RATE_LIMIT", kind: amountKind{}},
+
+	{key: "phone.default_country", env: "PHONE_DEFAULT_COUNTRY", kind: stringKind{}},
+
+	{key: "suppression.enabled", env: "SUPPRESSION", kind: enumKind{"on", "off"}},
+	{key: "suppression.keywords", env: "SUPPRESSION_KEYWORDS", kind: listKind{}},
+	{key: "suppression.lists", env: "SUPPRESSION_LISTS", kind: listKind{}},
+	{key: "suppression.refresh", env: "SUPPRESSION_REFRESH", kind: durationKind{}},
+	{key: "suppression.on_refresh_error", env: "SUPPRESSION_ON_ERROR", kind: enumKind{"block", "send"}},
+	{key: "suppression.file", env: "SUPPRESSION_FILE", kind: stringKind{}},
+	{key: "scheduling.quiet_hours", env: "QUIET_HOURS", kind: stringKind{}},
+	{key: "scheduling.timezone", env: "SCHEDULE_TIMEZONE", kind: stringKind{}},
+	{key: "templates.dir", env: "SMS_TEMPLATE_DIR", kind: stringKind{}},
+	{key: "templates.max_segments", env: "SMS_TEMPLATE_MAX_SEGMENTS", kind: countKind{}},
+	{key: "templates.history_file", env: "SMS_TEMPLATE_HISTORY_FILE", kind: stringKind{}},
+
+	{key: "budget.daily_limit", env: "BUDGET_DAILY_LIMIT", kind: amountKind{}},
+
+	{key: "logging.level", env: "LOG_LEVEL", kind: enumKind{"debug", "info", "warn", "error"}},
+	{key: "logging.format", env: "LOG_FORMAT", kind: enumKind{"json", "text"}},
+	{key: "logging.redact", env: "LOG_REDACT", kind: listKind{allowed: []string{"all", "none", "credentials", "phones", "emails", "bodies"}}},
+	{key: "logging.redact_keys", env: "LOG_REDACT_KEYS", kind: listKind{}},
+
+	{key: "readiness.probe", env: "READINESS_PROBE", kind: boolKind{}},
+	{key: "readiness.probe_ttl", env: "READINESS_PROBE_TTL", kind: durationKind{}},
+
+	{key: "cassette.mode", env: "CASSETTE_MODE", kind: enumKind{"record", "replay"}, restart: true},
+	{key: "cassette.file", env: "CASSETTE_FILE", kind: stringKind{}, restart: true},
+}
+
+var (
+	fieldsByKey = map[string]field{}
+	// sections are the tables settings are grouped in.
+	sections = map[string]bool{}
+)
+
+func init() {
+	for _, f := range fields {
+		fieldsByKey[f.key] = f
+		for i := range f.key {
+			if f.key[i] == '.' {
+				sections[f.key[:i]] = true
+			}
+		}
+	}
+}
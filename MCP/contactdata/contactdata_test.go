@@ -0,0 +1,285 @@
+package contactdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+)
+
+// importFields are the fields the import endpoint reports.
+var importFields = []clicksend.ImportField{
+	{Field: "phone", Label: "Phone"},
+	{Field: "first_name", Label: "First Name"},
+	{Field: "last_name", Label: "Last Name"},
+	{Field: "custom1", Label: "Custom String 1"},
+	{Field: "email", Label: "Email"},
+	{Field: "fax_number", Label: "Fax Number"},
+}
+
+func TestRead(t *testing.T) {
+	csv := &upload.File{Name: "contacts.csv", Content: []byte("\ufeffPhone, Name \n\n+61411111111,Ann,extra\n+61422222222\n")}
+	table, err := Read(csv, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Table{Header: []string{"Phone", "Name"}, Rows: [][]string{{"+61411111111", "Ann"}, {"+61422222222", ""}}}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("csv = %+v, want %+v", table, want)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range map[string]string{
+		"xl/sharedStrings.xml":     `<sst><si><t>Mobile</t></si><si><r><t>Fi</t></r><r><t>rst</t></r></si><si><t>Ann</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row><row><c r="A2"><v>61411111111</v></c><c r="C2" t="s"><v>2</v></c></row></sheetData></worksheet>`,
+	} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(body))
+	}
+	zw.Close()
+	table, err = Read(&upload.File{Name: "contacts.xlsx", Content: buf.Bytes()}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &Table{Header: []string{"Mobile", "", "First"}, Rows: [][]string{{"61411111111", "", "Ann"}}}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("xlsx = %+v, want %+v", table, want)
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	table, err := FromRows([]any{
+		map[string]any{"phone": 61411111111.0, "name": "Ann"},
+		map[string]any{"email": "bob@example.com", "name": "Bob"},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Table{Header: []string{"name", "phone", "email"}, Rows: [][]string{{"Ann", "61411111111", ""}, {"Bob", "", "bob@example.com"}}}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("objects = %+v, want %+v", table, want)
+	}
+
+	table, err = FromRows([]any{[]any{"+61411111111", "Ann"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"column_1", "column_2"}; !reflect.DeepEqual(table.Header, want) {
+		t.Errorf("header = %q, want %q", table.Header, want)
+	}
+
+	if _, err := FromRows([]any{map[string]any{}, "x"}, true); err == nil {
+		t.Error("mixed rows were accepted")
+	}
+}
+
+func TestMap(t *testing.T) {
+	header := []string{"Mobile", "Frist Name", "Surname", "Custom String 1", "E-mail", "Notes", "Phone 2"}
+	m, err := Map(header, importFields, map[string]string{"Phone 2": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Mapping{
+		{"Mobile", "phone", MatchAlias},
+		{"Frist Name", "first_name", MatchFuzzy},
+		{"Surname", "last_name", MatchAlias},
+		{"Custom String 1", "custom1", MatchExact},
+		{"E-mail", "email", MatchExact},
+		{"Notes", "", ""},
+		{"Phone 2", "", MatchManual},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Map = %+v, want %+v", m, want)
+	}
+	if got := m.Fields(); !reflect.DeepEqual(got, []string{"phone", "first_name", "last_name", "custom1", "email"}) {
+		t.Errorf("Fields = %q", got)
+	}
+
+	m, err = Map([]string{"Phone", "Cell"}, importFields, map[string]string{"Cell": "phone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m[0].Field != "" || m[1].Field != "phone" {
+		t.Errorf("override did not take the field from the exact match: %+v", m)
+	}
+
+	for overrides, wantErr := range map[string]string{
+		"Phone=mobile": `"mobile" is not an import field`,
+		"Fax=phone":    `no column is named "Fax"`,
+	} {
+		k, v, _ := strings.Cut(overrides, "=")
+		if _, err := Map([]string{"Phone", "Cell"}, importFields, map[string]string{k: v}); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Map with %s: err = %v, want %q", overrides, err, wantErr)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	table := &Table{
+		Header: []string{"phone", "email", "name"},
+		Rows: [][]string{
+			{"+61 411 111 111", "ann@example.com", "Ann"},
+			{"0412", "", "Bob"},
+			{"+61411111111", "", "Cat"},
+			{"", "Dan <dan@example.com>", "Dan"},
+			{"", "", "Eve"},
+			{"(02) 9999-0000", "", "Fay"},
+		},
+	}
+	m, err := Map(table.Header, importFields, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := m.Check(table, importFields, "AU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{
+		{2, []string{`phone: "0412" is not a valid number in Australia`}},
+		{3, []string{"phone: duplicate of row 1"}},
+		{4, []string{`email: "Dan <dan@example.com>" is not an email address`}},
+		{5, []string{"needs a phone number, email or fax number"}},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("Check = %+v, want %+v", issues, want)
+	}
+	if table.Rows[0][0] != "+61411111111" || table.Rows[5][0] != "+61299990000" {
+		t.Errorf("numbers were not normalized: %q, %q", table.Rows[0][0], table.Rows[5][0])
+	}
+
+	m, _ = Map([]string{"name"}, importFields, nil)
+	if _, err := m.Check(&Table{Header: []string{"name"}}, importFields, ""); err == nil {
+		t.Error("a mapping without phone, email or fax columns was accepted")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current := []clicksend.Contact{
+		{ContactID: 1, PhoneNumber: "+61411111111", FirstName: "Ann"},
+		{ContactID: 2, PhoneNumber: "+61422222222", FirstName: "Bob"},
+		{ContactID: 3, PhoneNumber: "+61433333333"},
+		{ContactID: 4, PhoneNumber: "+61444444444"},
+		{ContactID: 5, PhoneNumber: "0411 111 111"},
+		{ContactID: 6, Email: "no-phone@example.com"},
+	}
+	desired := []map[string]string{
+		{"phone_number": "0411111111", "first_name": "Ann"},
+		{"phone_number": "+61422222222", "first_name": "Robert", "last_name": ""},
+		{"phone_number": "+61455555555", "first_name": "Eve"},
+		{"phone_number": "+61444444444", "email": "bad"},
+	}
+	plan, err := Diff(desired, map[int]bool{4: true}, current, "phone_number", "AU", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Plan{
+		Changes: []Change{
+			{Op: OpUpdate, Key: "+61422222222", Row: 2, ContactID: "2", Fields: map[string]string{"first_name": "Robert"}, Before: map[string]string{"first_name": "Bob"}},
+			{Op: OpCreate, Key: "+61455555555", Row: 3, Fields: desired[2]},
+			{Op: OpDelete, Key: "+61433333333", ContactID: "3"},
+			{Op: OpDelete, Key: "+61411111111", ContactID: "5"},
+		},
+		Unchanged: 1,
+		Ignored:   1,
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("Diff = %+v, want %+v", plan, want)
+	}
+
+	plan, _ = Diff(desired, map[int]bool{4: true}, current, "phone_number", "AU", false)
+	if plan.Count(OpDelete) != 0 {
+		t.Errorf("deletes without deleteMissing: %+v", plan.Changes)
+	}
+
+	again, _ := Diff(desired, map[int]bool{4: true}, current, "phone_number", "AU", false)
+	if again.Hash() != plan.Hash() {
+		t.Error("Hash differs between equal plans")
+	}
+	current[1].FirstName = "Rob"
+	changed, _ := Diff(desired, map[int]bool{4: true}, current, "phone_number", "AU", false)
+	if changed.Hash() == plan.Hash() {
+		t.Error("Hash is the same after the list changed")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	rec := map[string]string{"custom_1": "Gold", "address_state": "NSW", "email": "ann@example.com", "custom_2": "42"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"custom_1 = gold", true},
+		{"custom_1 != gold", false},
+		{`email ~ "@EXAMPLE.com" and address_state in (nsw, vic)`, true},
+		{"email ^= bob or custom_1 $= old", true},
+		{"not (custom_1 = gold or custom_1 = silver)", false},
+		{"custom_3 is empty and custom_1 is not empty", true},
+		{"custom_2 > 9", true},
+		{"custom_2 <= 9", false},
+		{"custom_3 < 9", false},
+		{"address_state = NSW and custom_1 = silver or email !~ example", false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr, ContactFields)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(rec); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	f, _ := ParseFilter("custom_1 = a or (address_city ~ b and custom_1 != c)", ContactFields)
+	if got := f.Fields(); !reflect.DeepEqual(got, []string{"custom_1", "address_city"}) {
+		t.Errorf("Fields = %v", got)
+	}
+
+	for expr, want := range map[string]string{
+		"colour = red":          `"colour" at position 1 is not a field`,
+		"custom_1 = ":           "want a value at position 12",
+		"custom_1 = 'red":       "unterminated string at position 12",
+		"(custom_1 = red":       "want ) at position 16",
+		"custom_1 red":          "want an operator after custom_1 at position 10",
+		"custom_1 = a custom_2": `unexpected "custom_2" at position 14`,
+		"custom_1 is full":      "want empty at position 13",
+	} {
+		if _, err := ParseFilter(expr, ContactFields); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseFilter(%q) err = %v, want %q", expr, err, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	into := []clicksend.Contact{
+		{ContactID: 1, PhoneNumber: "+61411111111"},
+		{ContactID: 2, Email: "bob@example.com"},
+	}
+	from := []clicksend.Contact{
+		{ContactID: 10, PhoneNumber: "0411 111 111"},
+		{ContactID: 11, PhoneNumber: "+61422222222"},
+		{ContactID: 12, PhoneNumber: "0422222222"},
+		{ContactID: 13, Email: "bob@example.com"},
+	}
+	add, dups, err := Merge(from, into, "phone_number", "AU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(add) != 2 || add[0].ContactID != 11 || add[1].ContactID != 13 {
+		t.Errorf("add = %+v", add)
+	}
+	want := []Duplicate{{ContactID: "10", Key: "+61411111111", Of: "1"}, {ContactID: "12", Key: "+61422222222", Of: "11"}}
+	if !reflect.DeepEqual(dups, want) {
+		t.Errorf("duplicates = %+v, want %+v", dups, want)
+	}
+
+	add, dups, _ = Merge(from, into, "email", "AU")
+	if len(add) != 3 || len(dups) != 1 || dups[0].Of != "2" {
+		t.Errorf("by email: add %+v, duplicates %+v", add, dups)
+	}
+}
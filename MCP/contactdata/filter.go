@@ -0,0 +1,365 @@
+package contactdata
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+// Filter is a parsed filter expression over contact fields, such as
+//
+//	custom_1 = gold and (address_state in (NSW, VIC) or email ~ "@example.com")
+//
+// A condition compares a field with a value: = and != for equality, ~ and
+// !~ for containment, ^= and $= for prefixes and suffixes, < <= > >= for
+// order (numeric when both sides are numbers), and in (a, b) for a set.
+// "field is empty" and "field is not empty" test for a value. Conditions
+// combine with and, or, not and parentheses. Comparisons ignore case and
+// surrounding space; values with spaces or symbols are quoted.
+type Filter struct {
+	src    string
+	root   node
+	fields []string
+}
+
+// ParseFilter parses a filter expression whose fields are among fields.
+func ParseFilter(s string, fields []clicksend.ImportField) (*Filter, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, fields: fields}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return &Filter{src: s, root: root, fields: p.used}, nil
+}
+
+// Match reports whether the contact record rec, named as in ContactFields,
+// passes the filter.
+func (f *Filter) Match(rec map[string]string) bool {
+	return f.root.match(rec)
+}
+
+// Fields returns the fields the filter reads, in order of first use.
+func (f *Filter) Fields() []string {
+	return f.fields
+}
+
+func (f *Filter) String() string {
+	return f.src
+}
+
+type node interface {
+	match(rec map[string]string) bool
+}
+
+type andNode []node
+
+func (n andNode) match(rec map[string]string) bool {
+	for _, c := range n {
+		if !c.match(rec) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []node
+
+func (n orNode) match(rec map[string]string) bool {
+	for _, c := range n {
+		if c.match(rec) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct{ node }
+
+func (n notNode) match(rec map[string]string) bool {
+	return !n.node.match(rec)
+}
+
+type condNode struct {
+	field, op string
+	values    []string // lowercase; one except for "in"
+}
+
+func (n condNode) match(rec map[string]string) bool {
+	v := strings.ToLower(strings.TrimSpace(rec[n.field]))
+	switch n.op {
+	case "empty":
+		return v == ""
+	case "=":
+		return v == n.values[0]
+	case "!=":
+		return v != n.values[0]
+	case "~":
+		return strings.Contains(v, n.values[0])
+	case "!~":
+		return !strings.Contains(v, n.values[0])
+	case "^=":
+		return strings.HasPrefix(v, n.values[0])
+	case "$=":
+		return strings.HasSuffix(v, n.values[0])
+	case "in":
+		return slices.Contains(n.values, v)
+	}
+	if v == "" {
+		return false
+	}
+	c := strings.Compare(v, n.values[0])
+	a, aErr := strconv.ParseFloat(v, 64)
+	b, bErr := strconv.ParseFloat(n.values[0], 64)
+	if aErr == nil && bErr == nil {
+		c = 0
+		if a < b {
+			c = -1
+		} else if a > b {
+			c = 1
+		}
+	}
+	switch n.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+const (
+	tokEOF = iota
+	tokWord
+	tokString
+	tokOp
+	tokPunct
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int // 1-based rune position in the expression
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether t is the unquoted keyword kw.
+func (t token) is(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+var ops = []string{"!=", "!~", "^=", "$=", "<=", ">=", "=", "~", "<", ">"}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			toks = append(toks, token{tokPunct, string(r), i + 1})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				j++
+			}
+			if j == len(rs) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			toks = append(toks, token{tokString, string(rs[i+1 : j]), i + 1})
+			i = j + 1
+		default:
+			if op := opAt(rs[i:]); op != "" {
+				toks = append(toks, token{tokOp, op, i + 1})
+				i += len(op)
+				continue
+			}
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune(`()," '`, rs[j]) && opAt(rs[j:]) == "" {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i+1)
+			}
+			toks = append(toks, token{tokWord, string(rs[i:j]), i + 1})
+			i = j
+		}
+	}
+	return append(toks, token{tokEOF, "", len(rs) + 1}), nil
+}
+
+// opAt returns the operator rs starts with, or "".
+func opAt(rs []rune) string {
+	for _, op := range ops {
+		if strings.HasPrefix(string(rs[:min(len(rs), 2)]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type parser struct {
+	toks   []token
+	i      int
+	fields []clicksend.ImportField
+	used   []string
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	var n orNode
+	for {
+		c, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		n = append(n, c)
+		if !p.peek().is("or") {
+			break
+		}
+		p.next()
+	}
+	if len(n) == 1 {
+		return n[0], nil
+	}
+	return n, nil
+}
+
+func (p *parser) and() (node, error) {
+	var n andNode
+	for {
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		n = append(n, c)
+		if !p.peek().is("and") {
+			break
+		}
+		p.next()
+	}
+	if len(n) == 1 {
+		return n[0], nil
+	}
+	return n, nil
+}
+
+func (p *parser) unary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.is("not"):
+		p.next()
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{c}, nil
+	case t.kind == tokPunct && t.text == "(":
+		p.next()
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokPunct || t.text != ")" {
+			return nil, fmt.Errorf("want ) at position %d, got %s", t.pos, t)
+		}
+		return c, nil
+	}
+	return p.cond()
+}
+
+func (p *parser) cond() (node, error) {
+	t := p.next()
+	if t.kind != tokWord {
+		return nil, fmt.Errorf("want a field at position %d, got %s", t.pos, t)
+	}
+	field := strings.ToLower(t.text)
+	if !slices.ContainsFunc(p.fields, func(f clicksend.ImportField) bool { return f.Field == field }) {
+		return nil, fmt.Errorf("%q at position %d is not a field; use one of %s", t.text, t.pos, fieldNames(p.fields))
+	}
+	if !slices.Contains(p.used, field) {
+		p.used = append(p.used, field)
+	}
+
+	op := p.next()
+	switch {
+	case op.is("is"):
+		n := node(condNode{field: field, op: "empty"})
+		if p.peek().is("not") {
+			p.next()
+			n = notNode{n}
+		}
+		if t := p.next(); !t.is("empty") {
+			return nil, fmt.Errorf("want empty at position %d, got %s", t.pos, t)
+		}
+		return n, nil
+	case op.is("in"):
+		if t := p.next(); t.kind != tokPunct || t.text != "(" {
+			return nil, fmt.Errorf("want ( at position %d, got %s", t.pos, t)
+		}
+		n := condNode{field: field, op: "in"}
+		for {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, v)
+			t := p.next()
+			if t.kind == tokPunct && t.text == ")" {
+				return n, nil
+			}
+			if t.kind != tokPunct || t.text != "," {
+				return nil, fmt.Errorf("want , or ) at position %d, got %s", t.pos, t)
+			}
+		}
+	case op.kind == tokOp:
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return condNode{field: field, op: op.text, values: []string{v}}, nil
+	}
+	return nil, fmt.Errorf("want an operator after %s at position %d, got %s", field, op.pos, op)
+}
+
+func (p *parser) value() (string, error) {
+	t := p.next()
+	if t.kind != tokWord && t.kind != tokString {
+		return "", fmt.Errorf("want a value at position %d, got %s", t.pos, t)
+	}
+	return strings.ToLower(strings.TrimSpace(t.text)), nil
+}
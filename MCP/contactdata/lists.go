@@ -0,0 +1,50 @@
+package contactdata
+
+import (
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+// Duplicate is a contact left out of a merge because another contact has
+// its key.
+type Duplicate struct {
+	ContactID string `json:"contact_id"`
+	Key       string `json:"key"`
+	// Of is the contact with the key: one of the target list, or one merged
+	// before this one.
+	Of string `json:"of"`
+}
+
+// Merge plans adding the contacts of from to a list holding into, without
+// creating duplicates by key, "phone_number" or "email" (see SyncKey). It
+// returns the contacts of from to add, in order, and those left out because
+// a contact of into or an earlier one of from has their key. Contacts
+// without a value for the key are always added.
+func Merge(from, into []clicksend.Contact, key, country string) ([]clicksend.Contact, []Duplicate, error) {
+	if key != "phone_number" && key != "email" {
+		return nil, nil, fmt.Errorf("key must be phone_number or email, got %q", key)
+	}
+	seen := map[string]string{}
+	for _, c := range into {
+		if k := SyncKey(Record(c), key, country); k != "" {
+			if _, ok := seen[k]; !ok {
+				seen[k] = fmt.Sprint(c.ContactID)
+			}
+		}
+	}
+	var add []clicksend.Contact
+	var dups []Duplicate
+	for _, c := range from {
+		k := SyncKey(Record(c), key, country)
+		if of, ok := seen[k]; ok && k != "" {
+			dups = append(dups, Duplicate{ContactID: fmt.Sprint(c.ContactID), Key: k, Of: of})
+			continue
+		}
+		if k != "" {
+			seen[k] = fmt.Sprint(c.ContactID)
+		}
+		add = append(add, c)
+	}
+	return add, dups, nil
+}
@@ -0,0 +1,224 @@
+package contactdata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/internal/textmatch"
+)
+
+// How a column was matched to a field.
+const (
+	MatchManual = "manual" // named in the caller's field map
+	MatchExact  = "exact"  // header equals the field name or label
+	MatchAlias  = "alias"  // header is a known synonym, such as "mobile" for the phone field
+	MatchFuzzy  = "fuzzy"  // header is a near misspelling of the field name or label
+)
+
+// Column maps a table column to a contact field. Field is "" for columns
+// that are not imported.
+type Column struct {
+	Header string `json:"header"`
+	Field  string `json:"field,omitempty"`
+	Match  string `json:"match,omitempty"`
+}
+
+// Mapping maps each column of a table, in order.
+type Mapping []Column
+
+// Roles of contact fields, shared by the differently named fields of the
+// import endpoint ("phone", "custom1") and of contacts ("phone_number",
+// "custom_1").
+const (
+	RolePhone = "phone"
+	RoleEmail = "email"
+	RoleFax   = "fax"
+)
+
+// aliases maps header keys (see textmatch.Key) to the role they name.
+var aliases = map[string]string{}
+
+func init() {
+	for role, keys := range map[string][]string{
+		RolePhone:  {"phone", "phonenumber", "mobile", "mobilenumber", "mobilephone", "cell", "cellphone", "tel", "telephone", "msisdn"},
+		RoleEmail:  {"email", "emailaddress", "mail"},
+		RoleFax:    {"fax", "faxnumber"},
+		"first":    {"first", "firstname", "givenname", "forename", "fname"},
+		"last":     {"last", "lastname", "surname", "familyname", "lname"},
+		"org":      {"organization", "organisation", "organizationname", "organisationname", "company", "companyname", "business"},
+		"custom1":  {"custom1", "customstring1"},
+		"custom2":  {"custom2", "customstring2"},
+		"custom3":  {"custom3", "customstring3"},
+		"custom4":  {"custom4", "customstring4"},
+		"addr1":    {"addressline1", "address1", "address", "street", "streetaddress"},
+		"addr2":    {"addressline2", "address2"},
+		"city":     {"addresscity", "city", "suburb", "town"},
+		"state":    {"addressstate", "state", "province", "region"},
+		"postcode": {"addresspostalcode", "postalcode", "postcode", "zip", "zipcode"},
+		"country":  {"addresscountry", "country"},
+	} {
+		for _, k := range keys {
+			aliases[k] = role
+		}
+	}
+}
+
+// Role returns the role of a field, such as RolePhone, from its name or
+// label, or "".
+func Role(f clicksend.ImportField) string {
+	if r := aliases[textmatch.Key(f.Field)]; r != "" {
+		return r
+	}
+	return aliases[textmatch.Key(f.Label)]
+}
+
+// ContactFields are the fields of clicksend.Contact, for mapping tables to
+// contacts rather than to the import endpoint.
+var ContactFields = []clicksend.ImportField{
+	{Field: "phone_number", Label: "Phone Number"},
+	{Field: "email", Label: "Email"},
+	{Field: "fax_number", Label: "Fax Number"},
+	{Field: "first_name", Label: "First Name"},
+	{Field: "last_name", Label: "Last Name"},
+	{Field: "organization_name", Label: "Organization Name"},
+	{Field: "custom_1", Label: "Custom 1"},
+	{Field: "custom_2", Label: "Custom 2"},
+	{Field: "custom_3", Label: "Custom 3"},
+	{Field: "custom_4", Label: "Custom 4"},
+	{Field: "address_line_1", Label: "Address Line 1"},
+	{Field: "address_line_2", Label: "Address Line 2"},
+	{Field: "address_city", Label: "Address City"},
+	{Field: "address_state", Label: "Address State"},
+	{Field: "address_postal_code", Label: "Address Postal Code"},
+	{Field: "address_country", Label: "Address Country"},
+}
+
+// Map matches each header to one of fields. overrides maps headers to field
+// names chosen by the caller; an empty name skips the column. Other headers
+// are matched exactly first, then by alias, then fuzzily, and each field is
+// used at most once.
+func Map(header []string, fields []clicksend.ImportField, overrides map[string]string) (Mapping, error) {
+	m := make(Mapping, len(header))
+	used := map[string]bool{}
+	for i, h := range header {
+		m[i].Header = h
+		name, ok := overrides[h]
+		if !ok {
+			continue
+		}
+		m[i].Match = MatchManual
+		if name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(fields, func(f clicksend.ImportField) bool { return f.Field == name }) {
+			return nil, fmt.Errorf("field_map: %q is not an import field; use one of %s", name, fieldNames(fields))
+		}
+		if used[name] {
+			return nil, fmt.Errorf("field_map: %q is mapped from more than one column", name)
+		}
+		m[i].Field = name
+		used[name] = true
+	}
+	for h := range overrides {
+		if !slices.Contains(header, h) {
+			return nil, fmt.Errorf("field_map: no column is named %q", h)
+		}
+	}
+
+	passes := []struct {
+		match string
+		score func(key string, f clicksend.ImportField) int // lower is better; -1 is no match
+	}{
+		{MatchExact, func(key string, f clicksend.ImportField) int {
+			if key == textmatch.Key(f.Field) || key == textmatch.Key(f.Label) {
+				return 0
+			}
+			return -1
+		}},
+		{MatchAlias, func(key string, f clicksend.ImportField) int {
+			if r := aliases[key]; r != "" && r == Role(f) {
+				return 0
+			}
+			return -1
+		}},
+		{MatchFuzzy, func(key string, f clicksend.ImportField) int {
+			best := -1
+			for _, name := range []string{textmatch.Key(f.Field), textmatch.Key(f.Label)} {
+				d := textmatch.Distance(key, name)
+				if d <= max(1, len(name)/4) && (best < 0 || d < best) {
+					best = d
+				}
+			}
+			return best
+		}},
+	}
+	for _, pass := range passes {
+		for i := range m {
+			if m[i].Match != "" {
+				continue
+			}
+			key := textmatch.Key(m[i].Header)
+			if key == "" {
+				continue
+			}
+			best, bestScore := "", -1
+			for _, f := range fields {
+				if used[f.Field] {
+					continue
+				}
+				if s := pass.score(key, f); s >= 0 && (bestScore < 0 || s < bestScore) {
+					best, bestScore = f.Field, s
+				}
+			}
+			if best != "" {
+				m[i].Field, m[i].Match = best, pass.match
+				used[best] = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func fieldNames(fields []clicksend.ImportField) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Field
+	}
+	return strings.Join(names, ", ")
+}
+
+// Fields returns the mapped field names, in column order.
+func (m Mapping) Fields() []string {
+	var fields []string
+	for _, c := range m {
+		if c.Field != "" {
+			fields = append(fields, c.Field)
+		}
+	}
+	return fields
+}
+
+// Unmapped returns the headers of columns that are not imported.
+func (m Mapping) Unmapped() []string {
+	var headers []string
+	for _, c := range m {
+		if c.Field == "" {
+			headers = append(headers, c.Header)
+		}
+	}
+	return headers
+}
+
+// Record returns the mapped cells of a row keyed by field name. Empty cells
+// are left out.
+func (m Mapping) Record(row []string) map[string]string {
+	rec := map[string]string{}
+	for i, c := range m {
+		if c.Field != "" && i < len(row) && row[i] != "" {
+			rec[c.Field] = row[i]
+		}
+	}
+	return rec
+}
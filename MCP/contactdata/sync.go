@@ -0,0 +1,179 @@
+package contactdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+)
+
+// Operations of a sync plan.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Change is one step of a sync plan.
+type Change struct {
+	Op        string `json:"op"`
+	Key       string `json:"key"`
+	Row       int    `json:"row,omitempty"` // data row of the desired contact
+	ContactID string `json:"contact_id,omitempty"`
+	// Fields are the fields to set: every field for a create, the changed
+	// ones for an update.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Before holds the current values of the fields an update changes.
+	Before map[string]string `json:"before,omitempty"`
+}
+
+// Plan is the difference between the contacts a list should hold and those
+// it holds.
+type Plan struct {
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+	// Ignored counts contacts of the list without a value for the key,
+	// which a sync leaves alone.
+	Ignored int `json:"ignored"`
+}
+
+// Count returns the number of changes with op.
+func (p *Plan) Count(op string) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// Hash returns a digest of the changes of p, which a confirmed sync
+// compares with the preview's to refuse a plan that changed in between.
+func (p *Plan) Hash() string {
+	data, _ := json.Marshal(p.Changes)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Record returns the ContactFields of c that have a value.
+func Record(c clicksend.Contact) map[string]string {
+	data, _ := json.Marshal(c)
+	var all map[string]any
+	json.Unmarshal(data, &all)
+	rec := map[string]string{}
+	for _, f := range ContactFields {
+		if v := cell(all[f.Field]); v != "" {
+			rec[f.Field] = v
+		}
+	}
+	return rec
+}
+
+// Contact returns the contact with the fields of rec, which are named as in
+// ContactFields.
+func Contact(rec map[string]string) clicksend.Contact {
+	var c clicksend.Contact
+	data, _ := json.Marshal(rec)
+	json.Unmarshal(data, &c)
+	return c
+}
+
+// SyncKey returns the value contacts are matched on for the key field,
+// "phone_number" or "email": an E.164 number, read in country when local,
+// or a lowercase email.
+func SyncKey(rec map[string]string, key, country string) string {
+	v := strings.TrimSpace(rec[key])
+	if v == "" {
+		return ""
+	}
+	if key == "email" {
+		return strings.ToLower(v)
+	}
+	if n, err := phone.Parse(v, country); err == nil {
+		return n.E164
+	}
+	return NumberKey(v)
+}
+
+// Diff plans the changes that make current, the contacts of a list, match
+// desired, records of ContactFields from the data rows of a table with
+// skip marking rows left out. Contacts are matched on key, "phone_number"
+// or "email". Empty desired fields leave the current value alone. Contacts
+// of the list that match no desired row, counting the rows left out, and
+// all but the first contact sharing a key are deleted when deleteMissing
+// is set.
+func Diff(desired []map[string]string, skip map[int]bool, current []clicksend.Contact, key, country string, deleteMissing bool) (*Plan, error) {
+	if key != "phone_number" && key != "email" {
+		return nil, fmt.Errorf("key must be phone_number or email, got %q", key)
+	}
+	plan := &Plan{}
+	byKey := map[string]clicksend.Contact{}
+	var extra []clicksend.Contact
+	for _, c := range current {
+		k := SyncKey(Record(c), key, country)
+		switch _, dup := byKey[k]; {
+		case k == "":
+			plan.Ignored++
+		case dup:
+			extra = append(extra, c)
+		default:
+			byKey[k] = c
+		}
+	}
+
+	matched := map[string]bool{}
+	for i, rec := range desired {
+		k := SyncKey(rec, key, country)
+		if skip[i+1] {
+			// A row left out still keeps its contact from being deleted
+			if k != "" {
+				matched[k] = true
+			}
+			continue
+		}
+		if k == "" {
+			return nil, fmt.Errorf("row %d has no %s to match on", i+1, key)
+		}
+		if matched[k] {
+			return nil, fmt.Errorf("row %d repeats %s %s", i+1, key, k)
+		}
+		matched[k] = true
+		c, ok := byKey[k]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Op: OpCreate, Key: k, Row: i + 1, Fields: rec})
+			continue
+		}
+		have := Record(c)
+		fields, before := map[string]string{}, map[string]string{}
+		for f, v := range rec {
+			if v == "" || v == have[f] || f == key && SyncKey(have, key, country) == k {
+				continue
+			}
+			fields[f], before[f] = v, have[f]
+		}
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{Op: OpUpdate, Key: k, Row: i + 1, ContactID: fmt.Sprint(c.ContactID), Fields: fields, Before: before})
+	}
+
+	if deleteMissing {
+		for k, c := range byKey {
+			if !matched[k] {
+				extra = append(extra, c)
+			}
+		}
+		slices.SortFunc(extra, func(a, b clicksend.Contact) int { return int(a.ContactID - b.ContactID) })
+		for _, c := range extra {
+			plan.Changes = append(plan.Changes, Change{Op: OpDelete, Key: SyncKey(Record(c), key, country), ContactID: fmt.Sprint(c.ContactID)})
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,244 @@
+// Package contactdata reads contact tables from CSV and Excel files or
+// inline rows, maps their columns to ClickSend contact fields and checks
+// each row before contacts are imported or synced.
+package contactdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+)
+
+// Table is a header row and the data rows under it. Rows are padded or
+// trimmed to the header's width.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Read parses a CSV or XLSX file. The first row is the header unless
+// header is false, in which case columns are named column_1, column_2 and
+// so on.
+func Read(f *upload.File, header bool) (*Table, error) {
+	var records [][]string
+	var err error
+	switch upload.Detect(f) {
+	case "csv":
+		r := csv.NewReader(bytes.NewReader(f.Content))
+		r.FieldsPerRecord = -1
+		records, err = r.ReadAll()
+	case "xlsx":
+		records, err = readXLSX(f.Content)
+	default:
+		return nil, fmt.Errorf("%s files cannot be read; use csv or xlsx", upload.Detect(f))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newTable(records, header)
+}
+
+// FromRows builds a table from inline rows: objects keyed by column name, or
+// arrays of cells whose first row is the header unless header is false.
+func FromRows(rows []any, header bool) (*Table, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("no rows")
+	}
+	if _, ok := rows[0].(map[string]any); ok {
+		t := &Table{}
+		index := map[string]int{}
+		var objects []map[string]any
+		for i, row := range rows {
+			obj, ok := row.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("row %d is not an object", i+1)
+			}
+			keys := make([]string, 0, len(obj))
+			for k := range obj {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				if _, ok := index[k]; !ok {
+					index[k] = len(t.Header)
+					t.Header = append(t.Header, k)
+				}
+			}
+			objects = append(objects, obj)
+		}
+		for _, obj := range objects {
+			row := make([]string, len(t.Header))
+			for k, v := range obj {
+				row[index[k]] = cell(v)
+			}
+			t.Rows = append(t.Rows, row)
+		}
+		return t, nil
+	}
+	var records [][]string
+	for i, row := range rows {
+		cells, ok := row.([]any)
+		if !ok {
+			return nil, fmt.Errorf("row %d is not an array or object", i+1)
+		}
+		record := make([]string, len(cells))
+		for j, v := range cells {
+			record[j] = cell(v)
+		}
+		records = append(records, record)
+	}
+	return newTable(records, header)
+}
+
+// cell formats a JSON value as a table cell. Numbers keep every digit, so a
+// phone number sent as 61411111111 is not rounded.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func newTable(records [][]string, header bool) (*Table, error) {
+	// Blank lines carry no contact
+	records = slices.DeleteFunc(records, func(r []string) bool {
+		return !slices.ContainsFunc(r, func(c string) bool { return strings.TrimSpace(c) != "" })
+	})
+	if len(records) == 0 {
+		return nil, errors.New("the file has no rows")
+	}
+	t := &Table{}
+	if header {
+		for _, h := range records[0] {
+			t.Header = append(t.Header, strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		}
+		records = records[1:]
+	} else {
+		width := 0
+		for _, r := range records {
+			width = max(width, len(r))
+		}
+		for i := range width {
+			t.Header = append(t.Header, fmt.Sprintf("column_%d", i+1))
+		}
+	}
+	for _, r := range records {
+		row := make([]string, len(t.Header))
+		for i := range min(len(r), len(row)) {
+			row[i] = strings.TrimSpace(r[i])
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+// readXLSX returns the cells of the first worksheet of an Excel workbook.
+func readXLSX(content []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	open := func(name string) ([]byte, error) {
+		f, err := zr.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	var shared []string
+	if data, err := open("xl/sharedStrings.xml"); err == nil {
+		var sst struct {
+			Items []struct {
+				Text string `xml:"t"`
+				Runs []struct {
+					Text string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := xml.Unmarshal(data, &sst); err != nil {
+			return nil, fmt.Errorf("invalid xlsx shared strings: %w", err)
+		}
+		for _, si := range sst.Items {
+			s := si.Text
+			for _, r := range si.Runs {
+				s += r.Text
+			}
+			shared = append(shared, s)
+		}
+	}
+
+	data, err := open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, errors.New("invalid xlsx file: no first worksheet")
+	}
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("invalid xlsx worksheet: %w", err)
+	}
+	var records [][]string
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, c := range row.Cells {
+			col := columnIndex(c.Ref)
+			if col < 0 {
+				col = i
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("invalid xlsx cell %s", c.Ref)
+				}
+				record[col] = shared[n]
+			case "inlineStr":
+				record[col] = c.Inline
+			default:
+				record[col] = c.Value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// columnIndex returns the zero-based column of a cell reference such as
+// "C7", or -1.
+func columnIndex(ref string) int {
+	n := 0
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		n = n*26 + int(ref[i]-'A'+1)
+	}
+	if i == 0 {
+		return -1
+	}
+	return n - 1
+}
@@ -0,0 +1,153 @@
+package contactdata
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+)
+
+// Issue lists the problems that keep a row from being imported. Row is the
+// 1-based position of the row among the data rows.
+type Issue struct {
+	Row      int      `json:"row"`
+	Problems []string `json:"problems"`
+}
+
+// Check validates every row of t under m. It returns an error when no
+// column maps to a phone, email or fax field, and otherwise the rows that
+// cannot be imported: rows without a phone number, email or fax number,
+// rows with an invalid one, and repeats of an earlier row's phone number or
+// email. Phone and fax numbers of the other rows are rewritten in E.164,
+// reading local numbers in the row's country column or else in country.
+func (m Mapping) Check(t *Table, fields []clicksend.ImportField, country string) ([]Issue, error) {
+	roles := map[string]string{}
+	for _, f := range fields {
+		roles[f.Field] = Role(f)
+	}
+	hasKey := false
+	countryCol := -1
+	for j, c := range m {
+		switch roles[c.Field] {
+		case RolePhone, RoleEmail, RoleFax:
+			hasKey = true
+		case "country":
+			countryCol = j
+		}
+	}
+	if !hasKey {
+		return nil, errors.New("no column maps to a phone number, email or fax number field")
+	}
+
+	var issues []Issue
+	seen := map[string]int{}
+	for i, row := range t.Rows {
+		rowCountry := country
+		if countryCol >= 0 && phone.Lookup(row[countryCol]) != nil {
+			rowCountry = row[countryCol]
+		}
+		var problems []string
+		keyed := false
+		normalized := map[int]string{}
+		for j, c := range m {
+			field, v := c.Field, row[j]
+			if field == "" || v == "" {
+				continue
+			}
+			key := v
+			switch roles[field] {
+			case RolePhone, RoleFax:
+				keyed = true
+				n, err := phone.Parse(v, rowCountry)
+				switch {
+				case errors.Is(err, phone.ErrNoCountry) || errors.Is(err, phone.ErrUnknownCountry):
+					// Left for ClickSend to read in the account's country
+					err = CheckNumber(v)
+					key = NumberKey(v)
+				case err == nil:
+					key = n.E164
+					normalized[j] = n.E164
+				}
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", field, err))
+				}
+			case RoleEmail:
+				keyed = true
+				if err := CheckEmail(v); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", field, err))
+				}
+				key = strings.ToLower(v)
+			default:
+				continue
+			}
+			if roles[field] == RoleFax {
+				continue
+			}
+			key = roles[field] + ":" + key
+			if first, ok := seen[key]; ok {
+				problems = append(problems, fmt.Sprintf("%s: duplicate of row %d", field, first))
+			} else {
+				seen[key] = i + 1
+			}
+		}
+		if !keyed {
+			problems = append(problems, "needs a phone number, email or fax number")
+		}
+		if len(problems) > 0 {
+			issues = append(issues, Issue{Row: i + 1, Problems: problems})
+			continue
+		}
+		for j, v := range normalized {
+			row[j] = v
+		}
+	}
+	return issues, nil
+}
+
+// CheckNumber returns an error unless s looks like a phone or fax number,
+// for numbers whose country has no known numbering plan: 6 to 15 digits,
+// optionally after a +, with spaces, dots, dashes and parentheses allowed
+// between them.
+func CheckNumber(s string) error {
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" .-()", r):
+		default:
+			return fmt.Errorf("%q is not a phone number", s)
+		}
+	}
+	if digits < 6 || digits > 15 {
+		return fmt.Errorf("%q has %d digits, want 6 to 15", s, digits)
+	}
+	return nil
+}
+
+// NumberKey strips the punctuation CheckNumber allows, so numbers written
+// differently compare equal. Other values are returned unchanged.
+func NumberKey(s string) string {
+	if CheckNumber(s) != nil {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" .-()", r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// CheckEmail returns an error unless s is a bare email address.
+func CheckEmail(s string) error {
+	a, err := mail.ParseAddress(s)
+	if err != nil || a.Name != "" || a.Address != s {
+		return fmt.Errorf("%q is not an email address", s)
+	}
+	return nil
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected requests in testdata/tool_calls.json")
+
+const toolCallsFile = "testdata/tool_calls.json"
+
+// toolCase is one tool call and the ClickSend request it must produce. Strings
+// starting with "$" in Args and Request are replaced by the fixture of that
+// name created by seed.
+type toolCase struct {
+	Name    string         `json:"name,omitempty"`
+	Tool    string         `json:"tool"`
+	Args    map[string]any `json:"args"`
+	Request *wantedRequest `json:"request,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type wantedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  url.Values      `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+func (c toolCase) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Tool
+}
+
+func loadToolCases(t *testing.T) []toolCase {
+	t.Helper()
+	data, err := os.ReadFile(toolCallsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []toolCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("parse %s: %v", toolCallsFile, err)
+	}
+	return cases
+}
+
+// e2eEnv is a fake ClickSend API and an MCP client connected to the server
+// under test.
+type e2eEnv struct {
+	api      *clicksendtest.Server
+	auth     string
+	client   *client.Client
+	fixtures map[string]string
+}
+
+func newFakeAPI(t *testing.T) *clicksendtest.Server {
+	t.Helper()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := clicksendtest.NewServer(clicksendtest.WithClock(func() time.Time { return clock }))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func basicAuth() string {
+	return base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+}
+
+// connectStdio serves MCP over in-process pipes, the same way ServeStdio does
+// over the standard streams.
+func connectStdio(t *testing.T, cfg *config.APIConfig) *client.Client {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	stdio := server.NewStdioServer(createMCPServer(cfg, "STDIO"))
+	go stdio.Listen(ctx, serverIn, serverOut)
+	t.Cleanup(func() {
+		clientOut.Close()
+		serverOut.Close()
+	})
+
+	return startClient(t, client.NewClient(transport.NewIO(clientIn, clientOut, io.NopCloser(strings.NewReader("")))))
+}
+
+// connectHTTP serves MCP with newHTTPHandler and passes the API configuration
+// as headers, as an HTTP client would.
+func connectHTTP(t *testing.T, api *clicksendtest.Server) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(newHTTPHandler(&config.APIConfig{MaxOutputBytes: config.DefaultMaxOutputBytes}, "HTTP", nil))
+	t.Cleanup(srv.Close)
+	c, err := client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(map[string]string{
+		"API_BASE_URL": api.URL,
+		"BASIC_AUTH":   basicAuth(),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startClient(t, c)
+}
+
+func startClient(t *testing.T, c *client.Client) *client.Client {
+	t.Helper()
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "e2e", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, req); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	return c
+}
+
+func TestE2EStdio(t *testing.T) {
+	api := newFakeAPI(t)
+	runToolCases(t, &e2eEnv{api: api, auth: basicAuth(), client: connectStdio(t, &config.APIConfig{BaseURL: api.URL, BasicAuth: basicAuth(), MaxOutputBytes: config.DefaultMaxOutputBytes})})
+}
+
+func TestE2EHTTP(t *testing.T) {
+	if *update {
+		t.Skip("expected requests are recorded over stdio")
+	}
+	api := newFakeAPI(t)
+	runToolCases(t, &e2eEnv{api: api, auth: basicAuth(), client: connectHTTP(t, api)})
+}
+
+func runToolCases(t *testing.T, env *e2eEnv) {
+	cases := loadToolCases(t)
+	for i := range cases {
+		tc := &cases[i]
+		t.Run(tc.name(), func(t *testing.T) {
+			env.api.Reset()
+			env.fixtures = seed(t, env.api)
+			env.api.ClearRequests()
+
+			req := mcp.CallToolRequest{}
+			req.Params.Name = tc.Tool
+			req.Params.Arguments = env.resolve(tc.Args)
+			res, err := env.client.CallTool(context.Background(), req)
+			if err != nil {
+				t.Fatalf("call %s: %v", tc.Tool, err)
+			}
+			text := resultText(res)
+			switch {
+			case tc.Error != "" && (!res.IsError || !strings.Contains(text, tc.Error)):
+				t.Errorf("result = %s, want an error containing %q", text, tc.Error)
+			case tc.Error == "" && res.IsError:
+				t.Errorf("tool returned an error: %s", text)
+			}
+
+			recorded := env.api.Requests()
+			if len(recorded) != 1 {
+				t.Fatalf("got %d API requests, want 1: %+v", len(recorded), recorded)
+			}
+			got := recorded[0]
+			env.checkHeaders(t, got)
+			if *update {
+				tc.Request = env.golden(got)
+				return
+			}
+			if tc.Request == nil {
+				t.Fatal("no expected request; run with -update")
+			}
+			env.checkRequest(t, got, tc.Request)
+		})
+	}
+	if *update && !t.Failed() {
+		data, err := json.MarshalIndent(cases, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(toolCallsFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (env *e2eEnv) checkHeaders(t *testing.T, got clicksendtest.Request) {
+	t.Helper()
+	if h := got.Header.Get("Authorization"); h != "Basic "+env.auth {
+		t.Errorf("Authorization = %q, want Basic credentials", h)
+	}
+	if h := got.Header.Get("Accept"); h != "application/json" {
+		t.Errorf("Accept = %q, want application/json", h)
+	}
+	wantType := ""
+	if len(got.Body) > 0 {
+		wantType = "application/json"
+	}
+	if h := got.Header.Get("Content-Type"); h != wantType {
+		t.Errorf("Content-Type = %q, want %q", h, wantType)
+	}
+}
+
+func (env *e2eEnv) checkRequest(t *testing.T, got clicksendtest.Request, want *wantedRequest) {
+	t.Helper()
+	if got.Method != want.Method || got.Path != env.resolveString(want.Path) {
+		t.Errorf("request = %s %s, want %s %s", got.Method, got.Path, want.Method, env.resolveString(want.Path))
+	}
+	wantQuery := url.Values{}
+	for k, vs := range want.Query {
+		for _, v := range vs {
+			wantQuery.Add(k, env.resolveString(v))
+		}
+	}
+	if len(got.Query) != 0 || len(wantQuery) != 0 {
+		if !reflect.DeepEqual(got.Query, wantQuery) {
+			t.Errorf("query = %v, want %v", got.Query, wantQuery)
+		}
+	}
+	switch {
+	case len(want.Body) == 0 && len(got.Body) != 0:
+		t.Errorf("body = %s, want none", got.Body)
+	case len(want.Body) != 0:
+		var gotBody, wantBody any
+		if err := json.Unmarshal(got.Body, &gotBody); err != nil {
+			t.Fatalf("body %s is not JSON: %v", got.Body, err)
+		}
+		json.Unmarshal(want.Body, &wantBody)
+		wantBody = env.walk(wantBody, env.resolveString)
+		if !reflect.DeepEqual(gotBody, wantBody) {
+			w, _ := json.Marshal(wantBody)
+			t.Errorf("body = %s, want %s", got.Body, w)
+		}
+	}
+}
+
+// golden converts a recorded request to its expected form, replacing fixture
+// values with their placeholders.
+func (env *e2eEnv) golden(got clicksendtest.Request) *wantedRequest {
+	w := &wantedRequest{Method: got.Method, Path: env.placeholders(got.Path)}
+	if len(got.Query) > 0 {
+		w.Query = url.Values{}
+		for k, vs := range got.Query {
+			for _, v := range vs {
+				w.Query.Add(k, env.placeholders(v))
+			}
+		}
+	}
+	if len(got.Body) > 0 {
+		var body any
+		json.Unmarshal(got.Body, &body)
+		w.Body, _ = json.Marshal(env.unresolve(body))
+	}
+	return w
+}
+
+// resolve replaces "$name" strings in v with fixtures.
+func (env *e2eEnv) resolve(v any) map[string]any {
+	out, _ := env.walk(v, env.resolveString).(map[string]any)
+	return out
+}
+
+func (env *e2eEnv) resolveString(s string) string {
+	if strings.HasPrefix(s, "$") {
+		if v, ok := env.fixtures[s[1:]]; ok {
+			return v
+		}
+	}
+	for name, v := range env.fixtures {
+		s = strings.ReplaceAll(s, "{$"+name+"}", v)
+	}
+	return s
+}
+
+func (env *e2eEnv) unresolve(v any) any {
+	return env.walk(v, func(s string) string {
+		for name, fv := range env.fixtures {
+			if s == fv {
+				return "$" + name
+			}
+		}
+		return s
+	})
+}
+
+// placeholders replaces fixture values inside s, such as a message id in a
+// path, with "{$name}".
+func (env *e2eEnv) placeholders(s string) string {
+	for name, v := range env.fixtures {
+		s = strings.ReplaceAll(s, v, "{$"+name+"}")
+	}
+	return s
+}
+
+func (env *e2eEnv) walk(v any, f func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return f(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = env.walk(e, f)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = env.walk(e, f)
+		}
+		return out
+	}
+	return v
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	var b strings.Builder
+	for _, c := range res.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			b.WriteString(t.Text)
+		}
+	}
+	return b.String()
+}
+
+// seed creates the records the tool cases refer to and returns the generated
+// values that are not fixed ids: message ids and the uploaded file URL.
+func seed(t *testing.T, api *clicksendtest.Server) map[string]string {
+	t.Helper()
+	call := func(method, path string, body any) map[string]any {
+		t.Helper()
+		var r io.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			r = bytes.NewReader(data)
+		}
+		req, _ := http.NewRequest(method, api.URL+path, r)
+		req.SetBasicAuth(clicksendtest.Username, clicksendtest.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("seed %s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		var out struct {
+			Data map[string]any `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("seed %s %s: %d %s", method, path, resp.StatusCode, data)
+		}
+		json.Unmarshal(data, &out)
+		return out.Data
+	}
+	firstMessageID := func(data map[string]any) string {
+		messages, _ := data["messages"].([]any)
+		if len(messages) == 0 {
+			t.Fatalf("seed: no messages in %v", data)
+		}
+		m, _ := messages[0].(map[string]any)
+		return fmt.Sprint(m["message_id"])
+	}
+	const later = 1893456000 // 2030-01-01
+
+	call("POST", "/lists", map[string]any{"list_name": "Customers"})
+	call("POST", "/lists", map[string]any{"list_name": "Opt-outs"})
+	call("POST", "/lists/1000/contacts", map[string]any{
+		"phone_number": "+61411111111", "first_name": "Jane", "last_name": "Citizen", "email": "jane@example.com",
+		"address_line_1": "1 Collins St", "address_city": "Melbourne", "address_state": "VIC",
+		"address_postal_code": "3000", "address_country": "AU", "custom_1": "gold",
+	})
+	call("POST", "/lists/1000/contacts", map[string]any{"phone_number": "+61422222222", "first_name": "John"})
+	call("POST", "/email/addresses", map[string]any{"email_address": "sender@example.com"})
+	call("POST", "/email/templates", map[string]any{"template_name": "Newsletter", "template_id_master": 1})
+	call("POST", "/sms/templates", map[string]any{"template_name": "Hello", "body": "Hi {first_name}"})
+	rule := map[string]any{"rule_name": "Forward", "match_type": 0, "action": "URL", "action_address": "https://example.com/hook", "enabled": 1}
+	for _, p := range []string{"/automations/email/receipt", "/automations/sms/receipts", "/automations/voice/receipts", "/automations/fax/receipts"} {
+		call("POST", p, rule)
+	}
+	inbound := map[string]any{"rule_name": "Forward", "dedicated_number": "+61401234567", "message_search_type": 0, "action": "URL", "action_address": "https://example.com/hook", "enabled": 1}
+	call("POST", "/automations/sms/inbound", inbound)
+	call("POST", "/automations/fax/inbound", inbound)
+	call("POST", "/sms/email-sms", map[string]any{"email_address": "jane@example.com", "from": "+61401234567"})
+	call("POST", "/sms/email-sms-stripped-strings", map[string]any{"strip_string": "--"})
+	call("POST", "/post/return-addresses", map[string]any{
+		"address_name": "Mock Pty Ltd", "address_line_1": "1 Collins St", "address_city": "Melbourne",
+		"address_state": "VIC", "address_postal_code": "3000", "address_country": "AU",
+	})
+	call("POST", "/subaccounts", map[string]any{
+		"api_username": "sub", "password": "secret", "email": "sub@example.com",
+		"phone_number": "+61433333333", "first_name": "Sub", "last_name": "Account",
+	})
+	call("POST", "/reseller/accounts", map[string]any{
+		"username": "client", "password": "secret", "user_email": "client@example.com",
+		"user_phone": "+61444444444", "country": "AU",
+	})
+	call("PUT", "/recharge/credit-card", map[string]any{
+		"number": "4111111111111111", "expiry_month": "05", "expiry_year": "2030", "cvc": "123", "name": "Jane Citizen",
+	})
+	call("PUT", "/recharge/purchase/1", nil)
+
+	fixtures := map[string]string{}
+	fixtures["sms_message"] = firstMessageID(call("POST", "/sms/send", map[string]any{"messages": []any{
+		map[string]any{"to": "+61411111111", "body": "Hello"},
+	}}))
+	fixtures["sms_scheduled"] = firstMessageID(call("POST", "/sms/send", map[string]any{"messages": []any{
+		map[string]any{"to": "+61422222222", "body": "Later", "schedule": later},
+	}}))
+	fixtures["mms_message"] = firstMessageID(call("POST", "/mms/send", map[string]any{"media_file": "https://example.com/a.gif", "messages": []any{
+		map[string]any{"to": "+61411111111", "subject": "Hi", "body": "Hello"},
+	}}))
+	fixtures["mms_scheduled"] = firstMessageID(call("POST", "/mms/send", map[string]any{"media_file": "https://example.com/a.gif", "messages": []any{
+		map[string]any{"to": "+61411111111", "subject": "Hi", "body": "Later", "schedule": later},
+	}}))
+	fixtures["voice_message"] = firstMessageID(call("POST", "/voice/send", map[string]any{"messages": []any{
+		map[string]any{"to": "+61411111111", "body": "Hello", "voice": "female"},
+	}}))
+	fixtures["voice_scheduled"] = firstMessageID(call("POST", "/voice/send", map[string]any{"messages": []any{
+		map[string]any{"to": "+61411111111", "body": "Later", "voice": "female", "schedule": later},
+	}}))
+	fixtures["fax_message"] = firstMessageID(call("POST", "/fax/send", map[string]any{"file_url": "https://example.com/fax.pdf", "messages": []any{
+		map[string]any{"to": "+61298765432"},
+	}}))
+	api.ReceiveSms("+61411111111", "+61401234567", "Thanks")
+	fixtures["inbound_message"] = fmt.Sprint(lastInbound(t, call))
+	call("POST", "/email-campaigns/send", map[string]any{
+		"list_id": 1000, "name": "Newsletter", "subject": "News", "body": "<p>News</p>",
+		"from_email_address_id": 1, "from_name": "Mock", "schedule": later,
+	})
+	call("POST", "/sms-campaigns/send", map[string]any{"list_id": 1000, "name": "Sale", "body": "Sale on now", "schedule": later})
+	upload := call("POST", "/uploads?convert=csv", map[string]any{
+		"content": base64.StdEncoding.EncodeToString([]byte("phone_number,first_name\n+61411111113,Jim\n")),
+	})
+	fixtures["csv_url"] = fmt.Sprint(upload["_url"])
+	return fixtures
+}
+
+func lastInbound(t *testing.T, call func(method, path string, body any) map[string]any) any {
+	t.Helper()
+	items, _ := call("GET", "/sms/inbound", nil)["data"].([]any)
+	if len(items) == 0 {
+		t.Fatal("seed: no inbound message")
+	}
+	m, _ := items[len(items)-1].(map[string]any)
+	return m["message_id"]
+}
+
+// TestToolCoverage checks that every registered tool has a case, that tool
+// names are unique and that every OpenAPI operation has a tool.
+func TestToolCoverage(t *testing.T) {
+	ops := loadSpecOperations(t)
+	cases := map[string]bool{}
+	for _, c := range loadToolCases(t) {
+		cases[c.Tool] = true
+	}
+	names := map[string]bool{}
+	for _, tool := range GetAll(&config.APIConfig{}) {
+		name := tool.Definition.Name
+		if names[name] {
+			t.Errorf("tool %s is registered twice", name)
+		}
+		names[name] = true
+		if !cases[name] {
+			t.Errorf("tool %s has no case in %s", name, toolCallsFile)
+		}
+		if _, ok := ops[name]; !ok {
+			t.Errorf("tool %s has no OpenAPI operation", name)
+		}
+	}
+	for name := range ops {
+		if !names[name] {
+			t.Errorf("OpenAPI operation %s has no tool", name)
+		}
+	}
+}
+
+// TestToolCallsMatchSpec checks the expected requests against the OpenAPI
+// document, so a wrong method, path or query parameter name is caught even
+// when the expected requests were regenerated.
+func TestToolCallsMatchSpec(t *testing.T) {
+	ops := loadSpecOperations(t)
+	for _, c := range loadToolCases(t) {
+		op, ok := ops[c.Tool]
+		if !ok || c.Request == nil {
+			continue
+		}
+		if c.Request.Method != op.method {
+			t.Errorf("%s: method %s, spec says %s", c.name(), c.Request.Method, op.method)
+		}
+		if !op.path.MatchString(c.Request.Path) {
+			t.Errorf("%s: path %s does not match spec path %s", c.name(), c.Request.Path, op.template)
+		}
+		for k := range c.Request.Query {
+			if !op.query[k] && k != "page" && k != "limit" {
+				t.Errorf("%s: query parameter %s is not in spec path %s", c.name(), k, op.template)
+			}
+		}
+	}
+}
+
+type specOperation struct {
+	method   string
+	template string
+	path     *regexp.Regexp
+	query    map[string]bool
+}
+
+var specParam = regexp.MustCompile(`\\\{[^}]*\\\}`)
+
+// loadSpecOperations reads ../opeanapi.yaml and returns its operations keyed
+// by tool name.
+func loadSpecOperations(t *testing.T) map[string]specOperation {
+	t.Helper()
+	data, err := os.ReadFile("../opeanapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name string `yaml:"name"`
+				In   string `yaml:"in"`
+			} `yaml:"parameters"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	ops := map[string]specOperation{}
+	for p, methods := range doc.Paths {
+		path, rawQuery, _ := strings.Cut(p, "?")
+		pattern := specParam.ReplaceAllString(regexp.QuoteMeta(strings.TrimSuffix(path, "/")), `[^/]+`)
+		query := map[string]bool{}
+		for _, kv := range strings.Split(rawQuery, "&") {
+			k, _, _ := strings.Cut(kv, "=")
+			if k = strings.Trim(k, "{}"); k != "" {
+				query[k] = true
+			}
+		}
+		for method, op := range methods {
+			q := map[string]bool{}
+			for k := range query {
+				q[k] = true
+			}
+			for _, param := range op.Parameters {
+				if param.In == "query" {
+					q[param.Name] = true
+				}
+			}
+			name := method + "_" + strings.ReplaceAll(strings.TrimPrefix(p, "/"), "/", "_")
+			name = strings.NewReplacer("{", "", "}", "").Replace(name)
+			name = strings.TrimSuffix(name, "_")
+			ops[name] = specOperation{
+				method:   strings.ToUpper(method),
+				template: p,
+				path:     regexp.MustCompile("^" + pattern + "/?$"),
+				query:    q,
+			}
+		}
+	}
+	return ops
+}
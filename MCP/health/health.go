@@ -0,0 +1,154 @@
+// Package health serves liveness and readiness endpoints.
+//
+// Liveness only reports that the process is serving HTTP. Readiness checks
+// the API configuration and, when enabled, probes an authenticated ClickSend
+// endpoint, caching the outcome so frequent probes do not reach the API.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+)
+
+// probeTimeout bounds a single ClickSend probe.
+const probeTimeout = 5 * time.Second
+
+// Check is the outcome of one readiness check.
+type Check struct {
+	Status    string     `json:"status"` // "ok", "failed" or "skipped"
+	Message   string     `json:"message,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	LatencyMS *int64     `json:"latency_ms,omitempty"`
+}
+
+// Report is the body of the readiness endpoint.
+type Report struct {
+	Status  string           `json:"status"` // "ready" or "not_ready"
+	Version string           `json:"version"`
+	Tools   int              `json:"tools"`
+	Checks  map[string]Check `json:"checks"`
+}
+
+// Checker serves the health endpoints for a server.
+type Checker struct {
+	// Version is the build version reported by readiness.
+	Version string
+	// Tools is the number of registered tools.
+	Tools int
+	// Config is the process-level API configuration. In HTTP mode the base
+	// URL and credentials may instead arrive with each request.
+	Config *config.APIConfig
+	// Probe enables the authenticated ClickSend probe.
+	Probe bool
+	// ProbeTTL is how long a probe result is reused.
+	ProbeTTL time.Duration
+
+	mu     sync.Mutex
+	probed time.Time
+	result Check
+}
+
+// Liveness always answers 200 while the process can serve HTTP.
+func (c *Checker) Liveness(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness answers 200 when every check passes and 503 otherwise.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Report(r.Context())
+	status := http.StatusOK
+	if report.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// Report runs the readiness checks.
+func (c *Checker) Report(ctx context.Context) Report {
+	report := Report{Status: "ready", Version: c.Version, Tools: c.Tools, Checks: map[string]Check{}}
+	report.Checks["config"] = c.checkConfig()
+	report.Checks["tools"] = c.checkTools()
+	if report.Checks["config"].Status == "ok" {
+		report.Checks["clicksend"] = c.probe(ctx)
+	} else {
+		report.Checks["clicksend"] = Check{Status: "skipped", Message: "configuration is invalid"}
+	}
+	for _, check := range report.Checks {
+		if check.Status == "failed" {
+			report.Status = "not_ready"
+		}
+	}
+	return report
+}
+
+func (c *Checker) checkTools() Check {
+	if c.Tools == 0 {
+		return Check{Status: "failed", Message: "no tools are registered"}
+	}
+	return Check{Status: "ok"}
+}
+
+func (c *Checker) checkConfig() Check {
+	if c.Config == nil || c.Config.BaseURL == "" {
+		if c.Probe {
+			return Check{Status: "failed", Message: "READINESS_PROBE needs API_BASE_URL and BASIC_AUTH"}
+		}
+		return Check{Status: "ok", Message: "API configuration is provided with each request"}
+	}
+	u, err := url.Parse(c.Config.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Check{Status: "failed", Message: fmt.Sprintf("API_BASE_URL %q is not an http or https URL", c.Config.BaseURL)}
+	}
+	if c.Probe && c.Config.BasicAuth == "" && c.Config.Profile == "" {
+		return Check{Status: "failed", Message: "READINESS_PROBE needs BASIC_AUTH or CLICKSEND_PROFILE"}
+	}
+	return Check{Status: "ok"}
+}
+
+// probe calls get_account, reusing the last result for ProbeTTL.
+func (c *Checker) probe(ctx context.Context) Check {
+	if !c.Probe {
+		return Check{Status: "skipped", Message: "READINESS_PROBE is off"}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if !c.probed.IsZero() && now.Sub(c.probed) < c.ProbeTTL {
+		return c.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	_, err := c.Config.Client().GetAccount(ctx)
+	latency := time.Since(now).Milliseconds()
+	c.result = Check{Status: "ok", CheckedAt: &now, LatencyMS: &latency}
+	if err != nil {
+		c.result.Status = "failed"
+		c.result.Message = err.Error()
+		// Readiness is unauthenticated, so API error bodies only go to the log
+		var apiErr *clicksend.APIError
+		if errors.As(err, &apiErr) {
+			c.result.Message = fmt.Sprintf("ClickSend returned HTTP %d %s", apiErr.StatusCode, apiErr.ResponseCode)
+			slog.Warn("Readiness probe failed", "status", apiErr.StatusCode, "response_code", apiErr.ResponseCode, "body", string(apiErr.Body))
+		}
+	}
+	c.probed = now
+	return c.result
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
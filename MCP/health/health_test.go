@@ -0,0 +1,131 @@
+package health_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/health"
+)
+
+func TestReadiness(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	auth := func(key string) string {
+		return base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + key))
+	}
+
+	tests := []struct {
+		name   string
+		cfg    *config.APIConfig
+		probe  bool
+		tools  int
+		status int
+		checks map[string]string
+		probed string // the clicksend check's message
+	}{
+		{
+			name:   "per-request config",
+			cfg:    &config.APIConfig{},
+			tools:  205,
+			status: http.StatusOK,
+			checks: map[string]string{"config": "ok", "tools": "ok", "clicksend": "skipped"},
+		},
+		{
+			name:   "probe ok",
+			cfg:    &config.APIConfig{BaseURL: api.URL, BasicAuth: auth(clicksendtest.APIKey)},
+			probe:  true,
+			tools:  205,
+			status: http.StatusOK,
+			checks: map[string]string{"config": "ok", "tools": "ok", "clicksend": "ok"},
+		},
+		{
+			name:   "bad credentials",
+			cfg:    &config.APIConfig{BaseURL: api.URL, BasicAuth: auth("wrong")},
+			probe:  true,
+			tools:  205,
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"config": "ok", "tools": "ok", "clicksend": "failed"},
+			probed: "ClickSend returned HTTP 401 UNAUTHORIZED",
+		},
+		{
+			name:   "invalid base URL",
+			cfg:    &config.APIConfig{BaseURL: "rest.clicksend.com/v3"},
+			tools:  205,
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"config": "failed", "tools": "ok", "clicksend": "skipped"},
+		},
+		{
+			name:   "probe without credentials",
+			cfg:    &config.APIConfig{},
+			probe:  true,
+			tools:  205,
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"config": "failed", "tools": "ok", "clicksend": "skipped"},
+		},
+		{
+			name:   "no tools",
+			cfg:    &config.APIConfig{},
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"config": "ok", "tools": "failed", "clicksend": "skipped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &health.Checker{Version: "1.2.3", Tools: tt.tools, Config: tt.cfg, Probe: tt.probe, ProbeTTL: time.Minute}
+			rec := httptest.NewRecorder()
+			checker.Readiness(rec, httptest.NewRequest("GET", "/readyz", nil))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var report health.Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Version != "1.2.3" || report.Tools != tt.tools {
+				t.Errorf("version, tools = %q, %d", report.Version, report.Tools)
+			}
+			for name, want := range tt.checks {
+				if got := report.Checks[name].Status; got != want {
+					t.Errorf("check %s = %s (%s), want %s", name, got, report.Checks[name].Message, want)
+				}
+			}
+			if got := report.Checks["clicksend"].Message; tt.probed != "" && got != tt.probed {
+				t.Errorf("clicksend message = %q, want %q", got, tt.probed)
+			}
+		})
+	}
+}
+
+func TestProbeIsCached(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	checker := &health.Checker{
+		Tools: 1,
+		Config: &config.APIConfig{
+			BaseURL:   api.URL,
+			BasicAuth: base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey)),
+		},
+		Probe:    true,
+		ProbeTTL: time.Hour,
+	}
+	for range 3 {
+		if report := checker.Report(t.Context()); report.Status != "ready" {
+			t.Fatalf("report = %+v", report)
+		}
+	}
+	if n := len(api.Requests()); n != 1 {
+		t.Errorf("ClickSend was called %d times, want 1", n)
+	}
+
+	rec := httptest.NewRecorder()
+	checker.Liveness(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"status\":\"ok\"}\n" {
+		t.Errorf("liveness = %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,174 @@
+// Package impersonate lets tool calls act as a ClickSend subaccount.
+//
+// The server looks up the subaccount's API username and key with the
+// parent account's credentials, optionally regenerating the key when
+// ClickSend does not return it, and caches them in memory only.
+package impersonate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// Modes of SUBACCOUNT_IMPERSONATION.
+const (
+	ModeOff        = "off"
+	ModeOn         = "on"
+	ModeRegenerate = "regenerate"
+)
+
+// DefaultTTL is used when SUBACCOUNT_CREDENTIALS_TTL is not set.
+const DefaultTTL = 10 * time.Minute
+
+// fetchTimeout bounds a lookup, including regenerating a key.
+const fetchTimeout = 30 * time.Second
+
+// Resolver fetches and caches subaccount credentials.
+type Resolver struct {
+	ttl        time.Duration
+	regenerate bool
+
+	mu    sync.Mutex
+	cache map[cacheKey]*entry
+}
+
+// cacheKey identifies a subaccount of a parent account. The parent's
+// credentials are only kept hashed.
+type cacheKey struct {
+	parent       [sha256.Size]byte
+	subaccountID string
+}
+
+type entry struct {
+	ready   chan struct{}
+	basic   string
+	err     error
+	expires time.Time
+}
+
+// New returns a resolver caching credentials for ttl. With regenerate set,
+// a subaccount whose API key ClickSend does not return gets a new one,
+// which invalidates its previous key.
+func New(ttl time.Duration, regenerate bool) *Resolver {
+	return &Resolver{ttl: ttl, regenerate: regenerate, cache: map[cacheKey]*entry{}}
+}
+
+// FromEnv returns the resolver configured by SUBACCOUNT_IMPERSONATION and
+// SUBACCOUNT_CREDENTIALS_TTL, or nil when impersonation is off.
+func FromEnv() (*Resolver, error) {
+	ttl := DefaultTTL
+	if v := configfile.Getenv("SUBACCOUNT_CREDENTIALS_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("SUBACCOUNT_CREDENTIALS_TTL must be a non-negative duration such as 10m, got %q", v)
+		}
+		ttl = d
+	}
+	switch mode := configfile.Getenv("SUBACCOUNT_IMPERSONATION"); mode {
+	case "", ModeOff:
+		return nil, nil
+	case ModeOn:
+		return New(ttl, false), nil
+	case ModeRegenerate:
+		return New(ttl, true), nil
+	default:
+		return nil, fmt.Errorf("SUBACCOUNT_IMPERSONATION must be off, on or regenerate, got %q", mode)
+	}
+}
+
+// Credentials returns the base64 "api_username:api_key" pair of a
+// subaccount, looked up with parent. parentKey identifies the parent's
+// credentials so accounts never share cache entries. Concurrent calls for
+// the same subaccount share one lookup.
+func (r *Resolver) Credentials(ctx context.Context, parent *clicksend.Client, parentKey, subaccountID string) (string, error) {
+	key := cacheKey{sha256.Sum256([]byte(parent.BaseURL() + "\x00" + parentKey)), subaccountID}
+
+	r.mu.Lock()
+	e, ok := r.cache[key]
+	if ok {
+		select {
+		case <-e.ready:
+			if e.err != nil || time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &entry{ready: make(chan struct{})}
+		r.cache[key] = e
+		go r.resolve(ctx, e, parent, subaccountID)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.basic, e.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Forget drops the cached credentials of a subaccount, so the next call
+// looks them up again.
+func (r *Resolver) Forget(subaccountID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.cache {
+		if key.subaccountID == subaccountID {
+			delete(r.cache, key)
+		}
+	}
+}
+
+// resolve fills e for every caller waiting on it. The lookup outlives the
+// context of the call that started it, so that call being cancelled does
+// not fail the others, and is bounded by fetchTimeout instead.
+func (r *Resolver) resolve(ctx context.Context, e *entry, parent *clicksend.Client, subaccountID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+	defer cancel()
+	e.basic, e.err = r.fetch(ctx, parent, subaccountID)
+	e.expires = time.Now().Add(r.ttl)
+	close(e.ready)
+}
+
+func (r *Resolver) fetch(ctx context.Context, parent *clicksend.Client, subaccountID string) (string, error) {
+	resp, err := parent.GetSubaccount(ctx, subaccountID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up subaccount %s: %w", subaccountID, err)
+	}
+	username, apiKey := resp.Data.APIUsername, resp.Data.APIKey
+	if apiKey == "" && r.regenerate {
+		regen, err := parent.RegenerateSubaccountAPIKey(ctx, subaccountID)
+		if err != nil {
+			return "", fmt.Errorf("failed to regenerate the API key of subaccount %s: %w", subaccountID, err)
+		}
+		apiKey = regen.Data.APIKey
+	}
+	if username == "" || apiKey == "" {
+		return "", fmt.Errorf("ClickSend did not return the API credentials of subaccount %s; set SUBACCOUNT_IMPERSONATION=regenerate to issue a new key", subaccountID)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + apiKey)), nil
+}
+
+type subaccountKey struct{}
+
+// WithSubaccount returns a context whose ClickSend requests act as the
+// subaccount.
+func WithSubaccount(ctx context.Context, subaccountID string) context.Context {
+	return context.WithValue(ctx, subaccountKey{}, subaccountID)
+}
+
+// SubaccountFromContext returns the subaccount selected with WithSubaccount,
+// or "".
+func SubaccountFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(subaccountKey{}).(string)
+	return id
+}
@@ -0,0 +1,181 @@
+package impersonate_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/impersonate"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	tools_account "github.com/clicksend-rest-api-v3/mcp-server/tools/account"
+	tools_subaccounts "github.com/clicksend-rest-api-v3/mcp-server/tools/subaccounts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCredentials(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	parent := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	ctx := context.Background()
+	sub, err := parent.CreateSubaccount(ctx, &clicksend.Subaccount{
+		APIUsername: "customer-a", Password: "pw", Email: "a@example.com", PhoneNumber: "+61411111111", FirstName: "A", LastName: "Customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strconv.FormatInt(int64(sub.Data.SubaccountID), 10)
+
+	r := impersonate.New(time.Hour, false)
+	api.ClearRequests()
+	for range 3 {
+		basic, err := r.Credentials(ctx, parent, "parent", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := base64.StdEncoding.EncodeToString([]byte("customer-a:" + sub.Data.APIKey)); basic != want {
+			t.Fatalf("credentials = %s, want %s", basic, want)
+		}
+		asSub := clicksend.NewClient(api.URL, clicksend.WithBasicAuth(basic))
+		if _, err := asSub.GetAccount(ctx); err != nil {
+			t.Fatalf("request as the subaccount: %v", err)
+		}
+	}
+	if n := countPath(api.Requests(), "/subaccounts/"+id); n != 1 {
+		t.Errorf("looked up the subaccount %d times, want 1", n)
+	}
+	if _, err := r.Credentials(ctx, parent, "other parent", id); err != nil {
+		t.Fatal(err)
+	}
+	r.Forget(id)
+	if _, err := r.Credentials(ctx, parent, "parent", id); err != nil {
+		t.Fatal(err)
+	}
+	if n := countPath(api.Requests(), "/subaccounts/"+id); n != 3 {
+		t.Errorf("looked up the subaccount %d times, want a lookup per parent and one after Forget", n)
+	}
+	if _, err := r.Credentials(ctx, parent, "parent", "999"); err == nil {
+		t.Error("unknown subaccount resolved")
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	regenerated := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/regen-api-key") {
+			regenerated++
+			w.Write([]byte(`{"http_code":200,"response_code":"SUCCESS","data":{"subaccount_id":5,"api_username":"customer-b","api_key":"NEWKEY"}}`))
+			return
+		}
+		w.Write([]byte(`{"http_code":200,"response_code":"SUCCESS","data":{"subaccount_id":5,"api_username":"customer-b"}}`))
+	}))
+	defer srv.Close()
+	parent := clicksend.NewClient(srv.URL)
+
+	if _, err := impersonate.New(time.Hour, false).Credentials(context.Background(), parent, "", "5"); err == nil || regenerated != 0 {
+		t.Errorf("without regenerate: err = %v, regenerated %d times", err, regenerated)
+	}
+	basic, err := impersonate.New(time.Hour, true).Credentials(context.Background(), parent, "", "5")
+	if err != nil || basic != base64.StdEncoding.EncodeToString([]byte("customer-b:NEWKEY")) || regenerated != 1 {
+		t.Errorf("with regenerate: %s, %v, regenerated %d times", basic, err, regenerated)
+	}
+}
+
+// TestWrap checks that as_subaccount calls use the subaccount's
+// credentials, and that regenerating its key through a tool drops the cached
+// key.
+func TestWrap(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	parent := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	sub, err := parent.CreateSubaccount(context.Background(), &clicksend.Subaccount{
+		APIUsername: "customer", Password: "pw", Email: "c@example.com", PhoneNumber: "+61411111111", FirstName: "C", LastName: "Customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strconv.FormatInt(int64(sub.Data.SubaccountID), 10)
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	cfg := &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, Subaccounts: impersonate.New(time.Hour, false)}
+	account := impersonate.Wrap(tools_account.CreateGetaccountTool(cfg), cfg.Subaccounts)
+	regenerate := impersonate.Wrap(tools_subaccounts.CreateRegenerateapikeyTool(cfg), cfg.Subaccounts)
+
+	call := func(tool models.Tool, args map[string]any) {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Definition.Name
+		request.Params.Arguments = args
+		if res, err := tool.Handler(context.Background(), request); err != nil || res.IsError {
+			t.Fatalf("%s: %v %v", tool.Definition.Name, err, res)
+		}
+	}
+	lastAuth := func() string {
+		requests := api.Requests()
+		return requests[len(requests)-1].Header.Get("Authorization")
+	}
+
+	call(account, map[string]any{"as_subaccount": id})
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("customer:"+sub.Data.APIKey)); lastAuth() != want {
+		t.Errorf("Authorization = %s, want the subaccount's %s", lastAuth(), want)
+	}
+	call(regenerate, map[string]any{"subaccount_id": id})
+	if lastAuth() != "Basic "+basic {
+		t.Errorf("regenerating the key did not use the parent credentials")
+	}
+	call(account, map[string]any{"as_subaccount": id})
+}
+
+func countPath(requests []clicksendtest.Request, path string) int {
+	n := 0
+	for _, r := range requests {
+		if r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCancelledCaller(t *testing.T) {
+	release := make(chan struct{})
+	lookups := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"http_code":200,"response_code":"SUCCESS","data":{"subaccount_id":5,"api_username":"customer-c","api_key":"KEY"}}`))
+	}))
+	defer srv.Close()
+	parent := clicksend.NewClient(srv.URL)
+	r := impersonate.New(time.Hour, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := r.Credentials(ctx, parent, "", "5")
+		first <- err
+	}()
+	second := make(chan string)
+	go func() {
+		basic, _ := r.Credentials(context.Background(), parent, "", "5")
+		second <- basic
+	}()
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("cancelled caller: err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if basic := <-second; basic != base64.StdEncoding.EncodeToString([]byte("customer-c:KEY")) {
+		t.Errorf("the other caller got %q after the first was cancelled", basic)
+	}
+	if lookups != 1 {
+		t.Errorf("looked up the subaccount %d times, want 1", lookups)
+	}
+}
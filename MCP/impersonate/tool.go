@@ -0,0 +1,63 @@
+package impersonate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// keyChangingTools invalidate the cached credentials of the subaccount in
+// their subaccount_id argument.
+var keyChangingTools = map[string]bool{
+	"put_subaccounts_subaccount_id_regen-api-key": true,
+	"delete_subaccounts_subaccount_id":            true,
+}
+
+// Wrap adds an as_subaccount argument to a tool. The ClickSend requests of a
+// call naming a subaccount ID act as that subaccount. When the tool changes
+// or deletes a subaccount's API key, the cached credentials of that
+// subaccount are dropped.
+func Wrap(tool models.Tool, r *Resolver) models.Tool {
+	if _, ok := tool.Definition.InputSchema.Properties["as_subaccount"]; ok {
+		return tool
+	}
+	mcp.WithString("as_subaccount", mcp.Description("Subaccount ID to act as. The call uses the subaccount's own API credentials, for example to send or read history on behalf of a customer subaccount."))(&tool.Definition)
+
+	name := tool.Definition.Name
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]any)
+		if v, ok := args["as_subaccount"]; ok {
+			var id string
+			switch v := v.(type) {
+			case string:
+				id = v
+			case float64:
+				id = fmt.Sprintf("%.0f", v)
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid as_subaccount: %v", v)), nil
+			}
+			forwarded := make(map[string]any, len(args))
+			for k, v := range args {
+				if k != "as_subaccount" {
+					forwarded[k] = v
+				}
+			}
+			request.Params.Arguments = forwarded
+			if id != "" {
+				ctx = WithSubaccount(ctx, id)
+			}
+		}
+
+		result, err := handler(ctx, request)
+		if keyChangingTools[name] && err == nil && result != nil && !result.IsError {
+			if id, ok := args["subaccount_id"].(string); ok {
+				r.Forget(id)
+			}
+		}
+		return result, err
+	}
+	return tool
+}
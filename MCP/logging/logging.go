@@ -0,0 +1,78 @@
+// Package logging sets up structured logging with slog and redacts secrets
+// and personal data from every record.
+//
+// The logger writes JSON or text to stderr, which keeps stdout free for the
+// STDIO transport. ToolMiddleware logs every tool call and Transport logs
+// every ClickSend request with the tool and session that made it.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// Config controls the logger built by New.
+type Config struct {
+	Level  slog.Level
+	Format string // "json" or "text"
+	Redact Rules
+}
+
+// DefaultConfig logs at info level as JSON with every redaction rule on.
+func DefaultConfig() Config {
+	return Config{Level: slog.LevelInfo, Format: "json", Redact: AllRules}
+}
+
+// ConfigFromEnv reads LOG_LEVEL (debug, info, warn or error), LOG_FORMAT
+// (json or text), LOG_REDACT (a comma-separated list of rules, "all" or
+// "none") and LOG_REDACT_KEYS (extra comma-separated attribute names whose
+// values are always redacted).
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	if v := configfile.Getenv("LOG_LEVEL"); v != "" {
+		if err := cfg.Level.UnmarshalText([]byte(v)); err != nil {
+			return cfg, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", v)
+		}
+	}
+	if v := configfile.Getenv("LOG_FORMAT"); v != "" {
+		v = strings.ToLower(v)
+		if v != "json" && v != "text" {
+			return cfg, fmt.Errorf("LOG_FORMAT must be json or text, got %q", v)
+		}
+		cfg.Format = v
+	}
+	if v, ok := configfile.LookupEnv("LOG_REDACT"); ok {
+		rules, err := ParseRules(v)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_REDACT: %w", err)
+		}
+		cfg.Redact = rules
+	}
+	if v := configfile.Getenv("LOG_REDACT_KEYS"); v != "" {
+		cfg.Redact.Keys = splitList(v)
+	}
+	return cfg, nil
+}
+
+// New returns a logger writing to w.
+func New(w io.Writer, cfg Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Level, ReplaceAttr: cfg.Redact.ReplaceAttr}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, strings.ToLower(v))
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/logging"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := logging.DefaultConfig()
+	cfg.Level = slog.LevelDebug
+	cfg.Redact.Keys = []string{"custom_string"}
+	logger := logging.New(&buf, cfg)
+
+	logger.Info("call",
+		"authorization", "Basic dXNlcjprZXk=",
+		"note", "reply from +61411111111 to jane.citizen@example.com",
+		"args", map[string]any{
+			"api_key":       "secret",
+			"custom_string": "internal-ref",
+			"messages": []any{
+				map[string]any{"to": "+61411111111", "body": "Your code is 1234"},
+				map[string]any{"to": "0422 333 444", "from": "MyShop", "body": "hi"},
+			},
+			"phone_number": "(02) 9876 5432",
+			"fax_number":   []any{"0298765000"},
+		},
+	)
+	out := buf.String()
+	for _, leaked := range []string{"dXNlcjprZXk=", "61411111111", "jane.citizen", "secret", "internal-ref", "1234", "333", "9876", "876500"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log contains %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"+61*******11", "04** *** *44", "(02) **** **32", "02******00", "MyShop", "j***@example.com", "[17 bytes]", logging.Redacted} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log does not contain %q: %s", kept, out)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	r, err := logging.ParseRules("phones, emails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Phones || !r.Emails || r.Credentials || r.Bodies {
+		t.Errorf("ParseRules = %+v", r)
+	}
+	if r, _ := logging.ParseRules("none"); r.Credentials || r.Phones {
+		t.Errorf("ParseRules(none) = %+v", r)
+	}
+	if _, err := logging.ParseRules("secrets"); err == nil {
+		t.Error("unknown rule was accepted")
+	}
+}
+
+func TestToolCallAndTransport(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer api.Close()
+
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.DefaultConfig())
+	client := &http.Client{Transport: logging.Transport(logger, nil)}
+
+	handler := logging.ToolMiddleware(logger)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, api.URL+"/sms/send", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		return mcp.NewToolResultError("bad request"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "post_sms_send"
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2: %s", len(lines), buf.String())
+	}
+	upstream, call := lines[0], lines[1]
+	if upstream["msg"] != "clicksend request" || upstream["level"] != "WARN" || upstream["tool"] != "post_sms_send" ||
+		upstream["method"] != "POST" || upstream["path"] != "/sms/send" || upstream["status"] != float64(400) {
+		t.Errorf("upstream log = %v", upstream)
+	}
+	if _, ok := upstream["latency"]; !ok {
+		t.Errorf("upstream log has no latency: %v", upstream)
+	}
+	if call["msg"] != "tool call returned an error" || call["tool"] != "post_sms_send" {
+		t.Errorf("tool call log = %v", call)
+	}
+}
+
+func TestTransportRedaction(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer api.Close()
+
+	var buf bytes.Buffer
+	cfg := logging.DefaultConfig()
+	cfg.Level = slog.LevelDebug
+	logger := logging.New(&buf, cfg)
+	client := &http.Client{Transport: logging.Transport(logger, nil)}
+
+	handler := logging.ToolMiddleware(logger)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, api.URL+"/search/contacts-lists?q=%2B61411111111&email=jane.citizen%40example.com", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		return nil, &clicksend.APIError{StatusCode: 400, ResponseCode: "BAD_REQUEST", Body: []byte(`{"data":{"to":"0422 333 444","body":"Your code is 1234"}}`)}
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_search_contacts_lists"
+	handler(context.Background(), request)
+
+	out := buf.String()
+	for _, leaked := range []string{"61411111111", "%2B", "jane.citizen", "%40", "333", "1234"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log contains %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{`"q":"+61*******11"`, `"email":"j***@example.com"`, "API error: HTTP 400 BAD_REQUEST"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log does not contain %q: %s", kept, out)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type attrsKey struct{}
+
+// WithAttrs returns a context whose log records, such as the ClickSend
+// requests logged by Transport, carry attrs.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(append(merged, existing...), attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// Attrs returns the attributes added to ctx with WithAttrs.
+func Attrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// SessionID returns the MCP session ID of ctx, or "" outside a session.
+func SessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// ToolMiddleware logs each tool call with its session, duration and
+// outcome. The arguments are logged at debug level. The tool name and
+// session are added to the context for the ClickSend requests the call
+// makes. A nil logger uses the default logger at the time of each call,
+// which follows slog.SetDefault after a configuration reload.
+func ToolMiddleware(logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := cmp.Or(logger, slog.Default())
+			ctx = WithAttrs(ctx, slog.String("tool", request.Params.Name), slog.String("session_id", SessionID(ctx)))
+			logger.LogAttrs(ctx, slog.LevelDebug, "tool call started", append(Attrs(ctx), slog.Any("args", request.Params.Arguments))...)
+
+			start := time.Now()
+			result, err := next(ctx, request)
+			attrs := append(Attrs(ctx), slog.Duration("duration", time.Since(start)))
+			switch {
+			case err != nil:
+				logger.LogAttrs(ctx, slog.LevelError, "tool call failed", append(attrs, errorAttr(err))...)
+			case result != nil && result.IsError:
+				logger.LogAttrs(ctx, slog.LevelWarn, "tool call returned an error", attrs...)
+			default:
+				logger.LogAttrs(ctx, slog.LevelInfo, "tool call", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// Transport logs each request sent through next (http.DefaultTransport when
+// nil) with its method, path, status code and latency.
+func Transport(logger *slog.Logger, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{logger: logger, next: next}
+}
+
+type transport struct {
+	logger *slog.Logger
+	next   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs := append(Attrs(ctx),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", time.Since(start)),
+	)
+	if t.logger.Enabled(ctx, slog.LevelDebug) && req.URL.RawQuery != "" {
+		attrs = append(attrs, queryAttr(req.URL.RawQuery))
+	}
+	switch {
+	case err != nil:
+		t.logger.LogAttrs(ctx, slog.LevelError, "clicksend request failed", append(attrs, errorAttr(err))...)
+	case resp.StatusCode >= 500:
+		t.logger.LogAttrs(ctx, slog.LevelError, "clicksend request", append(attrs, slog.Int("status", resp.StatusCode))...)
+	case resp.StatusCode >= 400:
+		t.logger.LogAttrs(ctx, slog.LevelWarn, "clicksend request", append(attrs, slog.Int("status", resp.StatusCode))...)
+	default:
+		t.logger.LogAttrs(ctx, slog.LevelInfo, "clicksend request", append(attrs, slog.Int("status", resp.StatusCode))...)
+	}
+	return resp, err
+}
+
+// queryAttr logs a query string decoded into its parameters, so the
+// redaction rules see each name and value rather than percent-encoded text
+// such as %2B61 or %40. A query that does not parse is dropped.
+func queryAttr(rawQuery string) slog.Attr {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return slog.String("query", Redacted)
+	}
+	query := make(map[string]any, len(values))
+	for name, vs := range values {
+		if len(vs) == 1 {
+			query[name] = vs[0]
+			continue
+		}
+		all := make([]any, len(vs))
+		for i, v := range vs {
+			all[i] = v
+		}
+		query[name] = all
+	}
+	return slog.Any("query", query)
+}
+
+// errorAttr logs err without the parts the redaction rules cannot see into:
+// an API error keeps only its status and response code, since its body can
+// hold message content, and a transport error drops the request's query.
+func errorAttr(err error) slog.Attr {
+	var apiErr *clicksend.APIError
+	if errors.As(err, &apiErr) {
+		return slog.String("error", fmt.Sprintf("API error: HTTP %d %s", apiErr.StatusCode, apiErr.ResponseCode))
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		path := urlErr.URL
+		if u, perr := url.Parse(urlErr.URL); perr == nil {
+			path = u.Path
+		}
+		return slog.String("error", fmt.Sprintf("%s %s: %v", urlErr.Op, path, urlErr.Err))
+	}
+	return slog.String("error", err.Error())
+}
@@ -0,0 +1,218 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces credential values.
+const Redacted = "[REDACTED]"
+
+// Rules selects what is redacted from log records.
+type Rules struct {
+	// Credentials replaces Authorization headers, API keys, passwords and
+	// tokens.
+	Credentials bool
+	// Phones masks phone numbers, keeping the country code and last two
+	// digits.
+	Phones bool
+	// Emails masks the local part of email addresses.
+	Emails bool
+	// Bodies replaces message bodies and file content with their length.
+	Bodies bool
+	// Keys are extra attribute names whose values are always replaced.
+	Keys []string
+}
+
+// AllRules enables every rule.
+var AllRules = Rules{Credentials: true, Phones: true, Emails: true, Bodies: true}
+
+// ParseRules parses a comma-separated list of rule names: credentials,
+// phones, emails and bodies, or "all" or "none".
+func ParseRules(s string) (Rules, error) {
+	var r Rules
+	for _, name := range splitList(s) {
+		switch name {
+		case "all":
+			r = AllRules
+		case "none":
+			r = Rules{}
+		case "credentials":
+			r.Credentials = true
+		case "phones":
+			r.Phones = true
+		case "emails":
+			r.Emails = true
+		case "bodies":
+			r.Bodies = true
+		default:
+			return r, fmt.Errorf("unknown redaction rule %q", name)
+		}
+	}
+	return r, nil
+}
+
+// credentialKeys hold secrets, matched case-insensitively.
+var credentialKeys = map[string]bool{
+	"authorization":    true,
+	"api_key":          true,
+	"apikey":           true,
+	"basic_auth":       true,
+	"bearer_token":     true,
+	"password":         true,
+	"token":            true,
+	"activation_token": true,
+	"cvc":              true,
+}
+
+// bodyKeys hold message content.
+var bodyKeys = map[string]bool{
+	"body":          true,
+	"message_body":  true,
+	"content":       true,
+	"original_body": true,
+}
+
+// phoneKeys hold phone numbers, which are masked whatever their format,
+// since local numbers do not match phonePattern.
+var phoneKeys = map[string]bool{
+	"to":               true,
+	"from":             true,
+	"phone_number":     true,
+	"fax_number":       true,
+	"user_phone":       true,
+	"dedicated_number": true,
+	"mobile":           true,
+}
+
+var (
+	phonePattern = regexp.MustCompile(`\+\d{8,15}\b`)
+	emailPattern = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+)
+
+// ReplaceAttr redacts an attribute. It is used as slog.HandlerOptions
+// ReplaceAttr and also walks maps and slices logged with slog.Any, such as
+// tool arguments.
+func (r Rules) ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	a.Value = slog.AnyValue(r.redact(a.Key, a.Value.Any()))
+	return a
+}
+
+func (r Rules) redact(key string, v any) any {
+	key = strings.ToLower(key)
+	switch {
+	case r.Credentials && credentialKeys[key], r.isExtraKey(key):
+		if v == nil || v == "" {
+			return v
+		}
+		return Redacted
+	case r.Bodies && bodyKeys[key]:
+		if s, ok := v.(string); ok {
+			return fmt.Sprintf("[%d bytes]", len(s))
+		}
+	case r.Phones && phoneKeys[key]:
+		switch p := v.(type) {
+		case string:
+			return r.redactString(maskDigits(p))
+		case float64:
+			return maskDigits(strconv.FormatFloat(p, 'f', -1, 64))
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		return r.redactString(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = r.redact(k, e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = r.redact(key, e)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = r.redact(k, e)
+		}
+		return out
+	case []string:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = r.redact(key, e)
+		}
+		return out
+	}
+	return v
+}
+
+func (r Rules) isExtraKey(key string) bool {
+	for _, k := range r.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rules) redactString(s string) string {
+	if r.Phones {
+		s = phonePattern.ReplaceAllStringFunc(s, maskPhone)
+	}
+	if r.Emails {
+		s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+	}
+	return s
+}
+
+// maskPhone keeps the plus sign, two leading digits and two trailing digits.
+func maskPhone(s string) string {
+	if len(s) < 7 {
+		return s
+	}
+	return s[:3] + strings.Repeat("*", len(s)-5) + s[len(s)-2:]
+}
+
+// maskDigits masks the digits of a phone number in any format, keeping
+// the first two and last two. Values with fewer than six digits, such as
+// sender names, are kept.
+func maskDigits(s string) string {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	if n < 6 {
+		return s
+	}
+	seen := 0
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return r
+		}
+		seen++
+		if seen <= 2 || seen > n-2 {
+			return r
+		}
+		return '*'
+	}, s)
+}
+
+// maskEmail keeps the first character of the local part and the domain.
+func maskEmail(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at < 1 {
+		return s
+	}
+	return s[:1] + "***" + s[at:]
+}
@@ -1,38 +1,92 @@
 package main
 
 import (
+	"cmp"
 	"context"
-	"log"
-	"net"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/clicksend-rest-api-v3/mcp-server/auth"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+	"github.com/clicksend-rest-api-v3/mcp-server/health"
+	"github.com/clicksend-rest-api-v3/mcp-server/impersonate"
+	"github.com/clicksend-rest-api-v3/mcp-server/logging"
+	"github.com/clicksend-rest-api-v3/mcp-server/metrics"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/schemas"
+	"github.com/clicksend-rest-api-v3/mcp-server/sendtime"
+	"github.com/clicksend-rest-api-v3/mcp-server/shaping"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/clicksend-rest-api-v3/mcp-server/tracing"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
+// version is reported in the MCP initialize response and by /readyz. Release
+// builds set it with -ldflags "-X main.version=...".
+var version = "1.0.0"
+
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML configuration `file`; the environment overrides its settings")
+	flag.Parse()
+	if *configPath != "" {
+		f, err := configfile.Load(*configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		configfile.Use(f)
+	}
+
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load logging config", err)
+	}
+	slog.SetDefault(logging.New(os.Stderr, logCfg))
+
 	cfg, err := config.LoadAPIConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("Failed to load config", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		fatal("Failed to set up tracing", err)
 	}
+	defer flushTracing(shutdownTracing)
 
 	// Check transport environment variable (both uppercase and lowercase)
-	transport := os.Getenv("TRANSPORT")
+	transport := configfile.Getenv("TRANSPORT")
 	if transport == "" {
-		transport = os.Getenv("transport")
+		transport = configfile.Getenv("transport")
 	}
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
 
 	// HTTP/HTTPS Mode - if transport is "http", "HTTP", "https", or "HTTPS"
 	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" {
-		port := cfg.Port
-		if port == "" {
-			log.Fatalf("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
+		addr := cfg.ListenAddr
+		if addr == "" {
+			slog.Error("PORT or LISTEN_ADDR environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
+			os.Exit(1)
 		}
 
 		// Determine if HTTPS mode and normalize transport
@@ -42,104 +96,350 @@ func main() {
 		} else {
 			transport = "HTTP"
 		}
-		
-		log.Printf("Running in %s mode on port %s", transport, port)
-
-		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
-			}
 
-			if apiCfg.BaseURL == "" {
-				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
-				return
-			}
-
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
-
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
-
-			handler.ServeHTTP(w, r)
-		})
+		slog.Info("Running", "transport", transport, "addr", addr)
 
-		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(`{"status":"ok"}`))
-		})
+		authn, err := auth.FromEnv()
+		if err != nil {
+			fatal("Failed to load MCP_AUTH_FILE", err)
+		}
+		if err := checkProfileAuth(cfg, authn); err != nil {
+			fatal("Refusing to serve credential profiles", err)
+		}
+		if authn == nil {
+			slog.Warn("MCP_AUTH_FILE is not set; /mcp accepts unauthenticated requests")
+		}
 
-		addr := net.JoinHostPort("0.0.0.0", port)
-		httpServer := &http.Server{Addr: addr, Handler: mux}
+		// The handler is swapped on SIGHUP; the listener stays as it is
+		var handler atomic.Pointer[http.Handler]
+		h := newHTTPHandler(cfg, transport, authn)
+		handler.Store(&h)
+		httpServer := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			(*handler.Load()).ServeHTTP(w, r)
+		})}
 
 		go func() {
 			// Check if HTTPS mode
 			if isHTTPS {
-				certFile := os.Getenv("CERT_FILE")
-				keyFile := os.Getenv("KEY_FILE")
-				
+				certFile := configfile.Getenv("CERT_FILE")
+				keyFile := configfile.Getenv("KEY_FILE")
+
 				if certFile == "" || keyFile == "" {
-					log.Fatalf("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
+					slog.Error("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
+					os.Exit(1)
 				}
-				
-				log.Printf("Starting HTTPS server on %s", addr)
+
+				slog.Info("Starting HTTPS server", "addr", addr)
 				if err := httpServer.ListenAndServeTLS(certFile, keyFile); err != http.ErrServerClosed {
-					log.Fatalf("HTTPS server error: %v", err)
+					fatal("HTTPS server error", err)
 				}
 			} else {
-				log.Printf("Starting HTTP server on %s", addr)
+				slog.Info("Starting HTTP server", "addr", addr)
 				if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
-					log.Fatalf("HTTP server error: %v", err)
+					fatal("HTTP server error", err)
 				}
 			}
 		}()
 
-		<-sigChan
-		log.Println("Shutdown signal received")
+		waitForShutdown(sigChan, hupChan, func() {
+			newCfg, err := reload(*configPath, cfg)
+			if err != nil {
+				slog.Error("Reload failed; keeping the previous configuration", "error", err)
+				return
+			}
+			newAuthn, err := auth.FromEnv()
+			if err != nil {
+				slog.Error("Reload failed; keeping the previous configuration", "error", fmt.Errorf("MCP_AUTH_FILE: %w", err))
+				return
+			}
+			if err := checkProfileAuth(newCfg, newAuthn); err != nil {
+				slog.Error("Reload failed; keeping the previous configuration", "error", err)
+				return
+			}
+			cfg = newCfg
+			h := newHTTPHandler(cfg, transport, newAuthn)
+			handler.Store(&h)
+			slog.Info("Configuration reloaded")
+		})
+		slog.Info("Shutdown signal received")
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := httpServer.Shutdown(ctx); err != nil {
-			log.Printf("Shutdown error: %v", err)
+			slog.Error("Shutdown error", "error", err)
 		} else {
-			log.Println("HTTP server shutdown complete")
+			slog.Info("HTTP server shutdown complete")
 		}
 		return
 	}
 
 	// STDIO Mode - default when no transport or transport is "stdio"
-	log.Println("Running in STDIO mode")
+	slog.Info("Running", "transport", "STDIO")
 	mcp := createMCPServer(cfg, "STDIO")
 	go func() {
 		if err := server.ServeStdio(mcp); err != nil {
-			log.Fatalf("STDIO error: %v", err)
+			fatal("STDIO error", err)
 		}
 	}()
-	<-sigChan
-	log.Println("Received shutdown signal. Exiting STDIO mode.")
+	waitForShutdown(sigChan, hupChan, func() {
+		newCfg, err := reload(*configPath, cfg)
+		if err != nil {
+			slog.Error("Reload failed; keeping the previous configuration", "error", err)
+			return
+		}
+		cfg = newCfg
+		mcp.SetTools(serverTools(cfg)...)
+		slog.Info("Configuration reloaded")
+	})
+	slog.Info("Received shutdown signal. Exiting STDIO mode.")
+}
+
+// checkProfileAuth refuses credential profiles over HTTP without
+// MCP_AUTH_FILE, since any client could then act with the stored keys.
+func checkProfileAuth(cfg *config.APIConfig, authn *auth.Authenticator) error {
+	if cfg.Profiles != nil && authn == nil {
+		return errors.New("credential profiles are configured but MCP_AUTH_FILE is not set; HTTP clients could use any stored credentials")
+	}
+	return nil
+}
+
+// waitForShutdown calls reload for each SIGHUP until a shutdown signal
+// arrives.
+func waitForShutdown(sigChan, hupChan <-chan os.Signal, reload func()) {
+	for {
+		select {
+		case <-hupChan:
+			reload()
+		case <-sigChan:
+			return
+		}
+	}
+}
+
+// reload reads the configuration file at path again, when there is one, and
+// returns the configuration built from it. The default logger is replaced
+// too. Listener, TLS and cassette settings only apply at startup, so changes
+// to them are logged and otherwise ignored. On error nothing changes.
+func reload(path string, old *config.APIConfig) (*config.APIConfig, error) {
+	prev := configfile.Active()
+	if path != "" {
+		f, err := configfile.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil {
+			if changed := configfile.ListenerChanges(prev, f); len(changed) > 0 {
+				slog.Warn("Some changed settings only apply after a restart", "settings", changed)
+			}
+		}
+		configfile.Use(f)
+	}
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		configfile.Use(prev)
+		return nil, err
+	}
+	cfg, err := config.Reload(old)
+	if err != nil {
+		configfile.Use(prev)
+		return nil, err
+	}
+	slog.SetDefault(logging.New(os.Stderr, logCfg))
+	return cfg, nil
+}
+
+// flushTracing exports buffered spans before the process exits.
+func flushTracing(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		slog.Error("Tracing shutdown error", "error", err)
+	}
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// newHTTPHandler serves MCP over streamable HTTP on /mcp, building the API
+// configuration for each request from its headers, Prometheus metrics on
+// /metrics, liveness on /healthz and / and readiness on /readyz. The
+// CLICKSEND_SUBACCOUNT header selects a subaccount to act as. When authn is
+// set, /mcp requires a bearer token and uses the ClickSend credentials or
+// profile stored for its principal instead of the headers; credential
+// profiles are only available to principals and never take the API_BASE_URL
+// header as their base URL.
+func newHTTPHandler(cfg *config.APIConfig, transport string, authn *auth.Authenticator) http.Handler {
+	mux := http.NewServeMux()
+	var mcpHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Read headers for dynamic config
+		apiCfg := &config.APIConfig{
+			BaseURL:        r.Header.Get("API_BASE_URL"),
+			BearerToken:    r.Header.Get("BEARER_TOKEN"),
+			APIKey:         r.Header.Get("API_KEY"),
+			BasicAuth:      r.Header.Get("BASIC_AUTH"),
+			MaxOutputBytes: cfg.MaxOutputBytes,
+			HTTPClient:     cfg.HTTPClient,
+			MaxRetries:     cfg.MaxRetries,
+			Profiles:       cfg.Profiles,
+			ProfileBaseURL: cmp.Or(cfg.ProfileBaseURL, cfg.BaseURL, clicksend.DefaultBaseURL),
+			Subaccounts:    cfg.Subaccounts,
+			Subaccount:     cmp.Or(r.Header.Get("CLICKSEND_SUBACCOUNT"), cfg.Subaccount),
+			RequestTimeout: cfg.RequestTimeout,
+			ToolTimeout:    cfg.ToolTimeout,
+			ToolsInclude:   cfg.ToolsInclude,
+			ToolsExclude:   cfg.ToolsExclude,
+			Budget:         cfg.Budget,
+			Uploads:        cfg.Uploads,
+			Bulk:           cfg.Bulk,
+			PhoneCountry:   cfg.PhoneCountry,
+			Suppression:    cfg.Suppression,
+			SendTime:       cfg.SendTime,
+			Templates:      cfg.Templates,
+		}
+
+		if p := auth.FromContext(r.Context()); p != nil {
+			apiCfg.BaseURL = cmp.Or(p.Credentials.BaseURL, cfg.BaseURL, clicksend.DefaultBaseURL)
+			apiCfg.BearerToken = ""
+			apiCfg.APIKey = ""
+			apiCfg.BasicAuth = p.Credentials.Basic()
+			apiCfg.Profile = p.Credentials.Profile
+			apiCfg.AllowedProfiles = slices.DeleteFunc(append([]string{p.Credentials.Profile}, p.Credentials.Profiles...), func(name string) bool { return name == "" })
+			r = r.WithContext(logging.WithAttrs(r.Context(), slog.String("principal", p.Name)))
+		} else {
+			// Only principals may use the profiles stored on the server
+			apiCfg.AllowedProfiles = []string{}
+		}
+
+		if apiCfg.BaseURL == "" && apiCfg.Profiles != nil {
+			apiCfg.BaseURL = cmp.Or(cfg.BaseURL, clicksend.DefaultBaseURL)
+		}
+		if apiCfg.BaseURL == "" {
+			http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+			return
+		}
+
+		slog.Debug("Incoming HTTP request", "api_host", apiHost(apiCfg.BaseURL), "session_id", r.Header.Get(server.HeaderKeySessionID))
+
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if r.Method == http.MethodDelete {
+			metrics.Default.SessionClosed(sessionID)
+		} else {
+			metrics.Default.SessionSeen(sessionID)
+		}
+
+		// Create MCP server for this request
+		mcpSrv := createMCPServer(apiCfg, transport)
+		handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
+			func(ctx context.Context, req *http.Request) context.Context {
+				return context.WithValue(ctx, "apiConfig", apiCfg)
+			},
+		))
+
+		handler.ServeHTTP(w, r.WithContext(tracing.Extract(r.Context(), r.Header)))
+		// Only sessions this server issued count as active, not ids a client
+		// makes up.
+		if issued := w.Header().Get(server.HeaderKeySessionID); sessionID == "" && issued != "" {
+			metrics.Default.SessionStarted(issued)
+		}
+	})
+	if authn != nil {
+		mcpHandler = authn.Middleware(mcpHandler)
+		if path := authn.MetadataPath(); path != "" {
+			mux.Handle(path, authn.MetadataHandler())
+		}
+	}
+	mux.Handle("/mcp", mcpHandler)
+
+	mux.Handle("/metrics", metrics.Default.Handler())
+
+	checker := &health.Checker{
+		Version:  version,
+		Tools:    len(serverTools(cfg)),
+		Config:   cfg,
+		Probe:    cfg.ReadinessProbe,
+		ProbeTTL: cfg.ReadinessProbeTTL,
+	}
+	mux.HandleFunc("/healthz", checker.Liveness)
+	mux.HandleFunc("/readyz", checker.Readiness)
+	mux.HandleFunc("/", checker.Liveness)
+
+	return mux
+}
+
+// apiHost returns the host of baseURL, which is logged instead of the full
+// URL in case it embeds credentials.
+func apiHost(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil {
+		return u.Host
+	}
+	return ""
 }
 
 func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
-	mcp := server.NewMCPServer("ClickSend REST API v3", "1.0.0",
+	opts := []server.ServerOption{
 		server.WithToolCapabilities(true),
 		server.WithRecovery(),
-	)
+		server.WithToolHandlerMiddleware(tracing.ToolMiddleware()),
+		server.WithToolHandlerMiddleware(logging.ToolMiddleware(nil)),
+		server.WithToolHandlerMiddleware(metrics.Default.ToolMiddleware()),
+	}
+	if mode == "STDIO" {
+		// HTTP sessions are counted by the handler, which knows which
+		// session ids the server issued.
+		opts = append(opts, server.WithHooks(metrics.Default.Hooks()))
+	}
+	mcp := server.NewMCPServer("ClickSend REST API v3", version, opts...)
+
+	tools := serverTools(cfg)
+	slog.Debug("Loaded tools", "count", len(tools), "transport", mode)
+	mcp.AddTools(tools...)
+
+	return mcp
+}
 
-	tools := GetAll(cfg)
-	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
+// serverTools returns the tools enabled by cfg, wrapped with the output
+// upload, shaping, SMS template history, phone number, suppression, send
+// time, profile, subaccount and timeout handling it configures.
+func serverTools(cfg *config.APIConfig) []server.ServerTool {
+	var tools []server.ServerTool
+	for _, tool := range append(GetAll(cfg), GetWorkflows(cfg)...) {
+		if !cfg.ToolEnabled(tool.Definition.Name) {
+			continue
+		}
+		tool = shaping.Wrap(upload.Wrap(smstemplate.Wrap(schemas.Attach(tool), cfg.Templates, cfg.Client, cfg.Account), cfg.Uploads, cfg.Client), cfg.MaxOutputBytes)
+		tool = phone.Wrap(suppression.Wrap(sendtime.Wrap(tool, cfg.SendTime, cfg.Client), cfg.Suppression, cfg.Client, cfg.Account, cfg.PhoneCountry), cfg.PhoneCountry)
+		if cfg.Profiles != nil {
+			tool = vault.Wrap(tool, profileNames(cfg))
+		}
+		if cfg.Subaccounts != nil {
+			tool = impersonate.Wrap(tool, cfg.Subaccounts)
+		}
+		if cfg.ToolTimeout > 0 {
+			tool = withTimeout(tool, cfg.ToolTimeout)
+		}
+		tools = append(tools, server.ServerTool{Tool: tool.Definition, Handler: tool.Handler})
+	}
+	return tools
+}
 
-	for _, tool := range tools {
-		mcp.AddTool(tool.Definition, tool.Handler)
+// withTimeout gives each call of a tool a deadline.
+func withTimeout(tool models.Tool, timeout time.Duration) models.Tool {
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, request)
 	}
+	return tool
+}
 
-	return mcp
-}
\ No newline at end of file
+// profileNames returns the profiles a tool call may select.
+func profileNames(cfg *config.APIConfig) []string {
+	if cfg.AllowedProfiles != nil {
+		return cfg.AllowedProfiles
+	}
+	return cfg.Profiles.Names()
+}
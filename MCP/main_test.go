@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/auth"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestHTTPAuth checks that an authenticated client uses the ClickSend
+// credentials stored for it rather than any it sends itself.
+func TestHTTPAuth(t *testing.T) {
+	api := newFakeAPI(t)
+	authn, err := auth.New(auth.File{
+		Tokens:     []auth.Token{{Principal: "agent", Token: "secret"}},
+		Principals: map[string]auth.Credentials{"agent": {BaseURL: api.URL, Username: clicksendtest.Username, APIKey: clicksendtest.APIKey}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(newHTTPHandler(&config.APIConfig{}, "HTTP", authn))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Post(srv.URL+"/mcp", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("request without a token: status %d", resp.StatusCode)
+	}
+
+	c, err := client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(map[string]string{
+		"Authorization": "Bearer secret",
+		"API_BASE_URL":  "https://rest.clicksend.com/v3",
+		"BASIC_AUTH":    "aWdub3JlZDppZ25vcmVk",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	startClient(t, c)
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_account"
+	if res, err := c.CallTool(context.Background(), req); err != nil || res.IsError {
+		t.Fatalf("get_account: %v %v", err, res)
+	}
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].Header.Get("Authorization") != "Basic "+basicAuth() {
+		t.Errorf("ClickSend requests = %+v, want one with the stored credentials", requests)
+	}
+}
+
+// TestProfileBaseURL checks that a profile's stored credentials never go to
+// a base URL chosen by the API_BASE_URL header.
+func TestProfileBaseURL(t *testing.T) {
+	api, attacker := newFakeAPI(t), newFakeAPI(t)
+	profiles := profileVault(t, "prod")
+	authn, err := auth.New(auth.File{
+		Tokens:     []auth.Token{{Principal: "agent", Token: "secret"}},
+		Principals: map[string]auth.Credentials{"agent": {Profiles: []string{"prod"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(newHTTPHandler(&config.APIConfig{BaseURL: api.URL, Profiles: profiles}, "HTTP", authn))
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(map[string]string{
+		"Authorization": "Bearer secret",
+		"API_BASE_URL":  attacker.URL,
+		"BASIC_AUTH":    "aWdub3JlZDppZ25vcmVk",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	startClient(t, c)
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_account"
+	req.Params.Arguments = map[string]any{"profile": "prod"}
+	if res, err := c.CallTool(context.Background(), req); err != nil || res.IsError {
+		t.Fatalf("get_account: %v %v", err, res)
+	}
+	if got := attacker.Requests(); len(got) != 0 {
+		t.Errorf("the API_BASE_URL header received %d requests with profile credentials", len(got))
+	}
+	if got := api.Requests(); len(got) != 1 || got[0].Header.Get("Authorization") != "Basic "+basicAuth() {
+		t.Errorf("ClickSend requests = %+v, want one with the profile's credentials", got)
+	}
+}
+
+// TestUnauthenticatedProfile checks that without MCP_AUTH_FILE an HTTP
+// client cannot act with a profile's stored credentials.
+func TestUnauthenticatedProfile(t *testing.T) {
+	api := newFakeAPI(t)
+	profiles := profileVault(t, "prod")
+	cfg := &config.APIConfig{BaseURL: api.URL, Profiles: profiles, Profile: "prod"}
+	if err := checkProfileAuth(cfg, nil); err == nil {
+		t.Error("profiles without MCP_AUTH_FILE were accepted")
+	}
+	srv := httptest.NewServer(newHTTPHandler(cfg, "HTTP", nil))
+	t.Cleanup(srv.Close)
+
+	for name, tt := range map[string]struct {
+		headers map[string]string
+		args    map[string]any
+	}{
+		"session profile":  {map[string]string{"CLICKSEND_PROFILE": "prod"}, nil},
+		"profile argument": {nil, map[string]any{"profile": "prod"}},
+		"default profile":  {nil, nil},
+	} {
+		c, err := client.NewStreamableHttpClient(srv.URL+"/mcp", transport.WithHTTPHeaders(tt.headers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		startClient(t, c)
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "get_account"
+		req.Params.Arguments = tt.args
+		if res, err := c.CallTool(context.Background(), req); err != nil || !res.IsError {
+			t.Errorf("%s: get_account = %v %v, want it refused", name, err, res)
+		}
+	}
+	for _, r := range api.Requests() {
+		if r.Header.Get("Authorization") == "Basic "+basicAuth() {
+			t.Errorf("%s %s used the profile's credentials", r.Method, r.Path)
+		}
+	}
+}
+
+// profileVault returns a vault holding the fake API's credentials under
+// each of names.
+func profileVault(t *testing.T, names ...string) *vault.Vault {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		os.MkdirAll(filepath.Join(dir, name), 0o700)
+		os.WriteFile(filepath.Join(dir, name, "username"), []byte(clicksendtest.Username), 0o600)
+		os.WriteFile(filepath.Join(dir, name, "api_key"), []byte(clicksendtest.APIKey), 0o600)
+	}
+	profiles, err := vault.Open("", nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profiles
+}
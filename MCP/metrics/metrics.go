@@ -0,0 +1,259 @@
+// Package metrics exposes Prometheus metrics for tool calls and the
+// ClickSend requests they make.
+//
+// ToolMiddleware counts tool calls and tracks active sessions, Transport
+// measures ClickSend requests, and Handler serves everything on /metrics.
+// Default is the instance the server uses.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// SessionIdleTimeout is how long a session counts as active after its last
+// tool call when it is not closed explicitly.
+const SessionIdleTimeout = 30 * time.Minute
+
+// MaxSessions bounds the sessions tracked at once. When it is reached, the
+// least recently seen session is forgotten to make room.
+const MaxSessions = 10000
+
+// Metrics holds the collectors of one registry.
+type Metrics struct {
+	registry        *prometheus.Registry
+	toolCalls       *prometheus.CounterVec
+	upstreamErrors  *prometheus.CounterVec
+	upstreamLatency *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	creditSpent     *prometheus.CounterVec
+
+	mu       sync.Mutex
+	sessions map[string]time.Time
+	now      func() time.Time
+}
+
+// Default is used by the server.
+var Default = New()
+
+// New returns metrics registered on a new registry, which also holds the Go
+// runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_tool_calls_total",
+			Help: "Tool calls by tool and outcome (ok or error).",
+		}, []string{"tool", "outcome"}),
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clicksend_errors_total",
+			Help: "Failed ClickSend requests by tool and ClickSend response_code.",
+		}, []string{"tool", "response_code"}),
+		upstreamLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "clicksend_request_duration_seconds",
+			Help:    "ClickSend request latency by tool, method and HTTP status.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"tool", "method", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clicksend_retries_total",
+			Help: "Retried ClickSend requests by tool.",
+		}, []string{"tool"}),
+		creditSpent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clicksend_credit_spent_total",
+			Help: "Credit charged by ClickSend for sends, by tool, in the account currency.",
+		}, []string{"tool"}),
+		sessions: map[string]time.Time{},
+		now:      time.Now,
+	}
+	m.registry.MustRegister(
+		m.toolCalls, m.upstreamErrors, m.upstreamLatency, m.retries, m.creditSpent,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "mcp_active_sessions",
+			Help: "MCP sessions that made a request within the idle timeout and were not closed.",
+		}, func() float64 { return float64(m.ActiveSessions()) }),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the registry holding the metrics.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+type toolKey struct{}
+
+func toolFrom(ctx context.Context) string {
+	tool, _ := ctx.Value(toolKey{}).(string)
+	return tool
+}
+
+// ToolMiddleware counts tool calls, marks their session active and passes
+// the tool name to Transport through the context.
+func (m *Metrics) ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				m.SessionSeen(session.SessionID())
+			}
+			result, err := next(context.WithValue(ctx, toolKey{}, request.Params.Name), request)
+			outcome := "ok"
+			if err != nil || (result != nil && result.IsError) {
+				outcome = "error"
+			}
+			m.toolCalls.WithLabelValues(request.Params.Name, outcome).Inc()
+			return result, err
+		}
+	}
+}
+
+// SessionStarted counts a session the server created as active.
+func (m *Metrics) SessionStarted(id string) {
+	if id == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; !ok && len(m.sessions) >= MaxSessions {
+		m.expire()
+		if len(m.sessions) >= MaxSessions {
+			var oldest string
+			var at time.Time
+			for id, seen := range m.sessions {
+				if oldest == "" || seen.Before(at) {
+					oldest, at = id, seen
+				}
+			}
+			delete(m.sessions, oldest)
+		}
+	}
+	m.sessions[id] = m.now()
+}
+
+// SessionSeen marks a session started with SessionStarted active again.
+// Session ids the server did not create are ignored, since they come from
+// the client.
+func (m *Metrics) SessionSeen(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; ok {
+		m.sessions[id] = m.now()
+	}
+}
+
+// Hooks returns server hooks that count the sessions a server registers,
+// such as the stdio session, from registration to unregistration.
+func (m *Metrics) Hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		m.SessionStarted(session.SessionID())
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		m.SessionClosed(session.SessionID())
+	})
+	return hooks
+}
+
+// SessionClosed marks a session closed.
+func (m *Metrics) SessionClosed(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+// ActiveSessions returns the number of sessions seen within
+// SessionIdleTimeout and not closed, forgetting idle ones.
+func (m *Metrics) ActiveSessions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire()
+	return len(m.sessions)
+}
+
+// expire forgets the sessions idle for longer than SessionIdleTimeout. m.mu
+// must be held.
+func (m *Metrics) expire() {
+	cutoff := m.now().Add(-SessionIdleTimeout)
+	for id, seen := range m.sessions {
+		if seen.Before(cutoff) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// Transport measures requests sent through next (http.DefaultTransport when
+// nil): latency, failures by ClickSend response_code, retries and the
+// credit charged by successful sends.
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{m: m, next: next}
+}
+
+type transport struct {
+	m    *Metrics
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tool := toolFrom(req.Context())
+	if clicksend.Attempt(req.Context()) > 0 {
+		t.m.retries.WithLabelValues(tool).Inc()
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Seconds()
+	if err != nil {
+		t.m.upstreamLatency.WithLabelValues(tool, req.Method, "error").Observe(elapsed)
+		t.m.upstreamErrors.WithLabelValues(tool, "TRANSPORT_ERROR").Inc()
+		return nil, err
+	}
+	t.m.upstreamLatency.WithLabelValues(tool, req.Method, strconv.Itoa(resp.StatusCode)).Observe(elapsed)
+
+	isSend := clicksend.IsSend(req)
+	if resp.StatusCode < 400 && !isSend {
+		return resp, nil
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return resp, nil
+	}
+	if resp.StatusCode >= 400 {
+		var envelope struct {
+			ResponseCode string `json:"response_code"`
+		}
+		json.Unmarshal(body, &envelope)
+		code := envelope.ResponseCode
+		if code == "" {
+			code = "HTTP_" + strconv.Itoa(resp.StatusCode)
+		}
+		t.m.upstreamErrors.WithLabelValues(tool, code).Inc()
+		return resp, nil
+	}
+	if spent := clicksend.SendCost(body); spent > 0 {
+		t.m.creditSpent.WithLabelValues(tool).Add(spent)
+	}
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/metrics"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMetrics(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	m := metrics.New()
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: m.Transport(nil)}),
+		clicksend.WithRetries(1, time.Millisecond))
+
+	call := func(tool string, fn func(ctx context.Context) error) {
+		handler := m.ToolMiddleware()(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := fn(ctx); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText("ok"), nil
+		})
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool
+		handler(context.Background(), request)
+	}
+
+	sms := &clicksend.SmsMessageCollection{Messages: []clicksend.SmsMessage{
+		{To: "+61411111111", Body: "Hello"},
+		{To: "+61411111112", Body: "Hello"},
+	}}
+	call("post_sms_send", func(ctx context.Context) error {
+		_, err := client.SendSms(ctx, sms)
+		return err
+	})
+	call("post_sms_price", func(ctx context.Context) error {
+		_, err := client.SmsPrice(ctx, sms)
+		return err
+	})
+	call("get_lists_list_id", func(ctx context.Context) error {
+		_, err := client.GetContactList(ctx, "999")
+		return err
+	})
+	api.AddFault(clicksendtest.Fault{Method: "GET", Path: "/account", Status: http.StatusServiceUnavailable, Times: 1})
+	call("get_account", func(ctx context.Context) error {
+		_, err := client.GetAccount(ctx)
+		return err
+	})
+	m.SessionStarted("a")
+	m.SessionStarted("b")
+	m.SessionSeen("made-up")
+	m.SessionClosed("a")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	for _, want := range []string{
+		`mcp_tool_calls_total{outcome="ok",tool="post_sms_send"} 1`,
+		`mcp_tool_calls_total{outcome="error",tool="get_lists_list_id"} 1`,
+		`mcp_tool_calls_total{outcome="ok",tool="get_account"} 1`,
+		`clicksend_errors_total{response_code="NOT_FOUND",tool="get_lists_list_id"} 1`,
+		`clicksend_errors_total{response_code="SERVICE_UNAVAILABLE",tool="get_account"} 1`,
+		`clicksend_retries_total{tool="get_account"} 1`,
+		`clicksend_credit_spent_total{tool="post_sms_send"} 0.154`,
+		`clicksend_request_duration_seconds_count{method="POST",status="200",tool="post_sms_send"} 1`,
+		`mcp_active_sessions 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+	if strings.Contains(out, `clicksend_credit_spent_total{tool="post_sms_price"}`) {
+		t.Error("price check was counted as credit spent")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	m := metrics.New()
+	for i := range metrics.MaxSessions + 5 {
+		m.SessionStarted(fmt.Sprint("s", i))
+	}
+	if n := m.ActiveSessions(); n != metrics.MaxSessions {
+		t.Errorf("ActiveSessions = %d after starting more than MaxSessions, want %d", n, metrics.MaxSessions)
+	}
+	m.SessionSeen("made-up")
+	m.SessionClosed("s6")
+	if n := m.ActiveSessions(); n != metrics.MaxSessions-1 {
+		t.Errorf("ActiveSessions = %d, want a client-supplied id ignored and a closed one dropped", n)
+	}
+}
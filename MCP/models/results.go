@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DecodeArgs converts tool arguments into a typed request. String arguments
+// are accepted for numeric and boolean fields, and dotted argument names such
+// as "from.name" are decoded into nested objects.
+func DecodeArgs(args map[string]any, v any) error {
+	argsJSON, err := json.Marshal(nestArgs(args))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(argsJSON, v)
+}
+
+func nestArgs(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		parts := strings.Split(k, ".")
+		m := out
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := m[p].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				m[p] = child
+			}
+			m = child
+		}
+		m[parts[len(parts)-1]] = v
+	}
+	return out
+}
+
+// ErrorResult converts an error returned by the ClickSend client into a tool
+// error result.
+func ErrorResult(err error) *mcp.CallToolResult {
+	var apiErr *clicksend.APIError
+	if errors.As(err, &apiErr) {
+		return mcp.NewToolResultError(apiErr.Error())
+	}
+	return mcp.NewToolResultErrorFromErr("Request failed", err)
+}
+
+// APIResult returns the ClickSend response body as the tool result. Responses
+// that do not match the typed model are still returned as received.
+func APIResult[T any](resp *clicksend.Response[T], err error) (*mcp.CallToolResult, error) {
+	var decodeErr *clicksend.DecodeError
+	if err != nil && (resp == nil || !errors.As(err, &decodeErr)) {
+		return ErrorResult(err), nil
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(resp.Raw, &result); err != nil {
+		// Fallback to raw text if unmarshaling fails
+		return mcp.NewToolResultText(string(resp.Raw)), nil
+	}
+	return JSONResult(result)
+}
+
+// JSONResult returns v as indented JSON text.
+func JSONResult(v any) (*mcp.CallToolResult, error) {
+	prettyJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+	}
+	return mcp.NewToolResultText(string(prettyJSON)), nil
+}
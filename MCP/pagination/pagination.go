@@ -0,0 +1,236 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DefaultLimit is the page size ClickSend uses when no limit is given.
+	DefaultLimit = 15
+	// MinLimit and MaxLimit are the page size bounds accepted by ClickSend.
+	MinLimit = 15
+	MaxLimit = 100
+	// DefaultMaxPages caps how many pages fetch_all walks when max_pages is omitted.
+	DefaultMaxPages = 10
+	// MaxPagesCap is the hard upper bound for max_pages.
+	MaxPagesCap = 50
+)
+
+// Params holds the pagination arguments shared by every paginated tool.
+type Params struct {
+	Page     int
+	Limit    int
+	FetchAll bool
+	MaxPages int
+}
+
+// Envelope is the normalized pagination block returned alongside list data.
+type Envelope struct {
+	Total        int  `json:"total"`
+	PerPage      int  `json:"per_page"`
+	CurrentPage  int  `json:"current_page"`
+	LastPage     int  `json:"last_page"`
+	NextPage     *int `json:"next_page"`
+	FetchedPages int  `json:"fetched_pages"`
+	Truncated    bool `json:"truncated,omitempty"`
+}
+
+// Result is the response returned to the agent for a paginated tool call.
+type Result struct {
+	HTTPCode     int           `json:"http_code,omitempty"`
+	ResponseCode string        `json:"response_code,omitempty"`
+	ResponseMsg  string        `json:"response_msg,omitempty"`
+	Data         []interface{} `json:"data"`
+	Pagination   Envelope      `json:"pagination"`
+}
+
+// page mirrors the data wrapper ClickSend uses for paginated collections.
+type page struct {
+	Total       int           `json:"total"`
+	PerPage     json.Number   `json:"per_page"`
+	CurrentPage int           `json:"current_page"`
+	LastPage    int           `json:"last_page"`
+	NextPageURL *string       `json:"next_page_url"`
+	Data        []interface{} `json:"data"`
+}
+
+type response struct {
+	HTTPCode     int             `json:"http_code"`
+	ResponseCode string          `json:"response_code"`
+	ResponseMsg  string          `json:"response_msg"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// WithParams adds the page, limit, fetch_all and max_pages arguments to a tool.
+func WithParams() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber("page", mcp.Min(1), mcp.Description("Page number to return. Defaults to 1."))(t)
+		mcp.WithNumber("limit", mcp.Min(MinLimit), mcp.Max(MaxLimit), mcp.Description("Number of results per page (15-100). Defaults to 15, or 100 when fetch_all is set."))(t)
+		mcp.WithBoolean("fetch_all", mcp.Description("Follow next pages starting at page and merge every result, up to max_pages pages."))(t)
+		mcp.WithNumber("max_pages", mcp.Min(1), mcp.Max(MaxPagesCap), mcp.Description(fmt.Sprintf("Maximum number of pages to fetch when fetch_all is set. Defaults to %d, capped at %d.", DefaultMaxPages, MaxPagesCap)))(t)
+	}
+}
+
+// ParseParams reads the pagination arguments from a tool call, applying defaults and bounds.
+func ParseParams(args map[string]any) (Params, error) {
+	p := Params{Page: 1, MaxPages: DefaultMaxPages}
+
+	page, err := intArg(args, "page")
+	if err != nil {
+		return p, err
+	}
+	if page > 0 {
+		p.Page = page
+	}
+
+	if v, ok := args["fetch_all"]; ok && v != nil {
+		b, ok := v.(bool)
+		if !ok {
+			return p, fmt.Errorf("Invalid parameter: fetch_all must be a boolean")
+		}
+		p.FetchAll = b
+	}
+
+	limit, err := intArg(args, "limit")
+	if err != nil {
+		return p, err
+	}
+	switch {
+	case limit > 0:
+		p.Limit = clamp(limit, MinLimit, MaxLimit)
+	case p.FetchAll:
+		p.Limit = MaxLimit
+	default:
+		p.Limit = DefaultLimit
+	}
+
+	maxPages, err := intArg(args, "max_pages")
+	if err != nil {
+		return p, err
+	}
+	if maxPages > 0 {
+		p.MaxPages = clamp(maxPages, 1, MaxPagesCap)
+	}
+
+	return p, nil
+}
+
+// Lister fetches one page of a paginated collection with the ClickSend client.
+type Lister[T any] func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[T]], error)
+
+// Fetch reads a paginated ClickSend collection. When FetchAll is set it follows
+// pages until the last page or MaxPages is reached and merges the data arrays
+// into a single Result. Items are taken from the raw response so fields the
+// typed model does not know about are kept.
+func Fetch[T any](ctx context.Context, p Params, list Lister[T]) (*Result, error) {
+	result := &Result{Data: []interface{}{}}
+	current := p.Page
+	for {
+		resp, pg, err := fetchPage(ctx, list, current, p.Limit)
+		if err != nil {
+			return nil, err
+		}
+		if result.Pagination.FetchedPages == 0 {
+			result.HTTPCode = resp.HTTPCode
+			result.ResponseCode = resp.ResponseCode
+			result.ResponseMsg = resp.ResponseMsg
+		}
+		result.Data = append(result.Data, pg.Data...)
+		result.Pagination.FetchedPages++
+		result.Pagination.Total = pg.Total
+		result.Pagination.CurrentPage = pg.CurrentPage
+		result.Pagination.LastPage = pg.LastPage
+		if perPage, err := pg.PerPage.Int64(); err == nil {
+			result.Pagination.PerPage = int(perPage)
+		}
+
+		hasNext := pg.CurrentPage < pg.LastPage
+		if hasNext {
+			next := pg.CurrentPage + 1
+			result.Pagination.NextPage = &next
+		} else {
+			result.Pagination.NextPage = nil
+		}
+
+		if !p.FetchAll || !hasNext {
+			break
+		}
+		if result.Pagination.FetchedPages >= p.MaxPages {
+			result.Pagination.Truncated = true
+			break
+		}
+		current = pg.CurrentPage + 1
+	}
+	return result, nil
+}
+
+func fetchPage[T any](ctx context.Context, list Lister[T], pageNum, limit int) (*response, *page, error) {
+	resp, err := list(ctx, &clicksend.ListOptions{Page: pageNum, Limit: limit})
+	var decodeErr *clicksend.DecodeError
+	if err != nil && (resp == nil || !errors.As(err, &decodeErr)) {
+		return nil, nil, err
+	}
+
+	var envelope response
+	if err := json.Unmarshal(resp.Raw, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	var pg page
+	if len(envelope.Data) > 0 && envelope.Data[0] == '{' {
+		if err := json.Unmarshal(envelope.Data, &pg); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode pagination data: %w", err)
+		}
+	} else if len(envelope.Data) > 0 && envelope.Data[0] == '[' {
+		// Some endpoints return a bare array; treat it as a single page.
+		if err := json.Unmarshal(envelope.Data, &pg.Data); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode response data: %w", err)
+		}
+		pg.Total = len(pg.Data)
+		pg.CurrentPage = pageNum
+		pg.LastPage = pageNum
+	}
+	if pg.Data == nil {
+		pg.Data = []interface{}{}
+	}
+	return &envelope, &pg, nil
+}
+
+func intArg(args map[string]any, name string) (int, error) {
+	v, ok := args[name]
+	if !ok || v == nil {
+		return 0, nil
+	}
+	switch n := v.(type) {
+	case float64:
+		return int(n), nil
+	case int:
+		return n, nil
+	case string:
+		if n == "" {
+			return 0, nil
+		}
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid parameter: %s must be a number", name)
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("Invalid parameter: %s must be a number", name)
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
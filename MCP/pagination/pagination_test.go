@@ -0,0 +1,121 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    Params
+		wantErr bool
+	}{
+		{"defaults", map[string]any{}, Params{Page: 1, Limit: DefaultLimit, MaxPages: DefaultMaxPages}, false},
+		{"limit 0", map[string]any{"limit": 0.0}, Params{Page: 1, Limit: DefaultLimit, MaxPages: DefaultMaxPages}, false},
+		{"limit 0 fetch_all", map[string]any{"limit": 0.0, "fetch_all": true}, Params{Page: 1, Limit: MaxLimit, FetchAll: true, MaxPages: DefaultMaxPages}, false},
+		{"limit below min", map[string]any{"limit": 5.0}, Params{Page: 1, Limit: MinLimit, MaxPages: DefaultMaxPages}, false},
+		{"limit above max", map[string]any{"limit": 500.0}, Params{Page: 1, Limit: MaxLimit, MaxPages: DefaultMaxPages}, false},
+		{"page string", map[string]any{"page": "3", "limit": "20"}, Params{Page: 3, Limit: 20, MaxPages: DefaultMaxPages}, false},
+		{"page 0", map[string]any{"page": 0.0}, Params{Page: 1, Limit: DefaultLimit, MaxPages: DefaultMaxPages}, false},
+		{"max_pages cap", map[string]any{"fetch_all": true, "max_pages": 99.0}, Params{Page: 1, Limit: MaxLimit, FetchAll: true, MaxPages: MaxPagesCap}, false},
+		{"bad limit", map[string]any{"limit": "many"}, Params{}, true},
+		{"bad fetch_all", map[string]any{"fetch_all": "yes"}, Params{}, true},
+		{"bad max_pages", map[string]any{"max_pages": []any{}}, Params{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseParams(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseParams = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseParams = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// lister serves total items in pages, recording the pages asked for.
+func lister(total int, asked *[]int) Lister[any] {
+	return func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[any]], error) {
+		*asked = append(*asked, opts.Page)
+		last := max(1, (total+opts.Limit-1)/opts.Limit)
+		items := []any{}
+		for i := (opts.Page - 1) * opts.Limit; i < min(total, opts.Page*opts.Limit); i++ {
+			items = append(items, map[string]any{"id": i, "extra": "kept"})
+		}
+		raw, _ := json.Marshal(map[string]any{
+			"http_code":     200,
+			"response_code": "SUCCESS",
+			"data":          map[string]any{"total": total, "per_page": opts.Limit, "current_page": opts.Page, "last_page": last, "data": items},
+		})
+		return &clicksend.Response[clicksend.Page[any]]{HTTPCode: 200, ResponseCode: "SUCCESS", Raw: raw}, nil
+	}
+}
+
+func TestFetch(t *testing.T) {
+	next := func(n int) *int { return &n }
+	tests := []struct {
+		name  string
+		p     Params
+		items int
+		asked []int
+		want  Envelope
+	}{
+		{"first page", Params{Page: 1, Limit: 15, MaxPages: 10}, 15, []int{1}, Envelope{Total: 40, PerPage: 15, CurrentPage: 1, LastPage: 3, NextPage: next(2), FetchedPages: 1}},
+		{"last page", Params{Page: 3, Limit: 15, MaxPages: 10}, 10, []int{3}, Envelope{Total: 40, PerPage: 15, CurrentPage: 3, LastPage: 3, FetchedPages: 1}},
+		{"fetch_all", Params{Page: 1, Limit: 15, FetchAll: true, MaxPages: 10}, 40, []int{1, 2, 3}, Envelope{Total: 40, PerPage: 15, CurrentPage: 3, LastPage: 3, FetchedPages: 3}},
+		{"fetch_all from page 2", Params{Page: 2, Limit: 15, FetchAll: true, MaxPages: 10}, 25, []int{2, 3}, Envelope{Total: 40, PerPage: 15, CurrentPage: 3, LastPage: 3, FetchedPages: 2}},
+		{"fetch_all cap", Params{Page: 1, Limit: 15, FetchAll: true, MaxPages: 2}, 30, []int{1, 2}, Envelope{Total: 40, PerPage: 15, CurrentPage: 2, LastPage: 3, NextPage: next(3), FetchedPages: 2, Truncated: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked []int
+			got, err := Fetch(context.Background(), tt.p, lister(40, &asked))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Data) != tt.items || !reflect.DeepEqual(asked, tt.asked) {
+				t.Errorf("got %d items from pages %v, want %d from %v", len(got.Data), asked, tt.items, tt.asked)
+			}
+			if !reflect.DeepEqual(got.Pagination, tt.want) {
+				t.Errorf("Pagination = %+v, want %+v", got.Pagination, tt.want)
+			}
+			if got.ResponseCode != "SUCCESS" || got.Data[0].(map[string]any)["extra"] != "kept" {
+				t.Errorf("result = %+v, want the raw response kept", got)
+			}
+		})
+	}
+}
+
+func TestFetchBareArray(t *testing.T) {
+	got, err := Fetch(context.Background(), Params{Page: 1, Limit: 15, FetchAll: true, MaxPages: 10}, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[any]], error) {
+		return &clicksend.Response[clicksend.Page[any]]{Raw: json.RawMessage(`{"http_code":200,"data":[{"id":1},{"id":2}]}`)}, &clicksend.DecodeError{Err: errors.New("not a page")}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Envelope{Total: 2, CurrentPage: 1, LastPage: 1, FetchedPages: 1}); len(got.Data) != 2 || got.Pagination != want {
+		t.Errorf("Fetch = %+v, want one page of 2 items", got)
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	apiErr := &clicksend.APIError{StatusCode: 404, ResponseCode: "NOT_FOUND"}
+	_, err := Fetch(context.Background(), Params{Page: 1, Limit: 15, MaxPages: 10}, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[any]], error) {
+		return nil, apiErr
+	})
+	if !errors.Is(err, apiErr) {
+		t.Errorf("err = %v, want the API error", err)
+	}
+}
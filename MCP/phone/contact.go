@@ -0,0 +1,35 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+// NormalizeContact rewrites the phone and fax numbers of c in E.164, as Wrap
+// does for the contact tools. Local numbers are read in c's address country,
+// or in country when that is not known, and are left unchanged when neither
+// is.
+func NormalizeContact(c *clicksend.Contact, country string) error {
+	if Lookup(c.AddressCountry) != nil {
+		country = c.AddressCountry
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{{"phone_number", &c.PhoneNumber}, {"fax_number", &c.FaxNumber}} {
+		if *f.value == "" {
+			continue
+		}
+		n, err := Parse(*f.value, country)
+		if errors.Is(err, ErrNoCountry) || errors.Is(err, ErrUnknownCountry) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.value = n.E164
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package phone
+
+//go:generate go run ../cmd/gencountries -spec ../../opeanapi.yaml -plans plans.json -out countries.go
+
+// rule is a range of national significant numbers: the digits after the
+// calling code. Numbers match the rule with the longest prefix.
+type rule struct {
+	prefixes []string
+	lengths  []int
+	typ      Type
+}
+
+// Country is the numbering plan of a country.
+type Country struct {
+	Code        string // ISO 3166-1 alpha-2 code, as get_countries returns it
+	Name        string // name as get_countries returns it
+	CallingCode string
+	// Trunk is the prefix dialled before national numbers within the
+	// country, such as the 0 of 0411 111 111.
+	Trunk string
+	rules []rule
+}
@@ -0,0 +1,216 @@
+// Package phone normalizes phone and fax numbers to E.164 without calling
+// any service. It knows the numbering plans of the countries in countries,
+// generated from get_countries and plans.json, rejects numbers of a length
+// those plans rule out, flags numbers outside the ranges they list, and
+// tells mobile, landline and toll-free numbers apart where the plan does.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Type is the kind of line a number belongs to.
+type Type string
+
+const (
+	TypeMobile   Type = "mobile"
+	TypeLandline Type = "landline"
+	TypeTollFree Type = "toll_free"
+	// TypeUnknown is a valid number whose plan does not tell the kind of
+	// line, such as any North American number.
+	TypeUnknown Type = "unknown"
+)
+
+var (
+	// ErrNoCountry is returned for a national number without a country.
+	ErrNoCountry = errors.New("a local number needs a country")
+	// ErrUnknownCountry is returned for a national number of a country
+	// without a numbering plan.
+	ErrUnknownCountry = errors.New("no numbering plan for country")
+)
+
+// Number is a parsed phone number.
+type Number struct {
+	E164 string `json:"e164"`
+	// Country is the ISO code of the country the number belongs to, or ""
+	// for international numbers of countries without a numbering plan.
+	Country string `json:"country,omitempty"`
+	Type    Type   `json:"type"`
+	// Unlisted is set for a number of a possible length that is in none
+	// of the ranges the country's plan lists. It may be valid, in a range
+	// the plan lacks, so it is flagged rather than rejected.
+	Unlisted bool `json:"unlisted,omitempty"`
+}
+
+// Lookup returns the country with the given ISO code or name, as
+// get_countries lists them, or nil.
+func Lookup(country string) *Country {
+	for _, c := range countries {
+		if strings.EqualFold(c.Code, country) || strings.EqualFold(c.Name, country) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Codes returns the ISO codes of the countries with a numbering plan.
+func Codes() []string {
+	codes := make([]string, len(countries))
+	for i, c := range countries {
+		codes[i] = c.Code
+	}
+	return codes
+}
+
+// Parse normalizes s to E.164. Numbers starting with + or 00 are read as
+// international. Others are read as national numbers of country, an ISO
+// code or name, with or without the trunk prefix.
+func Parse(s, country string) (Number, error) {
+	digits, international, err := clean(s)
+	if err != nil {
+		return Number{}, err
+	}
+	if international {
+		return parseInternational(s, digits, Lookup(country))
+	}
+	if country == "" {
+		return Number{}, ErrNoCountry
+	}
+	c := Lookup(country)
+	if c == nil {
+		return Number{}, fmt.Errorf("%w %q", ErrUnknownCountry, country)
+	}
+	candidates := []string{digits}
+	if c.Trunk != "" && strings.HasPrefix(digits, c.Trunk) {
+		candidates = append([]string{digits[len(c.Trunk):]}, candidates...)
+	}
+	if strings.HasPrefix(digits, c.CallingCode) {
+		candidates = append(candidates, digits[len(c.CallingCode):])
+	}
+	for _, nsn := range candidates {
+		if typ, ok := c.match(nsn); ok {
+			return Number{E164: "+" + c.CallingCode + nsn, Country: c.Code, Type: typ}, nil
+		}
+	}
+	for _, nsn := range candidates {
+		if c.possible(nsn) {
+			return Number{E164: "+" + c.CallingCode + nsn, Country: c.Code, Type: TypeUnknown, Unlisted: true}, nil
+		}
+	}
+	return Number{}, fmt.Errorf("%q is not a valid number in %s", s, c.Name)
+}
+
+func parseInternational(s, digits string, preferred *Country) (Number, error) {
+	var known *Country
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		code, nsn := digits[:n], digits[n:]
+		var candidates []*Country
+		for _, c := range countries {
+			if c.CallingCode == code {
+				candidates = append(candidates, c)
+			}
+		}
+		if i := slices.Index(candidates, preferred); i > 0 {
+			candidates = append([]*Country{preferred}, slices.Delete(candidates, i, i+1)...)
+		}
+		for _, c := range candidates {
+			if typ, ok := c.match(nsn); ok {
+				return Number{E164: "+" + digits, Country: c.Code, Type: typ}, nil
+			}
+		}
+		for _, c := range candidates {
+			if c.possible(nsn) {
+				return Number{E164: "+" + digits, Country: c.Code, Type: TypeUnknown, Unlisted: true}, nil
+			}
+		}
+		if len(candidates) > 0 {
+			known = candidates[0]
+			break
+		}
+	}
+	if known != nil {
+		return Number{}, fmt.Errorf("%q is not a valid number in %s", s, known.Name)
+	}
+	if len(digits) < 8 || len(digits) > 15 {
+		return Number{}, fmt.Errorf("%q has %d digits, want 8 to 15", s, len(digits))
+	}
+	return Number{E164: "+" + digits, Type: TypeUnknown}, nil
+}
+
+// clean returns the digits of s and whether it has an international prefix.
+// Spaces, dots, dashes and parentheses are allowed between the digits.
+func clean(s string) (digits string, international bool, err error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			international = true
+		case strings.ContainsRune(" .-()", r):
+		default:
+			return "", false, fmt.Errorf("%q is not a phone number", s)
+		}
+	}
+	digits = b.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		digits, international = digits[2:], true
+	}
+	if digits == "" {
+		return "", false, fmt.Errorf("%q is not a phone number", s)
+	}
+	return digits, international, nil
+}
+
+// match returns the type of the national significant number nsn, from the
+// rule with the longest matching prefix that allows its length.
+func (c *Country) match(nsn string) (Type, bool) {
+	best, typ := -1, TypeUnknown
+	for _, r := range c.rules {
+		if !slices.Contains(r.lengths, len(nsn)) {
+			continue
+		}
+		for _, p := range r.prefixes {
+			if strings.HasPrefix(nsn, p) && len(p) > best {
+				best, typ = len(p), r.typ
+			}
+		}
+	}
+	return typ, best >= 0
+}
+
+// possible reports whether a rule of the plan allows the length of the
+// national significant number nsn.
+func (c *Country) possible(nsn string) bool {
+	return slices.ContainsFunc(c.rules, func(r rule) bool { return slices.Contains(r.lengths, len(nsn)) })
+}
+
+// Mismatch returns why n is unlikely to be reachable on channel: "sms",
+// "mms", "voice" or "fax", or on any channel when n is unlisted. It returns
+// "" when n suits the channel or its type is unknown.
+func (n Number) Mismatch(channel string) string {
+	if n.Unlisted {
+		name := n.Country
+		if c := Lookup(n.Country); c != nil {
+			name = c.Name
+		}
+		return fmt.Sprintf("%s is in no number range known for %s; check it is right", n.E164, name)
+	}
+	switch channel {
+	case "sms", "mms":
+		switch n.Type {
+		case TypeLandline:
+			return fmt.Sprintf("%s is a landline number, which usually cannot receive %s", n.E164, strings.ToUpper(channel))
+		case TypeTollFree:
+			return fmt.Sprintf("%s is a toll-free number, which usually cannot receive %s", n.E164, strings.ToUpper(channel))
+		}
+	case "fax":
+		if n.Type == TypeMobile {
+			return fmt.Sprintf("%s is a mobile number, which cannot receive faxes", n.E164)
+		}
+	}
+	return ""
+}
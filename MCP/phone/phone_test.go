@@ -0,0 +1,135 @@
+package phone
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, country string
+		want        Number
+		wantErr     string
+	}{
+		{"+61 411 111 111", "", Number{E164: "+61411111111", Country: "AU", Type: TypeMobile}, ""},
+		{"0411-111-111", "AU", Number{E164: "+61411111111", Country: "AU", Type: TypeMobile}, ""},
+		{"(02) 9876 5432", "Australia", Number{E164: "+61298765432", Country: "AU", Type: TypeLandline}, ""},
+		{"61411111111", "au", Number{E164: "+61411111111", Country: "AU", Type: TypeMobile}, ""},
+		{"1800 123 456", "AU", Number{E164: "+611800123456", Country: "AU", Type: TypeTollFree}, ""},
+		{"07700 900123", "GB", Number{E164: "+447700900123", Country: "GB", Type: TypeMobile}, ""},
+		{"0044 20 7946 0018", "", Number{E164: "+442079460018", Country: "GB", Type: TypeLandline}, ""},
+		{"(415) 555-0100", "US", Number{E164: "+14155550100", Country: "US", Type: TypeUnknown}, ""},
+		{"+1 800 555 0100", "", Number{E164: "+18005550100", Country: "US", Type: TypeTollFree}, ""},
+		{"+1 416 555 0100", "CA", Number{E164: "+14165550100", Country: "CA", Type: TypeUnknown}, ""},
+		{"+81 3 1234 5678", "", Number{E164: "+81312345678", Country: "", Type: TypeUnknown}, ""},
+		{"0412", "AU", Number{}, "not a valid number in Australia"},
+		{"+61 5 1234 5678", "", Number{E164: "+61512345678", Country: "AU", Type: TypeUnknown, Unlisted: true}, ""},
+		{"+61 5 1234 567 890", "", Number{}, "not a valid number in Australia"},
+		{"+353 818 123 456", "", Number{E164: "+353818123456", Country: "IE", Type: TypeUnknown}, ""},
+		{"0810 123 456", "IE", Number{E164: "+353810123456", Country: "IE", Type: TypeUnknown, Unlisted: true}, ""},
+		{"+81 1234", "", Number{}, "has 6 digits"},
+		{"call 0411", "AU", Number{}, "not a phone number"},
+		{"0411 111 111", "", Number{}, ErrNoCountry.Error()},
+		{"03-1234-5678", "JP", Number{}, ErrUnknownCountry.Error()},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.country)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q, %q) err = %v, want %q", tt.in, tt.country, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, %q) = %+v, %v, want %+v", tt.in, tt.country, got, err, tt.want)
+		}
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	landline, _ := Parse("02 9876 5432", "AU")
+	mobile, _ := Parse("0411 111 111", "AU")
+	if landline.Mismatch("sms") == "" || landline.Mismatch("fax") != "" || landline.Mismatch("voice") != "" {
+		t.Errorf("landline mismatches: sms %q, fax %q, voice %q", landline.Mismatch("sms"), landline.Mismatch("fax"), landline.Mismatch("voice"))
+	}
+	if mobile.Mismatch("fax") == "" || mobile.Mismatch("mms") != "" {
+		t.Errorf("mobile mismatches: fax %q, mms %q", mobile.Mismatch("fax"), mobile.Mismatch("mms"))
+	}
+	unlisted, _ := Parse("+61 5 1234 5678", "")
+	if !strings.Contains(unlisted.Mismatch(""), "no number range known for Australia") {
+		t.Errorf("unlisted mismatch = %q", unlisted.Mismatch(""))
+	}
+}
+
+// TestCountries checks that every country get_countries returns has a
+// numbering plan under the same name.
+func TestCountries(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	resp, err := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey)).ListCountries(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range resp.Data {
+		plan := Lookup(c.Code)
+		if plan == nil {
+			t.Errorf("no numbering plan for %s", c.Code)
+		} else if plan.Name != c.Value {
+			t.Errorf("%s is named %q, get_countries calls it %q", c.Code, plan.Name, c.Value)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	var got map[string]any
+	tool := Wrap(models.Tool{
+		Definition: mcp.NewTool("post_sms_send", mcp.WithString("to"), mcp.WithString("country"), mcp.WithArray("messages")),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			got = request.GetArguments()
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}, "AU")
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"messages": []any{
+		map[string]any{"to": "0411 111 111", "body": "hi"},
+		map[string]any{"to": "020 7946 0018", "country": "GB", "body": "hi"},
+		map[string]any{"list_id": 1000, "body": "hi"},
+	}})
+	messages := got["messages"].([]any)
+	if to := messages[0].(map[string]any)["to"]; to != "+61411111111" {
+		t.Errorf("messages[0].to = %v", to)
+	}
+	if to := messages[1].(map[string]any)["to"]; to != "+442079460018" {
+		t.Errorf("messages[1].to = %v", to)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "messages[1].to: +442079460018 is a landline number") {
+		t.Errorf("result = %v, want a landline warning", result.Content)
+	}
+
+	result = call(map[string]any{"to": "+353 810 123 456", "body": "hi"})
+	if result.IsError || got["to"] != "+353810123456" || !strings.Contains(result.Content[len(result.Content)-1].(mcp.TextContent).Text, "no number range known for Ireland") {
+		t.Errorf("result = %v, want an unlisted number sent with a warning", result.Content)
+	}
+
+	got = nil
+	result = call(map[string]any{"to": "0412", "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(result.Content[0].(mcp.TextContent).Text, `Invalid to: "0412" is not a valid number in Australia`) {
+		t.Errorf("result = %v, want an invalid number error before the call", result.Content)
+	}
+}
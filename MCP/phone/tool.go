@@ -0,0 +1,114 @@
+package phone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// target is a number argument of a tool.
+type target struct {
+	arg     string
+	country string // argument naming the number's country
+	channel string // channel the number must suit, "" for any
+}
+
+// targets are the tools whose numbers are normalized before they run. Send
+// and price tools take their numbers either directly or in messages.
+var targets = map[string][]target{
+	"post_sms_send":                         {{"to", "country", "sms"}},
+	"post_sms_price":                        {{"to", "country", "sms"}},
+	"post_mms_send":                         {{"to", "country", "mms"}},
+	"post_mms_price":                        {{"to", "country", "mms"}},
+	"post_voice_send":                       {{"to", "country", "voice"}},
+	"post_voice_price":                      {{"to", "country", "voice"}},
+	"post_fax_send":                         {{"to", "country", "fax"}},
+	"post_fax_price":                        {{"to", "country", "fax"}},
+	"post_lists_list_id_contacts":           {{"phone_number", "address_country", ""}, {"fax_number", "address_country", "fax"}},
+	"put_lists_list_id_contacts_contact_id": {{"phone_number", "address_country", ""}, {"fax_number", "address_country", "fax"}},
+}
+
+// Wrap normalizes the phone and fax numbers of a send, price or contact
+// tool to E.164 before it runs. Local numbers are read in the country the
+// call names, or in country when it names none, and are passed on unchanged
+// when neither is known. Impossible numbers fail the call, and numbers that
+// do not suit the channel add a warning to the result. Other tools are
+// returned unchanged.
+func Wrap(tool models.Tool, country string) models.Tool {
+	ts, ok := targets[tool.Definition.Name]
+	if !ok {
+		return tool
+	}
+	for _, t := range ts {
+		if prop, ok := tool.Definition.InputSchema.Properties[t.arg].(map[string]any); ok {
+			desc, _ := prop["description"].(string)
+			prop["description"] = desc + fmt.Sprintf(" Local numbers are normalized to E.164 using %s or the server's default country.", t.country)
+		}
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return handler(ctx, request)
+		}
+		var warnings []string
+		normalize := func(obj map[string]any, path string) (map[string]any, error) {
+			out := make(map[string]any, len(obj))
+			for k, v := range obj {
+				out[k] = v
+			}
+			for _, t := range ts {
+				s, ok := obj[t.arg].(string)
+				if !ok || s == "" {
+					continue
+				}
+				c, _ := obj[t.country].(string)
+				if c == "" || Lookup(c) == nil {
+					c = country
+				}
+				n, err := Parse(s, c)
+				if errors.Is(err, ErrNoCountry) || errors.Is(err, ErrUnknownCountry) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%s%s: %w", path, t.arg, err)
+				}
+				out[t.arg] = n.E164
+				if m := n.Mismatch(t.channel); m != "" {
+					warnings = append(warnings, fmt.Sprintf("%s%s: %s", path, t.arg, m))
+				}
+			}
+			return out, nil
+		}
+
+		forwarded, err := normalize(args, "")
+		if err == nil {
+			if messages, ok := args["messages"].([]any); ok {
+				normalized := make([]any, len(messages))
+				for i, m := range messages {
+					normalized[i] = m
+					if obj, ok := m.(map[string]any); ok && err == nil {
+						normalized[i], err = normalize(obj, fmt.Sprintf("messages[%d].", i))
+					}
+				}
+				forwarded["messages"] = normalized
+			}
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid %v", err)), nil
+		}
+		request.Params.Arguments = forwarded
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && !result.IsError {
+			for _, w := range warnings {
+				result.Content = append(result.Content, mcp.NewTextContent("Warning: "+w))
+			}
+		}
+		return result, err
+	}
+	return tool
+}
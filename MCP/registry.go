@@ -111,6 +111,7 @@ func GetAll(cfg *config.APIConfig) []models.Tool {
 		tools_mms.CreateGetdeliveryreceiptTool(cfg),
 		tools_sms_campaigns.CreateGetlistofsmscampaignsTool(cfg),
 		tools_account_recharge.CreateGettransactionsTool(cfg),
+		tools_account_recharge.CreateGetaspecifictransactionTool(cfg),
 		tools_email_marketing.CreateDeleteemailtemplateTool(cfg),
 		tools_email_marketing.CreateGetspecificemailtemplateTool(cfg),
 		tools_email_marketing.CreateUpdateanemailtemplateTool(cfg),
@@ -122,9 +123,9 @@ func GetAll(cfg *config.APIConfig) []models.Tool {
 		tools_email_marketing.CreateSendverificationtokenTool(cfg),
 		tools_email_marketing.CreateGetallallowedemailaddressesTool(cfg),
 		tools_email_marketing.CreateCreateallowedemailaddressTool(cfg),
-		tools_automation_rules.CreateUpdatearuleTool(cfg),
-		tools_automation_rules.CreateDeletearuleTool(cfg),
-		tools_automation_rules.CreateGetaspecificruleTool(cfg),
+		tools_automation_rules.CreatePut_automations_sms_inbound_inbound_rule_idTool(cfg),
+		tools_automation_rules.CreateDelete_automations_sms_inbound_inbound_rule_idTool(cfg),
+		tools_automation_rules.CreateGet_automations_sms_inbound_inbound_rule_idTool(cfg),
 		tools_account_recharge.CreateListofpackagesTool(cfg),
 		tools_contact_lists.CreateGetlistofacceptableimportfieldsTool(cfg),
 		tools_account.CreateVerifynewaccountTool(cfg),
@@ -201,10 +202,10 @@ func GetAll(cfg *config.APIConfig) []models.Tool {
 		tools_mms.CreateGetmmshistoryTool(cfg),
 		tools_forgot_account.CreateVerifyforgotpasswordTool(cfg),
 		tools_email_marketing.CreateUploadimagetospecifictemplateTool(cfg),
-		tools_automation_rules.CreateListrulesTool(cfg),
+		tools_automation_rules.CreateGet_automations_fax_receiptsTool(cfg),
 		tools_contact_lists.CreateShowcsvimportfilepreviewTool(cfg),
 		tools_sms.CreateCancelascheduledmessageTool(cfg),
-		tools_automation_rules.CreateCreateanewruleTool(cfg),
+		tools_automation_rules.CreatePost_automations_fax_receiptsTool(cfg),
 		tools_email_marketing.CreateCreateemailcampaignTool(cfg),
 		tools_post_letter.CreateGetlistofpostreturnaddressesTool(cfg),
 		tools_post_letter.CreateCreateapostreturnaddressTool(cfg),
@@ -0,0 +1,128 @@
+package schemas
+
+//go:generate go run ../cmd/genschemas -spec ../../opeanapi.yaml -out output_schemas.json
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+//go:embed output_schemas.json
+var outputSchemasJSON []byte
+
+var outputSchemas map[string]map[string]any
+
+func init() {
+	if err := json.Unmarshal(outputSchemasJSON, &outputSchemas); err != nil {
+		panic("schemas: invalid output_schemas.json: " + err.Error())
+	}
+}
+
+// paginationSchema describes the normalized envelope returned by paginated tools.
+var paginationSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"total":         map[string]any{"type": "integer"},
+		"per_page":      map[string]any{"type": "integer"},
+		"current_page":  map[string]any{"type": "integer"},
+		"last_page":     map[string]any{"type": "integer"},
+		"next_page":     map[string]any{"type": []string{"integer", "null"}},
+		"fetched_pages": map[string]any{"type": "integer"},
+		"truncated":     map[string]any{"type": "boolean"},
+	},
+}
+
+// OutputSchema returns the output schema generated for a tool, or nil when the
+// OpenAPI document has no JSON response example for it. Paginated tools (those
+// accepting fetch_all) get the normalized pagination envelope instead of
+// ClickSend's nested data wrapper.
+func OutputSchema(tool mcp.Tool) map[string]any {
+	schema, ok := outputSchemas[tool.Name]
+	if !ok {
+		return nil
+	}
+	if _, paged := tool.InputSchema.Properties["fetch_all"]; !paged {
+		return schema
+	}
+
+	items := map[string]any{}
+	if data, ok := property(schema, "data"); ok {
+		if inner, ok := property(data, "data"); ok {
+			if it, ok := inner["items"].(map[string]any); ok {
+				items = it
+			}
+		}
+	}
+	props := map[string]any{
+		"data":       map[string]any{"type": "array", "items": items},
+		"pagination": paginationSchema,
+	}
+	for _, k := range []string{"http_code", "response_code", "response_msg"} {
+		if p, ok := property(schema, k); ok {
+			props[k] = p
+		}
+	}
+	return map[string]any{"type": "object", "properties": props}
+}
+
+func property(schema map[string]any, name string) (map[string]any, bool) {
+	props, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	p, ok := props[name].(map[string]any)
+	return p, ok
+}
+
+// Attach declares the tool's output schema and makes its handler return the
+// decoded response as structuredContent next to the text content. Tools without
+// a generated schema are returned unchanged.
+func Attach(tool models.Tool) models.Tool {
+	schema := OutputSchema(tool.Definition)
+	if schema == nil {
+		return tool
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return tool
+	}
+	tool.Definition.RawOutputSchema = raw
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || result.StructuredContent != nil {
+			return result, err
+		}
+		result.StructuredContent = Structured(result)
+		return result, nil
+	}
+	return tool
+}
+
+// Structured decodes the first text content of a result into a JSON object.
+// Non-object payloads are wrapped so structuredContent is always an object.
+func Structured(result *mcp.CallToolResult) map[string]any {
+	var text string
+	for _, c := range result.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			text = tc.Text
+			break
+		}
+	}
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return map[string]any{"text": text}
+	}
+	if m, ok := doc.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{"data": doc}
+}
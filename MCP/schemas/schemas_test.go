@@ -0,0 +1,107 @@
+package schemas
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const smsHistory = "get_sms_history?date_from=date_from&date_to=date_to"
+
+func TestOutputSchema(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  mcp.Tool
+		paged bool
+		found bool
+	}{
+		{"unknown tool", mcp.NewTool("no_such_tool"), false, false},
+		{"record", mcp.NewTool("get_account"), false, true},
+		{"list without fetch_all", mcp.NewTool(smsHistory), false, true},
+		{"paged list", mcp.NewTool(smsHistory, mcp.WithBoolean("fetch_all")), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := OutputSchema(tt.tool)
+			if (schema != nil) != tt.found {
+				t.Fatalf("OutputSchema = %v, want found %v", schema, tt.found)
+			}
+			if !tt.found {
+				return
+			}
+			if !tt.paged {
+				if !reflect.DeepEqual(schema, outputSchemas[tt.tool.Name]) {
+					t.Error("OutputSchema is not the generated schema")
+				}
+				return
+			}
+			if p, _ := property(schema, "pagination"); !reflect.DeepEqual(p, paginationSchema) {
+				t.Errorf("pagination = %v, want the envelope schema", p)
+			}
+			data, _ := property(schema, "data")
+			items, _ := data["items"].(map[string]any)
+			if data["type"] != "array" || items == nil {
+				t.Fatalf("data = %v, want an array of the page's items", data)
+			}
+			if _, ok := property(items, "message_id"); !ok {
+				t.Errorf("items = %v, want the message properties", items)
+			}
+			if _, ok := property(schema, "response_code"); !ok {
+				t.Error("response_code was dropped")
+			}
+		})
+	}
+}
+
+func TestStructured(t *testing.T) {
+	tests := []struct {
+		text string
+		want map[string]any
+	}{
+		{`{"http_code":200,"data":{"balance":"9.50"}}`, map[string]any{"http_code": json.Number("200"), "data": map[string]any{"balance": "9.50"}}},
+		{`[1,2]`, map[string]any{"data": []any{json.Number("1"), json.Number("2")}}},
+		{`not json`, map[string]any{"text": "not json"}},
+	}
+	for _, tt := range tests {
+		if got := Structured(mcp.NewToolResultText(tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Structured(%s) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAttach(t *testing.T) {
+	handler := func(text string, isError bool) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if isError {
+				return mcp.NewToolResultError(text), nil
+			}
+			return mcp.NewToolResultText(text), nil
+		}
+	}
+
+	unknown := Attach(models.Tool{Definition: mcp.NewTool("no_such_tool"), Handler: handler(`{}`, false)})
+	if unknown.Definition.RawOutputSchema != nil {
+		t.Error("a tool without a schema got one")
+	}
+
+	tool := Attach(models.Tool{Definition: mcp.NewTool("get_account"), Handler: handler(`{"data":{"username":"ann"}}`, false)})
+	if len(tool.Definition.RawOutputSchema) == 0 {
+		t.Fatal("get_account has no output schema")
+	}
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.StructuredContent.(map[string]any)["data"]; !reflect.DeepEqual(got, map[string]any{"username": "ann"}) {
+		t.Errorf("structuredContent data = %v", got)
+	}
+
+	failing := Attach(models.Tool{Definition: mcp.NewTool("get_account"), Handler: handler("Request failed", true)})
+	if result, _ := failing.Handler(context.Background(), mcp.CallToolRequest{}); result.StructuredContent != nil {
+		t.Errorf("an error result got structuredContent %v", result.StructuredContent)
+	}
+}
@@ -0,0 +1,252 @@
+package sendtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// When is a send time as a caller wrote it: an instant, or a day and time
+// of day in a timezone, which may be each recipient's own.
+type When struct {
+	instant time.Time
+	loc     *time.Location // zone of the time of day; nil for recipient-local
+	clock   time.Duration  // time of day
+	day     func(today time.Time) time.Time
+	rolls   int // days to add while the time has passed; 0 to leave it
+}
+
+// Local reports whether w is a time of day in each recipient's timezone.
+func (w When) Local() bool {
+	return w.instant.IsZero() && w.loc == nil
+}
+
+// In returns the time w names in loc. Only recipient-local times depend on
+// loc.
+func (w When) In(loc *time.Location, now time.Time) time.Time {
+	if !w.instant.IsZero() {
+		return w.instant
+	}
+	if w.loc != nil {
+		loc = w.loc
+	}
+	t := at(w.day(now.In(loc)), w.clock)
+	if w.rolls > 0 && !t.After(now) {
+		t = at(t.AddDate(0, 0, w.rolls), w.clock)
+	}
+	return t
+}
+
+// Resolve returns the time w names for recipients in zones: the latest of
+// the times in each, so that none is sent to early.
+func (w When) Resolve(zones []*time.Location, now time.Time) time.Time {
+	var t time.Time
+	for _, loc := range zones {
+		if c := w.In(loc, now); c.After(t) {
+			t = c
+		}
+	}
+	return t
+}
+
+// parseError lists the forms Parse accepts.
+const parseError = `use unix seconds, RFC 3339, "now", "in 2 hours", or a day and time such as "tomorrow 9am recipient-local"`
+
+// Parse reads a send time: unix seconds, an RFC 3339 time, "now",
+// "in N minutes|hours|days|weeks", or a day ("today", "tomorrow", a
+// weekday, "next friday" or 2026-01-02) and time ("9am", "9:30 pm",
+// "17:00", "noon" or "midnight") followed by a zone: "recipient-local",
+// "utc" or an IANA name. Either the day or the time may be left out; a day
+// alone means 9am, and a time alone its next occurrence. Times without a
+// zone are in def.
+func Parse(s string, def *time.Location, now time.Time) (When, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return When{}, fmt.Errorf("empty send time; %s", parseError)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return When{instant: time.Unix(n, 0)}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return When{instant: t}, nil
+	}
+
+	words := strings.Fields(s)
+	lower := strings.Fields(strings.ToLower(s))
+	if len(lower) == 1 && lower[0] == "now" {
+		return When{instant: now}, nil
+	}
+	if lower[0] == "in" {
+		return parseIn(s, lower[1:], now)
+	}
+
+	w := When{loc: def}
+	switch last := lower[len(lower)-1]; {
+	case last == "recipient-local" || last == "local" && len(lower) > 1 && lower[len(lower)-2] == "recipient":
+		w.loc = nil
+		if last == "local" {
+			lower = lower[:len(lower)-1]
+		}
+		lower = lower[:len(lower)-1]
+	case last == "utc":
+		w.loc = time.UTC
+		lower = lower[:len(lower)-1]
+	case strings.Contains(last, "/"):
+		loc, err := time.LoadLocation(words[len(words)-1])
+		if err != nil {
+			return When{}, fmt.Errorf("unknown timezone %q in %q", words[len(words)-1], s)
+		}
+		w.loc = loc
+		lower = lower[:len(lower)-1]
+	}
+
+	var day []string
+	clockText := ""
+	for i := 0; i < len(lower); i++ {
+		switch word := lower[i]; {
+		case word == "at":
+		case isClock(word) && clockText == "":
+			clockText = word
+			if i+1 < len(lower) && isMeridiem(lower[i+1]) {
+				clockText += lower[i+1]
+				i++
+			}
+		default:
+			day = append(day, word)
+		}
+	}
+	if len(day) == 0 && clockText == "" {
+		return When{}, fmt.Errorf("%q is not a send time; %s", s, parseError)
+	}
+
+	w.clock = clock(9, 0)
+	if clockText != "" {
+		d, err := parseClock(clockText)
+		if err != nil {
+			return When{}, fmt.Errorf("%q is not a send time: %v; %s", s, err, parseError)
+		}
+		w.clock = d
+	}
+	if err := w.parseDay(day); err != nil {
+		return When{}, fmt.Errorf("%q is not a send time: %v; %s", s, err, parseError)
+	}
+	return w, nil
+}
+
+// parseIn reads the rest of "in 2 hours".
+func parseIn(s string, words []string, now time.Time) (When, error) {
+	if len(words) != 2 {
+		return When{}, fmt.Errorf("%q is not a send time; %s", s, parseError)
+	}
+	n, err := strconv.Atoi(words[0])
+	if err != nil || n < 0 {
+		return When{}, fmt.Errorf("%q is not a send time: want a count, got %q", s, words[0])
+	}
+	switch strings.TrimSuffix(words[1], "s") {
+	case "minute", "min":
+		return When{instant: now.Add(time.Duration(n) * time.Minute)}, nil
+	case "hour", "hr":
+		return When{instant: now.Add(time.Duration(n) * time.Hour)}, nil
+	case "day":
+		return When{instant: now.AddDate(0, 0, n)}, nil
+	case "week":
+		return When{instant: now.AddDate(0, 0, 7*n)}, nil
+	}
+	return When{}, fmt.Errorf("%q is not a send time: want minutes, hours, days or weeks, got %q", s, words[1])
+}
+
+// parseDay sets the day of w from words such as "next friday".
+func (w *When) parseDay(words []string) error {
+	next := len(words) > 0 && words[0] == "next"
+	if next {
+		words = words[1:]
+	}
+	switch {
+	case len(words) == 0 && !next:
+		w.day, w.rolls = func(today time.Time) time.Time { return today }, 1
+		return nil
+	case len(words) != 1:
+		return fmt.Errorf("want one day, got %q", strings.Join(words, " "))
+	}
+	word := words[0]
+	switch word {
+	case "today":
+		w.day = func(today time.Time) time.Time { return today }
+		return nil
+	case "tomorrow":
+		w.day = func(today time.Time) time.Time { return today.AddDate(0, 0, 1) }
+		return nil
+	}
+	if d, err := time.Parse(time.DateOnly, word); err == nil {
+		w.day = func(today time.Time) time.Time {
+			return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, today.Location())
+		}
+		return nil
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if word != name && word != name[:3] {
+			continue
+		}
+		w.day = func(today time.Time) time.Time {
+			ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+			if ahead == 0 && next {
+				ahead = 7
+			}
+			return today.AddDate(0, 0, ahead)
+		}
+		if !next {
+			w.rolls = 7
+		}
+		return nil
+	}
+	return fmt.Errorf("%q is not a day", word)
+}
+
+// isClock reports whether a word starts a time of day.
+func isClock(word string) bool {
+	return word == "noon" || word == "midnight" || word != "" && word[0] >= '0' && word[0] <= '9' && !strings.Contains(word, "-")
+}
+
+func isMeridiem(word string) bool {
+	switch word {
+	case "am", "pm", "a.m.", "p.m.":
+		return true
+	}
+	return false
+}
+
+// parseClock reads a time of day such as "9am", "9:30pm" or "17:00".
+func parseClock(s string) (time.Duration, error) {
+	switch s {
+	case "noon":
+		return clock(12, 0), nil
+	case "midnight":
+		return 0, nil
+	}
+	suffix := ""
+	for _, x := range []string{"am", "pm", "a.m.", "p.m."} {
+		if strings.HasSuffix(s, x) {
+			suffix, s = x[:1], strings.TrimSuffix(s, x)
+			break
+		}
+	}
+	h, m := s, "0"
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		h, m = s[:i], s[i+1:]
+	}
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if err1 != nil || err2 != nil || minute < 0 || minute > 59 || hour < 0 || hour > 23 ||
+		suffix != "" && (hour < 1 || hour > 12) || suffix == "" && !strings.Contains(s, ":") {
+		return 0, fmt.Errorf("%q is not a time of day", s+map[string]string{"a": "am", "p": "pm"}[suffix])
+	}
+	switch {
+	case suffix == "a" && hour == 12:
+		hour = 0
+	case suffix == "p" && hour < 12:
+		hour += 12
+	}
+	return clock(hour, minute), nil
+}
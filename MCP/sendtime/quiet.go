@@ -0,0 +1,77 @@
+package sendtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Window is a daily span of local time, such as the quiet hours 21:00 to
+// 08:00. It wraps past midnight when End is before Start.
+type Window struct {
+	Start, End time.Duration // since midnight
+}
+
+// ParseWindow parses a span such as "21:00-08:00".
+func ParseWindow(s string) (*Window, error) {
+	var sh, sm, eh, em int
+	if n, _ := fmt.Sscanf(s, "%d:%d-%d:%d", &sh, &sm, &eh, &em); n != 4 || sh > 23 || eh > 24 || sm > 59 || em > 59 || sh < 0 || eh < 0 || sm < 0 || em < 0 {
+		return nil, fmt.Errorf("want a span of local times such as 21:00-08:00, got %q", s)
+	}
+	w := &Window{Start: clock(sh, sm), End: clock(eh, em)}
+	if w.Start == w.End {
+		return nil, fmt.Errorf("%q is empty", s)
+	}
+	return w, nil
+}
+
+func (w *Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", int(w.Start.Hours()), int(w.Start.Minutes())%60, int(w.End.Hours()), int(w.End.Minutes())%60)
+}
+
+// Contains reports whether t falls in the window in its own zone.
+func (w *Window) Contains(t time.Time) bool {
+	d := sinceMidnight(t)
+	if w.Start < w.End {
+		return d >= w.Start && d < w.End
+	}
+	return d >= w.Start || d < w.End
+}
+
+// Next returns the first time at or after t outside the window in every
+// zone. It fails when the windows of the zones leave no such time.
+func (w *Window) Next(t time.Time, zones []*time.Location) (time.Time, error) {
+	limit := t.Add(8 * 24 * time.Hour)
+	for t.Before(limit) {
+		moved := false
+		for _, loc := range zones {
+			local := t.In(loc)
+			if !w.Contains(local) {
+				continue
+			}
+			// Move to the end of this zone's quiet period
+			end := at(local, w.End)
+			if !end.After(local) {
+				end = at(local.AddDate(0, 0, 1), w.End)
+			}
+			t, moved = end, true
+		}
+		if !moved {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no time is outside the quiet hours %s in every timezone of the recipients", w)
+}
+
+func clock(h, m int) time.Duration {
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+}
+
+// at returns the time of day d on t's day in its zone.
+func at(t time.Time, d time.Duration) time.Time {
+	y, m, day := t.Date()
+	return time.Date(y, m, day, int(d.Hours()), int(d.Minutes())%60, 0, 0, t.Location())
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return clock(t.Hour(), t.Minute()) + time.Duration(t.Second())*time.Second
+}
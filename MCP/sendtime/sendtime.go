@@ -0,0 +1,108 @@
+// Package sendtime resolves when scheduled sends go out.
+//
+// The send tools take schedule as unix seconds. With a Policy they also
+// take natural times such as "tomorrow 9am recipient-local", read in each
+// recipient's timezone as told by the country and area code of their
+// number, and a schedule falling in the quiet hours of any recipient is
+// moved to the end of them.
+package sendtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// Policy is how send times are read and shifted.
+type Policy struct {
+	Quiet *Window        // local hours no recipient is sent to; nil for none
+	Zone  *time.Location // zone of times that name none
+	now   func() time.Time
+}
+
+// FromEnv returns the policy set by QUIET_HOURS and SCHEDULE_TIMEZONE.
+// There are no quiet hours unless QUIET_HOURS is set, and times that name
+// no zone are UTC unless SCHEDULE_TIMEZONE is set.
+func FromEnv() (*Policy, error) {
+	p := &Policy{Zone: time.UTC, now: time.Now}
+	switch v := configfile.Getenv("QUIET_HOURS"); v {
+	case "", "off":
+	default:
+		w, err := ParseWindow(v)
+		if err != nil {
+			return nil, fmt.Errorf("QUIET_HOURS must be off or a span of local times such as 21:00-08:00, got %q", v)
+		}
+		p.Quiet = w
+	}
+	if v := configfile.Getenv("SCHEDULE_TIMEZONE"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("SCHEDULE_TIMEZONE must be an IANA timezone such as Australia/Sydney, got %q", v)
+		}
+		p.Zone = loc
+	}
+	return p, nil
+}
+
+// Now returns the current time.
+func (p *Policy) Now() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
+// Plan is when a send to recipients in some zones goes out.
+type Plan struct {
+	Requested time.Time // the time asked for
+	At        time.Time // the time after quiet hours
+	Shifted   bool      // At is later than Requested
+}
+
+// Schedule resolves w for recipients in zones, which are the zones of
+// recipient-local times when zones is empty, and moves it past the quiet
+// hours of every zone. An empty zones is taken as the policy zone.
+func (p *Policy) Schedule(w When, zones []*time.Location) (Plan, error) {
+	now := p.Now()
+	if len(zones) == 0 {
+		zones = []*time.Location{p.Zone}
+	}
+	requested := w.Resolve(zones, now)
+	if requested.Before(now) {
+		requested = now // ClickSend sends past schedules at once
+	}
+	plan := Plan{Requested: requested, At: requested}
+	if p.Quiet == nil {
+		return plan, nil
+	}
+	at, err := p.Quiet.Next(requested, zones)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.At, plan.Shifted = at, at.After(requested)
+	return plan, nil
+}
+
+// Resolve reads a schedule as the send tools take it, empty or 0 for now,
+// for recipients in zones and moves it past the quiet hours. A natural time
+// in the past is an error, while unix seconds in the past mean now, as they
+// do to ClickSend.
+func (p *Policy) Resolve(schedule string, zones []*time.Location) (Plan, error) {
+	if len(zones) == 0 {
+		zones = []*time.Location{p.Zone}
+	}
+	now := p.Now()
+	w := When{instant: now}
+	if schedule = strings.TrimSpace(schedule); schedule != "" && schedule != "0" {
+		var err error
+		if w, err = Parse(schedule, p.Zone, now); err != nil {
+			return Plan{}, err
+		}
+		if t := w.Resolve(zones, now); !isUnix(schedule) && t.Before(now.Add(-time.Minute)) {
+			return Plan{}, fmt.Errorf("%q is in the past (%s)", schedule, t.Format(time.RFC3339))
+		}
+	}
+	return p.Schedule(w, zones)
+}
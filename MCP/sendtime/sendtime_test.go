@@ -0,0 +1,230 @@
+package sendtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func zone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestZones(t *testing.T) {
+	for _, code := range phone.Codes() {
+		c, ok := countryZones[code]
+		if !ok {
+			t.Errorf("no timezones for %s", code)
+			continue
+		}
+		for _, name := range append(c.zones, func() []string {
+			var out []string
+			for _, z := range c.prefixes {
+				out = append(out, z)
+			}
+			return out
+		}()...) {
+			if _, err := time.LoadLocation(name); err != nil {
+				t.Errorf("%s: %v", code, err)
+			}
+		}
+	}
+	for number, want := range map[string]string{
+		"+61 8 9123 4567": "Australia/Perth",
+		"+61 2 9123 4567": "Australia/Sydney",
+		"+1 808 555 0100": "Pacific/Honolulu",
+		"+64 21 123 4567": "Pacific/Auckland",
+	} {
+		n, err := phone.Parse(number, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Zones(n); len(got) != 1 || got[0].String() != want {
+			t.Errorf("Zones(%s) = %v, want %s", number, got, want)
+		}
+	}
+	n, _ := phone.Parse("+61411111111", "")
+	if got := Zones(n); len(got) != 5 {
+		t.Errorf("Zones(AU mobile) = %v, want every AU zone", got)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	for _, bad := range []string{"21-08", "25:00-08:00", "09:00-09:00", "night"} {
+		if _, err := ParseWindow(bad); err == nil {
+			t.Errorf("ParseWindow(%q) succeeded", bad)
+		}
+	}
+	w, err := ParseWindow("21:00-08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sydney, perth := zone(t, "Australia/Sydney"), zone(t, "Australia/Perth")
+
+	// 22:30 in Sydney is quiet until 08:00 Sydney, which is 05:00 in Perth
+	// and quiet there until 08:00 Perth.
+	got, err := w.Next(time.Date(2026, 3, 2, 22, 30, 0, 0, sydney), []*time.Location{sydney, perth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 3, 8, 0, 0, 0, perth); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+	noon := time.Date(2026, 3, 2, 12, 0, 0, 0, sydney)
+	if got, _ := w.Next(noon, []*time.Location{sydney}); !got.Equal(noon) {
+		t.Errorf("Next(noon) = %s, want it unchanged", got)
+	}
+
+	// Quiet 09:00-03:00 leaves 03:00-09:00, which Sydney and New York never share.
+	long, _ := ParseWindow("09:00-03:00")
+	if _, err := long.Next(noon, []*time.Location{sydney, zone(t, "America/New_York")}); err == nil {
+		t.Error("Next found a time outside quiet hours covering the whole day across zones")
+	}
+}
+
+func TestParse(t *testing.T) {
+	sydney := zone(t, "Australia/Sydney")
+	// Monday 2 March 2026, 10:00 in Sydney.
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, sydney)
+	for s, want := range map[string]time.Time{
+		"1772406000":                    time.Unix(1772406000, 0),
+		"2026-03-05T12:00:00Z":          time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC),
+		"now":                           now,
+		"in 90 minutes":                 now.Add(90 * time.Minute),
+		"in 2 days":                     now.AddDate(0, 0, 2),
+		"tomorrow 9am":                  time.Date(2026, 3, 3, 9, 0, 0, 0, sydney),
+		"9am":                           time.Date(2026, 3, 3, 9, 0, 0, 0, sydney),
+		"5:30 pm":                       time.Date(2026, 3, 2, 17, 30, 0, 0, sydney),
+		"today at noon":                 time.Date(2026, 3, 2, 12, 0, 0, 0, sydney),
+		"friday":                        time.Date(2026, 3, 6, 9, 0, 0, 0, sydney),
+		"monday 9am":                    time.Date(2026, 3, 9, 9, 0, 0, 0, sydney),
+		"next mon 11:00":                time.Date(2026, 3, 9, 11, 0, 0, 0, sydney),
+		"2026-04-01 17:00 utc":          time.Date(2026, 4, 1, 17, 0, 0, 0, time.UTC),
+		"tomorrow 8am Europe/London":    time.Date(2026, 3, 2, 8, 0, 0, 0, zone(t, "Europe/London")), // still Sunday there
+		"tomorrow 9am recipient-local":  time.Date(2026, 3, 3, 9, 0, 0, 0, sydney),
+		"tomorrow 9am recipient local":  time.Date(2026, 3, 3, 9, 0, 0, 0, sydney),
+		"12am tomorrow recipient-local": time.Date(2026, 3, 3, 0, 0, 0, 0, sydney),
+	} {
+		w, err := Parse(s, sydney, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if got := w.In(sydney, now); !got.Equal(want) {
+			t.Errorf("Parse(%q) = %s, want %s", s, got, want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "in 2 fortnights", "tomorrow 25:00", "tomorrow 9", "9am Mars/Olympus", "blursday 9am"} {
+		if _, err := Parse(bad, sydney, now); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+
+	w, _ := Parse("tomorrow 9am recipient-local", time.UTC, now)
+	perth := zone(t, "Australia/Perth")
+	if got, want := w.Resolve([]*time.Location{sydney, perth}, now), time.Date(2026, 3, 3, 9, 0, 0, 0, perth); !got.Equal(want) {
+		t.Errorf("Resolve = %s, want the later 9am, %s", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	cs := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	list, err := cs.CreateContactList(context.Background(), &clicksend.ContactList{ListName: "Customers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listID := fmt.Sprint(list.Data.ListID)
+	for _, number := range []string{"+61291234567", "+61891234567", "+447700900123"} {
+		if _, err := cs.CreateContact(context.Background(), listID, &clicksend.Contact{PhoneNumber: number}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sydney, perth := zone(t, "Australia/Sydney"), zone(t, "Australia/Perth")
+	quiet, _ := ParseWindow("21:00-08:00")
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, sydney)
+	p := &Policy{Quiet: quiet, Zone: time.UTC, now: func() time.Time { return now }}
+
+	var got map[string]any
+	tool := Wrap(models.Tool{
+		Definition: mcp.NewTool("post_sms_send", mcp.WithString("schedule")),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			got = request.GetArguments()
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}, p, func() *clicksend.Client { return cs })
+	if _, ok := tool.Definition.InputSchema.Properties["timezone"]; !ok {
+		t.Error("timezone was not added to the schema")
+	}
+	call := func(args map[string]any) string {
+		t.Helper()
+		got = nil
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parts []string
+		for _, c := range result.Content {
+			parts = append(parts, c.(mcp.TextContent).Text)
+		}
+		return strings.Join(parts, "\n")
+	}
+	unix := func(t time.Time) string { return fmt.Sprint(t.Unix()) }
+
+	// 10:00 Sydney is 07:00 Perth, inside its quiet hours.
+	text := call(map[string]any{"to": "+61891234567", "body": "hi"})
+	if want := unix(time.Date(2026, 3, 2, 8, 0, 0, 0, perth)); got["schedule"] != want {
+		t.Errorf("schedule = %v, want %s", got["schedule"], want)
+	}
+	if !strings.Contains(text, "after the quiet hours 21:00-08:00 in Australia/Perth") {
+		t.Errorf("result = %q, want the shift noted", text)
+	}
+
+	call(map[string]any{"to": "+61291234567", "body": "hi"})
+	if _, ok := got["schedule"]; ok {
+		t.Errorf("an immediate send outside quiet hours was scheduled for %v", got["schedule"])
+	}
+
+	call(map[string]any{"messages": []any{
+		map[string]any{"to": "+61291234567", "schedule": "tomorrow 9am recipient-local"},
+		map[string]any{"to": "+61412345678", "schedule": "tomorrow 12:00 utc"},
+	}, "timezone": "Australia/Sydney"})
+	messages := got["messages"].([]any)
+	if s := messages[0].(map[string]any)["schedule"]; s != unix(time.Date(2026, 3, 3, 9, 0, 0, 0, sydney)) {
+		t.Errorf("messages[0].schedule = %v", s)
+	}
+	if s := messages[1].(map[string]any)["schedule"]; s != unix(time.Date(2026, 3, 3, 8, 0, 0, 0, sydney)) {
+		t.Errorf("messages[1].schedule = %v, want 08:00 after 23:00 Sydney", s)
+	}
+	if _, ok := got["timezone"]; ok {
+		t.Error("timezone was passed on")
+	}
+
+	// The list holds Sydney, Perth and London numbers. The latest 9am
+	// tomorrow is Perth's, 01:00 in London, where quiet hours last to 08:00.
+	text = call(map[string]any{"list_id": listID, "schedule": "tomorrow 9am recipient-local"})
+	if want := unix(time.Date(2026, 3, 3, 8, 0, 0, 0, zone(t, "Europe/London"))); got["schedule"] != want {
+		t.Errorf("list schedule = %v, want %s (%s)", got["schedule"], want, text)
+	}
+
+	if text := call(map[string]any{"to": "+61291234567", "schedule": "yesterday"}); got != nil || !strings.Contains(text, "Not sent") {
+		t.Errorf("bad schedule: result %q, forwarded %v", text, got)
+	}
+}
@@ -0,0 +1,283 @@
+package sendtime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sendTools are the tools whose schedule is resolved. Their recipients are
+// numbers in to, contact lists in list_id, or the same in each of messages.
+var sendTools = map[string]bool{
+	"post_sms_send":             true,
+	"post_mms_send":             true,
+	"post_voice_send":           true,
+	"post_sms-campaigns_send":   true,
+	"post_email-campaigns_send": true,
+}
+
+// Wrap resolves the schedule of a send tool before it runs: natural times
+// are read as Parse does, in the recipients' timezones when
+// recipient-local, and a send falling in the quiet hours of any recipient
+// is scheduled for the end of them. The schedule is passed on as unix
+// seconds, and the result notes the times it chose. Contact lists are read
+// with client to find their recipients' timezones. Other tools, and every
+// tool when p is nil, are returned unchanged.
+func Wrap(tool models.Tool, p *Policy, client func() *clicksend.Client) models.Tool {
+	if p == nil || !sendTools[tool.Definition.Name] {
+		return tool
+	}
+	props := tool.Definition.InputSchema.Properties
+	if props == nil {
+		props = map[string]any{}
+		tool.Definition.InputSchema.Properties = props
+	}
+	if prop, ok := props["schedule"].(map[string]any); ok {
+		desc, _ := prop["description"].(string)
+		prop["description"] = desc + ` Also takes RFC 3339 or natural times such as "in 2 hours", "friday 17:00" or "tomorrow 9am recipient-local".`
+	}
+	props["timezone"] = map[string]any{
+		"type":        "string",
+		"description": "IANA timezone of the recipients, such as Australia/Perth, when their numbers do not tell it. Used for recipient-local schedules and quiet hours.",
+	}
+	if p.Quiet != nil {
+		tool.Definition.Description += fmt.Sprintf(" Sends are not delivered in the recipients' local quiet hours %s; they are scheduled for the end of them.", p.Quiet)
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return handler(ctx, request)
+		}
+		forwarded := make(map[string]any, len(args))
+		for k, v := range args {
+			forwarded[k] = v
+		}
+		delete(forwarded, "timezone")
+		var fixed *time.Location
+		if name, _ := args["timezone"].(string); name != "" {
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("timezone must be an IANA timezone such as Australia/Perth, got %q", name)), nil
+			}
+			fixed = loc
+		}
+
+		s := scheduler{policy: p, client: client(), fixed: fixed, lists: map[string]listZones{}}
+		var notes []string
+		if messages, ok := args["messages"].([]any); ok {
+			resolved := make([]any, len(messages))
+			for i, m := range messages {
+				obj, ok := m.(map[string]any)
+				if !ok {
+					resolved[i] = m
+					continue
+				}
+				out, note, err := s.resolve(ctx, obj, fmt.Sprintf("messages[%d].", i))
+				if err != nil {
+					return mcp.NewToolResultError("Not sent: " + err.Error()), nil
+				}
+				resolved[i] = out
+				notes = append(notes, note...)
+			}
+			forwarded["messages"] = resolved
+		} else {
+			out, note, err := s.resolve(ctx, forwarded, "")
+			if err != nil {
+				return mcp.NewToolResultError("Not sent: " + err.Error()), nil
+			}
+			forwarded, notes = out, note
+		}
+
+		request.Params.Arguments = forwarded
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && !result.IsError {
+			for _, n := range notes {
+				result.Content = append(result.Content, mcp.NewTextContent(n))
+			}
+		}
+		return result, err
+	}
+	return tool
+}
+
+// listZones are the timezones of the contacts of a list, and how many
+// contacts have none known.
+type listZones struct {
+	zones   []*time.Location
+	unknown int
+}
+
+// scheduler resolves the schedules of one call.
+type scheduler struct {
+	policy *Policy
+	client *clicksend.Client
+	fixed  *time.Location // the timezone argument, if any
+	lists  map[string]listZones
+}
+
+// resolve returns a copy of obj with its schedule resolved, and notes on
+// the times chosen.
+func (s *scheduler) resolve(ctx context.Context, obj map[string]any, path string) (map[string]any, []string, error) {
+	var raw string
+	switch v := obj["schedule"].(type) {
+	case string:
+		raw = strings.TrimSpace(v)
+	case float64:
+		raw = fmt.Sprint(int64(v))
+	}
+	if raw == "0" {
+		raw = ""
+	}
+	natural := raw != "" && !isUnix(raw)
+	if !natural && s.policy.Quiet == nil {
+		return obj, nil, nil
+	}
+
+	zones, warning, err := s.zones(ctx, obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%srecipients: %w", path, err)
+	}
+	plan, err := s.policy.Resolve(raw, zones)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%sschedule: %w", path, err)
+	}
+
+	out := make(map[string]any, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	var notes []string
+	if natural || plan.Shifted {
+		out["schedule"] = fmt.Sprint(plan.At.Unix())
+	}
+	switch {
+	case plan.Shifted:
+		notes = append(notes, fmt.Sprintf("%sschedule moved from %s to %s (%d), after the quiet hours %s in %s.",
+			path, plan.Requested.Format(time.RFC3339), plan.At.Format(time.RFC3339), plan.At.Unix(), s.policy.Quiet, zoneNames(zones)))
+	case natural:
+		notes = append(notes, fmt.Sprintf("%sschedule %q is %s (%d).", path, raw, plan.At.Format(time.RFC3339), plan.At.Unix()))
+	}
+	if warning != "" {
+		notes = append(notes, path+warning)
+	}
+	return out, notes, nil
+}
+
+// zones returns the timezones of the recipients of obj, falling back to
+// the policy zone with a warning when none are known.
+func (s *scheduler) zones(ctx context.Context, obj map[string]any) ([]*time.Location, string, error) {
+	if s.fixed != nil {
+		return []*time.Location{s.fixed}, "", nil
+	}
+	var zones []*time.Location
+	unknown := ""
+	if to, ok := obj["to"].(string); ok && to != "" {
+		country, _ := obj["country"].(string)
+		if n, err := phone.Parse(to, country); err == nil {
+			zones = Zones(n)
+		}
+		if zones == nil {
+			unknown = "the timezone of " + to + " is not known"
+		}
+	}
+	if id := listID(obj["list_id"]); id != "" {
+		lz, err := s.list(ctx, id)
+		if err != nil {
+			return nil, "", fmt.Errorf("list %s could not be read: %w", id, err)
+		}
+		zones = append(zones, lz.zones...)
+		if lz.unknown > 0 {
+			unknown = fmt.Sprintf("the timezones of %d contacts of list %s are not known", lz.unknown, id)
+		}
+	}
+	if unknown == "" {
+		return zones, "", nil
+	}
+	if !containsZone(zones, s.policy.Zone) {
+		zones = append(zones, s.policy.Zone)
+	}
+	return zones, fmt.Sprintf("Warning: %s, so %s was used for them; pass timezone to name it.", unknown, s.policy.Zone), nil
+}
+
+// list returns the timezones of the contacts of a list, reading it once
+// per call.
+func (s *scheduler) list(ctx context.Context, id string) (listZones, error) {
+	if lz, ok := s.lists[id]; ok {
+		return lz, nil
+	}
+	contacts, err := clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.Contact]], error) {
+		return s.client.ListContacts(ctx, id, opts)
+	})
+	if err != nil {
+		return listZones{}, err
+	}
+	var lz listZones
+	for _, c := range contacts {
+		zones := ContactZones(c)
+		if zones == nil {
+			lz.unknown++
+		}
+		for _, z := range zones {
+			if !containsZone(lz.zones, z) {
+				lz.zones = append(lz.zones, z)
+			}
+		}
+	}
+	s.lists[id] = lz
+	return lz, nil
+}
+
+// ContactZones returns the timezones a contact may be in, from their phone
+// number or else their address country, or nil when neither tells.
+func ContactZones(c clicksend.Contact) []*time.Location {
+	if c.PhoneNumber != "" {
+		if n, err := phone.Parse(c.PhoneNumber, c.AddressCountry); err == nil {
+			if zones := Zones(n); zones != nil {
+				return zones
+			}
+		}
+	}
+	return Zones(phone.Number{Country: strings.ToUpper(c.AddressCountry)})
+}
+
+func containsZone(zones []*time.Location, loc *time.Location) bool {
+	for _, z := range zones {
+		if z.String() == loc.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneNames returns zones as "Australia/Perth, Australia/Sydney".
+func zoneNames(zones []*time.Location) string {
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.String()
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func isUnix(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+func listID(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(int64(v))
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package sendtime_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/sendtime"
+	tools_sms "github.com/clicksend-rest-api-v3/mcp-server/tools/sms"
+	tools_workflows "github.com/clicksend-rest-api-v3/mcp-server/tools/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestScheduledSend(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	quiet, _ := sendtime.ParseWindow("21:00-08:00")
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	cfg := &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, PhoneCountry: "AU", SendTime: &sendtime.Policy{Quiet: quiet, Zone: time.UTC}}
+	call := func(tool models.Tool, args map[string]any) (*mcp.CallToolResult, string) {
+		t.Helper()
+		tool = phone.Wrap(sendtime.Wrap(tool, cfg.SendTime, cfg.Client), cfg.PhoneCountry)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Definition.Name
+		request.Params.Arguments = args
+		res, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var text strings.Builder
+		for _, c := range res.Content {
+			if tc, ok := c.(mcp.TextContent); ok {
+				text.WriteString(tc.Text)
+			}
+		}
+		return res, text.String()
+	}
+	send := tools_sms.CreateSendansmsTool(cfg)
+
+	res, text := call(send, map[string]any{"to": "08 9123 4567", "body": "Your order is ready", "schedule": "tomorrow 9am recipient-local"})
+	if res.IsError || !strings.Contains(text, `schedule "tomorrow 9am recipient-local" is`) {
+		t.Fatalf("send = %v, want the resolved schedule noted", res.Content)
+	}
+	sent := api.Messages("sms")
+	if len(sent) != 1 {
+		t.Fatalf("sent = %v, want one message", sent)
+	}
+	f, _ := strconv.ParseFloat(fmt.Sprint(sent[0]["schedule"]), 64)
+	schedule := int64(f)
+	perth, _ := time.LoadLocation("Australia/Perth")
+	if local := time.Unix(schedule, 0).In(perth); local.Format("15:04") != "09:00" {
+		t.Errorf("scheduled for %s, want 09:00 in Perth", local)
+	}
+
+	_, text = call(tools_workflows.CreatePlansendtimeTool(cfg), map[string]any{"numbers": []any{"08 9123 4567"}, "when": "tomorrow 9am recipient-local"})
+	var plan struct {
+		Schedule   string            `json:"schedule"`
+		LocalTimes map[string]string `json:"local_times"`
+	}
+	if err := json.Unmarshal([]byte(text), &plan); err != nil || plan.Schedule != fmt.Sprint(schedule) || !strings.Contains(plan.LocalTimes["Australia/Perth"], "09:00") {
+		t.Errorf("plan_send_time = %s, want the schedule the send used", text)
+	}
+
+	if res, _ := call(send, map[string]any{"to": "0411 111 111", "body": "hi", "schedule": "whenever"}); !res.IsError {
+		t.Errorf("send with an unreadable schedule = %v, want an error", res.Content)
+	}
+}
@@ -0,0 +1,75 @@
+package sendtime
+
+import (
+	"strings"
+	"time"
+	_ "time/tzdata" // zones must load on hosts without a zoneinfo database
+
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+)
+
+// countryZones are the timezones of the countries phone has numbering
+// plans for, as get_timezones names them. A number of a country with
+// several zones may be in any of them unless a prefix places it; mobile
+// numbers never do.
+var countryZones = map[string]struct {
+	zones    []string
+	prefixes map[string]string // E.164 prefix to zone
+}{
+	"AU": {
+		zones: []string{"Australia/Sydney", "Australia/Brisbane", "Australia/Adelaide", "Australia/Darwin", "Australia/Perth"},
+		prefixes: map[string]string{
+			"+612": "Australia/Sydney", "+613": "Australia/Melbourne", "+617": "Australia/Brisbane",
+			"+618": "Australia/Adelaide", "+6189": "Australia/Perth",
+		},
+	},
+	"NZ": {zones: []string{"Pacific/Auckland"}},
+	"GB": {zones: []string{"Europe/London"}},
+	"US": {
+		zones:    []string{"America/New_York", "America/Chicago", "America/Denver", "America/Los_Angeles"},
+		prefixes: map[string]string{"+1808": "Pacific/Honolulu", "+1907": "America/Anchorage"},
+	},
+	"CA": {zones: []string{"America/Halifax", "America/Toronto", "America/Winnipeg", "America/Edmonton", "America/Vancouver"}},
+	"DE": {zones: []string{"Europe/Berlin"}},
+	"FR": {zones: []string{"Europe/Paris"}},
+	"FI": {zones: []string{"Europe/Helsinki"}},
+	"AE": {zones: []string{"Asia/Dubai"}},
+	"AF": {zones: []string{"Asia/Kabul"}},
+	"ZW": {zones: []string{"Africa/Harare"}},
+	"IE": {zones: []string{"Europe/Dublin"}},
+	"SG": {zones: []string{"Asia/Singapore"}},
+	"IN": {zones: []string{"Asia/Kolkata"}},
+	"ZA": {zones: []string{"Africa/Johannesburg"}},
+}
+
+// Zones returns the timezones a number may be in, or nil when its country
+// is not known.
+func Zones(n phone.Number) []*time.Location {
+	c, ok := countryZones[n.Country]
+	if !ok {
+		return nil
+	}
+	best := ""
+	for prefix := range c.prefixes {
+		if strings.HasPrefix(n.E164, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		return []*time.Location{load(c.prefixes[best])}
+	}
+	out := make([]*time.Location, len(c.zones))
+	for i, z := range c.zones {
+		out[i] = load(z)
+	}
+	return out
+}
+
+// load returns a zone of the table, which the tests check all load.
+func load(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,522 @@
+package shaping
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Supported output formats.
+const (
+	FormatJSON    = "json"
+	FormatCompact = "compact"
+	FormatTable   = "table"
+	FormatCSV     = "csv"
+)
+
+const moreAvailableHint = "Narrow the result with fields, max_items or page/limit to see more"
+
+// Options holds the shaping arguments of a single tool call.
+type Options struct {
+	Fields   []string
+	MaxItems int
+	Format   string
+	MaxBytes int
+}
+
+// Wrap adds the fields, max_items and format arguments to a tool and shapes
+// its text result after the handler has decoded the ClickSend response.
+// Arguments the tool already declares itself are left untouched. maxBytes is
+// the output size above which results are truncated; 0 disables truncation.
+func Wrap(tool models.Tool, maxBytes int) models.Tool {
+	owned := map[string]bool{}
+	props := tool.Definition.InputSchema.Properties
+	if _, ok := props["fields"]; !ok {
+		owned["fields"] = true
+		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to keep in each returned item (dot paths allowed, e.g. message_id,status,to)."))(&tool.Definition)
+	}
+	if _, ok := props["max_items"]; !ok {
+		owned["max_items"] = true
+		mcp.WithNumber("max_items", mcp.Min(1), mcp.Description("Maximum number of items to return from a list result."))(&tool.Definition)
+	}
+	if _, ok := props["format"]; !ok {
+		owned["format"] = true
+		mcp.WithString("format", mcp.Enum(FormatJSON, FormatCompact, FormatTable, FormatCSV), mcp.Description("Output format: json (default, indented), compact (minified JSON), table or csv."))(&tool.Definition)
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		opts := Options{Format: FormatJSON, MaxBytes: maxBytes}
+		if args, ok := request.Params.Arguments.(map[string]any); ok {
+			forwarded := make(map[string]any, len(args))
+			for k, v := range args {
+				if owned[k] {
+					continue
+				}
+				forwarded[k] = v
+			}
+			var err error
+			opts, err = parseOptions(args, owned, maxBytes)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			request.Params.Arguments = forwarded
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+		for i, c := range result.Content {
+			tc, ok := c.(mcp.TextContent)
+			if !ok {
+				continue
+			}
+			text, doc := shape(tc.Text, opts)
+			tc.Text = text
+			result.Content[i] = tc
+			if i == 0 && result.StructuredContent != nil && doc != nil {
+				if m, ok := doc.(map[string]any); ok {
+					result.StructuredContent = m
+				} else {
+					result.StructuredContent = map[string]any{"data": doc}
+				}
+			}
+		}
+		return result, nil
+	}
+	return tool
+}
+
+func parseOptions(args map[string]any, owned map[string]bool, maxBytes int) (Options, error) {
+	opts := Options{Format: FormatJSON, MaxBytes: maxBytes}
+	if owned["fields"] {
+		switch v := args["fields"].(type) {
+		case nil:
+		case string:
+			opts.Fields = splitFields(v)
+		case []any:
+			for _, f := range v {
+				s, ok := f.(string)
+				if !ok {
+					return opts, fmt.Errorf("Invalid parameter: fields must be a list of field names")
+				}
+				opts.Fields = append(opts.Fields, splitFields(s)...)
+			}
+		default:
+			return opts, fmt.Errorf("Invalid parameter: fields must be a comma-separated string")
+		}
+	}
+	if owned["max_items"] {
+		switch v := args["max_items"].(type) {
+		case nil:
+		case float64:
+			opts.MaxItems = int(v)
+		case string:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return opts, fmt.Errorf("Invalid parameter: max_items must be a number")
+			}
+			opts.MaxItems = n
+		default:
+			return opts, fmt.Errorf("Invalid parameter: max_items must be a number")
+		}
+	}
+	if owned["format"] {
+		if v, ok := args["format"]; ok && v != nil {
+			s, ok := v.(string)
+			if !ok {
+				return opts, fmt.Errorf("Invalid parameter: format must be a string")
+			}
+			switch s = strings.ToLower(strings.TrimSpace(s)); s {
+			case "":
+			case FormatJSON, FormatCompact, FormatTable, FormatCSV:
+				opts.Format = s
+			default:
+				return opts, fmt.Errorf("Invalid parameter: format must be one of json, compact, table, csv")
+			}
+		}
+	}
+	return opts, nil
+}
+
+func splitFields(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Apply shapes a JSON tool result according to opts. Text that is not JSON is
+// only subject to size truncation.
+func Apply(text string, opts Options) string {
+	out, _ := shape(text, opts)
+	return out
+}
+
+// shape returns the shaped text together with the shaped document, which is
+// nil when text is not JSON.
+func shape(text string, opts Options) (string, any) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return truncateText(text, opts.MaxBytes), nil
+	}
+
+	v := locate(doc)
+	if len(opts.Fields) > 0 {
+		if v.items != nil {
+			for i, item := range v.items {
+				v.items[i] = project(item, opts.Fields)
+			}
+		} else if v.record != nil {
+			v.setRecord(project(v.record, opts.Fields))
+		}
+	}
+
+	all := v.items
+	n := len(all)
+	reason := ""
+	if opts.MaxItems > 0 && n > opts.MaxItems {
+		n = opts.MaxItems
+		reason = "max_items"
+	}
+
+	out := render(v, all, n, reason, opts)
+	if opts.MaxBytes > 0 && len(out) > opts.MaxBytes {
+		if all != nil && n > 0 {
+			// Find the largest item count whose rendering fits the size limit.
+			lo, hi := 0, n-1
+			for lo < hi {
+				mid := (lo + hi + 1) / 2
+				if len(render(v, all, mid, "size_limit", opts)) <= opts.MaxBytes {
+					lo = mid
+				} else {
+					hi = mid - 1
+				}
+			}
+			n, reason = lo, "size_limit"
+			out = render(v, all, n, reason, opts)
+		}
+		out = truncateText(out, opts.MaxBytes)
+		if all == nil {
+			return out, recordStub(v.doc)
+		}
+	}
+	return out, finalize(v, all, n, reason)
+}
+
+// recordStub stands in for a single record whose text was cut to the size
+// limit, so the structured content does not return it whole: the response
+// envelope without its data, marked as having more available.
+func recordStub(doc any) map[string]any {
+	stub := map[string]any{"more_available": moreAvailable(0, 1, "size_limit")}
+	if m, ok := doc.(map[string]any); ok {
+		if _, ok := m["data"].(map[string]any); ok {
+			for k, val := range m {
+				if k != "data" {
+					stub[k] = val
+				}
+			}
+		}
+	}
+	return stub
+}
+
+// finalize applies the chosen item count and more_available marker to the
+// document itself.
+func finalize(v *view, all []any, n int, reason string) any {
+	if all == nil {
+		return v.doc
+	}
+	v.setItems(all[:n])
+	if n == len(all) {
+		return v.doc
+	}
+	marker := moreAvailable(n, len(all), reason)
+	if m, ok := v.doc.(map[string]any); ok {
+		m["more_available"] = marker
+		return m
+	}
+	return map[string]any{"data": v.doc, "more_available": marker}
+}
+
+func moreAvailable(returned, available int, reason string) map[string]any {
+	return map[string]any{
+		"returned":  returned,
+		"available": available,
+		"reason":    reason,
+		"hint":      moreAvailableHint,
+	}
+}
+
+// view describes where the list items or the single record live inside a
+// decoded ClickSend response.
+type view struct {
+	doc       any
+	items     []any
+	setItems  func([]any)
+	record    any
+	setRecord func(any)
+	meta      map[string]any
+}
+
+func locate(doc any) *view {
+	v := &view{doc: doc}
+	switch d := doc.(type) {
+	case []any:
+		v.items = d
+		v.setItems = func(items []any) { v.doc = items }
+		return v
+	case map[string]any:
+		switch data := d["data"].(type) {
+		case []any:
+			v.items = data
+			v.setItems = func(items []any) { d["data"] = items }
+			if p, ok := d["pagination"].(map[string]any); ok {
+				v.meta = p
+			}
+			return v
+		case map[string]any:
+			if inner, ok := data["data"].([]any); ok {
+				v.items = inner
+				v.setItems = func(items []any) { data["data"] = items }
+				v.meta = map[string]any{}
+				for k, val := range data {
+					if k != "data" {
+						v.meta[k] = val
+					}
+				}
+				return v
+			}
+			v.record = data
+			v.setRecord = func(r any) { d["data"] = r }
+			return v
+		}
+	}
+	v.record = doc
+	v.setRecord = func(r any) { v.doc = r }
+	return v
+}
+
+func project(item any, fields []string) any {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return item
+	}
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if val, ok := lookup(m, f); ok {
+			out[f] = val
+		}
+	}
+	return out
+}
+
+func lookup(m map[string]any, path string) (any, bool) {
+	if val, ok := m[path]; ok {
+		return val, true
+	}
+	var cur any = m
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func render(v *view, all []any, n int, reason string, opts Options) string {
+	var marker map[string]any
+	if all != nil {
+		v.setItems(all[:n])
+		if n < len(all) {
+			marker = moreAvailable(n, len(all), reason)
+		}
+		defer v.setItems(all)
+	}
+
+	switch opts.Format {
+	case FormatTable, FormatCSV:
+		rows := all
+		if rows != nil {
+			rows = all[:n]
+		} else {
+			rows = []any{v.record}
+		}
+		return renderRows(rows, opts, v.meta, marker)
+	}
+
+	doc := v.doc
+	if marker != nil {
+		if m, ok := doc.(map[string]any); ok {
+			m["more_available"] = marker
+			defer delete(m, "more_available")
+		} else {
+			doc = map[string]any{"data": doc, "more_available": marker}
+		}
+	}
+	var b []byte
+	if opts.Format == FormatCompact {
+		b, _ = json.Marshal(doc)
+	} else {
+		b, _ = json.MarshalIndent(doc, "", "  ")
+	}
+	return string(b)
+}
+
+func renderRows(rows []any, opts Options, meta, marker map[string]any) string {
+	columns := opts.Fields
+	if len(columns) == 0 {
+		seen := map[string]bool{}
+		for _, r := range rows {
+			if m, ok := r.(map[string]any); ok {
+				for k := range m {
+					if !seen[k] {
+						seen[k] = true
+						columns = append(columns, k)
+					}
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+	if len(columns) == 0 {
+		columns = []string{"value"}
+	}
+
+	cells := make([][]string, 0, len(rows))
+	for _, r := range rows {
+		row := make([]string, len(columns))
+		if m, ok := r.(map[string]any); ok {
+			for i, c := range columns {
+				if val, ok := lookup(m, c); ok {
+					row[i] = cell(val)
+				}
+			}
+		} else {
+			row[0] = cell(r)
+		}
+		cells = append(cells, row)
+	}
+
+	var buf bytes.Buffer
+	if opts.Format == FormatCSV {
+		w := csv.NewWriter(&buf)
+		w.Write(columns)
+		w.WriteAll(cells)
+		writeNotes(&buf, "# ", meta, marker)
+		return buf.String()
+	}
+
+	columns = slices.Clone(columns)
+	for _, row := range append([][]string{columns}, cells...) {
+		for i, c := range row {
+			row[i] = tableCell(c)
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, row := range cells {
+		for i, c := range row {
+			if w := utf8.RuneCountInString(c); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	writeRow := func(row []string) {
+		buf.WriteString("|")
+		for i, c := range row {
+			buf.WriteString(" ")
+			buf.WriteString(c)
+			buf.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c)))
+			buf.WriteString(" |")
+		}
+		buf.WriteString("\n")
+	}
+	writeRow(columns)
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(sep)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	writeNotes(&buf, "", meta, marker)
+	return buf.String()
+}
+
+func writeNotes(buf *bytes.Buffer, prefix string, meta, marker map[string]any) {
+	if len(meta) > 0 {
+		b, _ := json.Marshal(meta)
+		fmt.Fprintf(buf, "%spagination: %s\n", prefix, b)
+	}
+	if marker != nil {
+		b, _ := json.Marshal(marker)
+		fmt.Fprintf(buf, "%smore_available: %s\n", prefix, b)
+	}
+}
+
+func cell(val any) string {
+	var s string
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case bool:
+		s = strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		s = string(b)
+	}
+	return s
+}
+
+// tableCell escapes a cell for a Markdown table, which cannot hold line
+// breaks or unescaped pipes.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func truncateText(text string, maxBytes int) string {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+	// The note counts toward maxBytes; below its size only the cut text is
+	// returned.
+	note := fmt.Sprintf("\n... [truncated: output exceeded %d bytes, more available. %s]", maxBytes, moreAvailableHint)
+	cut := maxBytes - len(note)
+	if cut < 0 {
+		cut, note = maxBytes, ""
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + note
+}
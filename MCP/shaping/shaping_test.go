@@ -0,0 +1,197 @@
+package shaping
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const messages = `{"http_code":200,"data":{"total":3,"per_page":15,"current_page":1,"data":[
+	{"message_id":"m1","status":"Sent","to":"+61411111111","body":"Hi | there","_api":{"name":"api"}},
+	{"message_id":"m2","status":"Failed","to":"+61422222222","body":"Line one\nline two"},
+	{"message_id":"m3","status":"Sent","to":"+61433333333","body":"ok"}
+]}}`
+
+func TestFields(t *testing.T) {
+	out := Apply(messages, Options{Fields: []string{"message_id", "_api.name"}, Format: FormatCompact})
+	var doc struct {
+		Data struct {
+			Total int              `json:"total"`
+			Data  []map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Data.Total != 3 || len(doc.Data.Data) != 3 {
+		t.Fatalf("shaped = %s, want the page kept", out)
+	}
+	if got := doc.Data.Data[0]; len(got) != 2 || got["message_id"] != "m1" || got["_api.name"] != "api" {
+		t.Errorf("item 0 = %v, want message_id and _api.name only", got)
+	}
+	if got := doc.Data.Data[1]; len(got) != 1 {
+		t.Errorf("item 1 = %v, want a missing field left out", got)
+	}
+
+	record := Apply(`{"data":{"user_id":1,"username":"ann","balance":"9.50"}}`, Options{Fields: []string{"username"}, Format: FormatCompact})
+	if record != `{"data":{"username":"ann"}}` {
+		t.Errorf("record = %s", record)
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	tests := []struct {
+		max  int
+		want int
+		more bool
+	}{
+		{0, 3, false},
+		{2, 2, true},
+		{3, 3, false},
+		{10, 3, false},
+	}
+	for _, tt := range tests {
+		out := Apply(messages, Options{MaxItems: tt.max, Format: FormatCompact})
+		var doc struct {
+			Data struct {
+				Data []any `json:"data"`
+			} `json:"data"`
+			More map[string]any `json:"more_available"`
+		}
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if len(doc.Data.Data) != tt.want || (doc.More != nil) != tt.more {
+			t.Errorf("max_items %d: %d items, more_available %v; want %d items, more %v", tt.max, len(doc.Data.Data), doc.More, tt.want, tt.more)
+		}
+		if tt.more && (doc.More["returned"] != 2.0 || doc.More["available"] != 3.0 || doc.More["reason"] != "max_items") {
+			t.Errorf("max_items %d: more_available = %v", tt.max, doc.More)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	opts := Options{Fields: []string{"message_id", "body"}, MaxItems: 2}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatJSON, "{\n  \"data\": {\n"},
+		{FormatCompact, `{"data":{"current_page":1,"data":[{"body":"Hi | there","message_id":"m1"}`},
+		{FormatTable, "| message_id | body              |\n| ---------- | ----------------- |\n| m1         | Hi \\| there       |\n| m2         | Line one line two |\n"},
+		{FormatCSV, "message_id,body\nm1,Hi | there\nm2,\"Line one\nline two\"\n"},
+	}
+	for _, tt := range tests {
+		opts.Format = tt.format
+		out := Apply(messages, opts)
+		if !strings.HasPrefix(out, tt.want) {
+			t.Errorf("%s:\n%s\nwant it to start with\n%s", tt.format, out, tt.want)
+		}
+		if tt.format == FormatTable || tt.format == FormatCSV {
+			prefix := map[string]string{FormatTable: "", FormatCSV: "# "}[tt.format]
+			for _, note := range []string{prefix + "pagination: ", prefix + "more_available: "} {
+				if !strings.Contains(out, "\n"+note) {
+					t.Errorf("%s: no %q note in\n%s", tt.format, note, out)
+				}
+			}
+		}
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	const max = 400
+	out := Apply(messages, Options{MaxBytes: max})
+	if len(out) > max {
+		t.Errorf("shaped list is %d bytes, want at most %d", len(out), max)
+	}
+	if !strings.Contains(out, `"reason": "size_limit"`) {
+		t.Errorf("shaped list = %s, want a size_limit marker", out)
+	}
+
+	text := strings.Repeat("é", 300)
+	out = Apply(text, Options{MaxBytes: max})
+	if len(out) > max || !strings.Contains(out, "[truncated: output exceeded 400 bytes") {
+		t.Errorf("truncated text is %d bytes: %q", len(out), out)
+	}
+	if body, _, _ := strings.Cut(out, "\n..."); !strings.HasPrefix(text, body) {
+		t.Error("truncation split a character")
+	}
+	if out := Apply(text, Options{MaxBytes: 20}); len(out) > 20 || !strings.HasPrefix(text, out) {
+		t.Errorf("truncated below the note size = %q, want the text cut to 20 bytes", out)
+	}
+	if out := Apply(text, Options{}); out != text {
+		t.Error("text was truncated without a limit")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	var forwarded map[string]any
+	tool := Wrap(models.Tool{
+		Definition: mcp.NewTool("get_sms_history", mcp.WithString("date_from")),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			forwarded = request.GetArguments()
+			return mcp.NewToolResultText(messages), nil
+		},
+	}, 0)
+	for _, arg := range []string{"fields", "max_items", "format"} {
+		if _, ok := tool.Definition.InputSchema.Properties[arg]; !ok {
+			t.Errorf("Wrap did not add %s", arg)
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"date_from": "1", "fields": "message_id", "max_items": 1.0, "format": "csv"}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forwarded) != 1 || forwarded["date_from"] != "1" {
+		t.Errorf("forwarded %v, want the shaping arguments removed", forwarded)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.HasPrefix(text, "message_id\nm1\n# ") {
+		t.Errorf("result = %q", text)
+	}
+
+	request.Params.Arguments = map[string]any{"format": "xml"}
+	if result, _ := tool.Handler(context.Background(), request); !result.IsError {
+		t.Error("an unknown format was accepted")
+	}
+}
+
+func TestOversizedRecord(t *testing.T) {
+	record := `{"http_code":200,"response_code":"SUCCESS","data":{"user_id":1,"notes":"` + strings.Repeat("x", 1000) + `"}}`
+	tool := Wrap(models.Tool{
+		Definition: mcp.NewTool("get_account"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultStructured(map[string]any{"data": "unshaped"}, record), nil
+		},
+	}, 400)
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; len(text) > 400 || !strings.Contains(text, "[truncated:") {
+		t.Errorf("text is %d bytes: %q", len(text), text)
+	}
+	structured := result.StructuredContent.(map[string]any)
+	if _, ok := structured["data"]; ok {
+		t.Errorf("structuredContent kept the record the text cut: %v", structured)
+	}
+	if more, _ := structured["more_available"].(map[string]any); more["reason"] != "size_limit" || structured["response_code"] != "SUCCESS" {
+		t.Errorf("structuredContent = %v, want the envelope and a size_limit marker", structured)
+	}
+
+	small, _ := Wrap(models.Tool{
+		Definition: mcp.NewTool("get_account"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultStructured(map[string]any{}, `{"data":{"user_id":1}}`), nil
+		},
+	}, 400).Handler(context.Background(), mcp.CallToolRequest{})
+	if data := small.StructuredContent.(map[string]any)["data"]; data == nil {
+		t.Errorf("a record within the limit lost its structured content: %v", small.StructuredContent)
+	}
+}
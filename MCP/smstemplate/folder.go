@@ -0,0 +1,160 @@
+package smstemplate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Kinds of a template, which decide whether lint requires opt-out text.
+const (
+	KindMarketing     = "marketing"
+	KindTransactional = "transactional"
+)
+
+// FileExt is the extension of template files.
+const FileExt = ".sms"
+
+// File is a template kept as a file of a folder, such as a git checkout:
+//
+//	---
+//	id: 12
+//	name: Order ready
+//	kind: transactional
+//	---
+//	Hi {first_name|there}, order {custom_1} is ready.
+//
+// The header names the ClickSend template, which has no id until it is
+// first imported, and its kind, transactional unless it says otherwise.
+type File struct {
+	Path string `json:"path"` // relative to the folder
+	ID   string `json:"template_id,omitempty"`
+	Name string `json:"template_name"`
+	Kind string `json:"kind"`
+	Body string `json:"body"`
+}
+
+// Marketing reports whether f is linted as a marketing message.
+func (f File) Marketing() bool {
+	return f.Kind == KindMarketing
+}
+
+// Encode returns the content of f's file.
+func (f File) Encode() []byte {
+	var b strings.Builder
+	b.WriteString("---\n")
+	if f.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", f.ID)
+	}
+	fmt.Fprintf(&b, "name: %s\n", f.Name)
+	if f.Kind != "" {
+		fmt.Fprintf(&b, "kind: %s\n", f.Kind)
+	}
+	b.WriteString("---\n")
+	b.WriteString(f.Body)
+	b.WriteString("\n")
+	return []byte(b.String())
+}
+
+// Decode reads a template file. The body is everything after the header,
+// less one final newline.
+func Decode(path string, data []byte) (File, error) {
+	f := File{Path: path, Kind: KindTransactional}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return File{}, fmt.Errorf("%s:1: want a --- header naming the template", path)
+	}
+	header, body, ok := strings.Cut(text[4:], "\n---\n")
+	if !ok {
+		if header, ok = strings.CutSuffix(text[4:], "\n---"); !ok {
+			return File{}, fmt.Errorf("%s: the header has no closing ---", path)
+		}
+	}
+	sc := bufio.NewScanner(strings.NewReader(header))
+	for line := 2; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		value = strings.TrimSpace(value)
+		switch key = strings.TrimSpace(key); {
+		case !ok:
+			return File{}, fmt.Errorf("%s:%d: want key: value, got %q", path, line, sc.Text())
+		case key == "id":
+			f.ID = value
+		case key == "name":
+			f.Name = value
+		case key == "kind" && (value == KindMarketing || value == KindTransactional):
+			f.Kind = value
+		case key == "kind":
+			return File{}, fmt.Errorf("%s:%d: kind must be %s or %s, got %q", path, line, KindMarketing, KindTransactional, value)
+		default:
+			return File{}, fmt.Errorf("%s:%d: unknown key %q; use id, name or kind", path, line, key)
+		}
+	}
+	if f.Name == "" {
+		return File{}, fmt.Errorf("%s: the header has no name", path)
+	}
+	f.Body = strings.TrimSuffix(body, "\n")
+	return f, nil
+}
+
+// ReadDir reads the template files of a folder, sorted by path. Every file
+// that cannot be read is reported in the error.
+func ReadDir(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != FileExt {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f, err := Decode(e.Name(), data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, errors.Join(errs...)
+}
+
+// WriteFile writes f into dir.
+func WriteFile(dir string, f File) error {
+	return os.WriteFile(filepath.Join(dir, f.Path), f.Encode(), 0o644)
+}
+
+var unsafeName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FileName returns a file name for a template, from its name, that is not
+// one of taken.
+func FileName(name, id string, taken []string) string {
+	base := strings.Trim(unsafeName.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "template"
+	}
+	if name := base + FileExt; !slices.Contains(taken, name) {
+		return name
+	}
+	if name := base + "-" + id + FileExt; id != "" && !slices.Contains(taken, name) {
+		return name
+	}
+	for n := 2; ; n++ {
+		if name := fmt.Sprintf("%s-%d%s", base, n, FileExt); !slices.Contains(taken, name) {
+			return name
+		}
+	}
+}
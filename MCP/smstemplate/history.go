@@ -0,0 +1,247 @@
+package smstemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// Actions of a version.
+const (
+	ActionBaseline = "baseline" // the template as first seen, before a change through the server
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionRollback = "rollback"
+	ActionImport   = "import"
+	ActionDelete   = "delete"
+)
+
+// Version is a name and body a template had.
+type Version struct {
+	Version int       `json:"version"`
+	Name    string    `json:"template_name"`
+	Body    string    `json:"body"`
+	Action  string    `json:"action"`
+	At      time.Time `json:"at"`
+}
+
+// History keeps the versions of each SMS template changed through the
+// server. ClickSend keeps only the current body, so the versions are kept
+// in a file when one is configured, and in memory otherwise. Versions are
+// kept per ClickSend account, and an account sees only its own.
+type History struct {
+	mu       sync.Mutex
+	file     string
+	accounts map[string]map[string][]Version // by account, then template id
+	now      func() time.Time
+}
+
+// NewHistory returns the history kept in file, or in memory when file is
+// empty.
+func NewHistory(file string) (*History, error) {
+	h := &History{file: file, accounts: map[string]map[string][]Version{}, now: time.Now}
+	if file == "" {
+		return h, nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h.accounts); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return h, nil
+}
+
+// Library is where SMS templates are kept besides ClickSend: the folder
+// they are imported from and exported to, their version history, and the
+// segment limit they are linted against.
+type Library struct {
+	Dir         string // "" when folder import and export are off
+	MaxSegments int
+	History     *History
+}
+
+// FromEnv returns the library set by SMS_TEMPLATE_DIR,
+// SMS_TEMPLATE_HISTORY_FILE and SMS_TEMPLATE_MAX_SEGMENTS.
+func FromEnv() (*Library, error) {
+	lib := &Library{Dir: configfile.Getenv("SMS_TEMPLATE_DIR"), MaxSegments: DefaultMaxSegments}
+	if lib.Dir != "" {
+		if info, err := os.Stat(lib.Dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("SMS_TEMPLATE_DIR: %q is not a directory", lib.Dir)
+		}
+	}
+	if v := configfile.Getenv("SMS_TEMPLATE_MAX_SEGMENTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("SMS_TEMPLATE_MAX_SEGMENTS must be a positive integer, got %q", v)
+		}
+		lib.MaxSegments = n
+	}
+	h, err := NewHistory(configfile.Getenv("SMS_TEMPLATE_HISTORY_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("SMS_TEMPLATE_HISTORY_FILE: %w", err)
+	}
+	lib.History = h
+	return lib, nil
+}
+
+// File returns the history file, or "" when the history is in memory.
+func (h *History) File() string {
+	return h.file
+}
+
+// Versions returns the versions of an account's template, oldest first.
+func (h *History) Versions(account, id string) []Version {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Version(nil), h.accounts[account][id]...)
+}
+
+// Get returns version n of an account's template.
+func (h *History) Get(account, id string, n int) (Version, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, v := range h.accounts[account][id] {
+		if v.Version == n {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
+
+// Record adds a version of an account's template, unless its name and
+// body are those of the latest version and it is not a deletion. It
+// returns the latest version.
+func (h *History) Record(account, id, name, body, action string) (Version, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.accounts[account] == nil {
+		h.accounts[account] = map[string][]Version{}
+	}
+	versions := h.accounts[account][id]
+	if n := len(versions); n > 0 {
+		last := versions[n-1]
+		if last.Name == name && last.Body == body && action != ActionDelete && last.Action != ActionDelete {
+			return last, nil
+		}
+	}
+	v := Version{Version: len(versions) + 1, Name: name, Body: body, Action: action, At: h.now().UTC()}
+	h.accounts[account][id] = append(versions, v)
+	return v, h.save()
+}
+
+// save writes the history to its file, if any. The caller holds h.mu.
+func (h *History) save() error {
+	if h.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(h.accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := h.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.file)
+}
+
+// Diff returns the changes from a to b word by word, with removed words in
+// [-...-] and added words in {+...+}, as git diff --word-diff shows them.
+func Diff(a, b string) string {
+	x, y := words(a), words(b)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// ops are the words of a and b, kept (=), removed (-) or added (+).
+	type op struct {
+		kind byte
+		text string
+	}
+	var ops []op
+	for i, j := 0, 0; i < len(x) || j < len(y); {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			ops = append(ops, op{'=', x[i]})
+			i, j = i+1, j+1
+		case j < len(y) && (i == len(x) || lcs[i][j+1] >= lcs[i+1][j]):
+			ops = append(ops, op{'+', y[j]})
+			j++
+		default:
+			ops = append(ops, op{'-', x[i]})
+			i++
+		}
+	}
+
+	var out, removed, added strings.Builder
+	flush := func() {
+		if removed.Len() > 0 {
+			fmt.Fprintf(&out, "[-%s-]", removed.String())
+			removed.Reset()
+		}
+		if added.Len() > 0 {
+			fmt.Fprintf(&out, "{+%s+}", added.String())
+			added.Reset()
+		}
+	}
+	for k, o := range ops {
+		switch {
+		case o.kind == '-':
+			removed.WriteString(o.text)
+		case o.kind == '+':
+			added.WriteString(o.text)
+		case isSpace(o.text[0]) && removed.Len() > 0 && added.Len() > 0 && k+1 < len(ops) && ops[k+1].kind != '=':
+			// Spaces between changed words join the changes on both
+			// sides, so that a changed phrase reads as one.
+			removed.WriteString(o.text)
+			added.WriteString(o.text)
+		default:
+			flush()
+			out.WriteString(o.text)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// words splits s into words and the runs of spaces between them.
+func words(s string) []string {
+	var out []string
+	for start, i := 0, 0; i <= len(s); i++ {
+		if i == len(s) || (i > start && isSpace(s[i]) != isSpace(s[start])) {
+			if i > start {
+				out = append(out, s[start:i])
+			}
+			start = i
+		}
+	}
+	return out
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t'
+}
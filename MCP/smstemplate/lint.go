@@ -0,0 +1,138 @@
+package smstemplate
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+)
+
+// Lint rules.
+const (
+	RuleSyntax      = "syntax"
+	RuleUndefined   = "undefined_placeholder"
+	RuleNonGSM      = "non_gsm"
+	RuleLength      = "length"
+	RuleOptOut      = "opt_out"
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// DefaultMaxSegments is used when SMS_TEMPLATE_MAX_SEGMENTS is not set.
+const DefaultMaxSegments = 2
+
+// DefaultFieldLength is the length a placeholder is assumed to render at
+// when estimating a template's segments.
+const DefaultFieldLength = 12
+
+// Issue is a problem Lint found.
+type Issue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintOptions are the checks Lint makes.
+type LintOptions struct {
+	Fields      []string // fields placeholders may name besides the contact fields
+	MaxSegments int      // 0 for DefaultMaxSegments
+	FieldLength int      // 0 for DefaultFieldLength
+	Marketing   bool     // require opt-out text
+}
+
+// optOut matches the opt-out instructions a marketing message must carry.
+var optOut = regexp.MustCompile(`(?i)\b(stop|opt[ -]?out|unsubscribe)\b`)
+
+// Lint checks a template body: that it parses, that its placeholders name
+// contact fields or opts.Fields, that it keeps to the GSM-7 alphabet, that
+// it renders within opts.MaxSegments with fields opts.FieldLength long,
+// and, for marketing, that it tells recipients how to opt out.
+func Lint(body string, opts LintOptions) []Issue {
+	t, err := Parse(body)
+	if err != nil {
+		return []Issue{{RuleSyntax, SeverityError, err.Error()}}
+	}
+	maxSegments := cmp.Or(opts.MaxSegments, DefaultMaxSegments)
+	fieldLength := cmp.Or(opts.FieldLength, DefaultFieldLength)
+
+	var issues []Issue
+	known := KnownFields(opts.Fields)
+	reported := map[string]bool{}
+	for _, p := range t.Placeholders() {
+		if !slices.Contains(known, p.Name) && !reported[p.Raw] {
+			reported[p.Raw] = true
+			issues = append(issues, Issue{RuleUndefined, SeverityError, fmt.Sprintf("%s names no contact field; use one of %s, or pass it in fields", p.Raw, strings.Join(known, ", "))})
+		}
+	}
+
+	written := t.literal()
+	for _, p := range t.Placeholders() {
+		written += p.Fallback
+	}
+	if l := Measure(written); l.Encoding == UCS2 {
+		issues = append(issues, Issue{RuleNonGSM, SeverityWarning, fmt.Sprintf("%s are not GSM-7 characters, so messages are sent as UCS-2 with 70 characters a segment instead of 160", strings.Join(quote(l.NonGSM), ", "))})
+	}
+
+	shortest, _ := t.Render(nil, nil)
+	estimate := t.estimate(fieldLength)
+	switch short, est := Measure(shortest), Measure(estimate); {
+	case short.Segments > maxSegments:
+		issues = append(issues, Issue{RuleLength, SeverityError, fmt.Sprintf("the text alone takes %d segments (%d characters), over the limit of %d", short.Segments, short.Characters, maxSegments)})
+	case est.Segments > maxSegments:
+		issues = append(issues, Issue{RuleLength, SeverityWarning, fmt.Sprintf("with each field %d characters long, messages take %d segments (%d characters), over the limit of %d", fieldLength, est.Segments, est.Characters, maxSegments)})
+	}
+
+	if opts.Marketing && !optOut.MatchString(t.literal()) {
+		issues = append(issues, Issue{RuleOptOut, SeverityError, `marketing messages must tell recipients how to opt out, such as "Reply STOP to opt out"`})
+	}
+	return issues
+}
+
+// KnownFields returns the contact fields and extra, normalized and sorted.
+func KnownFields(extra []string) []string {
+	var out []string
+	for _, f := range contactdata.ContactFields {
+		out = append(out, f.Field)
+	}
+	for _, f := range extra {
+		if f = Field(f); !slices.Contains(out, f) {
+			out = append(out, f)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// literal returns the text of t without its placeholders.
+func (t *Template) literal() string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		b.WriteString(p.text)
+	}
+	return b.String()
+}
+
+// estimate renders t with each field n characters long, or as long as its
+// fallback when that is longer.
+func (t *Template) estimate(n int) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.field == nil {
+			b.WriteString(p.text)
+			continue
+		}
+		b.WriteString(strings.Repeat("x", max(n, len([]rune(p.field.Fallback)))))
+	}
+	return b.String()
+}
+
+func quote(s []string) []string {
+	out := make([]string, len(s))
+	for i, v := range s {
+		out[i] = fmt.Sprintf("%q", v)
+	}
+	return out
+}
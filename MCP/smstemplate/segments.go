@@ -0,0 +1,64 @@
+package smstemplate
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// Encodings of an SMS.
+const (
+	GSM7 = "GSM-7"
+	UCS2 = "UCS-2"
+)
+
+// gsmBasic and gsmExtended are the characters of the GSM 03.38 default
+// alphabet. Extended characters take two of a message's characters.
+const (
+	gsmBasic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsmExtended = "^{}\\[~]|€\f"
+)
+
+// Length is how an SMS body is encoded and split.
+type Length struct {
+	Encoding   string   `json:"encoding"`
+	Characters int      `json:"characters"` // GSM-7 septets or UCS-2 code units
+	Segments   int      `json:"segments"`
+	NonGSM     []string `json:"non_gsm,omitempty"` // characters that force UCS-2
+}
+
+// Measure returns the encoding, length and segment count of an SMS body. A
+// body of only GSM-7 characters fits 160 characters in one segment and 153
+// in each of several; any other character makes it UCS-2, which fits 70
+// and 67.
+func Measure(body string) Length {
+	l := Length{Encoding: GSM7}
+	seen := map[rune]bool{}
+	for _, r := range body {
+		switch {
+		case strings.ContainsRune(gsmBasic, r):
+			l.Characters++
+		case strings.ContainsRune(gsmExtended, r):
+			l.Characters += 2
+		default:
+			if !seen[r] {
+				seen[r] = true
+				l.NonGSM = append(l.NonGSM, string(r))
+			}
+		}
+	}
+	single, multi := 160, 153
+	if len(l.NonGSM) > 0 {
+		l.Encoding = UCS2
+		l.Characters = len(utf16.Encode([]rune(body)))
+		single, multi = 70, 67
+	}
+	switch {
+	case l.Characters == 0:
+		l.Segments = 0
+	case l.Characters <= single:
+		l.Segments = 1
+	default:
+		l.Segments = (l.Characters + multi - 1) / multi
+	}
+	return l
+}
@@ -0,0 +1,212 @@
+package smstemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("Hi {First Name|there}, {custom1} is ready at {address_city}. Reply {{STOP}} to opt out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tmpl.Fields(), []string{"address_city", "custom_1", "first_name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		data, defaults map[string]string
+		want           string
+		missing        []string
+	}{
+		{
+			data: map[string]string{"first_name": "Ann", "Custom 1": "Order 12", "address_city": "Perth"},
+			want: "Hi Ann, Order 12 is ready at Perth. Reply {STOP} to opt out",
+		},
+		{
+			data:     map[string]string{"first_name": " ", "custom_1": "Order 13"},
+			defaults: map[string]string{"address_city": "the store"},
+			want:     "Hi there, Order 13 is ready at the store. Reply {STOP} to opt out",
+		},
+		{
+			data:    map[string]string{},
+			want:    "Hi there,  is ready at . Reply {STOP} to opt out",
+			missing: []string{"custom_1", "address_city"},
+		},
+	} {
+		got, missing := tmpl.Render(tc.data, tc.defaults)
+		if got != tc.want || !reflect.DeepEqual(missing, tc.missing) {
+			t.Errorf("Render(%v) = %q, %v, want %q, %v", tc.data, got, missing, tc.want, tc.missing)
+		}
+	}
+
+	for body, want := range map[string]string{
+		"Hi {first_name":  "unclosed { at position 4",
+		"Hi {a {b}":       "unclosed { at position 4",
+		"Hi {}":           "{} at position 4 is not a placeholder",
+		"Save {50%} now":  "{50%} at position 6 is not a placeholder",
+		"Hi {first.name}": "{first.name} at position 4 is not a placeholder",
+	} {
+		if _, err := Parse(body); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) = %v, want %q", body, err, want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want Length
+	}{
+		{body: "", want: Length{Encoding: GSM7}},
+		{body: strings.Repeat("a", 160), want: Length{Encoding: GSM7, Characters: 160, Segments: 1}},
+		{body: strings.Repeat("a", 161), want: Length{Encoding: GSM7, Characters: 161, Segments: 2}},
+		{body: strings.Repeat("€", 80), want: Length{Encoding: GSM7, Characters: 160, Segments: 1}},
+		{body: "Café £5 @ 9", want: Length{Encoding: GSM7, Characters: 11, Segments: 1}},
+		{body: "Thanks 😀 — see you", want: Length{Encoding: UCS2, Characters: 19, Segments: 1, NonGSM: []string{"😀", "—"}}},
+		{body: strings.Repeat("ā", 71), want: Length{Encoding: UCS2, Characters: 71, Segments: 2, NonGSM: []string{"ā"}}},
+	} {
+		if got := Measure(tc.body); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Measure(%.20q) = %+v, want %+v", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	rules := func(issues []Issue) []string {
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.Severity+" "+i.Rule)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		body string
+		opts LintOptions
+		want []string
+	}{
+		{body: "Hi {first_name|there}, your order is ready"},
+		{body: "Hi {first_name", want: []string{"error syntax"}},
+		{body: "Hi {nickname}, {nickname}", want: []string{"error undefined_placeholder"}},
+		{body: "Hi {nickname}", opts: LintOptions{Fields: []string{"Nick Name"}}, want: []string{"error undefined_placeholder"}},
+		{body: "Hi {nick_name}", opts: LintOptions{Fields: []string{"Nick Name"}}},
+		{body: "Thanks — see you", want: []string{"warning non_gsm"}},
+		{body: strings.Repeat("a", 307), want: []string{"error length"}},
+		{body: strings.Repeat("a", 300) + "{first_name}", want: []string{"warning length"}},
+		{body: strings.Repeat("a", 200), opts: LintOptions{MaxSegments: 1}, want: []string{"error length"}},
+		{body: "Sale today", opts: LintOptions{Marketing: true}, want: []string{"error opt_out"}},
+		{body: "Sale today. Reply STOP to opt out", opts: LintOptions{Marketing: true}},
+		{body: "Sale today. Reply {{STOP}}", opts: LintOptions{Marketing: true}},
+	} {
+		if got := rules(Lint(tc.body, tc.opts)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Lint(%.30q, %+v) = %v, want %v", tc.body, tc.opts, got, tc.want)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.json")
+	h, err := NewHistory(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ name, body, action string }{
+		{"Pickup", "Ready", ActionBaseline},
+		{"Pickup", "Ready", ActionUpdate},
+		{"Pickup", "Ready now", ActionUpdate},
+		{"Pickup", "Ready now", ActionDelete},
+		{"Pickup", "Ready now", ActionRollback},
+	} {
+		if _, err := h.Record("acme", "12", c.name, c.body, c.action); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err = NewHistory(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range h.Versions("acme", "12") {
+		got = append(got, fmt.Sprintf("%d %s %s", v.Version, v.Action, v.Body))
+	}
+	want := []string{"1 baseline Ready", "2 update Ready now", "3 delete Ready now", "4 rollback Ready now"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions = %q, want %q", got, want)
+	}
+	if v, ok := h.Get("acme", "12", 2); !ok || v.Body != "Ready now" {
+		t.Errorf("Get(2) = %+v, %v", v, ok)
+	}
+	if _, ok := h.Get("acme", "13", 1); ok {
+		t.Error("Get of an unknown template succeeded")
+	}
+	if v := h.Versions("other", "12"); len(v) != 0 {
+		t.Errorf("another account sees versions %+v", v)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct{ a, b, want string }{
+		{"Hi there", "Hi there", "Hi there"},
+		{"Your order is ready", "Your parcel is ready now", "Your [-order-]{+parcel+} is ready{+ now+}"},
+		{"Order 12 is at the front desk", "Order 12 is in locker 4", "Order 12 is [-at the front desk-]{+in locker 4+}"},
+		{"Reply STOP", "", "[-Reply STOP-]"},
+		{"", "New", "{+New+}"},
+	} {
+		if got := Diff(tc.a, tc.b); got != tc.want {
+			t.Errorf("Diff(%q, %q) = %q, want %q", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestFolder(t *testing.T) {
+	dir := t.TempDir()
+	files := []File{
+		{Path: "order-ready.sms", ID: "12", Name: "Order ready", Kind: KindTransactional, Body: "Hi {first_name},\norder {custom_1} is ready.\n"},
+		{Path: "sale.sms", Name: "Sale", Kind: KindMarketing, Body: "Sale today. Reply STOP to opt out"},
+	}
+	for _, f := range files {
+		if err := WriteFile(dir, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a template"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDir(dir)
+	if err != nil || !reflect.DeepEqual(got, files) {
+		t.Errorf("ReadDir = %+v, %v, want %+v", got, err, files)
+	}
+
+	for data, want := range map[string]string{
+		"Hi":                              "bad.sms:1: want a --- header",
+		"---\nname: A\n":                  "bad.sms: the header has no closing ---",
+		"---\nname: A\ncolor: red\n---\n": `bad.sms:3: unknown key "color"`,
+		"---\nkind: promo\n---\n":         `bad.sms:2: kind must be marketing or transactional, got "promo"`,
+		"---\nid: 3\n---\nHi":             "bad.sms: the header has no name",
+	} {
+		if _, err := Decode("bad.sms", []byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Decode(%q) = %v, want %q", data, err, want)
+		}
+	}
+	if f, err := Decode("a.sms", []byte("---\r\nname: A\r\n---")); err != nil || f.Body != "" || f.Kind != KindTransactional {
+		t.Errorf("Decode of an empty body = %+v, %v", f, err)
+	}
+
+	taken := []string{"order-ready.sms", "order-ready-12.sms"}
+	for _, tc := range []struct{ name, id, want string }{
+		{"Sale!", "3", "sale.sms"},
+		{"Order Ready", "12", "order-ready-2.sms"},
+		{"Order Ready", "", "order-ready-2.sms"},
+		{"Order ready", "14", "order-ready-14.sms"},
+		{"¿?", "", "template.sms"},
+	} {
+		if got := FileName(tc.name, tc.id, taken); got != tc.want {
+			t.Errorf("FileName(%q, %q) = %q, want %q", tc.name, tc.id, got, tc.want)
+		}
+	}
+}
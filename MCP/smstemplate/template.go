@@ -0,0 +1,155 @@
+// Package smstemplate renders SMS templates for each recipient and measures
+// the messages they produce.
+//
+// A template is text with placeholders in braces, such as
+// "Hi {first_name|there}, your code is {custom_1}". A placeholder names a
+// contact field, or a key of data supplied with the recipient, and may give
+// a fallback after a bar for recipients without it. Names are matched
+// without regard to case, spaces or hyphens, so {First Name} is
+// {first_name}, and {custom1} is {custom_1}. Write {{ and }} for literal
+// braces.
+package smstemplate
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Placeholder is a field of a template.
+type Placeholder struct {
+	Name        string `json:"name"` // normalized, as Field returns it
+	Raw         string `json:"raw"`  // as written, braces included
+	Fallback    string `json:"fallback,omitempty"`
+	HasFallback bool   `json:"-"`
+}
+
+// part is literal text or, when field is set, a placeholder.
+type part struct {
+	text  string
+	field *Placeholder
+}
+
+// Template is a parsed template body.
+type Template struct {
+	body  string
+	parts []part
+}
+
+// Parse parses a template body.
+func Parse(body string) (*Template, error) {
+	t := &Template{body: body}
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			t.parts = append(t.parts, part{text: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(body); i++ {
+		switch {
+		case strings.HasPrefix(body[i:], "{{"), strings.HasPrefix(body[i:], "}}"):
+			lit.WriteByte(body[i])
+			i++
+		case body[i] == '{':
+			end := strings.IndexAny(body[i+1:], "{}")
+			if end < 0 || body[i+1+end] == '{' {
+				return nil, fmt.Errorf("unclosed { at position %d; write {{ for a literal brace", i+1)
+			}
+			raw := body[i : i+end+2]
+			name, fallback, hasFallback := strings.Cut(raw[1:len(raw)-1], "|")
+			p := &Placeholder{Name: Field(name), Raw: raw, Fallback: fallback, HasFallback: hasFallback}
+			if !fieldName.MatchString(p.Name) {
+				return nil, fmt.Errorf("%s at position %d is not a placeholder; name a field such as {first_name}", raw, i+1)
+			}
+			flush()
+			t.parts = append(t.parts, part{field: p})
+			i += end + 1
+		default:
+			lit.WriteByte(body[i])
+		}
+	}
+	flush()
+	return t, nil
+}
+
+var (
+	fieldName    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	fieldSpace   = regexp.MustCompile(`[\s\-]+`)
+	customNumber = regexp.MustCompile(`^custom(\d)$`)
+)
+
+// Field returns the normalized name of a field or data key: lowercase,
+// with spaces and hyphens as underscores, so "First Name" is first_name.
+func Field(name string) string {
+	name = fieldSpace.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_")
+	return customNumber.ReplaceAllString(name, "custom_$1")
+}
+
+// Body returns the template as written.
+func (t *Template) Body() string {
+	return t.body
+}
+
+// Placeholders returns the placeholders of t in order.
+func (t *Template) Placeholders() []Placeholder {
+	var out []Placeholder
+	for _, p := range t.parts {
+		if p.field != nil {
+			out = append(out, *p.field)
+		}
+	}
+	return out
+}
+
+// Fields returns the distinct field names of t, sorted.
+func (t *Template) Fields() []string {
+	var out []string
+	for _, p := range t.Placeholders() {
+		if !slices.Contains(out, p.Name) {
+			out = append(out, p.Name)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Render fills the placeholders of t from data, whose keys are normalized
+// with Field. A field that is missing or blank takes the placeholder's
+// fallback, or else the value of defaults. Fields with neither are
+// rendered empty and returned in missing.
+func (t *Template) Render(data, defaults map[string]string) (text string, missing []string) {
+	values := normalize(data)
+	fallbacks := normalize(defaults)
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.field == nil {
+			b.WriteString(p.text)
+			continue
+		}
+		v, ok := values[p.field.Name]
+		switch {
+		case ok:
+		case p.field.HasFallback:
+			v = p.field.Fallback
+		default:
+			if v, ok = fallbacks[p.field.Name]; !ok && !slices.Contains(missing, p.field.Name) {
+				missing = append(missing, p.field.Name)
+			}
+		}
+		b.WriteString(v)
+	}
+	return b.String(), missing
+}
+
+// normalize returns m with its keys normalized and blank values dropped.
+func normalize(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if strings.TrimSpace(v) != "" {
+			out[Field(k)] = v
+		}
+	}
+	return out
+}
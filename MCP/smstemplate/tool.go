@@ -0,0 +1,147 @@
+package smstemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateTools are the tools that change SMS templates, and the action
+// each records.
+var templateTools = map[string]string{
+	"post_sms_templates":               ActionCreate,
+	"put_sms_templates_template_id":    ActionUpdate,
+	"delete_sms_templates_template_id": ActionDelete,
+}
+
+// Wrap records each change the SMS template tools make in the library's
+// history, under the account the call acts as, after recording the
+// template as it was when the history holds none of it, and reports what
+// lint finds in the bodies they save. Other tools, and every tool when lib
+// is nil, are returned unchanged.
+func Wrap(tool models.Tool, lib *Library, client func() *clicksend.Client, account func(context.Context) (string, error)) models.Tool {
+	action, ok := templateTools[tool.Definition.Name]
+	if lib == nil || !ok {
+		return tool
+	}
+	tool.Definition.Description += " Changes are kept in the template history, for get_sms_template_history and rollback_sms_template."
+	if action != ActionDelete {
+		tool.Definition.Description += " The body is linted, and issues are reported with the result."
+		tool.Definition.InputSchema.Properties["kind"] = map[string]any{
+			"type":        "string",
+			"enum":        []string{KindMarketing, KindTransactional},
+			"description": "Lint the body as a marketing message, which must carry opt-out text, or as a transactional one (default).",
+		}
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return handler(ctx, request)
+		}
+		kind, _ := args["kind"].(string)
+		forwarded := make(map[string]any, len(args))
+		for k, v := range args {
+			if k != "kind" {
+				forwarded[k] = v
+			}
+		}
+		request.Params.Arguments = forwarded
+
+		var notes []string
+		acct, err := account(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("The account of the call could not be found, so its template history cannot be kept: %v", err)), nil
+		}
+		id, _ := args["template_id"].(string)
+		if id != "" {
+			if err := lib.Baseline(ctx, client(), acct, id); err != nil {
+				notes = append(notes, fmt.Sprintf("Warning: template %s could not be read, so its history has no version from before this change: %v", id, err))
+			}
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+		saved := resultTemplate(result)
+		if id == "" && saved.TemplateID != 0 {
+			id = strconv.FormatInt(int64(saved.TemplateID), 10)
+		}
+		name, _ := args["template_name"].(string)
+		body, _ := args["body"].(string)
+		if action == ActionDelete {
+			name, body = "", ""
+			if versions := lib.History.Versions(acct, id); len(versions) > 0 {
+				name, body = versions[len(versions)-1].Name, versions[len(versions)-1].Body
+			}
+		}
+		if id != "" {
+			if v, err := lib.History.Record(acct, id, name, body, action); err != nil {
+				notes = append(notes, fmt.Sprintf("Warning: the change could not be saved to the template history: %v", err))
+			} else {
+				notes = append(notes, fmt.Sprintf("Recorded as version %d of template %s.", v.Version, id))
+			}
+		}
+		if action != ActionDelete {
+			for _, issue := range Lint(body, LintOptions{MaxSegments: lib.MaxSegments, Marketing: kind == KindMarketing}) {
+				notes = append(notes, fmt.Sprintf("Lint %s (%s): %s", issue.Severity, issue.Rule, issue.Message))
+			}
+		}
+		for _, n := range notes {
+			result.Content = append(result.Content, mcp.NewTextContent(n))
+		}
+		return result, nil
+	}
+	return tool
+}
+
+// Baseline records a template of the account client acts as, as it is now,
+// unless the account's history already holds it, so that the first change
+// made through the server can be rolled back. A template the account does
+// not have is not recorded.
+func (lib *Library) Baseline(ctx context.Context, client *clicksend.Client, account, id string) error {
+	if len(lib.History.Versions(account, id)) > 0 {
+		return nil
+	}
+	t, ok, err := Get(ctx, client, id)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = lib.History.Record(account, id, t.TemplateName, t.Body, ActionBaseline)
+	return err
+}
+
+// Get returns the ClickSend template with an id, and whether there is one.
+func Get(ctx context.Context, client *clicksend.Client, id string) (clicksend.SmsTemplate, bool, error) {
+	templates, err := clicksend.All(ctx, client.ListSmsTemplates)
+	if err != nil {
+		return clicksend.SmsTemplate{}, false, err
+	}
+	for _, t := range templates {
+		if strconv.FormatInt(int64(t.TemplateID), 10) == id {
+			return t, true, nil
+		}
+	}
+	return clicksend.SmsTemplate{}, false, nil
+}
+
+// resultTemplate returns the template in the data of a tool result, or
+// the zero template when there is none.
+func resultTemplate(result *mcp.CallToolResult) clicksend.SmsTemplate {
+	var resp struct {
+		Data clicksend.SmsTemplate `json:"data"`
+	}
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok && json.Unmarshal([]byte(text.Text), &resp) == nil {
+			return resp.Data
+		}
+	}
+	return clicksend.SmsTemplate{}
+}
@@ -0,0 +1,188 @@
+package smstemplate_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/impersonate"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	tools_sms_templates "github.com/clicksend-rest-api-v3/mcp-server/tools/sms_templates"
+	tools_workflows "github.com/clicksend-rest-api-v3/mcp-server/tools/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLibrary(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	cs := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	dir := t.TempDir()
+	history, err := smstemplate.NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib := &smstemplate.Library{Dir: dir, MaxSegments: 2, History: history}
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	cfg := &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, Templates: lib, Subaccounts: impersonate.New(time.Hour, false)}
+	tools := map[string]models.Tool{}
+	for _, tool := range []models.Tool{
+		tools_sms_templates.CreateCreateatemplateTool(cfg),
+		tools_sms_templates.CreateUpdateatemplateTool(cfg),
+		tools_sms_templates.CreateDeleteatemplateTool(cfg),
+		tools_workflows.CreateGetsmstemplatehistoryTool(cfg),
+		tools_workflows.CreateRollbacksmstemplateTool(cfg),
+		tools_workflows.CreateExportsmstemplatesTool(cfg),
+		tools_workflows.CreateImportsmstemplatesTool(cfg),
+	} {
+		tools[tool.Definition.Name] = impersonate.Wrap(smstemplate.Wrap(tool, lib, cfg.Client, cfg.Account), cfg.Subaccounts)
+	}
+	callResult := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = args
+		res, err := tools[name].Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return res
+	}
+	call := func(name string, args map[string]any) (map[string]any, []string) {
+		t.Helper()
+		res := callResult(name, args)
+		if res.IsError {
+			t.Fatalf("%s: %v", name, res)
+		}
+		var out map[string]any
+		if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &out); err != nil {
+			t.Fatal(err)
+		}
+		var notes []string
+		for _, c := range res.Content[1:] {
+			notes = append(notes, c.(mcp.TextContent).Text)
+		}
+		return out, notes
+	}
+	body := func(id string) string {
+		t.Helper()
+		tmpl, ok, err := smstemplate.Get(context.Background(), cs, id)
+		if err != nil || !ok {
+			t.Fatalf("template %s: %v %v", id, ok, err)
+		}
+		return tmpl.Body
+	}
+
+	created, notes := call("post_sms_templates", map[string]any{"template_name": "Pickup", "body": "Hi {first_name}, your order is ready"})
+	id := fmt.Sprint(created["data"].(map[string]any)["template_id"])
+	if want := []string{"Recorded as version 1 of template " + id + "."}; !reflect.DeepEqual(notes, want) {
+		t.Errorf("create notes = %q, want %q", notes, want)
+	}
+	_, notes = call("put_sms_templates_template_id", map[string]any{"template_id": id, "template_name": "Pickup", "body": "Hi {nickname}, sale on — today only", "kind": "marketing"})
+	if text := strings.Join(notes, "\n"); !strings.Contains(text, "version 2") || !strings.Contains(text, "(undefined_placeholder)") || !strings.Contains(text, "(non_gsm)") || !strings.Contains(text, "(opt_out)") {
+		t.Errorf("update notes = %q, want version 2 and the lint issues", notes)
+	}
+	for _, r := range api.Requests() {
+		if r.Method == "PUT" && strings.Contains(string(r.Body), "kind") {
+			t.Errorf("PUT %s sent kind: %s", r.Path, r.Body)
+		}
+	}
+
+	hist, _ := call("get_sms_template_history", map[string]any{"template_id": id})
+	if len(hist["versions"].([]any)) != 2 || hist["diff"] != "Hi [-{first_name}, your order is ready-]{+{nickname}, sale on — today only+}" {
+		t.Errorf("history = %v", hist)
+	}
+	preview, _ := call("rollback_sms_template", map[string]any{"template_id": id, "version": 1.0})
+	if preview["next"] == nil || body(id) != "Hi {nickname}, sale on — today only" {
+		t.Errorf("rollback preview = %v, and the body changed", preview)
+	}
+	rolled, _ := call("rollback_sms_template", map[string]any{"template_id": id, "version": 1.0, "confirm": true})
+	if rolled["recorded_version"] != 3.0 || body(id) != "Hi {first_name}, your order is ready" {
+		t.Errorf("rollback = %v, body %q", rolled, body(id))
+	}
+
+	// A template made outside the server is exported, and its baseline
+	// recorded before an import changes it.
+	promo, err := cs.CreateSmsTemplate(context.Background(), &clicksend.SmsTemplate{TemplateName: "Promo", Body: "Sale today. Reply STOP to opt out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	promoID := fmt.Sprint(promo.Data.TemplateID)
+	exported, _ := call("export_sms_templates", map[string]any{"confirm": true})
+	if exported["created"] != 2.0 {
+		t.Errorf("export = %v, want 2 files created", exported)
+	}
+	files, err := smstemplate.ReadDir(dir)
+	if err != nil || len(files) != 2 || files[0].Path != "pickup.sms" || files[1].Path != "promo.sms" || files[1].ID != promoID {
+		t.Fatalf("exported files = %+v, %v", files, err)
+	}
+
+	files[1].Kind, files[1].Body = smstemplate.KindMarketing, "Sale ends today. Reply STOP to opt out"
+	for _, f := range []smstemplate.File{
+		files[1],
+		{Path: "welcome.sms", Name: "Welcome", Body: "Welcome {first_name|aboard}!"},
+		{Path: "broken.sms", Name: "Broken", Body: "Hi {first_name"},
+	} {
+		if err := smstemplate.WriteFile(dir, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plan, _ := call("import_sms_templates", map[string]any{})
+	if plan["created"] != 1.0 || plan["updated"] != 1.0 || plan["unchanged"] != 1.0 || plan["skipped"] != 1.0 || plan["next"] == nil {
+		t.Errorf("import preview = %v", plan)
+	}
+	if body(promoID) != "Sale today. Reply STOP to opt out" {
+		t.Error("import preview changed a template")
+	}
+	call("import_sms_templates", map[string]any{"confirm": true})
+	if body(promoID) != "Sale ends today. Reply STOP to opt out" {
+		t.Errorf("promo body = %q after import", body(promoID))
+	}
+	var actions []string
+	for _, v := range history.Versions(clicksendtest.Username, promoID) {
+		actions = append(actions, v.Action)
+	}
+	if want := []string{"baseline", "import"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("promo history = %v, want %v", actions, want)
+	}
+	welcome, err := os.ReadFile(filepath.Join(dir, "welcome.sms"))
+	if err != nil || !strings.Contains(string(welcome), "\nid: ") {
+		t.Errorf("welcome.sms = %q, %v, want the new id", welcome, err)
+	}
+
+	call("delete_sms_templates_template_id", map[string]any{"template_id": id})
+	if versions := history.Versions(clicksendtest.Username, id); versions[len(versions)-1].Action != "delete" {
+		t.Errorf("history after delete = %+v", versions)
+	}
+
+	// Another account can neither read the deleted template's history nor
+	// restore it into its own account.
+	sub, err := cs.CreateSubaccount(context.Background(), &clicksend.Subaccount{
+		APIUsername: "customer", Password: "pw", Email: "c@example.com", PhoneNumber: "+61411111111", FirstName: "C", LastName: "Customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := map[string]any{"template_id": id, "as_subaccount": fmt.Sprint(sub.Data.SubaccountID)}
+	if res := callResult("get_sms_template_history", other); !res.IsError {
+		t.Errorf("another account read the history: %v", res.Content)
+	}
+	other["version"], other["confirm"] = 1.0, true
+	if res := callResult("rollback_sms_template", other); !res.IsError {
+		t.Errorf("another account rolled back the template: %v", res.Content)
+	}
+	restored, _ := call("rollback_sms_template", map[string]any{"template_id": id, "version": 1.0, "confirm": true})
+	if restored["new_template_id"] == nil {
+		t.Errorf("rollback of the deleted template = %v, want a new id", restored)
+	}
+}
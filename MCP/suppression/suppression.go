@@ -0,0 +1,481 @@
+// Package suppression keeps the registry of recipients who must not be
+// messaged, and checks the send tools against it.
+//
+// Numbers are suppressed when they reply with an opt-out keyword such as
+// STOP, until they reply with an opt-in keyword such as START. Numbers and
+// emails are also suppressed while they are contacts of the lists
+// configured as opt-out lists, and when added by hand. Each account has its
+// own registry, which reads the account's inbound SMS and the opt-out lists
+// again at the first send after its refresh interval. ClickSend only lists
+// unread inbound SMS, so keyword opt-outs are kept in a file when one is
+// configured, to survive restarts.
+package suppression
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+)
+
+// Sources of an entry.
+const (
+	SourceKeyword = "keyword" // an inbound SMS opt-out keyword
+	SourceList    = "list"    // a contact of an opt-out list
+	SourceManual  = "manual"  // added with suppress_recipients
+)
+
+// DefaultKeywords are the opt-out keywords recognized without
+// SUPPRESSION_KEYWORDS.
+var DefaultKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "OPTOUT"}
+
+// OptInKeywords lift a keyword or manual opt-out sent before them.
+var OptInKeywords = []string{"START", "UNSTOP", "SUBSCRIBE"}
+
+// DefaultRefresh is used when SUPPRESSION_REFRESH is not set.
+const DefaultRefresh = 5 * time.Minute
+
+// Entry is a suppressed recipient.
+type Entry struct {
+	Address string    `json:"address"` // E.164 number or lowercase email
+	Source  string    `json:"source"`
+	Detail  string    `json:"detail,omitempty"` // the keyword, the list id or a note
+	Since   time.Time `json:"since"`
+}
+
+// record is the latest keyword or manual event of an address.
+type record struct {
+	Entry
+	OptIn bool `json:"opt_in,omitempty"`
+}
+
+// Registries holds the settings of suppression and a registry of
+// suppressed recipients for each account the server serves, so that the
+// inbound SMS, opt-out lists and entries of one account never suppress, or
+// are shown to, another.
+type Registries struct {
+	mu        sync.Mutex
+	keywords  []string // opt-out keywords, uppercase
+	lists     []string
+	refresh   time.Duration
+	onError   string // OnErrorBlock or OnErrorSend
+	file      string
+	saved     map[string][]record // the records of each account in file
+	registers map[string]*Registry
+	now       func() time.Time
+}
+
+// Registry is the set of recipients an account must not message.
+type Registry struct {
+	set       *Registries
+	account   string
+	mu        sync.Mutex
+	records   map[string]record
+	listed    map[string]Entry // contacts of the opt-out lists at the last refresh
+	country   string           // the account's default SMS country, read at refresh
+	refreshed time.Time        // the last refresh that read everything
+}
+
+// What a send does when the registry could not be refreshed.
+const (
+	OnErrorBlock = "block" // refuse the send (default)
+	OnErrorSend  = "send"  // send, with a warning that opt-outs may be missed
+)
+
+// New returns empty registries recognizing the opt-out keywords and reading
+// the opt-out lists again after refresh. Sends are refused while a registry
+// cannot be refreshed.
+func New(keywords, lists []string, refresh time.Duration) *Registries {
+	rs := &Registries{lists: lists, refresh: refresh, onError: OnErrorBlock, saved: map[string][]record{}, registers: map[string]*Registry{}, now: time.Now}
+	for _, k := range keywords {
+		rs.keywords = append(rs.keywords, strings.ToUpper(k))
+	}
+	return rs
+}
+
+// FromEnv returns the registries set by SUPPRESSION, SUPPRESSION_KEYWORDS,
+// SUPPRESSION_LISTS, SUPPRESSION_REFRESH, SUPPRESSION_ON_ERROR and
+// SUPPRESSION_FILE, or nil when SUPPRESSION is off.
+func FromEnv() (*Registries, error) {
+	switch v := configfile.Getenv("SUPPRESSION"); v {
+	case "", "on":
+	case "off":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("SUPPRESSION must be on or off, got %q", v)
+	}
+	keywords := slices.Clone(DefaultKeywords)
+	for _, k := range splitList(configfile.Getenv("SUPPRESSION_KEYWORDS")) {
+		if strings.ContainsFunc(k, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			return nil, fmt.Errorf("SUPPRESSION_KEYWORDS must be comma-separated words, got %q", k)
+		}
+		keywords = append(keywords, k)
+	}
+	refresh := DefaultRefresh
+	if v := configfile.Getenv("SUPPRESSION_REFRESH"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("SUPPRESSION_REFRESH must be a non-negative duration such as 5m, got %q", v)
+		}
+		refresh = d
+	}
+	rs := New(keywords, splitList(configfile.Getenv("SUPPRESSION_LISTS")), refresh)
+	switch v := configfile.Getenv("SUPPRESSION_ON_ERROR"); v {
+	case "", OnErrorBlock:
+	case OnErrorSend:
+		rs.onError = OnErrorSend
+	default:
+		return nil, fmt.Errorf("SUPPRESSION_ON_ERROR must be block or send, got %q", v)
+	}
+	if err := rs.load(configfile.Getenv("SUPPRESSION_FILE")); err != nil {
+		return nil, fmt.Errorf("SUPPRESSION_FILE: %w", err)
+	}
+	return rs, nil
+}
+
+// Reconfigure takes the settings of from, keeping the recipients rs knows.
+// The opt-out lists are read again at the next check.
+func (rs *Registries) Reconfigure(from *Registries) {
+	from.mu.Lock()
+	incoming := maps.Clone(from.saved)
+	from.mu.Unlock()
+
+	rs.mu.Lock()
+	rs.keywords, rs.lists, rs.refresh, rs.onError, rs.file = from.keywords, from.lists, from.refresh, from.onError, from.file
+	rs.mu.Unlock()
+	for account, records := range incoming {
+		r := rs.For(account)
+		r.mu.Lock()
+		for _, rec := range records {
+			if old, ok := r.records[rec.Address]; !ok || rec.Since.After(old.Since) {
+				r.records[rec.Address] = rec
+			}
+		}
+		r.mu.Unlock()
+	}
+
+	rs.mu.Lock()
+	registers := slices.Collect(maps.Values(rs.registers))
+	rs.mu.Unlock()
+	for _, r := range registers {
+		r.mu.Lock()
+		r.refreshed = time.Time{}
+		r.mu.Unlock()
+	}
+}
+
+// For returns the registry of an account, named by its ClickSend username.
+func (rs *Registries) For(account string) *Registry {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if r, ok := rs.registers[account]; ok {
+		return r
+	}
+	r := &Registry{set: rs, account: account, records: map[string]record{}, listed: map[string]Entry{}}
+	for _, rec := range rs.saved[account] {
+		r.records[rec.Address] = rec
+	}
+	rs.registers[account] = r
+	return r
+}
+
+// SendOnError reports whether sends go ahead, with a warning, when a
+// registry cannot be refreshed.
+func (rs *Registries) SendOnError() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.onError == OnErrorSend
+}
+
+// Keywords returns the opt-out keywords.
+func (rs *Registries) Keywords() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.keywords
+}
+
+// Lists returns the ids of the opt-out lists.
+func (rs *Registries) Lists() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.lists
+}
+
+// Refreshed returns when inbound SMS and the opt-out lists of the account
+// were last read in full.
+func (r *Registry) Refreshed() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshed
+}
+
+// Country returns the account's default SMS country, as read at the last
+// refresh, or "" before one.
+func (r *Registry) Country() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.country
+}
+
+// Key returns the address recipients are matched on: a lowercase email, or
+// a number in E.164, read in country when local. It returns "" for a local
+// number that cannot be read in country, since it could not match the
+// number written in E.164.
+func Key(address, country string) string {
+	address = strings.TrimSpace(address)
+	if strings.Contains(address, "@") {
+		return strings.ToLower(address)
+	}
+	if n, err := phone.Parse(address, country); err == nil {
+		return n.E164
+	}
+	international := strings.HasPrefix(address, "+") || strings.HasPrefix(address, "00")
+	if !international {
+		return ""
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, address)
+	digits = strings.TrimPrefix(digits, "00")
+	if digits == "" {
+		return ""
+	}
+	return "+" + digits
+}
+
+// Keyword returns the first word of an SMS body in upper case, without
+// punctuation.
+func Keyword(body string) string {
+	word, _, _ := strings.Cut(strings.TrimSpace(body), " ")
+	return strings.ToUpper(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+}
+
+// Lookup returns the entry suppressing the address with a key, as returned
+// by Key, if any.
+func (r *Registry) Lookup(k string) (Entry, bool) {
+	if k == "" {
+		return Entry{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec, ok := r.records[k]; ok && !rec.OptIn {
+		return rec.Entry, true
+	}
+	e, ok := r.listed[k]
+	return e, ok
+}
+
+// Entries returns every suppressed recipient, most recent first.
+func (r *Registry) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Entry
+	for _, rec := range r.records {
+		if !rec.OptIn {
+			out = append(out, rec.Entry)
+		}
+	}
+	for k, e := range r.listed {
+		if rec, ok := r.records[k]; !ok || rec.OptIn {
+			out = append(out, e)
+		}
+	}
+	slices.SortFunc(out, func(a, b Entry) int {
+		if c := b.Since.Compare(a.Since); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Address, b.Address)
+	})
+	return out
+}
+
+// Add suppresses the address with a key, as returned by Key, by hand.
+func (r *Registry) Add(k, note string) (Entry, error) {
+	if k == "" {
+		return Entry{}, errors.New("not a phone number in E.164 or with a known country, or an email")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := Entry{Address: k, Source: SourceManual, Detail: note, Since: r.set.now()}
+	r.records[k] = record{Entry: e}
+	return e, r.save()
+}
+
+// Remove lifts a keyword or manual opt-out of the address with a key, as
+// an opt-in keyword would. It returns the opt-out list entry that still
+// suppresses it, if any.
+func (r *Registry) Remove(k string) (Entry, bool, error) {
+	if k == "" {
+		return Entry{}, false, errors.New("not a phone number in E.164 or with a known country, or an email")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[k] = record{Entry: Entry{Address: k, Source: SourceManual, Since: r.set.now()}, OptIn: true}
+	e, listed := r.listed[k]
+	return e, listed, r.save()
+}
+
+// Refresh reads the account's default country, its inbound SMS for
+// keywords and the contacts of the opt-out lists with client, which must act
+// as the account. It keeps what it could read when part of it fails, but the
+// registry stays stale until a refresh reads everything. An opt-out list the
+// account does not have is skipped.
+func (r *Registry) Refresh(ctx context.Context, client *clicksend.Client) error {
+	keywords, lists := r.set.Keywords(), r.set.Lists()
+	var errs []error
+
+	var country string
+	if acct, err := client.GetAccount(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("account: %w", err))
+	} else if c := cmp.Or(acct.Data.DefaultCountrySms, acct.Data.Country); phone.Lookup(c) != nil {
+		country = c
+	}
+
+	inbound, err := clicksend.All(ctx, client.ListInboundSms)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("inbound SMS: %w", err))
+	}
+	var events []record
+	for _, m := range inbound {
+		kw, k := Keyword(m.Body), Key(m.From, "")
+		optIn := slices.Contains(OptInKeywords, kw)
+		if k == "" || !optIn && !slices.Contains(keywords, kw) {
+			continue
+		}
+		events = append(events, record{Entry: Entry{Address: k, Source: SourceKeyword, Detail: kw, Since: time.Unix(int64(m.Timestamp), 0).UTC()}, OptIn: optIn})
+	}
+
+	listed := map[string]Entry{}
+	for _, id := range lists {
+		contacts, err := clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.Contact]], error) {
+			return client.ListContacts(ctx, id, opts)
+		})
+		var apiErr *clicksend.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("opt-out list %s: %w", id, err))
+			continue
+		}
+		for _, c := range contacts {
+			for _, address := range []string{c.PhoneNumber, c.Email} {
+				if k := cmp.Or(Key(address, c.AddressCountry), Key(address, country)); k != "" {
+					if _, ok := listed[k]; !ok {
+						listed[k] = Entry{Address: k, Source: SourceList, Detail: id, Since: time.Unix(int64(c.DateAdded), 0).UTC()}
+					}
+				}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, ev := range events {
+		if old, ok := r.records[ev.Address]; !ok || ev.Since.After(old.Since) {
+			r.records[ev.Address] = ev
+			changed = true
+		}
+	}
+	if len(errs) == 0 || len(listed) > 0 {
+		r.listed = listed
+	}
+	if country != "" {
+		r.country = country
+	}
+	if len(errs) == 0 {
+		r.refreshed = r.set.now()
+	}
+	if changed {
+		if err := r.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RefreshIfStale refreshes the registry when it is older than its refresh
+// interval.
+func (r *Registry) RefreshIfStale(ctx context.Context, client *clicksend.Client) error {
+	r.set.mu.Lock()
+	refresh := r.set.refresh
+	r.set.mu.Unlock()
+	r.mu.Lock()
+	stale := r.refreshed.IsZero() || r.set.now().Sub(r.refreshed) >= refresh
+	r.mu.Unlock()
+	if !stale {
+		return nil
+	}
+	return r.Refresh(ctx, client)
+}
+
+// load reads the records of each account kept in file, which need not
+// exist, and keeps them there from then on.
+func (rs *Registries) load(file string) error {
+	rs.file = file
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &rs.saved)
+}
+
+// save writes the records of the registry's account to the file of the
+// registries, if any. r.mu must be held.
+func (r *Registry) save() error {
+	records := make([]record, 0, len(r.records))
+	for _, rec := range r.records {
+		records = append(records, rec)
+	}
+	slices.SortFunc(records, func(a, b record) int { return strings.Compare(a.Address, b.Address) })
+
+	rs := r.set
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.saved[r.account] = records
+	if rs.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(rs.saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := rs.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, rs.file)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,236 @@
+package suppression
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestKeyword(t *testing.T) {
+	for body, want := range map[string]string{
+		"STOP":             "STOP",
+		"  stop. thanks":   "STOP",
+		"Unsubscribe me":   "UNSUBSCRIBE",
+		"Can you stop?":    "CAN",
+		"":                 "",
+		"'Quit' right now": "QUIT",
+	} {
+		if got := Keyword(body); got != want {
+			t.Errorf("Keyword(%q) = %q, want %q", body, got, want)
+		}
+	}
+	if got := Key(" Ann@Example.com ", ""); got != "ann@example.com" {
+		t.Errorf("Key(email) = %q", got)
+	}
+	if got := Key("0411 111 111", "AU"); got != "+61411111111" {
+		t.Errorf("Key(local number) = %q", got)
+	}
+	if got := Key("0411 111 111", ""); got != "" {
+		t.Errorf("Key(local number of no country) = %q, want none", got)
+	}
+	if got := Key("0061 411 111 111", ""); got != "+61411111111" {
+		t.Errorf("Key(00 number) = %q", got)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	cs := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	ctx := context.Background()
+	list, err := cs.CreateContactList(ctx, &clicksend.ContactList{ListName: "Opt-outs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listID := fmt.Sprint(list.Data.ListID)
+	if _, err := cs.CreateContact(ctx, listID, &clicksend.Contact{Email: "Dan@Example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	api.ReceiveSms("+61411111111", "+61400000000", "STOP")
+	api.ReceiveSms("+61422222222", "+61400000000", "halt!")
+	api.ReceiveSms("+61433333333", "+61400000000", "Stop please")
+	api.Advance(time.Minute)
+	api.ReceiveSms("+61433333333", "+61400000000", "START")
+
+	r := New(append(DefaultKeywords, "HALT"), []string{listID, "404040"}, time.Hour).For(clicksendtest.Username)
+	if err := r.Refresh(ctx, cs); err != nil {
+		t.Fatal(err)
+	}
+	if r.Country() != "AU" || r.Refreshed().IsZero() {
+		t.Errorf("after refresh, country = %q and refreshed = %v", r.Country(), r.Refreshed())
+	}
+	for address, want := range map[string]string{
+		"+61411111111":    SourceKeyword,
+		"+61422222222":    SourceKeyword,
+		"+61433333333":    "",
+		"dan@example.com": SourceList,
+	} {
+		e, ok := r.Lookup(address)
+		if got := map[bool]string{true: e.Source}[ok]; got != want {
+			t.Errorf("Lookup(%s) = %+v, %v, want source %q", address, e, ok, want)
+		}
+	}
+	if n := len(r.Entries()); n != 3 {
+		t.Errorf("Entries = %+v, want 3", r.Entries())
+	}
+
+	if _, listed, _ := r.Remove("dan@example.com"); !listed {
+		t.Error("Remove of an opt-out list contact should report the list")
+	}
+	if _, ok := r.Lookup("dan@example.com"); !ok {
+		t.Error("an opt-out list contact was lifted by Remove")
+	}
+	r.Remove("+61411111111")
+	if _, ok := r.Lookup("+61411111111"); ok {
+		t.Error("Remove did not lift a keyword opt-out")
+	}
+	if err := r.Refresh(ctx, cs); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup("+61411111111"); ok {
+		t.Error("an older STOP was applied again after Remove")
+	}
+}
+
+func TestFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "suppressed.json")
+	rs := New(DefaultKeywords, nil, time.Hour)
+	if err := rs.load(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.For("acme").Add("+61411111111", "asked by phone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.For("acme").Add(Key("call me", ""), ""); err == nil {
+		t.Error("Add accepted an address without digits")
+	}
+
+	again := New(DefaultKeywords, nil, time.Hour)
+	if err := again.load(file); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := again.For("acme").Lookup("+61411111111"); !ok || e.Source != SourceManual || e.Detail != "asked by phone" {
+		t.Errorf("loaded entry = %+v, %v", e, ok)
+	}
+	if e, ok := again.For("other").Lookup("+61411111111"); ok {
+		t.Errorf("another account loaded %+v", e)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	cs := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	list, err := cs.CreateContactList(context.Background(), &clicksend.ContactList{ListName: "Customers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listID := fmt.Sprint(list.Data.ListID)
+	if _, err := cs.CreateContact(context.Background(), listID, &clicksend.Contact{PhoneNumber: "+61411111111"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := New(DefaultKeywords, nil, time.Hour)
+	r := rs.For(clicksendtest.Username)
+	r.Add("+61411111111", "")
+	r.Add("ann@example.com", "")
+
+	var got map[string]any
+	wrapAs := func(name, account string, cs *clicksend.Client) models.Tool {
+		return Wrap(models.Tool{
+			Definition: mcp.NewTool(name),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				got = request.GetArguments()
+				return mcp.NewToolResultText("ok"), nil
+			},
+		}, rs, func() *clicksend.Client { return cs }, func(context.Context) (string, error) { return account, nil }, "")
+	}
+	wrap := func(name string) models.Tool { return wrapAs(name, clicksendtest.Username, cs) }
+	call := func(tool models.Tool, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		got = nil
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		var parts []string
+		for _, c := range result.Content {
+			parts = append(parts, c.(mcp.TextContent).Text)
+		}
+		return strings.Join(parts, "\n")
+	}
+
+	sms := wrap("post_sms_send")
+	result := call(sms, map[string]any{"messages": []any{
+		map[string]any{"to": "+61411111111", "body": "hi"},
+		map[string]any{"to": "+61422222222", "body": "hi"},
+	}})
+	if messages := got["messages"].([]any); len(messages) != 1 || messages[0].(map[string]any)["to"] != "+61422222222" {
+		t.Errorf("forwarded messages = %v", got["messages"])
+	}
+	if !strings.Contains(text(result), "messages[0].to +61411111111 (suppressed by hand)") {
+		t.Errorf("result = %q, want the dropped recipient", text(result))
+	}
+
+	result = call(sms, map[string]any{"to": "+61411111111", "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(text(result), "every recipient is suppressed") {
+		t.Errorf("all suppressed: result = %q, forwarded %v", text(result), got)
+	}
+
+	result = call(sms, map[string]any{"to": "0411 111 111", "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(text(result), "every recipient is suppressed") {
+		t.Errorf("local number in the account's country: result = %q, forwarded %v", text(result), got)
+	}
+	result = call(sms, map[string]any{"to": "020 7946 0000", "country": "GB", "body": "hi"})
+	if result.IsError || got["to"] != "020 7946 0000" {
+		t.Errorf("local number of another country: result = %q, forwarded %v", text(result), got)
+	}
+
+	result = call(wrapAs("post_sms_send", "other", cs), map[string]any{"to": "+61411111111", "body": "hi"})
+	if result.IsError || got["to"] != "+61411111111" {
+		t.Errorf("send by another account: result = %q, forwarded %v", text(result), got)
+	}
+	result = call(wrapAs("post_sms_send", "unread", clicksend.NewClient(api.URL, clicksend.WithCredentials("unread", "wrong"))), map[string]any{"to": "0411 111 111", "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(text(result), "could not be refreshed") {
+		t.Errorf("send while the registry cannot be refreshed: result = %q, forwarded %v", text(result), got)
+	}
+	rs.onError = OnErrorSend
+	result = call(wrapAs("post_sms_send", "unread", clicksend.NewClient(api.URL, clicksend.WithCredentials("unread", "wrong"))), map[string]any{"to": "+61422222222", "body": "hi"})
+	if result.IsError || got["to"] != "+61422222222" || !strings.Contains(text(result), "Warning: the suppression list could not be refreshed") {
+		t.Errorf("send with SUPPRESSION_ON_ERROR=send: result = %q, forwarded %v", text(result), got)
+	}
+	result = call(wrapAs("post_sms_send", "unread", clicksend.NewClient(api.URL, clicksend.WithCredentials("unread", "wrong"))), map[string]any{"to": "0411 111 111", "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(text(result), "cannot be checked against the suppression list") {
+		t.Errorf("local number of no known country: result = %q, forwarded %v", text(result), got)
+	}
+	rs.onError = OnErrorBlock
+
+	result = call(sms, map[string]any{"list_id": listID, "body": "hi"})
+	if !result.IsError || got != nil || !strings.Contains(text(result), "holds 1 suppressed recipients") {
+		t.Errorf("list send: result = %q, forwarded %v", text(result), got)
+	}
+
+	email := wrap("post_email_send")
+	call(email, map[string]any{"to": []any{map[string]any{"email": "ANN@example.com"}, map[string]any{"email": "bob@example.com"}}, "cc": []any{"ann@example.com"}})
+	if to, cc := got["to"].([]any), got["cc"].([]any); len(to) != 1 || len(cc) != 0 {
+		t.Errorf("forwarded email to %v, cc %v", to, cc)
+	}
+
+	if tool := Wrap(models.Tool{Definition: mcp.NewTool("post_sms_price")}, rs, nil, nil, ""); tool.Handler != nil {
+		t.Error("a price tool was wrapped")
+	}
+}
@@ -0,0 +1,260 @@
+package suppression
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sendTools are the tools checked before they send. Their recipients are
+// numbers in to, email objects in to, cc and bcc, contact lists in list_id,
+// or the same in each of messages.
+var sendTools = map[string]bool{
+	"post_sms_send":             true,
+	"post_mms_send":             true,
+	"post_voice_send":           true,
+	"post_email_send":           true,
+	"post_sms-campaigns_send":   true,
+	"post_email-campaigns_send": true,
+}
+
+// Wrap drops the suppressed recipients of a send tool before it runs and
+// reports them in the result, checking them against the registry of the
+// account the call acts as. Local numbers are read in the country the
+// message names, in country, or in the account's default country. A call
+// left without recipients fails, and so does a call with a local number
+// none of these can read, a send to a contact list holding suppressed
+// contacts, since one contact of a list cannot be left out, and a send
+// while the registry cannot be refreshed, unless SUPPRESSION_ON_ERROR is
+// send. The registry is refreshed with client when stale. Other tools, and
+// every tool when rs is nil, are returned unchanged.
+func Wrap(tool models.Tool, rs *Registries, client func() *clicksend.Client, account func(context.Context) (string, error), country string) models.Tool {
+	if rs == nil || !sendTools[tool.Definition.Name] {
+		return tool
+	}
+	tool.Definition.Description += " Recipients on the suppression list are dropped and reported."
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return handler(ctx, request)
+		}
+		acct, err := account(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Not sent: the account of the call could not be found, so its suppression list cannot be checked: %v", err)), nil
+		}
+		r := rs.For(acct)
+		cs := client()
+		var notes []string
+		if err := r.RefreshIfStale(ctx, cs); err != nil {
+			if !rs.SendOnError() {
+				return mcp.NewToolResultError(fmt.Sprintf("Not sent: the suppression list could not be refreshed, so recent opt-outs could be missed: %v. Try again, or set SUPPRESSION_ON_ERROR=send to send anyway.", err)), nil
+			}
+			notes = append(notes, fmt.Sprintf("Warning: the suppression list could not be refreshed, so recent opt-outs may be missed: %v", err))
+		}
+		country := cmp.Or(country, r.Country())
+
+		var lists []string
+		messages, _ := args["messages"].([]any)
+		for _, obj := range append([]any{args}, messages...) {
+			if m, ok := obj.(map[string]any); ok {
+				if id := listID(m["list_id"]); id != "" {
+					lists = append(lists, id)
+				}
+			}
+		}
+		for _, id := range lists {
+			blocked, err := r.checkList(ctx, cs, id)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Not sent: list %s could not be checked for suppressed recipients: %v", id, err)), nil
+			}
+			if len(blocked) > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("Not sent: list %s holds %d suppressed recipients (%s); remove them with remove_suppressed_contacts first.", id, len(blocked), summarize(blocked))), nil
+			}
+		}
+
+		var dropped, unread []string
+		forwarded := filterRecipients(r, args, "", country, &dropped, &unread)
+		sendable := true
+		if messages != nil {
+			kept := make([]any, 0, len(messages))
+			for i, m := range messages {
+				obj, ok := m.(map[string]any)
+				if !ok {
+					kept = append(kept, m)
+					continue
+				}
+				if f := filterRecipients(r, obj, fmt.Sprintf("messages[%d].", i), country, &dropped, &unread); hasRecipients(f) {
+					kept = append(kept, f)
+				}
+			}
+			forwarded["messages"] = kept
+			sendable = len(kept) > 0
+		} else {
+			sendable = hasRecipients(forwarded)
+		}
+		if len(unread) > 0 {
+			return mcp.NewToolResultError("Not sent: these local numbers name no country the server knows, so they cannot be checked against the suppression list: " + strings.Join(unread, "; ") + ". Write them in E.164 (+61...), name their country, or set PHONE_DEFAULT_COUNTRY."), nil
+		}
+		if !sendable {
+			return mcp.NewToolResultError("Not sent: every recipient is suppressed: " + strings.Join(dropped, "; ")), nil
+		}
+
+		request.Params.Arguments = forwarded
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && !result.IsError {
+			if len(dropped) > 0 {
+				notes = append(notes, "Suppressed recipients were not sent to: "+strings.Join(dropped, "; "))
+			}
+			for _, n := range notes {
+				result.Content = append(result.Content, mcp.NewTextContent(n))
+			}
+		}
+		return result, err
+	}
+	return tool
+}
+
+// filterRecipients returns a copy of obj without its suppressed
+// recipients, adding them to dropped. Local numbers are read in the country
+// obj names, or in country; those neither can read are added to unread.
+func filterRecipients(r *Registry, obj map[string]any, path, country string, dropped, unread *[]string) map[string]any {
+	out := make(map[string]any, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	if c, _ := obj["country"].(string); phone.Lookup(c) != nil {
+		country = c
+	}
+	suppressed := func(address, where string) bool {
+		k := Key(address, country)
+		if k == "" {
+			*unread = append(*unread, where+" "+address)
+			return false
+		}
+		e, ok := r.Lookup(k)
+		if ok {
+			*dropped = append(*dropped, where+" "+describe(e))
+		}
+		return ok
+	}
+	if to, ok := obj["to"].(string); ok && to != "" {
+		if suppressed(to, path+"to") {
+			delete(out, "to")
+		}
+	}
+	for _, arg := range []string{"to", "cc", "bcc"} {
+		items, ok := obj[arg].([]any)
+		if !ok {
+			continue
+		}
+		kept := make([]any, 0, len(items))
+		for i, item := range items {
+			address, _ := item.(string)
+			if m, ok := item.(map[string]any); ok {
+				address, _ = m["email"].(string)
+			}
+			if address != "" && suppressed(address, fmt.Sprintf("%s%s[%d]", path, arg, i)) {
+				continue
+			}
+			kept = append(kept, item)
+		}
+		out[arg] = kept
+	}
+	return out
+}
+
+// hasRecipients reports whether a message still has a number, email or
+// list to send to.
+func hasRecipients(obj map[string]any) bool {
+	if listID(obj["list_id"]) != "" {
+		return true
+	}
+	switch to := obj["to"].(type) {
+	case string:
+		return to != ""
+	case []any:
+		return len(to) > 0
+	}
+	return false
+}
+
+// checkList returns the suppressed contacts of a list.
+func (r *Registry) checkList(ctx context.Context, client *clicksend.Client, listID string) ([]Entry, error) {
+	contacts, err := clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.Contact]], error) {
+		return client.ListContacts(ctx, listID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	for _, c := range contacts {
+		if e, ok := r.LookupContact(c); ok {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// LookupContact returns the entry suppressing the phone number or email of
+// a contact, if any.
+func (r *Registry) LookupContact(c clicksend.Contact) (Entry, bool) {
+	for _, address := range []string{c.PhoneNumber, c.Email} {
+		if address == "" {
+			continue
+		}
+		if e, ok := r.Lookup(cmp.Or(Key(address, c.AddressCountry), Key(address, r.Country()))); ok {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func listID(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(int64(v))
+	}
+	return ""
+}
+
+// describe returns an entry as "+61411111111 (replied STOP on 2026-01-02)".
+func describe(e Entry) string {
+	var why string
+	switch e.Source {
+	case SourceKeyword:
+		why = fmt.Sprintf("replied %s on %s", e.Detail, e.Since.Format(time.DateOnly))
+	case SourceList:
+		why = "in opt-out list " + e.Detail
+	default:
+		why = "suppressed by hand"
+		if e.Detail != "" {
+			why += ": " + e.Detail
+		}
+	}
+	return fmt.Sprintf("%s (%s)", e.Address, why)
+}
+
+// summarize describes the first few entries of a long list.
+func summarize(entries []Entry) string {
+	const shown = 5
+	parts := make([]string, 0, shown+1)
+	for i, e := range entries {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(entries)-shown))
+			break
+		}
+		parts = append(parts, describe(e))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,86 @@
+package suppression_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	tools_sms "github.com/clicksend-rest-api-v3/mcp-server/tools/sms"
+	tools_workflows "github.com/clicksend-rest-api-v3/mcp-server/tools/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// wrap wraps a tool the way the server does for cfg.
+func wrap(cfg *config.APIConfig, tool models.Tool) models.Tool {
+	return phone.Wrap(suppression.Wrap(tool, cfg.Suppression, cfg.Client, cfg.Account, cfg.PhoneCountry), cfg.PhoneCountry)
+}
+
+func call(t *testing.T, tool models.Tool, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool.Definition.Name
+	request.Params.Arguments = args
+	res, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s: %v", tool.Definition.Name, err)
+	}
+	return res
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	var b strings.Builder
+	for _, c := range res.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			b.WriteString(t.Text)
+		}
+	}
+	return b.String()
+}
+
+func TestSuppressedSend(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	api.ReceiveSms("+61411111111", "+61400000000", "STOP")
+	registry := suppression.New(suppression.DefaultKeywords, nil, time.Hour)
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	cfg := &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, PhoneCountry: "AU", Suppression: registry}
+	send := wrap(cfg, tools_sms.CreateSendansmsTool(cfg))
+	// noCountry has no default country, so local numbers are read in the
+	// account's.
+	noCountry := &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, Suppression: registry}
+
+	res := call(t, send, map[string]any{"messages": []any{
+		map[string]any{"to": "0411 111 111", "body": "Sale on now"},
+		map[string]any{"to": "0422 222 222", "body": "Sale on now"},
+	}})
+	if res.IsError || !strings.Contains(fmt.Sprint(res.Content), "+61411111111 (replied STOP on") {
+		t.Errorf("send = %v, want the STOP recipient reported", res.Content)
+	}
+	if sent := api.Messages("sms"); len(sent) != 1 || sent[0]["to"] != "+61422222222" {
+		t.Errorf("sent = %v, want only +61422222222", sent)
+	}
+	res = call(t, wrap(noCountry, tools_sms.CreateSendansmsTool(noCountry)), map[string]any{"to": "0411 111 111", "body": "Sale on now"})
+	if !res.IsError || !strings.Contains(resultText(res), "every recipient is suppressed") {
+		t.Errorf("send of a local number without a default country = %v, want it suppressed", res.Content)
+	}
+
+	if res := call(t, wrap(cfg, tools_workflows.CreateUnsuppressrecipientsTool(cfg)), map[string]any{"addresses": []any{"0411 111 111"}}); res.IsError {
+		t.Fatalf("unsuppress_recipients: %v", res.Content)
+	}
+	res = call(t, wrap(cfg, tools_workflows.CreateGetsuppressionlistTool(cfg)), map[string]any{"addresses": []any{"+61411111111"}})
+	var list struct {
+		Checked map[string]any `json:"checked"`
+	}
+	if err := json.Unmarshal([]byte(resultText(res)), &list); err != nil || list.Checked["+61411111111"] != nil {
+		t.Errorf("get_suppression_list = %s, want the number lifted", resultText(res))
+	}
+}
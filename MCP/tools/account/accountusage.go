@@ -2,10 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
@@ -34,52 +30,16 @@ func AccountusageHandler(cfg *config.APIConfig) func(ctx context.Context, reques
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: month"), nil
 		}
-		typeVal, ok := args["type"]
+		type_Val, ok := args["type"]
 		if !ok {
 			return mcp.NewToolResultError("Missing required path parameter: type"), nil
 		}
-		type, ok := typeVal.(string)
+		type_, ok := type_Val.(string)
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: type"), nil
 		}
-		url := fmt.Sprintf("%s/account/usage/%s/%s/%s", cfg.BaseURL, year, month, type)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		resp, err := cfg.Client().AccountUsage(ctx, year, month, type_)
+		return models.APIResult(resp, err)
 	}
 }
 
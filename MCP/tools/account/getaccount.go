@@ -2,10 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
@@ -14,44 +10,8 @@ import (
 
 func GetaccountHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/account", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		resp, err := cfg.Client().GetAccount(ctx)
+		return models.APIResult(resp, err)
 	}
 }
 
@@ -2,12 +2,9 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"bytes"
 
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,61 +16,12 @@ func UpdateaccountHandler(cfg *config.APIConfig) func(ctx context.Context, reque
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		// Create properly typed request body using the generated schema
-		var requestBody map[string]interface{}
-		
-		// Optimized: Single marshal/unmarshal with JSON tags handling field mapping
-		if argsJSON, err := json.Marshal(args); err == nil {
-			if err := json.Unmarshal(argsJSON, &requestBody); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
-			}
-		} else {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal arguments: %v", err)), nil
+		var requestBody clicksend.Account
+		if err := models.DecodeArgs(args, &requestBody); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
 		}
-		
-		bodyBytes, err := json.Marshal(requestBody)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
-		}
-		url := fmt.Sprintf("%s/account", cfg.BaseURL)
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		resp, err := cfg.Client().UpdateAccount(ctx, &requestBody)
+		return models.APIResult(resp, err)
 	}
 }
 
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func GetaspecifictransactionHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		transaction_idVal, ok := args["transaction_id"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required path parameter: transaction_id"), nil
+		}
+		transaction_id, ok := transaction_idVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid path parameter: transaction_id"), nil
+		}
+		resp, err := cfg.Client().GetTransaction(ctx, transaction_id)
+		return models.APIResult(resp, err)
+	}
+}
+
+func CreateGetaspecifictransactionTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("get_recharge_transactions_transaction_id",
+		mcp.WithDescription("Get a specific transaction"),
+		mcp.WithString("transaction_id", mcp.Required(), mcp.Description("Your transaction id.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetaspecifictransactionHandler(cfg),
+	}
+}
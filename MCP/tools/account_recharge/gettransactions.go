@@ -2,62 +2,36 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/pagination"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GettransactionsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/recharge/transactions", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		params, err := pagination.ParseParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
+		client := cfg.Client()
+		result, err := pagination.Fetch(ctx, params, client.ListTransactions)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return models.ErrorResult(err), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return models.JSONResult(result)
 	}
 }
 
 func CreateGettransactionsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_recharge_transactions",
 		mcp.WithDescription("Get Transactions"),
+		pagination.WithParams(),
 	)
 
 	return models.Tool{
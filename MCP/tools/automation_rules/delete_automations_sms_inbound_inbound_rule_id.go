@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func Delete_automations_sms_inbound_inbound_rule_idHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		inbound_rule_idVal, ok := args["inbound_rule_id"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required path parameter: inbound_rule_id"), nil
+		}
+		inbound_rule_id, ok := inbound_rule_idVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid path parameter: inbound_rule_id"), nil
+		}
+		resp, err := cfg.Client().DeleteSmsInboundRule(ctx, inbound_rule_id)
+		return models.APIResult(resp, err)
+	}
+}
+
+func CreateDelete_automations_sms_inbound_inbound_rule_idTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("delete_automations_sms_inbound_inbound_rule_id",
+		mcp.WithDescription("Delete a rule"),
+		mcp.WithString("inbound_rule_id", mcp.Required(), mcp.Description("Inbound Rule ID.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    Delete_automations_sms_inbound_inbound_rule_idHandler(cfg),
+	}
+}
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/pagination"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func Get_automations_fax_receiptsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		params, err := pagination.ParseParams(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		client := cfg.Client()
+		result, err := pagination.Fetch(ctx, params, client.ListFaxReceiptRules)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		return models.JSONResult(result)
+	}
+}
+
+func CreateGet_automations_fax_receiptsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("get_automations_fax_receipts",
+		mcp.WithDescription("List Rules"),
+		pagination.WithParams(),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    Get_automations_fax_receiptsHandler(cfg),
+	}
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func Post_automations_fax_receiptsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		var requestBody clicksend.ReceiptRule
+		if err := models.DecodeArgs(args, &requestBody); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
+		}
+		resp, err := cfg.Client().CreateFaxReceiptRule(ctx, &requestBody)
+		return models.APIResult(resp, err)
+	}
+}
+
+func CreatePost_automations_fax_receiptsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("post_automations_fax_receipts",
+		mcp.WithDescription("Create a New Rule"),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Input parameter: Action.")),
+		mcp.WithString("action_address", mcp.Required(), mcp.Description("Input parameter: Action Address.")),
+		mcp.WithString("enabled", mcp.Required(), mcp.Description("Input parameter: Enabled.")),
+		mcp.WithString("match_type", mcp.Required(), mcp.Description("Input parameter: Match Type. 0=All reports.")),
+		mcp.WithString("rule_name", mcp.Required(), mcp.Description("Input parameter: Rule Name.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    Post_automations_fax_receiptsHandler(cfg),
+	}
+}
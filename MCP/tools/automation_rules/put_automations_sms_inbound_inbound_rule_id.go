@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func Put_automations_sms_inbound_inbound_rule_idHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		inbound_rule_idVal, ok := args["inbound_rule_id"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required path parameter: inbound_rule_id"), nil
+		}
+		inbound_rule_id, ok := inbound_rule_idVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid path parameter: inbound_rule_id"), nil
+		}
+		var requestBody clicksend.InboundRule
+		if err := models.DecodeArgs(args, &requestBody); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
+		}
+		resp, err := cfg.Client().UpdateSmsInboundRule(ctx, inbound_rule_id, &requestBody)
+		return models.APIResult(resp, err)
+	}
+}
+
+func CreatePut_automations_sms_inbound_inbound_rule_idTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("put_automations_sms_inbound_inbound_rule_id",
+		mcp.WithDescription("Update a rule"),
+		mcp.WithString("inbound_rule_id", mcp.Required(), mcp.Description("Inbound Rule ID.")),
+		mcp.WithString("dedicated_number", mcp.Required(), mcp.Description("Input parameter: Dedicated Number")),
+		mcp.WithString("message_search_type", mcp.Required(), mcp.Description("Input parameter: Message Search Type: 0=Any message, 1=starts with, 2=contains, 3=does not contain.")),
+		mcp.WithString("message_search_term", mcp.Required(), mcp.Description("Input parameter: Message Search Term.")),
+		mcp.WithString("rule_name", mcp.Required(), mcp.Description("Input parameter: Rule Name.")),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Input parameter: Action.")),
+		mcp.WithString("action_address", mcp.Required(), mcp.Description("Input parameter: Action Address.")),
+		mcp.WithString("enabled", mcp.Required(), mcp.Description("Input parameter: Enabled.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    Put_automations_sms_inbound_inbound_rule_idHandler(cfg),
+	}
+}
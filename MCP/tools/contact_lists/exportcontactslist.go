@@ -2,10 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
@@ -18,14 +14,6 @@ func ExportcontactslistHandler(cfg *config.APIConfig) func(ctx context.Context,
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		filenameVal, ok := args["filename"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: filename"), nil
-		}
-		filename, ok := filenameVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: filename"), nil
-		}
 		list_idVal, ok := args["list_id"]
 		if !ok {
 			return mcp.NewToolResultError("Missing required path parameter: list_id"), nil
@@ -34,44 +22,16 @@ func ExportcontactslistHandler(cfg *config.APIConfig) func(ctx context.Context,
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: list_id"), nil
 		}
-		url := fmt.Sprintf("%s/lists/%s/export?filename=%s", cfg.BaseURL, filename, list_id)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		filenameVal, ok := args["filename"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required path parameter: filename"), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		filename, ok := filenameVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid path parameter: filename"), nil
 		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		resp, err := cfg.Client().ExportContactList(ctx, list_id, filename)
+		return models.APIResult(resp, err)
 	}
 }
 
@@ -2,13 +2,11 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/pagination"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -26,44 +24,18 @@ func GetspecificemailcampaignhistoryHandler(cfg *config.APIConfig) func(ctx cont
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: campaign_id"), nil
 		}
-		url := fmt.Sprintf("%s/email-campaigns/%s/history", cfg.BaseURL, campaign_id)
-		req, err := http.NewRequest("GET", url, nil)
+		params, err := pagination.ParseParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		client := cfg.Client()
+		result, err := pagination.Fetch(ctx, params, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.EmailCampaignMessage]], error) {
+			return client.EmailCampaignHistory(ctx, campaign_id, &clicksend.HistoryOptions{ListOptions: *opts})
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+			return models.ErrorResult(err), nil
 		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return models.JSONResult(result)
 	}
 }
 
@@ -71,6 +43,7 @@ func CreateGetspecificemailcampaignhistoryTool(cfg *config.APIConfig) models.Too
 	tool := mcp.NewTool("get_email-campaigns_campaign_id_history",
 		mcp.WithDescription("Get Specific Email Campaign History"),
 		mcp.WithString("campaign_id", mcp.Required(), mcp.Description("The email campaign id you want to access.")),
+		pagination.WithParams(),
 	)
 
 	return models.Tool{
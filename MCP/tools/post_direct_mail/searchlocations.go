@@ -2,13 +2,11 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/pagination"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -34,44 +32,18 @@ func SearchlocationsHandler(cfg *config.APIConfig) func(ctx context.Context, req
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: query"), nil
 		}
-		url := fmt.Sprintf("%s/post/direct-mail/locations/search/%s/?q=%s", cfg.BaseURL, country, query)
-		req, err := http.NewRequest("GET", url, nil)
+		params, err := pagination.ParseParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		client := cfg.Client()
+		result, err := pagination.Fetch(ctx, params, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.DirectMailLocation]], error) {
+			return client.SearchDirectMailLocations(ctx, country, query, opts)
+		})
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+			return models.ErrorResult(err), nil
 		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return models.JSONResult(result)
 	}
 }
 
@@ -80,6 +52,7 @@ func CreateSearchlocationsTool(cfg *config.APIConfig) models.Tool {
 		mcp.WithDescription("Search Locations"),
 		mcp.WithString("country", mcp.Required(), mcp.Description("Country code.")),
 		mcp.WithString("query", mcp.Required(), mcp.Description("A postal code or place name.")),
+		pagination.WithParams(),
 	)
 
 	return models.Tool{
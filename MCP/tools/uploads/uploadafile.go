@@ -2,14 +2,12 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"bytes"
 
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
 	"github.com/clicksend-rest-api-v3/mcp-server/config"
 	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -19,67 +17,50 @@ func UploadafileHandler(cfg *config.APIConfig) func(ctx context.Context, request
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		// Create properly typed request body using the generated schema
-		var requestBody interface{}
-		
-		// Optimized: Single marshal/unmarshal with JSON tags handling field mapping
-		if argsJSON, err := json.Marshal(args); err == nil {
-			if err := json.Unmarshal(argsJSON, &requestBody); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
-			}
-		} else {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal arguments: %v", err)), nil
+		convert, ok := args["convert"].(string)
+		if !ok {
+			return mcp.NewToolResultError("Missing required parameter: convert"), nil
 		}
-		
-		bodyBytes, err := json.Marshal(requestBody)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
+		var sources []string
+		for _, name := range []string{"content", "file_path", "resource"} {
+			if args[name] != nil {
+				sources = append(sources, name)
+			}
 		}
-		url := fmt.Sprintf("%s/uploads?convert=%s", cfg.BaseURL)
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+		if len(sources) != 1 {
+			return mcp.NewToolResultError("Exactly one of content, file_path and resource is required"), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BasicAuth != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Basic %s", cfg.BasicAuth))
+		var f *upload.File
+		var err error
+		if path, ok := args["file_path"].(string); ok {
+			f, err = cfg.Uploads.ReadLocal(path)
+		} else if content, ok := args["content"].(string); ok {
+			f, err = upload.Content(content)
+		} else {
+			f, err = cfg.Uploads.Source(args[sources[0]])
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		if err == nil && f == nil {
+			err = fmt.Errorf("%s must be local content, not a URL", sources[0])
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid %s: %v", sources[0], err)), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if name, ok := args["file_name"].(string); ok && name != "" {
+			f.Name = name
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		resp, err := cfg.Uploads.Upload(ctx, cfg.Client(), convert, f)
+		return models.APIResult(resp, err)
 	}
 }
 
 func CreateUploadafileTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("post_uploads?convert=convert",
-		mcp.WithDescription("Upload a file"),
+		mcp.WithDescription("Upload a file and convert it for use with a product. Returns the hosted URL (_url) to pass to MMS, fax, letter, postcard or contact import tools."),
+		mcp.WithString("convert", mcp.Required(), mcp.Enum(clicksend.ConvertFax, clicksend.ConvertMms, clicksend.ConvertPost, clicksend.ConvertCsv), mcp.Description("Input parameter: Convert the file for use with a product: 'fax', 'mms', 'post' or 'csv'.")),
+		mcp.WithString("content", mcp.Description("Input parameter: Your file contents encoded in base64, or a data: URI.")),
+		mcp.WithString("file_path", mcp.Description("Input parameter: Path of a local file to upload. Only files under the server's UPLOAD_DIRS can be read.")),
+		mcp.WithObject("resource", mcp.Description("Input parameter: MCP embedded resource to upload, with uri, mimeType and blob or text.")),
+		mcp.WithString("file_name", mcp.Description("Input parameter: File name, used to tell the file type when the content does not.")),
 	)
 
 	return models.Tool{
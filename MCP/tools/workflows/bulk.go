@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxBulkItems bounds the items of one bulk tool call.
+const maxBulkItems = 1000
+
+// bulkItem is the outcome of one item of a bulk tool call.
+type bulkItem struct {
+	Index     int    `json:"index"`
+	ContactID string `json:"contact_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// bulkResult is the result of a bulk tool call.
+type bulkResult struct {
+	ListID    string     `json:"list_id"`
+	Total     int        `json:"total"`
+	Succeeded int        `json:"succeeded"`
+	Failed    int        `json:"failed"`
+	Items     []bulkItem `json:"items"`
+}
+
+// bulkItems reads the array argument of a bulk tool.
+func bulkItems(args map[string]any, name string) ([]any, error) {
+	items, ok := args[name].([]any)
+	if !ok {
+		return nil, fmt.Errorf("Missing required parameter: %s", name)
+	}
+	if len(items) == 0 || len(items) > maxBulkItems {
+		return nil, fmt.Errorf("Invalid %s: pass 1 to %d items, got %d", name, maxBulkItems, len(items))
+	}
+	return items, nil
+}
+
+// runBulk calls fn for each of n items with the configured worker pool and
+// reports the outcome of each. fn returns the ID of the contact it changed.
+func runBulk(ctx context.Context, cfg *config.APIConfig, listID string, n int, fn func(ctx context.Context, i int) (string, error)) (*mcp.CallToolResult, error) {
+	out := bulkResult{ListID: listID, Total: n, Items: make([]bulkItem, n)}
+	errs := cfg.Bulk.Run(ctx, n, func(ctx context.Context, i int) error {
+		id, err := fn(ctx, i)
+		out.Items[i].ContactID = id
+		return err
+	})
+	for i, err := range errs {
+		out.Items[i].Index = i
+		out.Items[i].Status = "ok"
+		if err != nil {
+			out.Items[i].Status, out.Items[i].Error = "failed", err.Error()
+			out.Failed++
+		}
+	}
+	out.Succeeded = n - out.Failed
+	return models.JSONResult(out)
+}
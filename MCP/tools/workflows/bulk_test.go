@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/batch"
+)
+
+func TestBulkContacts(t *testing.T) {
+	api := newFakeAPI(t)
+	listID := api.newList(t, "Bulk")
+	cfg := api.config()
+	cfg.Bulk = batch.New(2, 0)
+
+	created := callJSON(t, CreateBulkcreatecontactsTool(cfg), map[string]any{"list_id": listID, "contacts": []any{
+		map[string]any{"phone_number": "0411 111 111", "first_name": "Ann"},
+		map[string]any{"phone_number": "0412", "first_name": "Bad"},
+		map[string]any{"email": "cat@example.com", "first_name": "Cat"},
+	}})
+	if created["succeeded"] != 2.0 || created["failed"] != 1.0 {
+		t.Fatalf("created = %v", created)
+	}
+	items := created["items"].([]any)
+	if bad := items[1].(map[string]any); bad["status"] != "failed" || !strings.Contains(fmt.Sprint(bad["error"]), "not a valid number") {
+		t.Errorf("items[1] = %v", bad)
+	}
+	ann := items[0].(map[string]any)["contact_id"]
+	cat := items[2].(map[string]any)["contact_id"]
+
+	updated := callJSON(t, CreateBulkupdatecontactsTool(cfg), map[string]any{"list_id": listID, "contacts": []any{
+		map[string]any{"contact_id": ann, "first_name": "Anne"},
+		map[string]any{"first_name": "Nobody"},
+	}})
+	if updated["succeeded"] != 1.0 || updated["failed"] != 1.0 {
+		t.Errorf("updated = %v", updated)
+	}
+	got := map[string]any{}
+	for _, contact := range api.Contacts(listID) {
+		got[fmt.Sprint(contact["contact_id"])] = contact["first_name"]
+	}
+	if got[fmt.Sprint(ann)] != "Anne" {
+		t.Errorf("contacts = %v, want %v renamed", got, ann)
+	}
+
+	deleted := callJSON(t, CreateBulkdeletecontactsTool(cfg), map[string]any{"list_id": listID, "contact_ids": []any{ann, cat}})
+	if deleted["succeeded"] != 2.0 || len(api.Contacts(listID)) != 0 {
+		t.Errorf("deleted = %v, %d contacts left", deleted, len(api.Contacts(listID)))
+	}
+}
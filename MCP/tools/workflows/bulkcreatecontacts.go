@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// bulkContact decodes item i of a bulk contacts argument and normalizes its
+// numbers as the contact tools do.
+func bulkContact(cfg *config.APIConfig, item any) (clicksend.Contact, error) {
+	var c clicksend.Contact
+	fields, ok := item.(map[string]any)
+	if !ok {
+		return c, fmt.Errorf("contact is not an object")
+	}
+	if err := models.DecodeArgs(fields, &c); err != nil {
+		return c, err
+	}
+	if c.PhoneNumber == "" && c.Email == "" && c.FaxNumber == "" && c.ContactID == 0 {
+		return c, fmt.Errorf("contact needs a phone_number, email or fax_number")
+	}
+	if err := phone.NormalizeContact(&c, cfg.PhoneCountry); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func BulkcreatecontactsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		items, err := bulkItems(args, "contacts")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		client := cfg.Client()
+		return runBulk(ctx, cfg, listID, len(items), func(ctx context.Context, i int) (string, error) {
+			contact, err := bulkContact(cfg, items[i])
+			if err != nil {
+				return "", err
+			}
+			contact.ContactID, contact.ListID = 0, 0
+			resp, err := client.CreateContact(ctx, listID, &contact)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprint(resp.Data.ContactID), nil
+		})
+	}
+}
+
+func CreateBulkcreatecontactsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("bulk_create_contacts",
+		mcp.WithDescription("Create many contacts in a list in one call. Contacts are created with the server's bulk worker pool and rate limit; a contact that fails does not stop the others. Returns the new contact id or the error of each contact, by index."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id.")),
+		mcp.WithArray("contacts", mcp.Required(), mcp.MaxItems(maxBulkItems), mcp.Items(map[string]any{"type": "object"}), mcp.Description("Input parameter: Contacts to create, each with the fields of create_a_new_contact such as phone_number, email, first_name and custom_1. Each needs a phone_number, email or fax_number.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    BulkcreatecontactsHandler(cfg),
+	}
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func BulkdeletecontactsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		items, err := bulkItems(args, "contact_ids")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		client := cfg.Client()
+		return runBulk(ctx, cfg, listID, len(items), func(ctx context.Context, i int) (string, error) {
+			var id string
+			switch v := items[i].(type) {
+			case string:
+				id = v
+			case float64:
+				id = fmt.Sprint(int64(v))
+			}
+			if id == "" {
+				return "", fmt.Errorf("contact id %v is not a string or number", items[i])
+			}
+			_, err := client.DeleteContact(ctx, listID, id)
+			return id, err
+		})
+	}
+}
+
+func CreateBulkdeletecontactsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("bulk_delete_contacts",
+		mcp.WithDescription("Delete many contacts of a list in one call. Contacts are deleted with the server's bulk worker pool and rate limit; a contact that fails does not stop the others. Returns the outcome of each contact, by index."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id.")),
+		mcp.WithArray("contact_ids", mcp.Required(), mcp.MaxItems(maxBulkItems), mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Ids of the contacts to delete.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    BulkdeletecontactsHandler(cfg),
+	}
+}
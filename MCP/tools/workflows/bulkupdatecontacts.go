@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func BulkupdatecontactsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		items, err := bulkItems(args, "contacts")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		client := cfg.Client()
+		return runBulk(ctx, cfg, listID, len(items), func(ctx context.Context, i int) (string, error) {
+			contact, err := bulkContact(cfg, items[i])
+			if err != nil {
+				return "", err
+			}
+			if contact.ContactID == 0 {
+				return "", fmt.Errorf("contact has no contact_id")
+			}
+			id := fmt.Sprint(contact.ContactID)
+			contact.ContactID, contact.ListID = 0, 0
+			_, err = client.UpdateContact(ctx, listID, id, &contact)
+			return id, err
+		})
+	}
+}
+
+func CreateBulkupdatecontactsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("bulk_update_contacts",
+		mcp.WithDescription("Update many contacts of a list in one call. Contacts are updated with the server's bulk worker pool and rate limit; a contact that fails does not stop the others. Returns the outcome of each contact, by index."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id.")),
+		mcp.WithArray("contacts", mcp.Required(), mcp.MaxItems(maxBulkItems), mcp.Items(map[string]any{"type": "object"}), mcp.Description("Input parameter: Contacts to update, each with its contact_id and the fields of update_a_contact to change.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    BulkupdatecontactsHandler(cfg),
+	}
+}
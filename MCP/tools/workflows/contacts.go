@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tableOptions are the arguments of tools that read a contact table.
+func tableOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("file", mcp.Description("Input parameter: The CSV or xlsx file: a local file path under the server's UPLOAD_DIRS, a data: URI or an MCP embedded resource. Pass inline content as a data: URI; any other string is read as a path.")),
+		mcp.WithArray("rows", mcp.Description("Input parameter: Inline rows instead of a file: objects keyed by column name, or arrays of cells.")),
+		mcp.WithBoolean("has_header", mcp.DefaultBool(true), mcp.Description("Input parameter: Whether the first row of the file or array rows names the columns. Without a header, columns are named column_1, column_2 and so on.")),
+		mcp.WithObject("field_map", mcp.Description("Input parameter: Columns to map by hand, from column name to field name. Map a column to \"\" to leave it out.")),
+	}
+}
+
+// withSourceSchema lets the file argument of tool also take an embedded
+// resource.
+func withSourceSchema(tool *mcp.Tool) {
+	file := tool.InputSchema.Properties["file"].(map[string]any)
+	tool.InputSchema.Properties["file"] = map[string]any{"description": file["description"], "anyOf": upload.SourceSchema}
+}
+
+// readTable reads the table and field map arguments of tableOptions.
+func readTable(cfg *config.APIConfig, request mcp.CallToolRequest) (*contactdata.Table, map[string]string, error) {
+	args := request.GetArguments()
+	header := request.GetBool("has_header", true)
+	var table *contactdata.Table
+	switch {
+	case args["file"] != nil && args["rows"] != nil:
+		return nil, nil, fmt.Errorf("Pass file or rows, not both")
+	case args["file"] != nil:
+		f, err := cfg.Uploads.Source(args["file"])
+		if err == nil && f == nil {
+			err = fmt.Errorf("must be a local file, a data: URI or an embedded resource, not a URL")
+		}
+		if err == nil {
+			table, err = contactdata.Read(f, header)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid file: %w", err)
+		}
+	case args["rows"] != nil:
+		rows, ok := args["rows"].([]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("Invalid rows: expected an array")
+		}
+		var err error
+		if table, err = contactdata.FromRows(rows, header); err != nil {
+			return nil, nil, fmt.Errorf("Invalid rows: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("One of file and rows is required")
+	}
+
+	overrides := map[string]string{}
+	if raw, ok := args["field_map"].(map[string]any); ok {
+		for column, field := range raw {
+			name, ok := field.(string)
+			if !ok && field != nil {
+				return nil, nil, fmt.Errorf("Invalid field_map: %q must map to a field name or \"\"", column)
+			}
+			overrides[column] = name
+		}
+	}
+	return table, overrides, nil
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func CreatesegmentlistHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		ids, ok := args["list_ids"].([]any)
+		if !ok || len(ids) == 0 {
+			return mcp.NewToolResultError("Missing required parameter: list_ids"), nil
+		}
+		listIDs := make([]string, len(ids))
+		for i, v := range ids {
+			switch v := v.(type) {
+			case string:
+				listIDs[i] = v
+			case float64:
+				listIDs[i] = fmt.Sprint(int64(v))
+			}
+			if listIDs[i] == "" {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid list_ids[%d]: want a list id", i)), nil
+			}
+		}
+		expr, ok := args["filter"].(string)
+		if !ok || expr == "" {
+			return mcp.NewToolResultError("Missing required parameter: filter"), nil
+		}
+		name := request.GetString("new_list_name", "")
+		if name == "" && request.GetString("target_list_id", "") == "" {
+			return mcp.NewToolResultError("Missing required parameter: new_list_name or target_list_id"), nil
+		}
+		key := request.GetString("key", "phone_number")
+		if key != "phone_number" && key != "email" {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid key %q: use phone_number or email", key)), nil
+		}
+		filter, err := contactdata.ParseFilter(expr, contactdata.ContactFields)
+		if err != nil {
+			return mcp.NewToolResultError("Invalid filter: " + err.Error()), nil
+		}
+
+		client := cfg.Client()
+		out := listResult{Action: "copy", From: listIDs, ListName: name, Filter: filter.String(), Key: key}
+		var matched []clicksend.Contact
+		for _, id := range listIDs {
+			contacts, err := listContacts(ctx, client, id)
+			if err != nil {
+				return models.ErrorResult(err), nil
+			}
+			out.Scanned += len(contacts)
+			for _, c := range contacts {
+				if filter.Match(contactdata.Record(c)) {
+					matched = append(matched, c)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("No contact of lists %s matches %s, so there is no segment to create", strings.Join(listIDs, ", "), filter)), nil
+		}
+		into, err := targetList(ctx, client, request, &out)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		add, dups, err := contactdata.Merge(matched, into, key, cfg.PhoneCountry)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out.Duplicates = dups
+		return applyList(ctx, cfg, request, out, "", add)
+	}
+}
+
+func CreateCreatesegmentlistTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("create_segment_list", append([]mcp.ToolOption{
+		mcp.WithDescription("Create a new contact list holding copies of the contacts of one or more lists that match a filter expression, without duplicates by phone number or email. The source lists are unchanged. A list already named new_list_name, or the list target_list_id names, is added to rather than a new one created, leaving out contacts it already holds. Without confirm, returns the contacts that match; with confirm: true, creates the list if needed and copies them with the server's bulk worker pool."),
+		mcp.WithArray("list_ids", mcp.Required(), mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Your contact list ids to select contacts from.")),
+		mcp.WithString("filter", mcp.Required(), mcp.Description("Input parameter: Filter expression over contact fields, for example `custom_2 = vip and not email is empty`. Operators are = != ~ (contains) !~ ^= (starts with) $= (ends with) < <= > >= in (...) and is [not] empty, combined with and, or, not and parentheses. Comparisons ignore case; quote values with spaces.")),
+		mcp.WithString("new_list_name", mcp.Description("Input parameter: Name of the segment list, created unless a list has this name. Required without target_list_id.")),
+		mcp.WithString("target_list_id", mcp.Description("Input parameter: Your contact list id to copy the matching contacts into, instead of new_list_name.")),
+		mcp.WithString("key", mcp.Enum("phone_number", "email"), mcp.DefaultString("phone_number"), mcp.Description("Input parameter: Contact field that identifies duplicates across the source lists.")),
+	}, listOptions()...)...)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    CreatesegmentlistHandler(cfg),
+	}
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateFileChange is what export_sms_templates or import_sms_templates
+// does with one template.
+type templateFileChange struct {
+	Path       string              `json:"path,omitempty"`
+	TemplateID string              `json:"template_id,omitempty"`
+	Name       string              `json:"template_name"`
+	Action     string              `json:"action"` // create, update, unchanged, skipped or failed
+	Diff       string              `json:"diff,omitempty"`
+	Issues     []smstemplate.Issue `json:"issues,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+}
+
+// templateFolderSync is the result of export_sms_templates and
+// import_sms_templates.
+type templateFolderSync struct {
+	Folder    string               `json:"folder"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Skipped   int                  `json:"skipped,omitempty"`
+	Failed    int                  `json:"failed,omitempty"`
+	Changes   []templateFileChange `json:"changes"`
+	// Unmatched are the files without a ClickSend template on export, and
+	// the ClickSend templates without a file on import. They are left as
+	// they are.
+	Unmatched []string `json:"unmatched,omitempty"`
+	Next      string   `json:"next,omitempty"`
+}
+
+// count adds a change to the totals.
+func (s *templateFolderSync) count(c templateFileChange) {
+	switch c.Action {
+	case "create":
+		s.Created++
+	case "update":
+		s.Updated++
+	case "unchanged":
+		s.Unchanged++
+	case "skipped":
+		s.Skipped++
+	case "failed":
+		s.Failed++
+	}
+	s.Changes = append(s.Changes, c)
+}
+
+func ExportsmstemplatesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lib, errResult := templateFolder(cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		files, err := smstemplate.ReadDir(lib.Dir)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("The template folder could not be read, so templates could be written twice: %v", err)), nil
+		}
+		byID, ids, err := templatesByID(ctx, cfg.Client())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		fileByID := map[string]smstemplate.File{}
+		var taken []string
+		for _, f := range files {
+			taken = append(taken, f.Path)
+			if _, ok := byID[f.ID]; ok {
+				fileByID[f.ID] = f
+			}
+		}
+		out := templateFolderSync{Folder: lib.Dir}
+		for _, f := range files {
+			if _, ok := byID[f.ID]; !ok {
+				out.Unmatched = append(out.Unmatched, f.Path)
+			}
+		}
+
+		confirm := request.GetBool("confirm", false)
+		for _, id := range ids {
+			t := byID[id]
+			f, ok := fileByID[id]
+			change := templateFileChange{TemplateID: id, Name: t.TemplateName, Action: "update"}
+			switch {
+			case !ok:
+				f = smstemplate.File{ID: id, Kind: smstemplate.KindTransactional, Path: smstemplate.FileName(t.TemplateName, id, taken)}
+				taken = append(taken, f.Path)
+				change.Action = "create"
+			case f.Name == t.TemplateName && f.Body == t.Body:
+				change.Action = "unchanged"
+			default:
+				change.Diff = smstemplate.Diff(f.Body, t.Body)
+			}
+			f.Name, f.Body = t.TemplateName, t.Body
+			change.Path = f.Path
+			if confirm && change.Action != "unchanged" {
+				if err := smstemplate.WriteFile(lib.Dir, f); err != nil {
+					change.Action, change.Reason = "failed", err.Error()
+				}
+			}
+			out.count(change)
+		}
+		if !confirm && out.Created+out.Updated > 0 {
+			out.Next = fmt.Sprintf("Check the changes, then call %s again with confirm: true to write the %d files.", request.Params.Name, out.Created+out.Updated)
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreateExportsmstemplatesTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("export_sms_templates",
+		mcp.WithDescription("Write each ClickSend SMS template to a file of the template folder (SMS_TEMPLATE_DIR), so the folder can be kept in git. A file starts with a header naming the template's id, name and kind between --- lines, followed by the body. Templates already in the folder keep their file and kind; files of templates no longer in ClickSend are left alone. Without confirm, shows the files that would change; with confirm: true, writes them."),
+		mcp.WithBoolean("confirm", mcp.DefaultBool(false), mcp.Description("Input parameter: Write the files. Without it, only previews them.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ExportsmstemplatesHandler(cfg),
+	}
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateHistory is the result of get_sms_template_history.
+type templateHistory struct {
+	TemplateID string                `json:"template_id"`
+	Versions   []smstemplate.Version `json:"versions"`
+	From       int                   `json:"from,omitempty"`
+	To         int                   `json:"to,omitempty"`
+	Diff       string                `json:"diff,omitempty"`
+	NameDiff   string                `json:"name_diff,omitempty"`
+	Warnings   []string              `json:"warnings,omitempty"`
+}
+
+func GetsmstemplatehistoryHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lib, errResult := templateLibrary(cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		id, err := request.RequireString("template_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		account, errResult := templateAccount(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		out := templateHistory{TemplateID: id}
+		client := cfg.Client()
+		if err := lib.Baseline(ctx, client, account, id); err != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("The template could not be read: %v", err))
+		}
+		out.Versions = lib.History.Versions(account, id)
+		if len(out.Versions) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("SMS template %s is not in this account, and the account's history holds no versions of it", id)), nil
+		}
+		latest := out.Versions[len(out.Versions)-1]
+		if latest.Action != smstemplate.ActionDelete {
+			if current, ok, err := smstemplate.Get(ctx, client, id); err == nil && ok && (current.Body != latest.Body || current.TemplateName != latest.Name) {
+				out.Warnings = append(out.Warnings, fmt.Sprintf("The template was changed outside this server after version %d, so that change is not in the history.", latest.Version))
+			}
+		}
+
+		out.To = request.GetInt("to", latest.Version)
+		out.From = request.GetInt("from", out.To-1)
+		if out.From < 1 {
+			return models.JSONResult(out)
+		}
+		from, ok := lib.History.Get(account, id, out.From)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Template %s has no version %d; it has versions 1 to %d", id, out.From, latest.Version)), nil
+		}
+		to, ok := lib.History.Get(account, id, out.To)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Template %s has no version %d; it has versions 1 to %d", id, out.To, latest.Version)), nil
+		}
+		out.Diff = smstemplate.Diff(from.Body, to.Body)
+		if from.Name != to.Name {
+			out.NameDiff = smstemplate.Diff(from.Name, to.Name)
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreateGetsmstemplatehistoryTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("get_sms_template_history",
+		mcp.WithDescription("List the versions of an SMS template saved through this server, with a word diff between two of them: removed words in [-...-] and added words in {+...+}. The template as it was before its first change here is version 1. Only the versions recorded under the account the call acts as are shown."),
+		mcp.WithString("template_id", mcp.Required(), mcp.Description("Input parameter: The template.")),
+		mcp.WithNumber("from", mcp.Description("Input parameter: The version to diff from. Defaults to the one before to.")),
+		mcp.WithNumber("to", mcp.Description("Input parameter: The version to diff to. Defaults to the latest.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetsmstemplatehistoryHandler(cfg),
+	}
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// suppressionList is the result of get_suppression_list.
+type suppressionList struct {
+	Keywords     []string            `json:"keywords"`
+	OptInWords   []string            `json:"opt_in_keywords"`
+	OptOutLists  []string            `json:"opt_out_lists"`
+	RefreshedAt  *time.Time          `json:"refreshed_at,omitempty"`
+	RefreshError string              `json:"refresh_error,omitempty"`
+	Count        int                 `json:"count"`
+	Entries      []suppression.Entry `json:"entries"`
+	// Checked maps each address passed in to its entry, or null when it may
+	// be messaged.
+	Checked map[string]*suppression.Entry `json:"checked,omitempty"`
+}
+
+func GetsuppressionlistHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r, errResult := suppressionRegistry(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		args, _ := request.Params.Arguments.(map[string]any)
+
+		out := suppressionList{Keywords: cfg.Suppression.Keywords(), OptInWords: suppression.OptInKeywords, OptOutLists: append([]string{}, cfg.Suppression.Lists()...)}
+		if request.GetBool("refresh", false) || r.Refreshed().IsZero() {
+			if err := r.Refresh(ctx, cfg.Client()); err != nil {
+				out.RefreshError = err.Error()
+			}
+		}
+		if t := r.Refreshed(); !t.IsZero() {
+			out.RefreshedAt = &t
+		}
+		if _, ok := args["addresses"]; ok {
+			list, err := addresses(args)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			out.Checked = map[string]*suppression.Entry{}
+			for _, a := range list {
+				if e, ok := r.Lookup(suppressionKey(cfg, r, a)); ok {
+					out.Checked[a] = &e
+				} else {
+					out.Checked[a] = nil
+				}
+			}
+		} else {
+			out.Entries = r.Entries()
+		}
+		out.Count = len(r.Entries())
+		return models.JSONResult(out)
+	}
+}
+
+func CreateGetsuppressionlistTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("get_suppression_list",
+		mcp.WithDescription("List the recipients send tools leave out for the account the call acts as: numbers that replied with an opt-out keyword such as STOP (until they reply START), contacts of the configured opt-out lists, and recipients suppressed by hand. Pass addresses to check just those."),
+		mcp.WithArray("addresses", mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Phone numbers or emails to check instead of listing every entry.")),
+		mcp.WithBoolean("refresh", mcp.Description("Input parameter: Read inbound SMS and the opt-out lists again first.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetsuppressionlistHandler(cfg),
+	}
+}
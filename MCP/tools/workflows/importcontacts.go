@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// importPreview is the result of import_contacts before and after the
+// import runs.
+type importPreview struct {
+	ListID   string                  `json:"list_id"`
+	Rows     int                     `json:"rows"`
+	Valid    int                     `json:"valid"`
+	Skipped  int                     `json:"skipped"`
+	Created  *int                    `json:"created,omitempty"`
+	Message  string                  `json:"message,omitempty"`
+	Mapping  contactdata.Mapping     `json:"mapping"`
+	Unmapped []string                `json:"unmapped,omitempty"`
+	Issues   []contactdata.Issue     `json:"issues,omitempty"`
+	Sample   []map[string]string     `json:"sample,omitempty"`
+	Fields   []clicksend.ImportField `json:"import_fields,omitempty"`
+	Next     string                  `json:"next,omitempty"`
+}
+
+func ImportcontactsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		table, overrides, err := readTable(cfg, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		client := cfg.Client()
+		fields, err := client.ImportFields(ctx, listID)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		mapping, err := contactdata.Map(table.Header, fields.Data, overrides)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		issues, err := mapping.Check(table, fields.Data, cfg.PhoneCountry)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%v; name the columns in field_map", err)), nil
+		}
+		skip := map[int]bool{}
+		for _, issue := range issues {
+			skip[issue.Row] = true
+		}
+
+		out := importPreview{
+			ListID:   listID,
+			Rows:     len(table.Rows),
+			Valid:    len(table.Rows) - len(issues),
+			Skipped:  len(issues),
+			Mapping:  mapping,
+			Unmapped: mapping.Unmapped(),
+			Issues:   issues,
+		}
+		if !request.GetBool("confirm", false) {
+			for i, row := range table.Rows {
+				if len(out.Sample) == request.GetInt("preview_rows", 5) {
+					break
+				}
+				if !skip[i+1] {
+					out.Sample = append(out.Sample, mapping.Record(row))
+				}
+			}
+			out.Fields = fields.Data
+			out.Next = "Check the mapping and issues, then call import_contacts again with the same arguments and confirm: true to import the valid rows."
+			return models.JSONResult(out)
+		}
+		if out.Valid == 0 {
+			return mcp.NewToolResultError("No rows can be imported; see the issues in the preview"), nil
+		}
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		for i, row := range table.Rows {
+			if skip[i+1] {
+				continue
+			}
+			var record []string
+			for j, c := range mapping {
+				if c.Field != "" {
+					record = append(record, row[j])
+				}
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to write contacts", err), nil
+		}
+		uploaded, err := cfg.Uploads.Upload(ctx, client, clicksend.ConvertCsv, &upload.File{Name: "contacts.csv", MediaType: "text/csv", Content: buf.Bytes()})
+		if err != nil {
+			return models.ErrorResult(fmt.Errorf("uploading contacts: %w", err)), nil
+		}
+		imported, err := client.ImportContacts(ctx, listID, &clicksend.ContactImport{FileURL: uploaded.Data.URL, FieldOrder: mapping.Fields()})
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		// The import may be queued, in which case no contact IDs come back yet.
+		if len(imported.Data.IDs) > 0 {
+			out.Created = clicksend.Ptr(len(imported.Data.IDs))
+		}
+		out.Message = imported.ResponseMsg
+		return models.JSONResult(out)
+	}
+}
+
+func CreateImportcontactsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("import_contacts", append(tableOptions(),
+		mcp.WithDescription("Import contacts into a list from a local CSV or Excel (xlsx) file or from inline rows in one step. Columns are matched to the list's import fields by name, synonym or near spelling, phone and fax numbers are normalized to E.164, and rows without a valid phone number, email or fax number, or repeating an earlier row, are skipped. Without confirm, returns a preview of the mapping, the problem rows and sample contacts; with confirm: true, uploads the valid rows, imports them and reports the created and skipped counts."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id to import into.")),
+		mcp.WithNumber("preview_rows", mcp.DefaultNumber(5), mcp.Description("Input parameter: Number of sample contacts in the preview.")),
+		mcp.WithBoolean("confirm", mcp.Description("Input parameter: Run the import. Preview first, then call again with confirm: true.")),
+	)...)
+	withSourceSchema(&tool)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ImportcontactsHandler(cfg),
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/upload"
+)
+
+func TestImportContacts(t *testing.T) {
+	api := newFakeAPI(t)
+	listID := api.newList(t, "Import")
+	dir := t.TempDir()
+	file := filepath.Join(dir, "contacts.csv")
+	csv := "Mobile,Frist Name,Surname,E-mail,Notes\n" +
+		"+61411111111,Ann,Lee,ann@example.com,vip\n" +
+		"0412,Bob,Bell,,\n" +
+		"+61 411 111 111,Cat,Cole,,\n" +
+		",Dan,Day,dan@example.com,\n" +
+		",Eve,,not-an-email,\n"
+	if err := os.WriteFile(file, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := api.config()
+	cfg.PhoneCountry = ""
+	cfg.Uploads = upload.New([]string{dir}, upload.DefaultMaxBytes)
+	tool := CreateImportcontactsTool(cfg)
+
+	preview := callJSON(t, tool, map[string]any{"list_id": listID, "file": file})
+	if preview["valid"] != 2.0 || preview["skipped"] != 3.0 {
+		t.Errorf("preview counts = %v valid, %v skipped, want 2 and 3", preview["valid"], preview["skipped"])
+	}
+	var fields []string
+	for _, col := range preview["mapping"].([]any) {
+		field, _ := col.(map[string]any)["field"].(string)
+		fields = append(fields, field)
+	}
+	if want := []string{"phone_number", "first_name", "last_name", "email", ""}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("mapping = %q, want %q", fields, want)
+	}
+	if got := len(preview["issues"].([]any)); got != 3 {
+		t.Errorf("issues = %v, want 3", preview["issues"])
+	}
+	if len(api.Contacts(listID)) != 0 {
+		t.Fatal("the preview imported contacts")
+	}
+
+	result := callJSON(t, tool, map[string]any{"list_id": listID, "file": file, "confirm": true})
+	if result["created"] != 2.0 {
+		t.Errorf("created = %v, want 2", result["created"])
+	}
+	contacts := api.Contacts(listID)
+	if len(contacts) != 2 || contacts[0]["phone_number"] != "+61411111111" || contacts[1]["email"] != "dan@example.com" {
+		t.Errorf("contacts = %v", contacts)
+	}
+}
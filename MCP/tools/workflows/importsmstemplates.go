@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func ImportsmstemplatesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lib, errResult := templateFolder(cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		fields, err := stringList(request.GetArguments()["fields"], "fields")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		files, err := smstemplate.ReadDir(lib.Dir)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("The template folder could not be read: %v", err)), nil
+		}
+		account, errResult := templateAccount(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		client := cfg.Client()
+		byID, ids, err := templatesByID(ctx, client)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		byName := map[string][]string{}
+		for _, id := range ids {
+			byName[byID[id].TemplateName] = append(byName[byID[id].TemplateName], id)
+		}
+
+		out := templateFolderSync{Folder: lib.Dir}
+		confirm := request.GetBool("confirm", false)
+		matched := map[string]string{} // template id to the file it was matched to
+		for _, f := range files {
+			change := templateFileChange{Path: f.Path, TemplateID: f.ID, Name: f.Name, Action: "create"}
+			_, exists := byID[f.ID]
+			switch {
+			case f.ID != "" && exists:
+			case f.ID != "":
+				change.TemplateID = ""
+				change.Reason = fmt.Sprintf("template %s no longer exists, so it is created again under a new id", f.ID)
+			case len(byName[f.Name]) == 1:
+				change.TemplateID = byName[f.Name][0]
+				change.Reason = "matched by name; its id is written to the file"
+			case len(byName[f.Name]) > 1:
+				change.Action, change.Reason = "skipped", fmt.Sprintf("%d templates are named %q; add the id of one to the file", len(byName[f.Name]), f.Name)
+			}
+			if path, ok := matched[change.TemplateID]; ok && change.TemplateID != "" {
+				change.Action, change.Reason = "skipped", fmt.Sprintf("%s is also template %s", path, change.TemplateID)
+			}
+			if change.Action == "skipped" {
+				out.count(change)
+				continue
+			}
+
+			if current, ok := byID[change.TemplateID]; ok {
+				matched[change.TemplateID] = f.Path
+				change.Action = "update"
+				if current.TemplateName == f.Name && current.Body == f.Body {
+					change.Action = "unchanged"
+				}
+				if current.Body != f.Body {
+					change.Diff = smstemplate.Diff(current.Body, f.Body)
+				}
+			}
+			change.Issues = smstemplate.Lint(f.Body, smstemplate.LintOptions{Fields: fields, MaxSegments: lib.MaxSegments, Marketing: f.Marketing()})
+			if n := lintErrors(change.Issues); n > 0 && change.Action != "unchanged" {
+				change.Action, change.Reason = "skipped", fmt.Sprintf("lint found %d errors; fix the file, or pass the fields it uses", n)
+			}
+			if confirm {
+				importTemplate(ctx, lib, client, account, f, &change)
+			}
+			out.count(change)
+		}
+		for _, id := range ids {
+			if _, ok := matched[id]; !ok {
+				out.Unmatched = append(out.Unmatched, fmt.Sprintf("%s %s", id, byID[id].TemplateName))
+			}
+		}
+		if !confirm && out.Created+out.Updated > 0 {
+			out.Next = fmt.Sprintf("Check the changes, then call %s again with the same arguments and confirm: true to save the %d templates.", request.Params.Name, out.Created+out.Updated)
+		}
+		return models.JSONResult(out)
+	}
+}
+
+// importTemplate saves a file's template to ClickSend as change plans,
+// records it in the account's history, and writes a new id back to the file.
+func importTemplate(ctx context.Context, lib *smstemplate.Library, client *clicksend.Client, account string, f smstemplate.File, change *templateFileChange) {
+	saved := &clicksend.SmsTemplate{TemplateName: f.Name, Body: f.Body}
+	switch change.Action {
+	case "create":
+		resp, err := client.CreateSmsTemplate(ctx, saved)
+		if err != nil {
+			change.Action, change.Reason = "failed", err.Error()
+			return
+		}
+		change.TemplateID = templateID(resp.Data)
+	case "update":
+		if err := lib.Baseline(ctx, client, account, change.TemplateID); err != nil {
+			change.Action, change.Reason = "failed", fmt.Sprintf("the template could not be read: %v", err)
+			return
+		}
+		if _, err := client.UpdateSmsTemplate(ctx, change.TemplateID, saved); err != nil {
+			change.Action, change.Reason = "failed", err.Error()
+			return
+		}
+	case "unchanged":
+	default:
+		return
+	}
+	if change.Action != "unchanged" {
+		if _, err := lib.History.Record(account, change.TemplateID, f.Name, f.Body, smstemplate.ActionImport); err != nil {
+			change.Reason = fmt.Sprintf("saved, but the history could not be: %v", err)
+		}
+	}
+	if f.ID != change.TemplateID {
+		f.ID = change.TemplateID
+		if err := smstemplate.WriteFile(lib.Dir, f); err != nil {
+			change.Reason = fmt.Sprintf("saved as template %s, but the id could not be written to the file: %v", f.ID, err)
+		}
+	}
+}
+
+func CreateImportsmstemplatesTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("import_sms_templates",
+		mcp.WithDescription("Save the files of the template folder (SMS_TEMPLATE_DIR) as ClickSend SMS templates, as written by export_sms_templates. A file updates the template its header names by id, or the one template of its name, and creates a template otherwise, writing the new id back to the file. Files that lint finds errors in are skipped. Without confirm, shows the diff of each template; with confirm: true, saves them and records the changes in the template history."),
+		mcp.WithArray("fields", mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Placeholder names allowed besides the contact fields.")),
+		mcp.WithBoolean("confirm", mcp.DefaultBool(false), mcp.Description("Input parameter: Save the templates. Without it, only previews them.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ImportsmstemplatesHandler(cfg),
+	}
+}
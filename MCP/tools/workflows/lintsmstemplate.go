@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateLintReport is the result of lint_sms_template.
+type templateLintReport struct {
+	Templates []templateLint `json:"templates"`
+	Errors    int            `json:"errors"`
+	Warnings  int            `json:"warnings"`
+}
+
+func LintsmstemplateHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		fields, err := stringList(args["fields"], "fields")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		kind := request.GetString("kind", smstemplate.KindTransactional)
+		maxSegments := request.GetInt("max_segments", 0)
+		body := request.GetString("body", "")
+		id := request.GetString("template_id", "")
+		folder := request.GetBool("folder", false)
+
+		var out templateLintReport
+		switch {
+		case body != "" && (id != "" || folder), id != "" && folder:
+			return mcp.NewToolResultError("Pass one of body, template_id and folder"), nil
+		case body != "":
+			out.Templates = append(out.Templates, lintTemplate(cfg, body, kind, fields, maxSegments))
+		case folder:
+			lib, errResult := templateFolder(cfg)
+			if errResult != nil {
+				return errResult, nil
+			}
+			files, err := smstemplate.ReadDir(lib.Dir)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("The template folder could not be read: %v", err)), nil
+			}
+			for _, f := range files {
+				l := lintTemplate(cfg, f.Body, f.Kind, fields, maxSegments)
+				l.TemplateID, l.Name, l.Path = f.ID, f.Name, f.Path
+				out.Templates = append(out.Templates, l)
+			}
+		default:
+			byID, ids, err := templatesByID(ctx, cfg.Client())
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if id != "" {
+				if _, ok := byID[id]; !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("SMS template %s not found", id)), nil
+				}
+				ids = []string{id}
+			}
+			for _, id := range ids {
+				t := byID[id]
+				l := lintTemplate(cfg, t.Body, kind, fields, maxSegments)
+				l.TemplateID, l.Name = id, t.TemplateName
+				out.Templates = append(out.Templates, l)
+			}
+		}
+		for _, t := range out.Templates {
+			n := lintErrors(t.Issues)
+			out.Errors += n
+			out.Warnings += len(t.Issues) - n
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreateLintsmstemplateTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("lint_sms_template",
+		mcp.WithDescription("Check SMS templates for placeholders that name no contact field, characters outside the GSM-7 alphabet that force UCS-2, a length over the segment limit, and missing opt-out text in marketing messages. Lints body, the ClickSend template template_id, the files of the template folder with folder: true, or every ClickSend template when none is given."),
+		mcp.WithString("body", mcp.Description("Input parameter: A template body to lint.")),
+		mcp.WithString("template_id", mcp.Description("Input parameter: The ClickSend template to lint.")),
+		mcp.WithBoolean("folder", mcp.Description("Input parameter: Lint the files of the template folder, with the kind each file names.")),
+		mcp.WithString("kind", mcp.Enum(smstemplate.KindMarketing, smstemplate.KindTransactional), mcp.DefaultString(smstemplate.KindTransactional), mcp.Description("Input parameter: Lint as marketing messages, which must carry opt-out text such as \"Reply STOP to opt out\", or as transactional ones. Ignored with folder.")),
+		mcp.WithArray("fields", mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Placeholder names allowed besides the contact fields, such as the keys of send_personalized_sms defaults.")),
+		mcp.WithNumber("max_segments", mcp.Description("Input parameter: The most segments a message may take. Defaults to SMS_TEMPLATE_MAX_SEGMENTS.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    LintsmstemplateHandler(cfg),
+	}
+}
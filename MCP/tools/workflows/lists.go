@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// listResult is the result of the list tools before and after their plan
+// is applied.
+type listResult struct {
+	Action     string                  `json:"action"` // "copy" or "move"
+	From       []string                `json:"from_list_ids"`
+	ListID     string                  `json:"list_id,omitempty"`
+	ListName   string                  `json:"list_name,omitempty"`
+	Filter     string                  `json:"filter,omitempty"`
+	Key        string                  `json:"key,omitempty"`
+	Scanned    int                     `json:"scanned"`
+	Count      int                     `json:"count"`
+	Duplicates []contactdata.Duplicate `json:"duplicates,omitempty"`
+	Contacts   []listContact           `json:"contacts,omitempty"`
+	More       int                     `json:"more_contacts,omitempty"`
+	Failed     *int                    `json:"failed,omitempty"`
+	Next       string                  `json:"next,omitempty"`
+}
+
+// listContact is a contact a list tool copies or moves and, once applied,
+// its outcome.
+type listContact struct {
+	ContactID    string            `json:"contact_id"`
+	Fields       map[string]string `json:"fields"`
+	NewContactID string            `json:"new_contact_id,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// listOptions are the arguments shared by the list tools.
+func listOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithNumber("preview_contacts", mcp.DefaultNumber(20), mcp.Description("Input parameter: Number of contacts listed in the preview.")),
+		mcp.WithBoolean("confirm", mcp.Description("Input parameter: Apply the plan. Preview first, then call again with confirm: true.")),
+	}
+}
+
+// listContacts returns every contact of a list.
+func listContacts(ctx context.Context, client *clicksend.Client, listID string) ([]clicksend.Contact, error) {
+	return clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.Contact]], error) {
+		return client.ListContacts(ctx, listID, opts)
+	})
+}
+
+// targetList points out at the list the contacts go to: target_list_id when
+// given, or else the list named out.ListName when one exists, so that a
+// confirm run again after a failure adds to the list the first one created
+// rather than creating another. out.ListID stays empty when the list is to
+// be created. It returns the contacts already in the list.
+func targetList(ctx context.Context, client *clicksend.Client, request mcp.CallToolRequest, out *listResult) ([]clicksend.Contact, error) {
+	if id := request.GetString("target_list_id", ""); id != "" {
+		resp, err := client.GetContactList(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		out.ListID, out.ListName = id, resp.Data.ListName
+	} else {
+		lists, err := clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.ContactList]], error) {
+			return client.ListContactLists(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, l := range lists {
+			if l.ListName == out.ListName {
+				ids = append(ids, fmt.Sprint(l.ListID))
+			}
+		}
+		switch len(ids) {
+		case 0:
+			return nil, nil
+		case 1:
+			out.ListID = ids[0]
+		default:
+			return nil, fmt.Errorf("lists %s are all named %q; name one with target_list_id", strings.Join(ids, ", "), out.ListName)
+		}
+	}
+	return listContacts(ctx, client, out.ListID)
+}
+
+// applyList previews out, or with confirm applies it: it creates the list
+// named out.ListName when out.ListID is empty (see targetList), then copies contacts into the
+// list or, when out.Action is "move", transfers them from fromListID.
+func applyList(ctx context.Context, cfg *config.APIConfig, request mcp.CallToolRequest, out listResult, fromListID string, contacts []clicksend.Contact) (*mcp.CallToolResult, error) {
+	out.Count = len(contacts)
+	items := make([]listContact, len(contacts))
+	for i, c := range contacts {
+		items[i] = listContact{ContactID: fmt.Sprint(c.ContactID), Fields: contactdata.Record(c)}
+	}
+
+	if !request.GetBool("confirm", false) {
+		limit := max(0, request.GetInt("preview_contacts", 20))
+		out.Contacts = items[:min(limit, len(items))]
+		out.More = len(items) - len(out.Contacts)
+		out.Next = fmt.Sprintf("Check the plan, then call %s again with the same arguments and confirm: true to apply it.", request.Params.Name)
+		return models.JSONResult(out)
+	}
+
+	client := cfg.Client()
+	if out.ListID == "" {
+		resp, err := client.CreateContactList(ctx, &clicksend.ContactList{ListName: out.ListName})
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		out.ListID = fmt.Sprint(resp.Data.ListID)
+	}
+	errs := cfg.Bulk.Run(ctx, len(items), func(ctx context.Context, i int) error {
+		if out.Action == "move" {
+			_, err := client.TransferContact(ctx, fromListID, items[i].ContactID, out.ListID)
+			return err
+		}
+		contact := contactdata.Contact(items[i].Fields)
+		resp, err := client.CreateContact(ctx, out.ListID, &contact)
+		if err == nil {
+			items[i].NewContactID = fmt.Sprint(resp.Data.ContactID)
+		}
+		return err
+	})
+	failed := 0
+	for i, err := range errs {
+		items[i].Status = "ok"
+		if err != nil {
+			items[i].Status, items[i].Error = "failed", err.Error()
+			failed++
+		}
+	}
+	out.Failed = &failed
+	out.Contacts = items
+	return models.JSONResult(out)
+}
+
+// action returns the action of the move argument.
+func action(move bool) string {
+	if move {
+		return "move"
+	}
+	return "copy"
+}
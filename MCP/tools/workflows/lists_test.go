@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+func TestMergeSplitSegment(t *testing.T) {
+	api := newFakeAPI(t)
+	a := api.newList(t, "A",
+		clicksend.Contact{PhoneNumber: "+61411111111", FirstName: "Ann", Custom1: "gold", AddressState: "NSW"},
+		clicksend.Contact{PhoneNumber: "+61422222222", FirstName: "Bob", Custom1: "silver", AddressState: "VIC"},
+		clicksend.Contact{PhoneNumber: "+61433333333", FirstName: "Cat", Custom1: "Gold", AddressState: "QLD"},
+	)
+	b := api.newList(t, "B", clicksend.Contact{PhoneNumber: "+61411111111", FirstName: "Ann"})
+	cfg := api.config()
+	merge, split, segment := CreateMergecontactlistsTool(cfg), CreateSplitcontactlistTool(cfg), CreateCreatesegmentlistTool(cfg)
+
+	args := map[string]any{"from_list_id": a, "to_list_id": b}
+	plan := callJSON(t, merge, args)
+	if plan["count"] != 2.0 || len(plan["duplicates"].([]any)) != 1 || len(api.Contacts(b)) != 1 {
+		t.Fatalf("merge plan = %v", plan)
+	}
+	args["confirm"] = true
+	if result := callJSON(t, merge, args); result["failed"] != 0.0 {
+		t.Errorf("merge = %v", result)
+	}
+	if got, want := api.phones(b), []string{"+61411111111", "+61422222222", "+61433333333"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged list = %v, want %v", got, want)
+	}
+
+	result := callJSON(t, split, map[string]any{"list_id": a, "filter": "custom_1 = gold", "new_list_name": "Gold", "confirm": true})
+	gold := fmt.Sprint(result["list_id"])
+	if result["count"] != 2.0 || result["failed"] != 0.0 {
+		t.Errorf("split = %v", result)
+	}
+	if got, want := api.phones(gold), []string{"+61411111111", "+61433333333"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("split list = %v, want %v", got, want)
+	}
+	if got, want := api.phones(a), []string{"+61422222222"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("source list = %v, want %v", got, want)
+	}
+	for _, tt := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"list_id": a, "filter": "first_name = Bob", "new_list_name": "Bobs"}, `"first_name" at position 1 is not a field`},
+		{map[string]any{"list_id": a, "filter": "custom_1 = gold", "new_list_name": "Gold", "confirm": true}, "nothing to split"},
+	} {
+		if res := callTool(t, split, tt.args); !res.IsError || !strings.Contains(resultText(res), tt.want) {
+			t.Errorf("split_contact_list %v = %s, want an error containing %q", tt.args, resultText(res), tt.want)
+		}
+	}
+
+	copied := callJSON(t, split, map[string]any{"list_id": a, "filter": "custom_1 = silver", "target_list_id": gold, "move": false, "confirm": true})
+	if copied["list_id"] != gold || copied["list_name"] != "Gold" || copied["count"] != 1.0 {
+		t.Errorf("split into target_list_id = %v", copied)
+	}
+	if got, want := api.phones(gold), []string{"+61411111111", "+61422222222", "+61433333333"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("target list = %v, want %v", got, want)
+	}
+
+	args = map[string]any{"list_ids": []any{gold, b}, "filter": "address_state in (nsw, qld)", "new_list_name": "North", "confirm": true}
+	result = callJSON(t, segment, args)
+	north := fmt.Sprint(result["list_id"])
+	if result["count"] != 2.0 || len(result["duplicates"].([]any)) != 1 {
+		t.Errorf("segment = %v", result)
+	}
+	if got, want := api.phones(north), []string{"+61411111111", "+61433333333"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("segment list = %v, want %v", got, want)
+	}
+	again := callJSON(t, segment, args)
+	if again["list_id"] != north || again["count"] != 0.0 || len(api.phones(north)) != 2 {
+		t.Errorf("segment run again = %v, want the list reused and nothing added", again)
+	}
+	empty := map[string]any{"list_ids": []any{b}, "filter": "custom_4 = none", "new_list_name": "Empty", "confirm": true}
+	if res := callTool(t, segment, empty); !res.IsError || !strings.Contains(resultText(res), "no segment to create") {
+		t.Errorf("create_segment_list of no contacts = %s, want it refused", resultText(res))
+	}
+}
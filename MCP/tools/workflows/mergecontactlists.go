@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func MergecontactlistsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		fromListID, ok := args["from_list_id"].(string)
+		if !ok || fromListID == "" {
+			return mcp.NewToolResultError("Missing required parameter: from_list_id"), nil
+		}
+		toListID, ok := args["to_list_id"].(string)
+		if !ok || toListID == "" {
+			return mcp.NewToolResultError("Missing required parameter: to_list_id"), nil
+		}
+		if fromListID == toListID {
+			return mcp.NewToolResultError("from_list_id and to_list_id are the same list; use put_lists_list_id_remove-duplicates to de-duplicate one list"), nil
+		}
+		key := request.GetString("key", "phone_number")
+		if key != "phone_number" && key != "email" {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid key %q: use phone_number or email", key)), nil
+		}
+
+		client := cfg.Client()
+		from, err := listContacts(ctx, client, fromListID)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		into, err := listContacts(ctx, client, toListID)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		add, dups, err := contactdata.Merge(from, into, key, cfg.PhoneCountry)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out := listResult{
+			Action:     action(request.GetBool("move", false)),
+			From:       []string{fromListID},
+			ListID:     toListID,
+			Key:        key,
+			Scanned:    len(from),
+			Duplicates: dups,
+		}
+		return applyList(ctx, cfg, request, out, fromListID, add)
+	}
+}
+
+func CreateMergecontactlistsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("merge_contact_lists", append([]mcp.ToolOption{
+		mcp.WithDescription("Merge one contact list into another without creating duplicates. Contacts of the source list whose phone number or email is already in the target list, or repeats an earlier one, are left out and reported. Without confirm, returns the plan; with confirm: true, copies (or with move, transfers) the other contacts with the server's bulk worker pool and reports the result of each."),
+		mcp.WithString("from_list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id to merge from.")),
+		mcp.WithString("to_list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id to merge into.")),
+		mcp.WithString("key", mcp.Enum("phone_number", "email"), mcp.DefaultString("phone_number"), mcp.Description("Input parameter: Contact field that identifies duplicates.")),
+		mcp.WithBoolean("move", mcp.DefaultBool(false), mcp.Description("Input parameter: Transfer the contacts out of the source list rather than copy them. Duplicates stay in the source list.")),
+	}, listOptions()...)...)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    MergecontactlistsHandler(cfg),
+	}
+}
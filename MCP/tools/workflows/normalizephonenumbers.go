@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// normalizedNumber is one number in the result of normalize_phone_numbers.
+type normalizedNumber struct {
+	Input string `json:"input"`
+	phone.Number
+	Warning string `json:"warning,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func NormalizephonenumbersHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		numbers, ok := args["numbers"].([]any)
+		if !ok {
+			return mcp.NewToolResultError("Missing required parameter: numbers"), nil
+		}
+		country := request.GetString("country", cfg.PhoneCountry)
+		if country != "" && phone.Lookup(country) == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid country %q: use one of %s", country, strings.Join(phone.Codes(), ", "))), nil
+		}
+		channel := request.GetString("channel", "")
+
+		out := make([]normalizedNumber, len(numbers))
+		for i, v := range numbers {
+			s, _ := v.(string)
+			out[i].Input = s
+			n, err := phone.Parse(s, country)
+			switch {
+			case errors.Is(err, phone.ErrNoCountry):
+				out[i].Error = "local number: pass country or use the international format"
+			case err != nil:
+				out[i].Error = err.Error()
+			default:
+				out[i].Number = n
+				out[i].Warning = n.Mismatch(channel)
+			}
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreateNormalizephonenumbersTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("normalize_phone_numbers",
+		mcp.WithDescription("Normalize phone or fax numbers to E.164 and check them offline. Returns each number's E.164 form, country and line type (mobile, landline, toll_free or unknown), a warning when it does not suit the channel, or why it is invalid. Send and contact tools apply the same normalization themselves."),
+		mcp.WithArray("numbers", mcp.Required(), mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Numbers in international or local format.")),
+		mcp.WithString("country", mcp.Description("Input parameter: ISO code or name of the country of local numbers, as get_countries lists them. Defaults to the server's default country.")),
+		mcp.WithString("channel", mcp.Enum("sms", "mms", "voice", "fax"), mcp.Description("Input parameter: Channel the numbers will be used on, to warn about numbers that cannot receive it.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    NormalizephonenumbersHandler(cfg),
+	}
+}
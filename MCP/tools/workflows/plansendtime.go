@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/sendtime"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sendTimePlan is the result of plan_send_time.
+type sendTimePlan struct {
+	When       string            `json:"when"`
+	QuietHours string            `json:"quiet_hours,omitempty"`
+	Requested  time.Time         `json:"requested"`
+	SendAt     time.Time         `json:"send_at"`
+	Schedule   string            `json:"schedule"` // unix seconds, as the send tools take it
+	Shifted    bool              `json:"shifted"`
+	LocalTimes map[string]string `json:"local_times"` // zone to the local time of SendAt
+	Recipients []plannedNumber   `json:"recipients,omitempty"`
+	Warnings   []string          `json:"warnings,omitempty"`
+}
+
+// plannedNumber is a number passed to plan_send_time and its timezones.
+type plannedNumber struct {
+	Number string   `json:"number"`
+	Zones  []string `json:"zones,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// sendPolicy returns the send time policy of cfg, or one without quiet
+// hours reading times in UTC when it has none.
+func sendPolicy(cfg *config.APIConfig) *sendtime.Policy {
+	if cfg.SendTime == nil {
+		return &sendtime.Policy{Zone: time.UTC}
+	}
+	return cfg.SendTime
+}
+
+func PlansendtimeHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		p := sendPolicy(cfg)
+		out := sendTimePlan{When: request.GetString("when", "now"), LocalTimes: map[string]string{}}
+		if p.Quiet != nil {
+			out.QuietHours = p.Quiet.String()
+		}
+
+		var zones []*time.Location
+		add := func(locs []*time.Location) {
+			for _, loc := range locs {
+				if !slices.ContainsFunc(zones, func(z *time.Location) bool { return z.String() == loc.String() }) {
+					zones = append(zones, loc)
+				}
+			}
+		}
+		unknown := 0
+		if name := request.GetString("timezone", ""); name != "" {
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone %q: want an IANA timezone such as Australia/Perth", name)), nil
+			}
+			add([]*time.Location{loc})
+		} else {
+			numbers, _ := args["numbers"].([]any)
+			for _, v := range numbers {
+				s, _ := v.(string)
+				r := plannedNumber{Number: s}
+				n, err := phone.Parse(s, request.GetString("country", cfg.PhoneCountry))
+				switch {
+				case err != nil:
+					r.Error = err.Error()
+					unknown++
+				case sendtime.Zones(n) == nil:
+					r.Error = "timezone not known for country " + n.Country
+					unknown++
+				default:
+					for _, z := range sendtime.Zones(n) {
+						r.Zones = append(r.Zones, z.String())
+					}
+					add(sendtime.Zones(n))
+				}
+				out.Recipients = append(out.Recipients, r)
+			}
+			if listID := request.GetString("list_id", ""); listID != "" {
+				contacts, err := listContacts(ctx, cfg.Client(), listID)
+				if err != nil {
+					return models.ErrorResult(err), nil
+				}
+				for _, c := range contacts {
+					if locs := sendtime.ContactZones(c); locs != nil {
+						add(locs)
+					} else {
+						unknown++
+					}
+				}
+			}
+		}
+		if unknown > 0 {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%d recipients have no known timezone, so %s was used for them; pass timezone to name it.", unknown, p.Zone))
+			add([]*time.Location{p.Zone})
+		}
+		if len(zones) == 0 {
+			add([]*time.Location{p.Zone})
+		}
+
+		plan, err := p.Resolve(out.When, zones)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid when: %v", err)), nil
+		}
+		out.Requested, out.SendAt, out.Shifted = plan.Requested, plan.At, plan.Shifted
+		out.Schedule = fmt.Sprint(plan.At.Unix())
+		for _, z := range zones {
+			out.LocalTimes[z.String()] = plan.At.In(z).Format("Mon 2006-01-02 15:04 MST")
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreatePlansendtimeTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("plan_send_time",
+		mcp.WithDescription("Work out when a send would go out without sending it. Reads a natural time such as \"tomorrow 9am recipient-local\" in the timezones of the recipients' numbers or list contacts, moves it past the configured quiet hours in every one of them, and returns the unix schedule the send tools take with the local time in each timezone. The send tools resolve their schedule the same way themselves."),
+		mcp.WithString("when", mcp.DefaultString("now"), mcp.Description("Input parameter: Unix seconds, RFC 3339, \"now\", \"in 2 hours\", or a day and time such as \"friday 17:00\" followed by recipient-local, utc or an IANA timezone.")),
+		mcp.WithArray("numbers", mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Recipient numbers in E.164 or local format.")),
+		mcp.WithString("list_id", mcp.Description("Input parameter: Contact list whose contacts are the recipients.")),
+		mcp.WithString("country", mcp.Description("Input parameter: ISO country code of local numbers; defaults to the server's default country.")),
+		mcp.WithString("timezone", mcp.Description("Input parameter: IANA timezone of every recipient, such as Australia/Perth, instead of reading it from their numbers.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    PlansendtimeHandler(cfg),
+	}
+}
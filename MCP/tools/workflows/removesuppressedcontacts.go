@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// suppressedContact is a contact remove_suppressed_contacts deletes.
+type suppressedContact struct {
+	ContactID string            `json:"contact_id"`
+	Entry     suppression.Entry `json:"suppressed"`
+}
+
+func RemovesuppressedcontactsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r, errResult := suppressionRegistry(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+
+		client := cfg.Client()
+		if err := r.Refresh(ctx, client); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("The suppression list could not be refreshed: %v", err)), nil
+		}
+		contacts, err := listContacts(ctx, client, listID)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		var found []suppressedContact
+		for _, c := range contacts {
+			if e, ok := r.LookupContact(c); ok {
+				found = append(found, suppressedContact{ContactID: fmt.Sprint(c.ContactID), Entry: e})
+			}
+		}
+
+		if !request.GetBool("confirm", false) {
+			return models.JSONResult(map[string]any{
+				"list_id":  listID,
+				"scanned":  len(contacts),
+				"count":    len(found),
+				"contacts": found,
+				"next":     "Check the contacts, then call remove_suppressed_contacts again with confirm: true to delete them.",
+			})
+		}
+		return runBulk(ctx, cfg, listID, len(found), func(ctx context.Context, i int) (string, error) {
+			_, err := client.DeleteContact(ctx, listID, found[i].ContactID)
+			return found[i].ContactID, err
+		})
+	}
+}
+
+func CreateRemovesuppressedcontactsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("remove_suppressed_contacts",
+		mcp.WithDescription("Delete the contacts of a list whose phone number or email is on the suppression list, so the list can be sent to. Unlike put_lists_list_id_remove-opted-out-contacts_opt_out_list_id, this covers keyword opt-outs such as STOP replies and manual suppressions too. Without confirm, lists the contacts; with confirm: true, deletes them with the server's bulk worker pool."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id.")),
+		mcp.WithBoolean("confirm", mcp.Description("Input parameter: Delete the contacts. Preview first, then call again with confirm: true.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    RemovesuppressedcontactsHandler(cfg),
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateRollback is the result of rollback_sms_template.
+type templateRollback struct {
+	TemplateID    string              `json:"template_id"`
+	Version       int                 `json:"version"`
+	TemplateName  string              `json:"template_name"`
+	Body          string              `json:"body"`
+	Diff          string              `json:"diff"` // from the current body
+	Issues        []smstemplate.Issue `json:"issues,omitempty"`
+	Recreate      bool                `json:"recreate,omitempty"` // the template was deleted
+	NewTemplateID string              `json:"new_template_id,omitempty"`
+	Recorded      int                 `json:"recorded_version,omitempty"`
+	Next          string              `json:"next,omitempty"`
+}
+
+func RollbacksmstemplateHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lib, errResult := templateLibrary(cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		id, err := request.RequireString("template_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		account, errResult := templateAccount(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		n, err := request.RequireInt("version")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		client := cfg.Client()
+		if err := lib.Baseline(ctx, client, account, id); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("SMS template %s could not be read: %v", id, err)), nil
+		}
+		target, ok := lib.History.Get(account, id, n)
+		if !ok || target.Action == smstemplate.ActionDelete {
+			return mcp.NewToolResultError(fmt.Sprintf("Template %s has no version %d to roll back to; see get_sms_template_history", id, n)), nil
+		}
+
+		out := templateRollback{TemplateID: id, Version: n, TemplateName: target.Name, Body: target.Body}
+		current, exists, err := smstemplate.Get(ctx, client, id)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("SMS template %s could not be read: %v", id, err)), nil
+		}
+		out.Recreate = !exists
+		out.Diff = smstemplate.Diff(current.Body, target.Body)
+		out.Issues = smstemplate.Lint(target.Body, smstemplate.LintOptions{MaxSegments: lib.MaxSegments})
+		if exists && current.Body == target.Body && current.TemplateName == target.Name {
+			out.Next = "The template already has this name and body; nothing to roll back."
+			return models.JSONResult(out)
+		}
+		if !request.GetBool("confirm", false) {
+			action := "update the template"
+			if out.Recreate {
+				action = "create the deleted template again, under a new id"
+			}
+			out.Next = fmt.Sprintf("Check the diff, then call %s again with the same arguments and confirm: true to %s.", request.Params.Name, action)
+			return models.JSONResult(out)
+		}
+
+		saved := &clicksend.SmsTemplate{TemplateName: target.Name, Body: target.Body}
+		if out.Recreate {
+			resp, err := client.CreateSmsTemplate(ctx, saved)
+			if err != nil {
+				return models.ErrorResult(err), nil
+			}
+			id = templateID(resp.Data)
+			out.NewTemplateID = id
+		} else if _, err := client.UpdateSmsTemplate(ctx, id, saved); err != nil {
+			return models.ErrorResult(err), nil
+		}
+		v, err := lib.History.Record(account, id, target.Name, target.Body, smstemplate.ActionRollback)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Template %s was rolled back, but the history could not be saved: %v", id, err)), nil
+		}
+		out.Recorded = v.Version
+		return models.JSONResult(out)
+	}
+}
+
+func CreateRollbacksmstemplateTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("rollback_sms_template",
+		mcp.WithDescription("Restore the name and body an SMS template had at a version of get_sms_template_history. A deleted template is created again under a new id. Only versions recorded under the account the call acts as can be restored. Without confirm, shows the diff from the current body and what lint finds; with confirm: true, saves it."),
+		mcp.WithString("template_id", mcp.Required(), mcp.Description("Input parameter: The template.")),
+		mcp.WithNumber("version", mcp.Required(), mcp.Description("Input parameter: The version to restore.")),
+		mcp.WithBoolean("confirm", mcp.DefaultBool(false), mcp.Description("Input parameter: Save the version. Without it, only previews it.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    RollbacksmstemplateHandler(cfg),
+	}
+}
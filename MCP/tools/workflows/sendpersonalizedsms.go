@@ -0,0 +1,394 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/phone"
+	"github.com/clicksend-rest-api-v3/mcp-server/sendtime"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxSendBatch is the most messages sent in one /sms/send call.
+const maxSendBatch = 1000
+
+// personalizedMessage is the message of one recipient of
+// send_personalized_sms.
+type personalizedMessage struct {
+	Index     int    `json:"index"`
+	ContactID string `json:"contact_id,omitempty"`
+	To        string `json:"to,omitempty"`
+	Message   string `json:"message,omitempty"`
+	*smstemplate.Length
+	Missing   []string `json:"missing,omitempty"`
+	Schedule  string   `json:"schedule,omitempty"` // unix seconds; empty sends at once
+	Status    string   `json:"status"`             // ready, skipped, sent or failed
+	Reason    string   `json:"reason,omitempty"`   // why it was skipped or failed
+	MessageID string   `json:"message_id,omitempty"`
+
+	zones []*time.Location
+	data  map[string]string
+}
+
+// personalizedSend is the result of send_personalized_sms.
+type personalizedSend struct {
+	Template    string                `json:"template"`
+	Fields      []string              `json:"fields"`
+	Recipients  int                   `json:"recipients"`
+	Ready       int                   `json:"ready"`
+	Skipped     int                   `json:"skipped"`
+	Sent        int                   `json:"sent,omitempty"`
+	Failed      int                   `json:"failed,omitempty"`
+	Segments    int                   `json:"segments"`     // total of the messages to send
+	MaxSegments int                   `json:"max_segments"` // of the longest message
+	UCS2        int                   `json:"ucs2_messages"`
+	TotalPrice  float64               `json:"total_price,omitempty"`
+	Warnings    []string              `json:"warnings,omitempty"`
+	Messages    []personalizedMessage `json:"messages"`
+	More        int                   `json:"more,omitempty"`
+	Next        string                `json:"next,omitempty"`
+}
+
+func SendpersonalizedsmsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		client := cfg.Client()
+		body, err := templateBody(ctx, client, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		tmpl, err := smstemplate.Parse(body)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid template: %v", err)), nil
+		}
+		defaults, err := stringMap(args["defaults"], "defaults")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		onMissing := request.GetString("on_missing", "skip")
+		if onMissing != "skip" && onMissing != "send" {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid on_missing %q: use skip or send", onMissing)), nil
+		}
+
+		messages, err := personalizedRecipients(ctx, cfg, client, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out := personalizedSend{Template: body, Fields: tmpl.Fields(), Recipients: len(messages)}
+
+		var suppressed *suppression.Registry
+		if cfg.Suppression != nil {
+			r, errResult := suppressionRegistry(ctx, cfg)
+			if errResult != nil {
+				return errResult, nil
+			}
+			if err := r.RefreshIfStale(ctx, client); err != nil {
+				if !cfg.Suppression.SendOnError() {
+					return mcp.NewToolResultError(fmt.Sprintf("The suppression list could not be refreshed, so recent opt-outs could be missed: %v. Try again, or set SUPPRESSION_ON_ERROR=send to send anyway.", err)), nil
+				}
+				out.Warnings = append(out.Warnings, fmt.Sprintf("The suppression list could not be refreshed, so recent opt-outs may be missed: %v", err))
+			}
+			suppressed = r
+		}
+		policy := sendPolicy(cfg)
+		schedule := request.GetString("schedule", "")
+		maxSegments := request.GetInt("max_segments", 0)
+		seen := map[string]int{}
+		unknownZones := 0
+		for i := range messages {
+			m := &messages[i]
+			if m.Status == "skipped" {
+				continue
+			}
+			if first, ok := seen[m.To]; ok {
+				m.Status, m.Reason = "skipped", fmt.Sprintf("duplicate of messages[%d]", first)
+				continue
+			}
+			seen[m.To] = i
+			if suppressed != nil {
+				key := suppressionKey(cfg, suppressed, m.To)
+				if key == "" {
+					m.Status, m.Reason = "skipped", "a local number of no known country, so it cannot be checked against the suppression list"
+					continue
+				}
+				if e, ok := suppressed.Lookup(key); ok {
+					m.Status, m.Reason = "skipped", fmt.Sprintf("suppressed (%s)", e.Source)
+					continue
+				}
+			}
+
+			text, missing := tmpl.Render(m.data, defaults)
+			length := smstemplate.Measure(text)
+			m.Message, m.Length, m.Missing = text, &length, missing
+			switch {
+			case len(missing) > 0 && onMissing == "skip":
+				m.Status, m.Reason = "skipped", "no value for "+strings.Join(missing, ", ")
+				continue
+			case strings.TrimSpace(text) == "":
+				m.Status, m.Reason = "skipped", "empty message"
+				continue
+			case maxSegments > 0 && length.Segments > maxSegments:
+				m.Status, m.Reason = "skipped", fmt.Sprintf("%d segments, over max_segments %d", length.Segments, maxSegments)
+				continue
+			}
+
+			if schedule != "" || policy.Quiet != nil {
+				if m.zones == nil {
+					unknownZones++
+				}
+				plan, err := policy.Resolve(schedule, m.zones)
+				if err != nil {
+					m.Status, m.Reason = "skipped", "schedule: "+err.Error()
+					continue
+				}
+				if schedule != "" || plan.Shifted {
+					m.Schedule = fmt.Sprint(plan.At.Unix())
+				}
+			}
+			m.Status = "ready"
+		}
+		if unknownZones > 0 {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%d recipients have no known timezone, so %s was used for their schedule.", unknownZones, policy.Zone))
+		}
+
+		var ready []int
+		for i, m := range messages {
+			if m.Status != "ready" {
+				out.Skipped++
+				continue
+			}
+			ready = append(ready, i)
+			out.Segments += m.Segments
+			out.MaxSegments = max(out.MaxSegments, m.Segments)
+			if m.Encoding == smstemplate.UCS2 {
+				out.UCS2++
+			}
+		}
+		out.Ready = len(ready)
+
+		if !request.GetBool("confirm", false) {
+			limit := max(0, request.GetInt("preview_messages", 20))
+			out.Messages = messages[:min(limit, len(messages))]
+			out.More = len(messages) - len(out.Messages)
+			out.Next = fmt.Sprintf("Check the messages, then call %s again with the same arguments and confirm: true to send the %d ready messages.", request.Params.Name, out.Ready)
+			return models.JSONResult(out)
+		}
+		if len(ready) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Not sent: none of the %d recipients has a message ready; call without confirm to see why.", len(messages))), nil
+		}
+
+		from := request.GetString("from", "")
+		batches := (len(ready) + maxSendBatch - 1) / maxSendBatch
+		prices := make([]float64, batches)
+		errs := cfg.Bulk.Run(ctx, batches, func(ctx context.Context, b int) error {
+			batch := ready[b*maxSendBatch : min(len(ready), (b+1)*maxSendBatch)]
+			req := &clicksend.SmsMessageCollection{Messages: make([]clicksend.SmsMessage, len(batch))}
+			for j, i := range batch {
+				m := messages[i]
+				req.Messages[j] = clicksend.SmsMessage{To: m.To, Body: m.Message, From: from, CustomString: m.ContactID}
+				if at, err := strconv.ParseInt(m.Schedule, 10, 64); err == nil {
+					req.Messages[j].Schedule = clicksend.Int(at)
+				}
+			}
+			resp, err := client.SendSms(ctx, req)
+			if err != nil {
+				return err
+			}
+			prices[b] = float64(resp.Data.TotalPrice)
+			for j, i := range batch {
+				m := &messages[i]
+				if j >= len(resp.Data.Messages) {
+					m.Status, m.Reason = "failed", "no result returned"
+					continue
+				}
+				sent := resp.Data.Messages[j]
+				m.MessageID = sent.MessageID
+				if m.Status = "sent"; sent.Status != "SUCCESS" {
+					m.Status, m.Reason = "failed", sent.Status
+				}
+			}
+			return nil
+		})
+		for b, err := range errs {
+			if err == nil {
+				continue
+			}
+			for _, i := range ready[b*maxSendBatch : min(len(ready), (b+1)*maxSendBatch)] {
+				messages[i].Status, messages[i].Reason = "failed", err.Error()
+			}
+		}
+		for i := range messages {
+			messages[i].Message = ""
+			switch messages[i].Status {
+			case "sent":
+				out.Sent++
+			case "failed":
+				out.Failed++
+			}
+		}
+		for _, p := range prices {
+			out.TotalPrice += p
+		}
+		out.Messages = messages
+		return models.JSONResult(out)
+	}
+}
+
+// templateBody returns the body argument, or the body of the SMS template
+// named by template_id.
+func templateBody(ctx context.Context, client *clicksend.Client, request mcp.CallToolRequest) (string, error) {
+	body := request.GetString("body", "")
+	id := request.GetString("template_id", "")
+	switch {
+	case body != "" && id != "":
+		return "", errors.New("Pass body or template_id, not both")
+	case body != "":
+		return body, nil
+	case id == "":
+		return "", errors.New("Missing required parameter: body or template_id")
+	}
+	templates, err := clicksend.All(ctx, client.ListSmsTemplates)
+	if err != nil {
+		return "", fmt.Errorf("SMS templates could not be listed: %w", err)
+	}
+	for _, t := range templates {
+		if fmt.Sprint(t.TemplateID) == id {
+			return t.Body, nil
+		}
+	}
+	return "", fmt.Errorf("SMS template %s not found", id)
+}
+
+// personalizedRecipients returns a message for each contact of list_id or
+// each of recipients, with its number normalized to E.164 where its
+// country is known. Recipients without a usable number are skipped.
+func personalizedRecipients(ctx context.Context, cfg *config.APIConfig, client *clicksend.Client, request mcp.CallToolRequest) ([]personalizedMessage, error) {
+	args := request.GetArguments()
+	listID := request.GetString("list_id", "")
+	_, hasRecipients := args["recipients"]
+	switch {
+	case listID != "" && hasRecipients:
+		return nil, errors.New("Pass list_id or recipients, not both")
+	case listID != "":
+		contacts, err := listContacts(ctx, client, listID)
+		if err != nil {
+			return nil, fmt.Errorf("list %s could not be read: %w", listID, err)
+		}
+		out := make([]personalizedMessage, len(contacts))
+		for i, c := range contacts {
+			out[i] = personalizedMessage{Index: i, ContactID: fmt.Sprint(c.ContactID), data: contactdata.Record(c)}
+			setNumber(&out[i], c.PhoneNumber, c.AddressCountry, cfg.PhoneCountry)
+			if out[i].Status == "" {
+				out[i].zones = sendtime.ContactZones(c)
+			}
+		}
+		return out, nil
+	case !hasRecipients:
+		return nil, errors.New("Missing required parameter: list_id or recipients")
+	}
+
+	items, err := bulkItems(args, "recipients")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]personalizedMessage, len(items))
+	for i, item := range items {
+		data, err := stringMap(item, fmt.Sprintf("recipients[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		to := data["to"]
+		if to == "" {
+			to = data["phone_number"]
+		}
+		out[i] = personalizedMessage{Index: i, data: data}
+		setNumber(&out[i], to, data["address_country"], cfg.PhoneCountry)
+		if out[i].Status == "" {
+			if n, err := phone.Parse(out[i].To, ""); err == nil {
+				out[i].zones = sendtime.Zones(n)
+			}
+		}
+	}
+	return out, nil
+}
+
+// setNumber sets the number of m, normalized to E.164 in country or else
+// fallback, or skips m when it has none or it is impossible.
+func setNumber(m *personalizedMessage, number, country, fallback string) {
+	if strings.TrimSpace(number) == "" {
+		m.Status, m.Reason = "skipped", "no phone number"
+		return
+	}
+	if country == "" || phone.Lookup(country) == nil {
+		country = fallback
+	}
+	n, err := phone.Parse(number, country)
+	switch {
+	case errors.Is(err, phone.ErrNoCountry), errors.Is(err, phone.ErrUnknownCountry):
+		m.To = number
+	case err != nil:
+		m.To, m.Status, m.Reason = number, "skipped", err.Error()
+	default:
+		m.To = n.E164
+	}
+}
+
+// stringMap reads an object argument of string values. Numbers and booleans
+// are formatted; a missing argument is an empty map.
+func stringMap(v any, name string) (map[string]string, error) {
+	if v == nil {
+		return map[string]string{}, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid %s: want an object of field values", name)
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case string:
+			out[k] = v
+		case float64, bool:
+			out[k] = fmt.Sprint(v)
+		case nil:
+		default:
+			return nil, fmt.Errorf("Invalid %s.%s: want a string", name, k)
+		}
+	}
+	return out, nil
+}
+
+func CreateSendpersonalizedsmsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("send_personalized_sms",
+		mcp.WithDescription("Send one personalized SMS to each contact of a list, or to each of the given recipients, by rendering a template against their fields. Placeholders such as {first_name} or {custom_1} take the contact's value, and {first_name|there} falls back to the text after the bar when the contact has none. Without confirm, previews each rendered message with its encoding and segment count and the total segments; with confirm: true, sends them through /sms/send. Suppressed recipients, duplicate numbers and recipients missing a field without fallback are skipped."),
+		mcp.WithString("body", mcp.Description("Input parameter: The template text. Use {{ and }} for literal braces.")),
+		mcp.WithString("template_id", mcp.Description("Input parameter: An SMS template to use instead of body.")),
+		mcp.WithString("list_id", mcp.Description("Input parameter: Contact list whose contacts are the recipients.")),
+		mcp.WithArray("recipients", mcp.MaxItems(maxBulkItems), mcp.Items(map[string]any{"type": "object"}), mcp.Description("Input parameter: Recipients instead of list_id, each an object with the number in to or phone_number and the values of the template's fields, such as {\"to\": \"+61411111111\", \"first_name\": \"Ann\"}.")),
+		mcp.WithObject("defaults", mcp.Description("Input parameter: Values of fields for recipients who have none, used when a placeholder gives no fallback of its own.")),
+		mcp.WithString("on_missing", mcp.Enum("skip", "send"), mcp.DefaultString("skip"), mcp.Description("Input parameter: Skip recipients missing a field that has no fallback or default, or send their message with the field left empty.")),
+		mcp.WithNumber("max_segments", mcp.Description("Input parameter: Skip recipients whose message would take more SMS segments than this.")),
+		mcp.WithString("from", mcp.Description("Input parameter: Your sender id.")),
+		mcp.WithString("schedule", mcp.Description("Input parameter: Leave blank to send at once. Unix seconds, RFC 3339 or a natural time such as \"tomorrow 9am recipient-local\", resolved for each recipient.")),
+		mcp.WithNumber("preview_messages", mcp.DefaultNumber(20), mcp.Description("Input parameter: How many rendered messages to show without confirm.")),
+		mcp.WithBoolean("confirm", mcp.DefaultBool(false), mcp.Description("Input parameter: Send the messages. Without it, only previews them.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SendpersonalizedsmsHandler(cfg),
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+)
+
+func TestSendPersonalizedSms(t *testing.T) {
+	api := newFakeAPI(t)
+	listID := api.newList(t, "Customers",
+		clicksend.Contact{PhoneNumber: "0411 111 111", FirstName: "Ann", Custom1: "A-100"},
+		clicksend.Contact{PhoneNumber: "+61422222222", Custom1: "B-200"},
+		clicksend.Contact{PhoneNumber: "+61433333333", FirstName: "Cat"},
+		clicksend.Contact{PhoneNumber: "+61444444444", FirstName: "Dan", Custom1: "D-400"},
+		clicksend.Contact{Email: "eve@example.com", FirstName: "Eve"},
+	)
+	template, err := api.cs.CreateSmsTemplate(context.Background(), &clicksend.SmsTemplate{TemplateName: "Pickup", Body: "Hi {first_name|there}, order {custom_1} is ready"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := api.config()
+	cfg.Suppression = suppression.New(suppression.DefaultKeywords, nil, time.Hour)
+	cfg.Suppression.For(clicksendtest.Username).Add("+61444444444", "")
+	tool := CreateSendpersonalizedsmsTool(cfg)
+	args := map[string]any{"template_id": fmt.Sprint(template.Data.TemplateID), "list_id": listID}
+
+	preview := callJSON(t, tool, args)
+	statuses := map[string]string{}
+	for _, m := range preview["messages"].([]any) {
+		m := m.(map[string]any)
+		statuses[fmt.Sprint(m["to"], " ", m["message"])] = fmt.Sprint(m["status"], " ", m["reason"])
+	}
+	want := map[string]string{
+		"+61411111111 Hi Ann, order A-100 is ready":   "ready <nil>",
+		"+61422222222 Hi there, order B-200 is ready": "ready <nil>",
+		"+61433333333 Hi Cat, order  is ready":        "skipped no value for custom_1",
+		"+61444444444 <nil>":                          "skipped suppressed (manual)",
+		"<nil> <nil>":                                 "skipped no phone number",
+	}
+	if !reflect.DeepEqual(statuses, want) || preview["ready"] != 2.0 || preview["segments"] != 2.0 {
+		t.Errorf("preview = %v, want %v", statuses, want)
+	}
+	if sent := api.Messages("sms"); len(sent) != 0 {
+		t.Fatalf("preview sent %v", sent)
+	}
+
+	args["confirm"] = true
+	result := callJSON(t, tool, args)
+	if result["sent"] != 2.0 || result["skipped"] != 3.0 {
+		t.Errorf("send = %v, want 2 sent and 3 skipped", result)
+	}
+	var bodies []string
+	for _, m := range api.Messages("sms") {
+		bodies = append(bodies, fmt.Sprint(m["to"], ": ", m["body"]))
+	}
+	slices.Sort(bodies)
+	if want := []string{"+61411111111: Hi Ann, order A-100 is ready", "+61422222222: Hi there, order B-200 is ready"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("sent %v, want %v", bodies, want)
+	}
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/smstemplate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// templateLint is a template and what lint found in it.
+type templateLint struct {
+	TemplateID string              `json:"template_id,omitempty"`
+	Name       string              `json:"template_name,omitempty"`
+	Path       string              `json:"path,omitempty"`
+	Kind       string              `json:"kind"`
+	Length     smstemplate.Length  `json:"length"` // of the text without fields
+	Issues     []smstemplate.Issue `json:"issues,omitempty"`
+}
+
+// lintTemplate lints a body with the segment limit of cfg unless
+// maxSegments is set.
+func lintTemplate(cfg *config.APIConfig, body, kind string, fields []string, maxSegments int) templateLint {
+	if maxSegments == 0 && cfg.Templates != nil {
+		maxSegments = cfg.Templates.MaxSegments
+	}
+	out := templateLint{Kind: kind, Issues: smstemplate.Lint(body, smstemplate.LintOptions{
+		Fields:      fields,
+		MaxSegments: maxSegments,
+		Marketing:   kind == smstemplate.KindMarketing,
+	})}
+	if t, err := smstemplate.Parse(body); err == nil {
+		shortest, _ := t.Render(nil, nil)
+		out.Length = smstemplate.Measure(shortest)
+	}
+	return out
+}
+
+// lintErrors counts the issues of severity error.
+func lintErrors(issues []smstemplate.Issue) int {
+	n := 0
+	for _, i := range issues {
+		if i.Severity == smstemplate.SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// templateLibrary returns the library of cfg, or an error result when the
+// template tools are off.
+func templateLibrary(cfg *config.APIConfig) (*smstemplate.Library, *mcp.CallToolResult) {
+	if cfg.Templates == nil {
+		return nil, mcp.NewToolResultError("The SMS template library is off")
+	}
+	return cfg.Templates, nil
+}
+
+// templateAccount returns the account a call acts as, which its template
+// history is kept under, or an error result.
+func templateAccount(ctx context.Context, cfg *config.APIConfig) (string, *mcp.CallToolResult) {
+	account, err := cfg.Account(ctx)
+	if err != nil {
+		return "", mcp.NewToolResultError(fmt.Sprintf("The account of the call could not be found: %v", err))
+	}
+	return account, nil
+}
+
+// templateFolder returns the library of cfg, or an error result when it
+// has no folder.
+func templateFolder(cfg *config.APIConfig) (*smstemplate.Library, *mcp.CallToolResult) {
+	lib, errResult := templateLibrary(cfg)
+	if errResult == nil && lib.Dir == "" {
+		return nil, mcp.NewToolResultError("No template folder is set; set SMS_TEMPLATE_DIR, or templates.dir in the configuration file, to the folder of template files")
+	}
+	return lib, errResult
+}
+
+// templatesByID lists the SMS templates by id.
+func templatesByID(ctx context.Context, client *clicksend.Client) (map[string]clicksend.SmsTemplate, []string, error) {
+	templates, err := clicksend.All(ctx, client.ListSmsTemplates)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SMS templates could not be listed: %w", err)
+	}
+	byID := make(map[string]clicksend.SmsTemplate, len(templates))
+	ids := make([]string, 0, len(templates))
+	for _, t := range templates {
+		id := templateID(t)
+		byID[id] = t
+		ids = append(ids, id)
+	}
+	return byID, ids, nil
+}
+
+func templateID(t clicksend.SmsTemplate) string {
+	return strconv.FormatInt(int64(t.TemplateID), 10)
+}
+
+// stringList reads an optional array of strings.
+func stringList(v any, name string) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid %s: want an array of strings", name)
+	}
+	out := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("Invalid %s[%d]: want a string", name, i)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
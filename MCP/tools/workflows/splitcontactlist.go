@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// splitFields are the fields a split_contact_list filter can read.
+var splitFields = func() []clicksend.ImportField {
+	var fields []clicksend.ImportField
+	for _, f := range contactdata.ContactFields {
+		if strings.HasPrefix(f.Field, "custom_") || strings.HasPrefix(f.Field, "address_") {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}()
+
+func SplitcontactlistHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		expr, ok := args["filter"].(string)
+		if !ok || expr == "" {
+			return mcp.NewToolResultError("Missing required parameter: filter"), nil
+		}
+		name := request.GetString("new_list_name", "")
+		if name == "" && request.GetString("target_list_id", "") == "" {
+			return mcp.NewToolResultError("Missing required parameter: new_list_name or target_list_id"), nil
+		}
+		if request.GetString("target_list_id", "") == listID {
+			return mcp.NewToolResultError("target_list_id is the list being split; name another list"), nil
+		}
+		filter, err := contactdata.ParseFilter(expr, splitFields)
+		if err != nil {
+			return mcp.NewToolResultError("Invalid filter: " + err.Error()), nil
+		}
+
+		client := cfg.Client()
+		contacts, err := listContacts(ctx, client, listID)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		var matched []clicksend.Contact
+		for _, c := range contacts {
+			if filter.Match(contactdata.Record(c)) {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("No contact of list %s matches %s, so there is nothing to split", listID, filter)), nil
+		}
+		out := listResult{
+			Action:   action(request.GetBool("move", true)),
+			From:     []string{listID},
+			ListName: name,
+			Filter:   filter.String(),
+			Key:      "phone_number",
+			Scanned:  len(contacts),
+		}
+		into, err := targetList(ctx, client, request, &out)
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		// Contacts an earlier run already put in the list are left out
+		add, dups, err := contactdata.Merge(matched, into, out.Key, cfg.PhoneCountry)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out.Duplicates = dups
+		return applyList(ctx, cfg, request, out, listID, add)
+	}
+}
+
+func CreateSplitcontactlistTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("split_contact_list", append([]mcp.ToolOption{
+		mcp.WithDescription("Split a contact list in two by a filter on its custom or address fields. Contacts that match the filter go to a new list; by default they are transferred, so the source list keeps the rest. A list already named new_list_name, or the list target_list_id names, is added to rather than a new one created, leaving out contacts whose phone number it already holds. Without confirm, returns the contacts that match; with confirm: true, creates the list if needed and applies the split with the server's bulk worker pool."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id to split.")),
+		mcp.WithString("filter", mcp.Required(), mcp.Description("Input parameter: Filter expression over custom_1 to custom_4 and the address_ fields, for example `custom_1 = gold and address_state in (NSW, VIC)`. Operators are = != ~ (contains) !~ ^= (starts with) $= (ends with) < <= > >= in (...) and is [not] empty, combined with and, or, not and parentheses. Comparisons ignore case; quote values with spaces.")),
+		mcp.WithString("new_list_name", mcp.Description("Input parameter: Name of the list for the matching contacts, created unless a list has this name. Required without target_list_id.")),
+		mcp.WithString("target_list_id", mcp.Description("Input parameter: Your contact list id to put the matching contacts in, instead of new_list_name.")),
+		mcp.WithBoolean("move", mcp.DefaultBool(true), mcp.Description("Input parameter: Transfer the matching contacts out of the source list. When false, they are copied and the source list is unchanged.")),
+	}, listOptions()...)...)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SplitcontactlistHandler(cfg),
+	}
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// suppressionOff is the error of the suppression tools when the registry
+// is turned off.
+func suppressionOff() *mcp.CallToolResult {
+	return mcp.NewToolResultError("Suppression is off; set SUPPRESSION=on to use it")
+}
+
+// suppressionRegistry returns the suppression registry of the account a
+// call acts as, or an error result when suppression is off or the account
+// cannot be found.
+func suppressionRegistry(ctx context.Context, cfg *config.APIConfig) (*suppression.Registry, *mcp.CallToolResult) {
+	if cfg.Suppression == nil {
+		return nil, suppressionOff()
+	}
+	account, err := cfg.Account(ctx)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("The account of the call could not be found, so its suppression list cannot be used: %v", err))
+	}
+	return cfg.Suppression.For(account), nil
+}
+
+// suppressionKey returns the key of an address in r, reading local numbers
+// in the server's default country or else the account's.
+func suppressionKey(cfg *config.APIConfig, r *suppression.Registry, address string) string {
+	return suppression.Key(address, cmp.Or(cfg.PhoneCountry, r.Country()))
+}
+
+// addresses reads the addresses argument of a suppression tool.
+func addresses(args map[string]any) ([]string, error) {
+	items, ok := args["addresses"].([]any)
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("Missing required parameter: addresses")
+	}
+	out := make([]string, len(items))
+	for i, v := range items {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("Invalid addresses[%d]: want a phone number or email", i)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
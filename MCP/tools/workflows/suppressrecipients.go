@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/clicksend-rest-api-v3/mcp-server/suppression"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func SuppressrecipientsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r, errResult := suppressionRegistry(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		args, _ := request.Params.Arguments.(map[string]any)
+		list, err := addresses(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		note := request.GetString("note", "")
+		added := make([]suppression.Entry, 0, len(list))
+		for _, a := range list {
+			e, err := r.Add(suppressionKey(cfg, r, a), note)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid address %q: %v", a, err)), nil
+			}
+			added = append(added, e)
+		}
+		return models.JSONResult(added)
+	}
+}
+
+func CreateSuppressrecipientsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("suppress_recipients",
+		mcp.WithDescription("Add phone numbers or emails to the suppression list of the account the call acts as by hand, so that send tools leave them out, for example after an opt-out by phone or email."),
+		mcp.WithArray("addresses", mcp.Required(), mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Phone numbers or emails to suppress. Local numbers are read in the server's default country, or else the account's.")),
+		mcp.WithString("note", mcp.Description("Input parameter: Why they are suppressed.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SuppressrecipientsHandler(cfg),
+	}
+}
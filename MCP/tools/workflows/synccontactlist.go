@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/contactdata"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// syncResult is the result of sync_contact_list before and after the plan
+// is applied.
+type syncResult struct {
+	ListID    string              `json:"list_id"`
+	Key       string              `json:"key"`
+	Rows      int                 `json:"rows"`
+	Create    int                 `json:"create"`
+	Update    int                 `json:"update"`
+	Delete    int                 `json:"delete"`
+	Unchanged int                 `json:"unchanged"`
+	Ignored   int                 `json:"ignored,omitempty"`
+	Skipped   int                 `json:"skipped"`
+	Failed    *int                `json:"failed,omitempty"`
+	Mapping   contactdata.Mapping `json:"mapping"`
+	Unmapped  []string            `json:"unmapped,omitempty"`
+	Issues    []contactdata.Issue `json:"issues,omitempty"`
+	Changes   []syncChange        `json:"changes,omitempty"`
+	More      int                 `json:"more_changes,omitempty"`
+	PlanHash  string              `json:"plan_hash"`
+	Next      string              `json:"next,omitempty"`
+}
+
+// syncChange is a planned change and, once applied, its outcome.
+type syncChange struct {
+	contactdata.Change
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func SynccontactlistHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		listID, ok := args["list_id"].(string)
+		if !ok || listID == "" {
+			return mcp.NewToolResultError("Missing required parameter: list_id"), nil
+		}
+		key := request.GetString("key", "phone_number")
+		if key != "phone_number" && key != "email" {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid key %q: use phone_number or email", key)), nil
+		}
+		table, overrides, err := readTable(cfg, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		mapping, err := contactdata.Map(table.Header, contactdata.ContactFields, overrides)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if !slices.Contains(mapping.Fields(), key) {
+			return mcp.NewToolResultError(fmt.Sprintf("No column maps to %s, the sync key; name it in field_map", key)), nil
+		}
+		issues, err := mapping.Check(table, contactdata.ContactFields, cfg.PhoneCountry)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%v; name the columns in field_map", err)), nil
+		}
+		skip := map[int]bool{}
+		for _, issue := range issues {
+			skip[issue.Row] = true
+		}
+		desired := make([]map[string]string, len(table.Rows))
+		for i, row := range table.Rows {
+			desired[i] = mapping.Record(row)
+			if !skip[i+1] && contactdata.SyncKey(desired[i], key, cfg.PhoneCountry) == "" {
+				issues = append(issues, contactdata.Issue{Row: i + 1, Problems: []string{fmt.Sprintf("no %s to match on", key)}})
+				skip[i+1] = true
+			}
+		}
+
+		client := cfg.Client()
+		current, err := clicksend.All(ctx, func(ctx context.Context, opts *clicksend.ListOptions) (*clicksend.Response[clicksend.Page[clicksend.Contact]], error) {
+			return client.ListContacts(ctx, listID, opts)
+		})
+		if err != nil {
+			return models.ErrorResult(err), nil
+		}
+		plan, err := contactdata.Diff(desired, skip, current, key, cfg.PhoneCountry, request.GetBool("delete_missing", false))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		out := syncResult{
+			ListID:    listID,
+			Key:       key,
+			Rows:      len(table.Rows),
+			Create:    plan.Count(contactdata.OpCreate),
+			Update:    plan.Count(contactdata.OpUpdate),
+			Delete:    plan.Count(contactdata.OpDelete),
+			Unchanged: plan.Unchanged,
+			Ignored:   plan.Ignored,
+			Skipped:   len(skip),
+			Mapping:   mapping,
+			Unmapped:  mapping.Unmapped(),
+			Issues:    issues,
+			PlanHash:  plan.Hash(),
+		}
+		changes := make([]syncChange, len(plan.Changes))
+		for i, c := range plan.Changes {
+			changes[i].Change = c
+		}
+
+		if !request.GetBool("confirm", false) {
+			limit := max(0, request.GetInt("preview_changes", 20))
+			out.Changes = changes[:min(limit, len(changes))]
+			out.More = len(changes) - len(out.Changes)
+			out.Next = "Check the plan, then call sync_contact_list again with the same arguments, confirm: true and this plan_hash to apply it."
+			return models.JSONResult(out)
+		}
+		switch hash := request.GetString("plan_hash", ""); hash {
+		case "":
+			return mcp.NewToolResultError("Missing required parameter: plan_hash; preview the sync first and pass the plan_hash it returns"), nil
+		case out.PlanHash:
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("The plan changed since the preview (plan_hash %s, now %s), so nothing was applied; preview the sync again", hash, out.PlanHash)), nil
+		}
+
+		errs := cfg.Bulk.Run(ctx, len(changes), func(ctx context.Context, i int) error {
+			c := &changes[i]
+			switch c.Op {
+			case contactdata.OpCreate:
+				contact := contactdata.Contact(c.Fields)
+				resp, err := client.CreateContact(ctx, listID, &contact)
+				if err == nil {
+					c.ContactID = fmt.Sprint(resp.Data.ContactID)
+				}
+				return err
+			case contactdata.OpUpdate:
+				contact := contactdata.Contact(c.Fields)
+				_, err := client.UpdateContact(ctx, listID, c.ContactID, &contact)
+				return err
+			default:
+				_, err := client.DeleteContact(ctx, listID, c.ContactID)
+				return err
+			}
+		})
+		failed := 0
+		for i, err := range errs {
+			changes[i].Status = "ok"
+			if err != nil {
+				changes[i].Status, changes[i].Error = "failed", err.Error()
+				failed++
+			}
+		}
+		out.Failed = &failed
+		out.Changes = changes
+		return models.JSONResult(out)
+	}
+}
+
+func CreateSynccontactlistTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("sync_contact_list", append(tableOptions(),
+		mcp.WithDescription("Make a contact list match a desired set of contacts from a CSV or xlsx file or inline JSON rows, such as a CRM export. Contacts are matched on phone number or email; columns are mapped to contact fields by name, synonym or near spelling. Without confirm, returns the plan of creates, updates (with the changed fields) and deletes, and its plan_hash; with confirm: true and that plan_hash, applies it if the plan is unchanged with the server's bulk worker pool and reports the result of each change."),
+		mcp.WithString("list_id", mcp.Required(), mcp.Description("Input parameter: Your contact list id to sync.")),
+		mcp.WithString("key", mcp.Enum("phone_number", "email"), mcp.DefaultString("phone_number"), mcp.Description("Input parameter: Contact field that identifies a contact in both sets.")),
+		mcp.WithBoolean("delete_missing", mcp.DefaultBool(false), mcp.Description("Input parameter: Delete contacts of the list that are not in the desired set, and duplicates sharing a key. Contacts without a value for the key are never deleted.")),
+		mcp.WithNumber("preview_changes", mcp.DefaultNumber(20), mcp.Description("Input parameter: Number of planned changes listed in the preview.")),
+		mcp.WithBoolean("confirm", mcp.Description("Input parameter: Apply the plan. Preview first, then call again with confirm: true and the preview's plan_hash.")),
+		mcp.WithString("plan_hash", mcp.Description("Input parameter: plan_hash of the preview, required with confirm. The sync is refused if the plan has changed since.")),
+	)...)
+	withSourceSchema(&tool)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SynccontactlistHandler(cfg),
+	}
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+)
+
+func TestSyncContactList(t *testing.T) {
+	api := newFakeAPI(t)
+	listID := api.newList(t, "CRM",
+		clicksend.Contact{PhoneNumber: "+61411111111", FirstName: "Ann"},
+		clicksend.Contact{PhoneNumber: "+61422222222", FirstName: "Bob"},
+		clicksend.Contact{PhoneNumber: "+61433333333", FirstName: "Cat"},
+	)
+	tool := CreateSynccontactlistTool(api.config())
+
+	args := map[string]any{"list_id": listID, "rows": []any{
+		map[string]any{"Mobile": "0411 111 111", "First Name": "Ann"},
+		map[string]any{"Mobile": "0422 222 222", "First Name": "Robert"},
+		map[string]any{"Mobile": "0444 444 444", "First Name": "Dan"},
+	}, "delete_missing": true}
+
+	plan := callJSON(t, tool, args)
+	if plan["create"] != 1.0 || plan["update"] != 1.0 || plan["delete"] != 1.0 || plan["unchanged"] != 1.0 {
+		t.Errorf("plan = %v", plan)
+	}
+	if len(api.Contacts(listID)) != 3 {
+		t.Fatal("the preview changed the list")
+	}
+
+	args["confirm"] = true
+	if res := callTool(t, tool, args); !res.IsError || !strings.Contains(resultText(res), "plan_hash") {
+		t.Errorf("confirm without plan_hash = %s, want it refused", resultText(res))
+	}
+	args["plan_hash"] = plan["plan_hash"]
+	eve, err := api.cs.CreateContact(context.Background(), listID, &clicksend.Contact{PhoneNumber: "+61455555555", FirstName: "Eve"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := callTool(t, tool, args); !res.IsError || !strings.Contains(resultText(res), "plan changed") {
+		t.Errorf("confirm of a changed plan = %s, want it refused", resultText(res))
+	}
+	if len(api.Contacts(listID)) != 4 {
+		t.Fatal("a changed plan was applied")
+	}
+	if _, err := api.cs.DeleteContact(context.Background(), listID, fmt.Sprint(eve.Data.ContactID)); err != nil {
+		t.Fatal(err)
+	}
+	result := callJSON(t, tool, args)
+	if result["failed"] != 0.0 {
+		t.Errorf("result = %v", result)
+	}
+	got := map[string]any{}
+	for _, contact := range api.Contacts(listID) {
+		got[fmt.Sprint(contact["phone_number"])] = contact["first_name"]
+	}
+	if want := map[string]any{"+61411111111": "Ann", "+61422222222": "Robert", "+61444444444": "Dan"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("contacts = %v, want %v", got, want)
+	}
+}
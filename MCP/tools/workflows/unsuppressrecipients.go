@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// unsuppressed is the outcome of unsuppress_recipients for one address.
+type unsuppressed struct {
+	Address string `json:"address"`
+	Status  string `json:"status"` // "removed", or "still_suppressed" by an opt-out list
+	Detail  string `json:"detail,omitempty"`
+}
+
+func UnsuppressrecipientsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r, errResult := suppressionRegistry(ctx, cfg)
+		if errResult != nil {
+			return errResult, nil
+		}
+		args, _ := request.Params.Arguments.(map[string]any)
+		list, err := addresses(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out := make([]unsuppressed, 0, len(list))
+		for _, a := range list {
+			key := suppressionKey(cfg, r, a)
+			listed, ok, err := r.Remove(key)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid address %q: %v", a, err)), nil
+			}
+			u := unsuppressed{Address: key, Status: "removed"}
+			if ok {
+				u.Status = "still_suppressed"
+				u.Detail = fmt.Sprintf("a contact of opt-out list %s; remove the contact from that list", listed.Detail)
+			}
+			out = append(out, u)
+		}
+		return models.JSONResult(out)
+	}
+}
+
+func CreateUnsuppressrecipientsTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("unsuppress_recipients",
+		mcp.WithDescription("Lift the keyword or manual suppression of phone numbers or emails, as an opt-in reply such as START would, for recipients who have asked to be messaged again. Contacts of an opt-out list stay suppressed until they are removed from it."),
+		mcp.WithArray("addresses", mcp.Required(), mcp.Items(map[string]any{"type": "string"}), mcp.Description("Input parameter: Phone numbers or emails to lift. Local numbers are read in the server's default country, or else the account's.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    UnsuppressrecipientsHandler(cfg),
+	}
+}
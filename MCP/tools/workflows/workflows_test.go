@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeAPI is a fake ClickSend API with a client for seeding it.
+type fakeAPI struct {
+	*clicksendtest.Server
+	cs *clicksend.Client
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := clicksendtest.NewServer(clicksendtest.WithClock(func() time.Time { return clock }))
+	t.Cleanup(api.Close)
+	return &fakeAPI{api, clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))}
+}
+
+// config returns the API configuration of a session using the fake's
+// credentials.
+func (api *fakeAPI) config() *config.APIConfig {
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	return &config.APIConfig{BaseURL: api.URL, BasicAuth: basic, PhoneCountry: "AU"}
+}
+
+// newList creates a contact list holding contacts and returns its id.
+func (api *fakeAPI) newList(t *testing.T, name string, contacts ...clicksend.Contact) string {
+	t.Helper()
+	list, err := api.cs.CreateContactList(context.Background(), &clicksend.ContactList{ListName: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := fmt.Sprint(list.Data.ListID)
+	for _, c := range contacts {
+		if _, err := api.cs.CreateContact(context.Background(), id, &c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return id
+}
+
+// phones returns the sorted phone numbers of a list's contacts.
+func (api *fakeAPI) phones(listID string) []string {
+	var got []string
+	for _, contact := range api.Contacts(listID) {
+		got = append(got, fmt.Sprint(contact["phone_number"]))
+	}
+	slices.Sort(got)
+	return got
+}
+
+func callTool(t *testing.T, tool models.Tool, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool.Definition.Name
+	request.Params.Arguments = args
+	res, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s: %v", tool.Definition.Name, err)
+	}
+	return res
+}
+
+// callJSON calls a tool that must succeed and decodes its result.
+func callJSON(t *testing.T, tool models.Tool, args map[string]any) map[string]any {
+	t.Helper()
+	res := callTool(t, tool, args)
+	if res.IsError {
+		t.Fatalf("%s: %s", tool.Definition.Name, resultText(res))
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(resultText(res)), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	var b strings.Builder
+	for _, c := range res.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			b.WriteString(t.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+// Package tracing adds OpenTelemetry spans for tool calls and the ClickSend
+// requests they make.
+//
+// Tracing is off unless Setup finds an exporter in the standard OTEL_*
+// environment variables. While it is off the global no-op tracer provider
+// is used and the middleware costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the default service.name resource attribute; OTEL_SERVICE_NAME
+// overrides it.
+const ServiceName = "clicksend-mcp-server"
+
+const instrumentation = "github.com/clicksend-rest-api-v3/mcp-server/tracing"
+
+// Enabled reports whether the environment asks for traces: OTEL_SDK_DISABLED
+// is not "true" and either OTEL_TRACES_EXPORTER is "otlp" or an OTLP
+// endpoint is set without OTEL_TRACES_EXPORTER.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "otlp":
+		return true
+	case "":
+		return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	}
+	return false
+}
+
+// Setup installs an OTLP/HTTP tracer provider and the W3C trace context
+// propagator when Enabled. The exporter endpoint, headers, timeout and the
+// sampler come from the standard OTEL_EXPORTER_OTLP_* and OTEL_TRACES_SAMPLER
+// variables. The returned function flushes and stops the provider; it is a
+// no-op when tracing is off.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+	if p := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); p != "" && p != "http/protobuf" {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL %q is not supported, use http/protobuf", p)
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return Install(exporter).Shutdown, nil
+}
+
+// Install sets a global tracer provider batching spans to exporter and
+// returns it. Tests pass an in-memory exporter and call ForceFlush before
+// reading it.
+func Install(exporter sdktrace.SpanExporter, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res, _ := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(ServiceName)),
+		resource.Environment(),
+	)
+	opts = append([]sdktrace.TracerProviderOption{sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)}, opts...)
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return tp
+}
+
+// Extract returns ctx with the trace context of incoming HTTP headers, such
+// as traceparent, so tool call spans join the caller's trace.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// ToolMiddleware starts a span for each tool call. ClickSend requests made
+// by the call, including retries and the pages fetched by fetch_all, become
+// its children.
+func ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			attrs := []attribute.KeyValue{attribute.String("mcp.tool.name", request.Params.Name)}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+			}
+			ctx, span := tracer().Start(ctx, "tools/call "+request.Params.Name,
+				trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetStatus(codes.Error, "tool returned an error")
+			}
+			return result, err
+		}
+	}
+}
+
+// Transport starts a client span for each request sent through next
+// (http.DefaultTransport when nil). Only the path is recorded; query strings
+// can hold phone numbers and message content.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(req.URL.Path),
+		semconv.ServerAddress(req.URL.Hostname()),
+		attribute.Int("clicksend.attempt", clicksend.Attempt(req.Context())),
+	}
+	if page, err := strconv.Atoi(req.URL.Query().Get("page")); err == nil {
+		attrs = append(attrs, attribute.Int("clicksend.page", page))
+	}
+	ctx, span := tracer().Start(req.Context(), "ClickSend "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,136 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	tools_contact_lists "github.com/clicksend-rest-api-v3/mcp-server/tools/contact_lists"
+	"github.com/clicksend-rest-api-v3/mcp-server/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestToolAndRequestSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.Install(exporter)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	api.AddFault(clicksendtest.Fault{Method: "GET", Path: "/account", Status: http.StatusServiceUnavailable, Times: 1})
+	client := clicksend.NewClient(api.URL,
+		clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey),
+		clicksend.WithHTTPClient(&http.Client{Transport: tracing.Transport(nil)}),
+		clicksend.WithRetries(1, time.Millisecond))
+
+	handler := tracing.ToolMiddleware()(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := client.GetAccount(ctx); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_account"
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	tp.ForceFlush(context.Background())
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want a tool span and two attempts", len(spans))
+	}
+	first, retry, tool := spans[0], spans[1], spans[2]
+	if tool.Name != "tools/call get_account" || tool.Parent.IsValid() {
+		t.Errorf("tool span = %s with parent %v", tool.Name, tool.Parent)
+	}
+	for i, s := range []tracetest.SpanStub{first, retry} {
+		if s.Name != "ClickSend GET" || s.Parent.SpanID() != tool.SpanContext.SpanID() {
+			t.Errorf("request span %d = %s, not a child of the tool span", i, s.Name)
+		}
+		if got := attr(s.Attributes, "clicksend.attempt"); got.AsInt64() != int64(i) {
+			t.Errorf("request span %d attempt = %v", i, got.Emit())
+		}
+	}
+	if first.Status.Code != codes.Error || retry.Status.Code == codes.Error {
+		t.Errorf("statuses = %v, %v; want the first attempt to fail", first.Status, retry.Status)
+	}
+	if got := attr(retry.Attributes, "http.response.status_code"); got.AsInt64() != 200 {
+		t.Errorf("retry status = %v", got.Emit())
+	}
+}
+
+// TestTracePropagation checks that a tool call joins the trace of an
+// incoming traceparent header and that each page fetched by fetch_all is a
+// child span of the call.
+func TestTracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.Install(exporter)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	if _, err := tracing.Setup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	cs := clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	for i := range 20 {
+		if _, err := cs.CreateContactList(context.Background(), &clicksend.ContactList{ListName: fmt.Sprintf("List %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	basic := base64.StdEncoding.EncodeToString([]byte(clicksendtest.Username + ":" + clicksendtest.APIKey))
+	tool := tools_contact_lists.CreateGetallcontactlistsTool(&config.APIConfig{BaseURL: api.URL, BasicAuth: basic})
+
+	const traceID, parentID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	ctx := tracing.Extract(context.Background(), http.Header{"Traceparent": {"00-" + traceID + "-" + parentID + "-01"}})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_lists"
+	request.Params.Arguments = map[string]any{"fetch_all": true, "limit": 15.0}
+	if res, err := tracing.ToolMiddleware()(tool.Handler)(ctx, request); err != nil || res.IsError {
+		t.Fatalf("get_lists: %v %v", err, res)
+	}
+	tp.ForceFlush(context.Background())
+
+	var call tracetest.SpanStub
+	var pages []tracetest.SpanStub
+	for _, s := range exporter.GetSpans() {
+		switch s.Name {
+		case "tools/call get_lists":
+			call = s
+		case "ClickSend GET":
+			pages = append(pages, s)
+		}
+	}
+	if call.SpanContext.TraceID().String() != traceID || call.Parent.SpanID().String() != parentID {
+		t.Errorf("tool span trace %s parent %s, want %s %s", call.SpanContext.TraceID(), call.Parent.SpanID(), traceID, parentID)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("got %d ClickSend spans, want 2 pages", len(pages))
+	}
+	for _, p := range pages {
+		if p.Parent.SpanID() != call.SpanContext.SpanID() {
+			t.Errorf("page span is not a child of the tool span")
+		}
+	}
+}
+
+func attr(attrs []attribute.KeyValue, key string) attribute.Value {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
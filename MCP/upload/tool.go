@@ -0,0 +1,116 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// target is the file argument of a tool and the convert mode its files
+// are uploaded with.
+type target struct {
+	arg     string
+	convert string
+}
+
+// targets are the tools whose file URL arguments also accept local content.
+var targets = map[string]target{
+	"post_mms_send":                         {"media_file", clicksend.ConvertMms},
+	"post_mms_price":                        {"media_file", clicksend.ConvertMms},
+	"post_fax_send":                         {"file_url", clicksend.ConvertFax},
+	"post_fax_price":                        {"file_url", clicksend.ConvertFax},
+	"post_post_letters_send":                {"file_url", clicksend.ConvertPost},
+	"post_post_letters_price":               {"file_url", clicksend.ConvertPost},
+	"post_post_postcards_send":              {"file_urls", clicksend.ConvertPost},
+	"post_post_postcards_price":             {"file_urls", clicksend.ConvertPost},
+	"post_post_direct-mail_campaigns_send":  {"file_urls", clicksend.ConvertPost},
+	"post_post_direct-mail_campaigns_price": {"file_urls", clicksend.ConvertPost},
+	"post_lists_list_id_import":             {"file_url", clicksend.ConvertCsv},
+	"post_lists_list_id_import-csv-preview": {"file_url", clicksend.ConvertCsv},
+}
+
+// SourceSchema is the JSON schema of a file argument Source accepts: a URL,
+// path or data: URI, or an embedded resource.
+var SourceSchema = []any{
+	map[string]any{"type": "string"},
+	map[string]any{"type": "object", "description": "MCP embedded resource with uri, mimeType and blob or text."},
+}
+
+// Wrap lets the file argument of a send, price or import tool take local
+// content, which is uploaded with client before the tool runs. Other tools
+// are returned unchanged.
+func Wrap(tool models.Tool, u *Uploader, client func() *clicksend.Client) models.Tool {
+	t, ok := targets[tool.Definition.Name]
+	if !ok {
+		return tool
+	}
+	props := tool.Definition.InputSchema.Properties
+	prop, _ := props[t.arg].(map[string]any)
+	desc, _ := prop["description"].(string)
+	desc += fmt.Sprintf(" Also accepts a local file path, a data: URI or an embedded resource, which is uploaded first (%s). Pass inline content as a data: URI; any other string is read as a path.", joinTypes(t.convert))
+	if prop["type"] == "array" {
+		props[t.arg] = map[string]any{"type": "array", "description": desc, "items": map[string]any{"anyOf": SourceSchema}}
+	} else {
+		props[t.arg] = map[string]any{"description": desc, "anyOf": SourceSchema}
+	}
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok || args[t.arg] == nil {
+			return handler(ctx, request)
+		}
+		resolve := func(v any) (any, error) {
+			f, err := u.Source(v)
+			if err != nil || f == nil {
+				return v, err
+			}
+			resp, err := u.Upload(ctx, client(), t.convert, f)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Data.URL, nil
+		}
+		var value any
+		var err error
+		if items, ok := args[t.arg].([]any); ok {
+			resolved := make([]any, len(items))
+			for i, item := range items {
+				if resolved[i], err = resolve(item); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to upload %s[%d]: %v", t.arg, i, err)), nil
+				}
+			}
+			value = resolved
+		} else if value, err = resolve(args[t.arg]); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to upload %s: %v", t.arg, err)), nil
+		}
+
+		forwarded := make(map[string]any, len(args))
+		for k, v := range args {
+			forwarded[k] = v
+		}
+		forwarded[t.arg] = value
+		request.Params.Arguments = forwarded
+		return handler(ctx, request)
+	}
+	return tool
+}
+
+func joinTypes(convert string) string {
+	types := Types[convert]
+	s := ""
+	for i, ext := range types {
+		switch {
+		case i == 0:
+		case i == len(types)-1:
+			s += " or "
+		default:
+			s += ", "
+		}
+		s += ext
+	}
+	return s
+}
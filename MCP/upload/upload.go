@@ -0,0 +1,319 @@
+// Package upload stores files with ClickSend so that sends can use them.
+//
+// MMS, fax, letter, postcard and contact import endpoints only take the URL
+// of a file ClickSend can download. The server accepts a local path, a data:
+// URI or an MCP embedded resource instead, checks the file
+// against the types the channel supports, posts it to /uploads with the
+// channel's convert mode and uses the hosted URL it gets back.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+)
+
+// DefaultMaxBytes is used when UPLOAD_MAX_BYTES is not set.
+const DefaultMaxBytes = 10 << 20
+
+// Types lists the file types accepted for each convert mode, by extension.
+var Types = map[string][]string{
+	clicksend.ConvertFax:  {"pdf", "doc", "docx", "rtf"},
+	clicksend.ConvertMms:  {"jpg", "gif", "png", "bmp"},
+	clicksend.ConvertPost: {"pdf", "doc", "docx"},
+	clicksend.ConvertCsv:  {"csv"},
+}
+
+// File is content to upload.
+type File struct {
+	Name      string // file name, used when the content alone does not tell its type
+	MediaType string // declared media type, if any
+	Content   []byte
+}
+
+// Uploader reads and checks files before uploading them. A nil Uploader
+// refuses local paths and accepts files of up to DefaultMaxBytes.
+type Uploader struct {
+	dirs     []string
+	maxBytes int64
+}
+
+// New returns an uploader reading local files only from dirs, and accepting
+// files of up to maxBytes. With no dirs, local paths are refused.
+func New(dirs []string, maxBytes int64) *Uploader {
+	u := &Uploader{maxBytes: maxBytes}
+	for _, dir := range dirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			u.dirs = append(u.dirs, abs)
+		}
+	}
+	return u
+}
+
+// FromEnv returns the uploader configured by UPLOAD_DIRS, a comma-separated
+// list of directories local files may be read from, and UPLOAD_MAX_BYTES.
+func FromEnv() (*Uploader, error) {
+	maxBytes := int64(DefaultMaxBytes)
+	if v := configfile.Getenv("UPLOAD_MAX_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("UPLOAD_MAX_BYTES must be a positive integer, got %q", v)
+		}
+		maxBytes = n
+	}
+	var dirs []string
+	for _, dir := range strings.Split(configfile.Getenv("UPLOAD_DIRS"), ",") {
+		if dir = strings.TrimSpace(dir); dir == "" {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("UPLOAD_DIRS: %q is not a directory", dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	return New(dirs, maxBytes), nil
+}
+
+func (u *Uploader) limit() int64 {
+	if u == nil {
+		return DefaultMaxBytes
+	}
+	return u.maxBytes
+}
+
+// Source returns the file a tool argument refers to: a local path or file://
+// URL, a data: URI or an embedded resource object. Any other string is a
+// path, even one that would decode as base64, so a file name is never
+// mistaken for content. It returns nil for http and https URLs, which
+// ClickSend downloads itself.
+func (u *Uploader) Source(v any) (*File, error) {
+	switch v := v.(type) {
+	case string:
+		switch {
+		case v == "":
+			return nil, errors.New("empty file")
+		case strings.HasPrefix(v, "http://"), strings.HasPrefix(v, "https://"):
+			return nil, nil
+		case strings.HasPrefix(v, "data:"):
+			return parseDataURI(v)
+		case strings.HasPrefix(v, "file://"):
+			p, err := url.Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file URL %q", v)
+			}
+			return u.ReadLocal(p.Path)
+		}
+		return u.ReadLocal(v)
+	case map[string]any:
+		return parseResource(v)
+	}
+	return nil, fmt.Errorf("expected a URL, path, data: URI or embedded resource, got %T", v)
+}
+
+// Content returns the file of an argument that only holds content: base64
+// or a data: URI.
+func Content(s string) (*File, error) {
+	if strings.HasPrefix(s, "data:") {
+		return parseDataURI(s)
+	}
+	content, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("not base64 or a data: URI")
+	}
+	return &File{Content: content}, nil
+}
+
+// ReadLocal reads a local file, which must be under one of the uploader's
+// directories.
+func (u *Uploader) ReadLocal(name string) (*File, error) {
+	if u == nil || len(u.dirs) == 0 {
+		return nil, fmt.Errorf("cannot read local file %q: set UPLOAD_DIRS to allow local files", name)
+	}
+	resolved, err := filepath.Abs(name)
+	if err == nil {
+		resolved, err = filepath.EvalSymlinks(resolved)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read local file %q: %w", name, err)
+	}
+	allowed := slices.ContainsFunc(u.dirs, func(dir string) bool {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = real
+		}
+		rel, err := filepath.Rel(dir, resolved)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	})
+	if !allowed {
+		return nil, fmt.Errorf("cannot read local file %q: it is outside UPLOAD_DIRS", name)
+	}
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read local file %q: %w", name, err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > u.limit() {
+		return nil, fmt.Errorf("file %q is %d bytes, over the %d byte limit", name, info.Size(), u.limit())
+	}
+	content, err := io.ReadAll(io.LimitReader(f, u.limit()+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read local file %q: %w", name, err)
+	}
+	return &File{Name: filepath.Base(name), Content: content}, nil
+}
+
+// parseDataURI decodes a data: URI such as data:application/pdf;base64,....
+func parseDataURI(s string) (*File, error) {
+	meta, data, ok := strings.Cut(strings.TrimPrefix(s, "data:"), ",")
+	if !ok {
+		return nil, errors.New("invalid data: URI")
+	}
+	mediaType, isBase64 := strings.CutSuffix(meta, ";base64")
+	var content []byte
+	if isBase64 {
+		b, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, errors.New("invalid base64 in data: URI")
+		}
+		content = b
+	} else {
+		text, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, errors.New("invalid data: URI")
+		}
+		content = []byte(text)
+	}
+	return &File{MediaType: mediaType, Content: content}, nil
+}
+
+// parseResource decodes an MCP embedded resource, either the content block
+// {"type": "resource", "resource": {...}} or the resource contents alone.
+func parseResource(m map[string]any) (*File, error) {
+	if inner, ok := m["resource"].(map[string]any); ok {
+		m = inner
+	}
+	f := &File{}
+	if uri, _ := m["uri"].(string); uri != "" {
+		if u, err := url.Parse(uri); err == nil {
+			f.Name = path.Base(u.Path)
+		}
+	}
+	f.MediaType, _ = m["mimeType"].(string)
+	switch {
+	case m["blob"] != nil:
+		blob, _ := m["blob"].(string)
+		content, err := base64.StdEncoding.DecodeString(blob)
+		if err != nil {
+			return nil, errors.New("invalid base64 in embedded resource blob")
+		}
+		f.Content = content
+	case m["text"] != nil:
+		text, _ := m["text"].(string)
+		f.Content = []byte(text)
+	default:
+		return nil, errors.New("embedded resource has neither blob nor text")
+	}
+	return f, nil
+}
+
+// Check returns an error unless f is a file convert accepts, within the
+// size limit.
+func (u *Uploader) Check(convert string, f *File) error {
+	accepted, ok := Types[convert]
+	if !ok {
+		return fmt.Errorf("convert must be one of fax, mms, post or csv, got %q", convert)
+	}
+	if len(f.Content) == 0 {
+		return errors.New("file is empty")
+	}
+	if int64(len(f.Content)) > u.limit() {
+		return fmt.Errorf("file is %d bytes, over the %d byte limit", len(f.Content), u.limit())
+	}
+	kind := Detect(f)
+	if !slices.Contains(accepted, kind) {
+		return fmt.Errorf("%s files are not accepted for %s; use %s", kind, convert, strings.Join(accepted, ", "))
+	}
+	if kind == "csv" {
+		rows, err := csv.NewReader(bytes.NewReader(f.Content)).ReadAll()
+		if err != nil {
+			return fmt.Errorf("file is not valid CSV: %w", err)
+		}
+		if len(rows) == 0 {
+			return errors.New("CSV file has no rows")
+		}
+	}
+	return nil
+}
+
+// oleMagic starts legacy Office documents such as .doc files.
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// Detect returns the type of f as a file extension, from its content where
+// that is conclusive and otherwise from its name or declared media type.
+func Detect(f *File) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(f.Name), "."))
+	if ext == "" && f.MediaType != "" {
+		if exts, _ := mime.ExtensionsByType(f.MediaType); len(exts) > 0 {
+			ext = strings.TrimPrefix(exts[0], ".")
+		}
+		if f.MediaType == "text/csv" {
+			ext = "csv"
+		}
+	}
+	switch sniffed := http.DetectContentType(f.Content); {
+	case sniffed == "application/pdf":
+		return "pdf"
+	case sniffed == "image/jpeg":
+		return "jpg"
+	case sniffed == "image/gif":
+		return "gif"
+	case sniffed == "image/png":
+		return "png"
+	case sniffed == "image/bmp":
+		return "bmp"
+	case bytes.HasPrefix(f.Content, []byte(`{\rtf`)):
+		return "rtf"
+	case bytes.HasPrefix(f.Content, oleMagic):
+		return "doc"
+	case sniffed == "application/zip":
+		switch {
+		case ext == "docx" || bytes.Contains(f.Content, []byte("word/")):
+			return "docx"
+		case ext == "xlsx" || bytes.Contains(f.Content, []byte("xl/")):
+			return "xlsx"
+		}
+		return "zip"
+	case strings.HasPrefix(sniffed, "text/plain"):
+		if ext == "" || ext == "txt" {
+			return "csv"
+		}
+	}
+	if ext == "" {
+		return "unknown"
+	}
+	return ext
+}
+
+// Upload checks f and uploads it converted for convert. The hosted URL is
+// in the _url field of the response data.
+func (u *Uploader) Upload(ctx context.Context, client *clicksend.Client, convert string, f *File) (*clicksend.Response[clicksend.Upload], error) {
+	if err := u.Check(convert, f); err != nil {
+		return nil, err
+	}
+	return client.UploadFile(ctx, convert, &clicksend.UploadRequest{Content: base64.StdEncoding.EncodeToString(f.Content)})
+}
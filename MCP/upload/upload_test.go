@@ -0,0 +1,163 @@
+package upload
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend"
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var pdf = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n%%EOF\n")
+
+func TestSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "letter.pdf"), pdf, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A name that also decodes as base64
+	if err := os.WriteFile(filepath.Join(dir, "QUJD"), pdf, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.pdf")
+	if err := os.WriteFile(outside, pdf, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	u := New([]string{dir}, 1024)
+	t.Chdir(dir)
+	encoded := base64.StdEncoding.EncodeToString(pdf)
+
+	tests := []struct {
+		name    string
+		arg     any
+		want    string // Detect of the file, "" for a passed-through URL
+		wantErr string
+	}{
+		{"url", "https://example.com/letter.pdf", "", ""},
+		{"path", filepath.Join(dir, "letter.pdf"), "pdf", ""},
+		{"file url", "file://" + filepath.Join(dir, "letter.pdf"), "pdf", ""},
+		{"escape", filepath.Join(dir, "..", filepath.Base(filepath.Dir(outside)), "secret.pdf"), "", "outside UPLOAD_DIRS"},
+		{"missing", filepath.Join(dir, "nope.pdf"), "", "no such file"},
+		{"base64 name", "QUJD", "pdf", ""},
+		{"base64", encoded, "", "cannot read local file"},
+		{"data uri", "data:text/csv,phone%0A%2B61411111111%0A", "csv", ""},
+		{"base64 data uri", "data:application/pdf;base64," + encoded, "pdf", ""},
+		{"resource", map[string]any{"type": "resource", "resource": map[string]any{"uri": "file:///tmp/logo.gif", "mimeType": "image/gif", "blob": base64.StdEncoding.EncodeToString([]byte("GIF89a\x01\x00\x01\x00"))}}, "gif", ""},
+		{"text resource", map[string]any{"uri": "mem://contacts.csv", "text": "phone\n+61411111111\n"}, "csv", ""},
+		{"number", 42.0, "", "expected a URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := u.Source(tt.arg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if f != nil {
+					t.Fatalf("got a file for a URL")
+				}
+				return
+			}
+			if got := Detect(f); got != tt.want {
+				t.Errorf("Detect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (*Uploader)(nil).ReadLocal(filepath.Join(dir, "letter.pdf")); err == nil || !strings.Contains(err.Error(), "set UPLOAD_DIRS") {
+		t.Errorf("nil uploader read a local file: %v", err)
+	}
+}
+
+func TestContent(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pdf)
+	for _, s := range []string{encoded, "data:application/pdf;base64," + encoded} {
+		if f, err := Content(s); err != nil || Detect(f) != "pdf" {
+			t.Errorf("Content(%.20q) = %v, %v; want the pdf", s, f, err)
+		}
+	}
+	if _, err := Content("letter.pdf"); err == nil {
+		t.Error("Content accepted a file name")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	u := New(nil, 64)
+	tests := []struct {
+		convert string
+		file    File
+		wantErr string
+	}{
+		{clicksend.ConvertPost, File{Content: pdf}, ""},
+		{clicksend.ConvertMms, File{Content: pdf}, "pdf files are not accepted for mms; use jpg, gif, png, bmp"},
+		{clicksend.ConvertFax, File{Content: []byte(`{\rtf1 hello}`)}, ""},
+		{clicksend.ConvertPost, File{Content: []byte(`{\rtf1 hello}`)}, "rtf files are not accepted"},
+		{clicksend.ConvertCsv, File{Content: []byte("phone\n+61411111111\n")}, ""},
+		{clicksend.ConvertCsv, File{Content: []byte("+61411111111\n")}, ""},
+		{clicksend.ConvertCsv, File{Content: []byte("\n")}, "has no rows"},
+		{clicksend.ConvertCsv, File{Content: []byte("a,\"b\n")}, "not valid CSV"},
+		{clicksend.ConvertFax, File{Content: make([]byte, 65)}, "over the 64 byte limit"},
+		{clicksend.ConvertFax, File{}, "file is empty"},
+		{"zip", File{Content: pdf}, "convert must be one of"},
+	}
+	for _, tt := range tests {
+		err := u.Check(tt.convert, &tt.file)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Check(%s, %.10q) = %v, want %q", tt.convert, tt.file.Content, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	client := func() *clicksend.Client {
+		return clicksend.NewClient(api.URL, clicksend.WithCredentials(clicksendtest.Username, clicksendtest.APIKey))
+	}
+
+	var got map[string]any
+	tool := Wrap(models.Tool{
+		Definition: mcp.NewTool("post_post_postcards_send", mcp.WithArray("file_urls", mcp.Description("Postcard file urls."))),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			got = request.GetArguments()
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}, nil, client)
+
+	prop := tool.Definition.InputSchema.Properties["file_urls"].(map[string]any)
+	if !strings.Contains(prop["description"].(string), "local file path") {
+		t.Errorf("description = %q, want it to mention local files", prop["description"])
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"file_urls": []any{"https://example.com/front.pdf", "data:application/pdf;base64," + base64.StdEncoding.EncodeToString(pdf)}}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("call failed: %v %v", err, result)
+	}
+	urls := got["file_urls"].([]any)
+	if urls[0] != "https://example.com/front.pdf" {
+		t.Errorf("file_urls[0] = %v, want the URL unchanged", urls[0])
+	}
+	if back, _ := urls[1].(string); !strings.HasPrefix(back, api.URL+"/uploads/files/") {
+		t.Errorf("file_urls[1] = %v, want an uploaded file URL", urls[1])
+	}
+
+	request.Params.Arguments = map[string]any{"file_urls": []any{"data:image/png;base64,iVBORw0KGgo="}}
+	result, _ = tool.Handler(context.Background(), request)
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "file_urls[0]: png files are not accepted for post") {
+		t.Errorf("result = %v, want a file type error", result.Content)
+	}
+}
@@ -0,0 +1,83 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// header starts every encrypted profiles file.
+const header = "clicksend-profiles v1\n"
+
+// KeySize is the length of a vault key: AES-256.
+const KeySize = 32
+
+// NewKey returns a random key, base64-encoded.
+func NewKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a base64 key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("profiles key must be %d base64-encoded bytes", KeySize)
+	}
+	return key, nil
+}
+
+// Encrypt seals plain with AES-256-GCM. The result is a header line followed
+// by the base64 nonce and ciphertext.
+func Encrypt(plain, key []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(header))
+	return []byte(header + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt opens a file written by Encrypt.
+func Decrypt(data, key []byte) ([]byte, error) {
+	body, ok := bytes.CutPrefix(data, []byte(header))
+	if !ok {
+		return nil, errors.New("not an encrypted profiles file")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(body)))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt profiles file: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("corrupt profiles file")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(header))
+	if err != nil {
+		return nil, errors.New("cannot decrypt profiles file: wrong key or corrupt file")
+	}
+	return plain, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,48 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Wrap adds a profile argument to a tool. The ClickSend requests of a call
+// naming a profile use its credentials. names lists the profiles offered in
+// the argument description.
+func Wrap(tool models.Tool, names []string) models.Tool {
+	if _, ok := tool.Definition.InputSchema.Properties["profile"]; ok {
+		return tool
+	}
+	description := "Credential profile to call ClickSend with, overriding the session's profile."
+	if len(names) > 0 {
+		description += " Available: " + strings.Join(names, ", ") + "."
+	}
+	mcp.WithString("profile", mcp.Description(description))(&tool.Definition)
+
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if args, ok := request.Params.Arguments.(map[string]any); ok {
+			if v, ok := args["profile"]; ok {
+				name, ok := v.(string)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("Invalid profile: %v", v)), nil
+				}
+				forwarded := make(map[string]any, len(args))
+				for k, v := range args {
+					if k != "profile" {
+						forwarded[k] = v
+					}
+				}
+				request.Params.Arguments = forwarded
+				if name != "" {
+					ctx = WithProfile(ctx, name)
+				}
+			}
+		}
+		return handler(ctx, request)
+	}
+	return tool
+}
@@ -0,0 +1,266 @@
+// Package vault keeps named ClickSend credential profiles on the server.
+//
+// Profiles come from an AES-256-GCM encrypted YAML file, from a secrets
+// directory with one subdirectory per profile (the layout of a mounted
+// Kubernetes secret), or both. Sources are re-read when they change, so a
+// rotated API key takes effect without restarting the server.
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/configfile"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of ClickSend credentials. BasicAuth is the base64
+// "username:api_key" pair; Username and APIKey may be given instead.
+type Profile struct {
+	BaseURL   string `yaml:"api_base_url"`
+	Username  string `yaml:"username"`
+	APIKey    string `yaml:"api_key"`
+	BasicAuth string `yaml:"basic_auth"`
+}
+
+// Basic returns the base64 "username:api_key" pair.
+func (p Profile) Basic() string {
+	if p.BasicAuth != "" {
+		return p.BasicAuth
+	}
+	return base64.StdEncoding.EncodeToString([]byte(p.Username + ":" + p.APIKey))
+}
+
+// secretFiles are the files read from a profile directory.
+var secretFiles = []string{"api_base_url", "username", "api_key", "basic_auth"}
+
+// Vault holds the profiles of its sources.
+type Vault struct {
+	file string
+	key  []byte
+	dir  string
+
+	mu          sync.Mutex
+	fingerprint uint64
+	profiles    map[string]Profile
+}
+
+// FromEnv opens the vault configured by CLICKSEND_PROFILES_FILE (with its
+// key in CLICKSEND_PROFILES_KEY or CLICKSEND_PROFILES_KEY_FILE) and
+// CLICKSEND_PROFILES_DIR. It returns nil, nil when neither source is set.
+func FromEnv() (*Vault, error) {
+	file, dir := configfile.Getenv("CLICKSEND_PROFILES_FILE"), configfile.Getenv("CLICKSEND_PROFILES_DIR")
+	if file == "" && dir == "" {
+		return nil, nil
+	}
+	var key []byte
+	if file != "" {
+		encoded := configfile.Getenv("CLICKSEND_PROFILES_KEY")
+		if path := configfile.Getenv("CLICKSEND_PROFILES_KEY_FILE"); path != "" {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("CLICKSEND_PROFILES_KEY_FILE: %w", err)
+			}
+			encoded = string(b)
+		}
+		if encoded == "" {
+			return nil, errors.New("CLICKSEND_PROFILES_FILE needs CLICKSEND_PROFILES_KEY or CLICKSEND_PROFILES_KEY_FILE")
+		}
+		var err error
+		if key, err = ParseKey(encoded); err != nil {
+			return nil, err
+		}
+	}
+	return Open(file, key, dir)
+}
+
+// Open loads the profiles of an encrypted file and a secrets directory.
+// Either may be empty. A profile defined in both is taken from the
+// directory.
+func Open(file string, key []byte, dir string) (*Vault, error) {
+	v := &Vault{file: file, key: key, dir: dir}
+	if err := v.refresh(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Get returns the named profile, first re-reading the sources if they
+// changed. When a changed source cannot be read the previous profiles are
+// kept.
+func (v *Vault) Get(name string) (Profile, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.refresh(); err != nil {
+		slog.Warn("Failed to reload credential profiles; using the previous ones", "error", err)
+	}
+	p, ok := v.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	return p, nil
+}
+
+// Names returns the profile names, sorted.
+func (v *Vault) Names() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	names := make([]string, 0, len(v.profiles))
+	for name := range v.profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// refresh reloads the profiles when the sources' fingerprint changed. The
+// caller holds mu, except in Open.
+func (v *Vault) refresh() error {
+	fp, err := v.currentFingerprint()
+	if err != nil {
+		return err
+	}
+	if v.profiles != nil && fp == v.fingerprint {
+		return nil
+	}
+	profiles := map[string]Profile{}
+	if v.file != "" {
+		if err := v.loadFile(profiles); err != nil {
+			return err
+		}
+	}
+	if v.dir != "" {
+		if err := v.loadDir(profiles); err != nil {
+			return err
+		}
+	}
+	for name, p := range profiles {
+		if p.BasicAuth == "" && (p.Username == "" || p.APIKey == "") {
+			return fmt.Errorf("profile %q needs basic_auth or username and api_key", name)
+		}
+	}
+	v.profiles, v.fingerprint = profiles, fp
+	return nil
+}
+
+// currentFingerprint hashes the names, sizes and modification times of the
+// source files.
+func (v *Vault) currentFingerprint() (uint64, error) {
+	h := fnv.New64a()
+	add := func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	}
+	if v.file != "" {
+		if err := add(v.file); err != nil {
+			return 0, err
+		}
+	}
+	if v.dir != "" {
+		names, err := v.profileDirs()
+		if err != nil {
+			return 0, err
+		}
+		for _, name := range names {
+			for _, f := range secretFiles {
+				if err := add(filepath.Join(v.dir, name, f)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return 0, err
+				}
+			}
+		}
+	}
+	return h.Sum64(), nil
+}
+
+func (v *Vault) loadFile(profiles map[string]Profile) error {
+	data, err := os.ReadFile(v.file)
+	if err != nil {
+		return err
+	}
+	plain, err := Decrypt(data, v.key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.file, err)
+	}
+	var file map[string]Profile
+	if err := yaml.Unmarshal(plain, &file); err != nil {
+		return fmt.Errorf("%s: %w", v.file, err)
+	}
+	for name, p := range file {
+		profiles[name] = p
+	}
+	return nil
+}
+
+// profileDirs lists the profile subdirectories of dir, skipping hidden
+// entries such as the ..data link of a Kubernetes secret volume.
+func (v *Vault) profileDirs() ([]string, error) {
+	entries, err := os.ReadDir(v.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(v.dir, e.Name())); err == nil && info.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (v *Vault) loadDir(profiles map[string]Profile) error {
+	names, err := v.profileDirs()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		values := map[string]string{}
+		for _, f := range secretFiles {
+			b, err := os.ReadFile(filepath.Join(v.dir, name, f))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			values[f] = strings.TrimSpace(string(b))
+		}
+		profiles[name] = Profile{
+			BaseURL:   values["api_base_url"],
+			Username:  values["username"],
+			APIKey:    values["api_key"],
+			BasicAuth: values["basic_auth"],
+		}
+	}
+	return nil
+}
+
+type profileKey struct{}
+
+// WithProfile returns a context whose ClickSend requests use the named
+// profile.
+func WithProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileKey{}, name)
+}
+
+// ProfileFromContext returns the profile selected with WithProfile, or "".
+func ProfileFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(profileKey{}).(string)
+	return name
+}
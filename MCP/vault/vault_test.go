@@ -0,0 +1,173 @@
+package vault_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clicksend-rest-api-v3/mcp-server/clicksend/clicksendtest"
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	tools_account "github.com/clicksend-rest-api-v3/mcp-server/tools/account"
+	"github.com/clicksend-rest-api-v3/mcp-server/vault"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	encoded, err := vault.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := vault.ParseKey(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := vault.Encrypt([]byte("prod: {}"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := vault.Decrypt(sealed, key)
+	if err != nil || string(plain) != "prod: {}" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+	other, _ := vault.NewKey()
+	otherKey, _ := vault.ParseKey(other)
+	if _, err := vault.Decrypt(sealed, otherKey); err == nil {
+		t.Error("decrypted with the wrong key")
+	}
+	if _, err := vault.Decrypt([]byte("prod: {}"), key); err == nil {
+		t.Error("accepted a plaintext file")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	encoded, _ := vault.NewKey()
+	key, _ := vault.ParseKey(encoded)
+	sealed, err := vault.Encrypt([]byte(`
+prod:
+  username: prod-user
+  api_key: prod-key
+staging:
+  api_base_url: https://staging.example.com/v3
+  basic_auth: c3RhZ2luZzprZXk=
+`), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "profiles.enc")
+	writeFile(t, file, string(sealed))
+	secrets := filepath.Join(dir, "secrets")
+	writeFile(t, filepath.Join(secrets, "staging", "username"), "dir-user\n")
+	writeFile(t, filepath.Join(secrets, "staging", "api_key"), "dir-key\n")
+	writeFile(t, filepath.Join(secrets, "..data", "ignored"), "")
+
+	v, err := vault.Open(file, key, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := v.Names(); len(names) != 2 || names[0] != "prod" || names[1] != "staging" {
+		t.Errorf("Names = %v", names)
+	}
+	prod, err := v.Get("prod")
+	if err != nil || prod.Basic() != "cHJvZC11c2VyOnByb2Qta2V5" {
+		t.Errorf("prod = %+v, %v", prod, err)
+	}
+	staging, _ := v.Get("staging")
+	if staging.Username != "dir-user" || staging.BaseURL != "" {
+		t.Errorf("staging = %+v, want the secrets directory to win", staging)
+	}
+	if _, err := v.Get("dev"); err == nil {
+		t.Error("Get of an unknown profile succeeded")
+	}
+
+	// Rotating a key is picked up without reopening the vault.
+	writeFile(t, filepath.Join(secrets, "staging", "api_key"), "rotated-key-value\n")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(secrets, "staging", "api_key"), future, future)
+	if staging, _ := v.Get("staging"); staging.APIKey != "rotated-key-value" {
+		t.Errorf("api_key after rotation = %q", staging.APIKey)
+	}
+
+	// A broken update keeps the previous profiles.
+	writeFile(t, file, "garbage")
+	if prod, err := v.Get("prod"); err != nil || prod.Username != "prod-user" {
+		t.Errorf("prod after a broken update = %+v, %v", prod, err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	var got string
+	var args map[string]any
+	tool := vault.Wrap(models.Tool{
+		Definition: mcp.NewTool("get_account"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			got = vault.ProfileFromContext(ctx)
+			args, _ = request.Params.Arguments.(map[string]any)
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}, []string{"prod", "staging"})
+
+	if _, ok := tool.Definition.InputSchema.Properties["profile"]; !ok {
+		t.Fatal("profile argument was not added")
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"profile": "staging", "limit": 1}
+	tool.Handler(context.Background(), request)
+	if got != "staging" || len(args) != 1 {
+		t.Errorf("profile = %q, forwarded args = %v", got, args)
+	}
+	request.Params.Arguments = map[string]any{"profile": 7}
+	if res, _ := tool.Handler(context.Background(), request); !res.IsError {
+		t.Error("accepted a non-string profile")
+	}
+}
+
+// TestSessionProfile checks that tool calls use the session's credential
+// profile unless they name another one.
+func TestSessionProfile(t *testing.T) {
+	api := clicksendtest.NewServer()
+	defer api.Close()
+	dir := t.TempDir()
+	for name, key := range map[string]string{"good": clicksendtest.APIKey, "revoked": "old-key"} {
+		writeFile(t, filepath.Join(dir, name, "username"), clicksendtest.Username)
+		writeFile(t, filepath.Join(dir, name, "api_key"), key)
+	}
+	profiles, err := vault.Open("", nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := vault.Wrap(tools_account.CreateGetaccountTool(&config.APIConfig{BaseURL: api.URL, Profiles: profiles, Profile: "revoked"}), profiles.Names())
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		res, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	if res := call(map[string]any{}); !res.IsError {
+		t.Error("session profile with a revoked key succeeded")
+	}
+	if res := call(map[string]any{"profile": "good"}); res.IsError {
+		t.Errorf("profile argument: %v", res.Content)
+	}
+	if res := call(map[string]any{"profile": "missing"}); !res.IsError {
+		t.Error("unknown profile succeeded")
+	}
+}
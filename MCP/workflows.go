@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/clicksend-rest-api-v3/mcp-server/config"
+	"github.com/clicksend-rest-api-v3/mcp-server/models"
+	tools_workflows "github.com/clicksend-rest-api-v3/mcp-server/tools/workflows"
+)
+
+// GetWorkflows returns the tools that combine several API calls. Unlike the
+// tools of GetAll, they have no single OpenAPI operation.
+func GetWorkflows(cfg *config.APIConfig) []models.Tool {
+	return []models.Tool{
+		tools_workflows.CreateBulkcreatecontactsTool(cfg),
+		tools_workflows.CreateBulkdeletecontactsTool(cfg),
+		tools_workflows.CreateBulkupdatecontactsTool(cfg),
+		tools_workflows.CreateCreatesegmentlistTool(cfg),
+		tools_workflows.CreateExportsmstemplatesTool(cfg),
+		tools_workflows.CreateGetsmstemplatehistoryTool(cfg),
+		tools_workflows.CreateGetsuppressionlistTool(cfg),
+		tools_workflows.CreateImportcontactsTool(cfg),
+		tools_workflows.CreateImportsmstemplatesTool(cfg),
+		tools_workflows.CreateLintsmstemplateTool(cfg),
+		tools_workflows.CreateMergecontactlistsTool(cfg),
+		tools_workflows.CreateNormalizephonenumbersTool(cfg),
+		tools_workflows.CreatePlansendtimeTool(cfg),
+		tools_workflows.CreateRemovesuppressedcontactsTool(cfg),
+		tools_workflows.CreateRollbacksmstemplateTool(cfg),
+		tools_workflows.CreateSendpersonalizedsmsTool(cfg),
+		tools_workflows.CreateSplitcontactlistTool(cfg),
+		tools_workflows.CreateSuppressrecipientsTool(cfg),
+		tools_workflows.CreateSynccontactlistTool(cfg),
+		tools_workflows.CreateUnsuppressrecipientsTool(cfg),
+	}
+}